@@ -0,0 +1,102 @@
+// Package asn1 implements a parser for ASN.1 modules as specified by ITU-T
+// X.680 (types and values), X.681 (information objects), X.682 (constraints)
+// and X.683 (parameterisation).
+//
+// The parser builds a description of types, classes and their fields.
+// Values, objects and object sets are not evaluated, but kept as source text.
+package asn1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/internal/memoize"
+	ttcn3 "github.com/nokia/ntt/ttcn3/token"
+)
+
+// Error is a syntax error.
+type Error struct {
+	Pos loc.Position
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+// cache stores the syntax trees of parsed files.
+var cache = memoize.Store{}
+
+type result struct {
+	mods []*Module
+	err  error
+}
+
+// ParseFile parses the ASN.1 modules of a file. Results are cached until the
+// file changes. The returned modules must not be modified.
+func ParseFile(path string) ([]*Module, error) {
+	f := fs.Open(path)
+	f.Handle = cache.Bind(f.ID(), func(ctx context.Context) interface{} {
+		b, err := f.Bytes()
+		if err != nil {
+			return &result{err: err}
+		}
+		mods, err := Parse(path, b)
+		return &result{mods: mods, err: err}
+	})
+	r := f.Handle.Get(context.TODO()).(*result)
+	return r.mods, r.err
+}
+
+// Parse parses the ASN.1 modules in src. Syntax errors are returned as
+// multierror, together with all modules and assignments which could be
+// parsed.
+func Parse(filename string, src []byte) ([]*Module, error) {
+	s := newScanner(filename, src)
+	p := &parser{src: src}
+	for {
+		tok := s.scan()
+		p.toks = append(p.toks, tok)
+		if tok.kind == tEOF {
+			break
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	var mods []*Module
+	for p.tok().kind != tEOF {
+		m := p.parseModuleDefinition()
+		if m == nil {
+			break
+		}
+		mods = append(mods, m)
+	}
+	return mods, p.errs.ErrorOrNil()
+}
+
+func (p *parser) parseModuleDefinition() (m *Module) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			m = nil
+		}
+	}()
+	return p.parseModule()
+}
+
+// TTCN3Name returns the TTCN-3 name of an ASN.1 identifier as specified by
+// ETSI ES 201 873-7: hyphens are replaced by underscores and TTCN-3 keywords
+// get an underscore appended.
+func TTCN3Name(name string) string {
+	name = strings.Replace(name, "-", "_", -1)
+	if ttcn3.Lookup(name) != ttcn3.IDENT {
+		name += "_"
+	}
+	return name
+}
@@ -0,0 +1,324 @@
+package asn1
+
+import "github.com/nokia/ntt/internal/loc"
+
+// Module is an ASN.1 module definition.
+type Module struct {
+	Name string
+	OID  string // Definitive identifier, like "{ itu-t(0) 1 }", if any.
+
+	// TagDefault is "EXPLICIT", "IMPLICIT", "AUTOMATIC" or empty.
+	TagDefault           string
+	ExtensibilityImplied bool
+
+	// Exports lists the exported symbols. Exports is nil if all symbols are
+	// exported.
+	Exports     []string
+	Imports     []*Import
+	Assignments []*Assignment
+
+	Pos, End loc.Position
+}
+
+// Lookup returns the assignment with the given name or nil.
+func (m *Module) Lookup(name string) *Assignment {
+	for _, a := range m.Assignments {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// Import is a list of symbols imported from another module.
+type Import struct {
+	Module  string
+	Symbols []string
+	Pos     loc.Position
+}
+
+// AssignmentKind distinguishes the kinds of assignments.
+type AssignmentKind int
+
+const (
+	TypeAssignment      AssignmentKind = iota // T ::= INTEGER
+	ValueAssignment                           // v INTEGER ::= 1
+	ValueSetAssignment                        // S INTEGER ::= { 1 | 2 }
+	ClassAssignment                           // C ::= CLASS { ... }
+	ObjectAssignment                          // o C ::= { ... }
+	ObjectSetAssignment                       // O C ::= { o1 | o2 }
+)
+
+func (k AssignmentKind) String() string {
+	switch k {
+	case TypeAssignment:
+		return "type"
+	case ValueAssignment:
+		return "value"
+	case ValueSetAssignment:
+		return "value set"
+	case ClassAssignment:
+		return "class"
+	case ObjectAssignment:
+		return "object"
+	case ObjectSetAssignment:
+		return "object set"
+	}
+	return "unknown"
+}
+
+// Assignment is a type, value, value set, class, object or object set
+// assignment.
+type Assignment struct {
+	Kind AssignmentKind
+	Name string
+
+	// Params holds the formal parameters of parameterised assignments.
+	Params []*Param
+
+	// Type is the assigned type of type assignments and the governing type of
+	// values and value sets. For objects and object sets Type references the
+	// governing class. For class assignments Type is set when a class is
+	// defined by reference, like "C ::= TYPE-IDENTIFIER".
+	Type *Type
+
+	// Class is the class defined by a class assignment.
+	Class *Class
+
+	// Value is the assigned value, value set, object or object set.
+	Value *Value
+
+	Pos, End loc.Position
+}
+
+// Param is a formal parameter of a parameterised assignment, like "Type" or
+// "INTEGER : ub".
+type Param struct {
+	Governor *Type // nil for type and class parameters
+	Name     string
+}
+
+// TypeKind distinguishes ASN.1 types.
+type TypeKind int
+
+const (
+	ReferenceType TypeKind = iota // T, M.T, T{P}
+	FieldType                     // C.&Type, C.&id
+	InstanceOfType
+	BooleanType
+	IntegerType
+	RealType
+	NullType
+	EnumeratedType
+	BitStringType
+	OctetStringType
+	ObjectIdentifierType
+	RelativeOIDType
+	StringType // IA5String, UTF8String, GeneralizedTime, ...
+	SequenceType
+	SetType
+	ChoiceType
+	SequenceOfType
+	SetOfType
+	AnyType      // ANY [DEFINED BY id] from X.208
+	ExternalType // EXTERNAL, EMBEDDED PDV, CHARACTER STRING
+)
+
+// Type is an ASN.1 type.
+type Type struct {
+	Kind TypeKind
+	Tag  *Tag
+
+	// Module and Name of references. Name also contains the name of
+	// restricted character string types, like "IA5String", and the class
+	// of InstanceOfType.
+	Module string
+	Name   string
+
+	// Field of object class field types, like "&Type" or "&id.&Type".
+	Field string
+
+	// Args holds the actual parameters of parameterised references.
+	Args []*Value
+
+	// Components of SEQUENCE, SET and CHOICE types.
+	Components []*Component
+
+	// Elem is the element type of SEQUENCE OF and SET OF types; ElemName
+	// the optional element identifier.
+	Elem     *Type
+	ElemName string
+
+	// NamedNumbers holds enumeration items, named numbers of INTEGER and
+	// named bits of BIT STRING types.
+	NamedNumbers []*NamedNumber
+
+	// Extensible is set if component or enumeration lists contain an
+	// extension marker.
+	Extensible bool
+
+	Constraints []*Constraint
+
+	Pos, End loc.Position
+}
+
+// Tag is a type tag, like "[APPLICATION 3] IMPLICIT".
+type Tag struct {
+	Class  string // "UNIVERSAL", "APPLICATION", "PRIVATE" or empty
+	Number string
+	Mode   string // "IMPLICIT", "EXPLICIT" or empty
+}
+
+// Component is a component of a SEQUENCE, SET or CHOICE type.
+type Component struct {
+	Name     string
+	Type     *Type
+	Optional bool
+	Default  *Value
+
+	// ComponentsOf is set for "COMPONENTS OF Type" clauses.
+	ComponentsOf bool
+
+	// Extension is set for extension additions.
+	Extension bool
+
+	Pos loc.Position
+}
+
+// NamedNumber is a named number, enumeration item or named bit. Value is
+// empty for enumeration items without explicit number.
+type NamedNumber struct {
+	Name  string
+	Value string
+
+	// Extension is set for enumeration items following the extension
+	// marker.
+	Extension bool
+
+	Pos loc.Position
+}
+
+// ConstraintKind distinguishes constraints.
+type ConstraintKind int
+
+const (
+	ValueConstraint        ConstraintKind = iota // (1), ("abc")
+	RangeConstraint                              // (1..10), (MIN..0)
+	SizeConstraint                               // SIZE(1..10)
+	AlphabetConstraint                           // FROM("a".."z")
+	PatternConstraint                            // PATTERN "[a-z]*"
+	TypeConstraint                               // (INCLUDES T), (T), (ValueSet)
+	InnerTypeConstraint                          // WITH COMPONENTS { ... }
+	UnionConstraint                              // (1 | 2), (A UNION B)
+	IntersectionConstraint                       // (A ^ B)
+	ExceptConstraint                             // (A EXCEPT B)
+	AllExceptConstraint                          // (ALL EXCEPT A)
+	ContentsConstraint                           // (CONTAINING T ENCODED BY e)
+	TableConstraint                              // ({ObjSet}{@id})
+	UserConstraint                               // (CONSTRAINED BY { ... })
+)
+
+// Constraint is a subtype or general constraint.
+type Constraint struct {
+	Kind ConstraintKind
+
+	// Value of value and pattern constraints.
+	Value *Value
+
+	// Lower and Upper bounds of ranges. Nil denotes MIN and MAX
+	// respectively. LowerOpen and UpperOpen mark excluded bounds, like in
+	// "0<..<10".
+	Lower, Upper         *Value
+	LowerOpen, UpperOpen bool
+
+	// Elems holds the operands of unions, intersections and exclusions, and
+	// the inner constraint of SIZE and FROM.
+	Elems []*Constraint
+
+	// Type of type and contents constraints.
+	Type *Type
+
+	// EncodedBy is the encoding of contents constraints.
+	EncodedBy *Value
+
+	// ObjectSet and AtNotations of table constraints, like "ObjSet" and
+	// ["@id"] of "({ObjSet}{@id})".
+	ObjectSet   *Value
+	AtNotations []string
+
+	// Extensible is set if the element set contains an extension marker;
+	// Additional holds the additional element set.
+	Extensible bool
+	Additional *Constraint
+
+	// Text is the source text of the constraint.
+	Text string
+
+	Pos, End loc.Position
+}
+
+// Class is an information object class.
+type Class struct {
+	Fields []*ClassField
+
+	// Syntax is the source text of the WITH SYNTAX clause, without braces.
+	Syntax string
+
+	Pos, End loc.Position
+}
+
+// Lookup returns the field with the given name, like "&id", or nil.
+func (c *Class) Lookup(name string) *ClassField {
+	for _, f := range c.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// FieldKind distinguishes the fields of information object classes.
+type FieldKind int
+
+const (
+	TypeField                 FieldKind = iota // &Type
+	FixedTypeValueField                        // &id INTEGER
+	VariableTypeValueField                     // &val &Type
+	FixedTypeValueSetField                     // &Values INTEGER
+	VariableTypeValueSetField                  // &Values &Type
+	ObjectField                                // &obj CLASS
+	ObjectSetField                             // &Objs CLASS
+)
+
+// ClassField is a field of an information object class.
+type ClassField struct {
+	Kind FieldKind
+	Name string
+
+	// Type is the type of fixed type fields and the class of object and
+	// object set fields.
+	Type *Type
+
+	// TypeField names the field defining the type of variable type fields.
+	TypeField string
+
+	Unique   bool
+	Optional bool
+	Default  *Value
+
+	Pos loc.Position
+}
+
+// Value is a value, value set, object or object set. Values are kept as
+// source text.
+type Value struct {
+	Text     string
+	Pos, End loc.Position
+}
+
+func (v *Value) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.Text
+}
@@ -0,0 +1,1039 @@
+package asn1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/nokia/ntt/internal/loc"
+)
+
+// bailout is used by the parser to abort parsing of the current assignment.
+type bailout struct{}
+
+type parser struct {
+	src  []byte
+	toks []token
+	i    int
+	errs *multierror.Error
+}
+
+func (p *parser) tok() token { return p.peek(0) }
+
+func (p *parser) peek(n int) token {
+	if p.i+n < len(p.toks) {
+		return p.toks[p.i+n]
+	}
+	return p.toks[len(p.toks)-1]
+}
+
+func (p *parser) next() {
+	if p.i < len(p.toks)-1 {
+		p.i++
+	}
+}
+
+// is returns true if the current token has one of the given literals.
+func (p *parser) is(lits ...string) bool {
+	t := p.tok()
+	if t.kind != tIdent && t.kind != tSymbol {
+		return false
+	}
+	for _, lit := range lits {
+		if t.lit == lit {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...interface{}) {
+	p.errs = multierror.Append(p.errs, &Error{Pos: p.tok().pos, Msg: fmt.Sprintf(format, args...)})
+	panic(bailout{})
+}
+
+func (p *parser) expect(lit string) token {
+	t := p.tok()
+	if !p.is(lit) {
+		p.errorf("%q expected, found %s", lit, t)
+	}
+	p.next()
+	return t
+}
+
+func (p *parser) ident() string {
+	t := p.tok()
+	if t.kind != tIdent {
+		p.errorf("identifier expected, found %s", t)
+	}
+	p.next()
+	return t.lit
+}
+
+// end returns the end position of the previous token.
+func (p *parser) end() loc.Position {
+	if p.i == 0 {
+		return p.tok().pos
+	}
+	t := p.toks[p.i-1]
+	pos := t.pos
+	pos.Column += t.end - pos.Offset
+	pos.Offset = t.end
+	return pos
+}
+
+// text returns the source text from token start up to the previous token.
+func (p *parser) text(start token) string {
+	end := p.end().Offset
+	if end < start.pos.Offset {
+		return ""
+	}
+	return string(p.src[start.pos.Offset:end])
+}
+
+func (p *parser) value(start token) *Value {
+	return &Value{Text: p.text(start), Pos: start.pos, End: p.end()}
+}
+
+// skipBalanced skips tokens up to and including the matching closing
+// bracket of the current opening bracket.
+func (p *parser) skipBalanced() {
+	var stack []string
+	for {
+		switch t := p.tok(); {
+		case t.kind == tEOF:
+			p.errorf("unexpected end of file")
+		case p.is("{", "(", "[", "[["):
+			stack = append(stack, closing[t.lit])
+		case p.is("}", ")", "]", "]]"):
+			if len(stack) == 0 || stack[len(stack)-1] != t.lit {
+				p.errorf("unexpected %s", t)
+			}
+			stack = stack[:len(stack)-1]
+		}
+		p.next()
+		if len(stack) == 0 {
+			return
+		}
+	}
+}
+
+var closing = map[string]string{"{": "}", "(": ")", "[": "]", "[[": "]]"}
+
+// braced parses a brace enclosed list of tokens and returns its content.
+func (p *parser) braced() *Value {
+	p.expect("{")
+	start := p.tok()
+	for !p.is("}") {
+		p.skipItem()
+	}
+	v := p.value(start)
+	p.next()
+	return v
+}
+
+// list parses a brace enclosed, comma separated list of items and returns
+// their source text.
+func (p *parser) list() []*Value {
+	var items []*Value
+	p.expect("{")
+	for !p.is("}") {
+		start := p.tok()
+		for !p.is(",", "}") {
+			p.skipItem()
+		}
+		items = append(items, p.value(start))
+		if p.is(",") {
+			p.next()
+		}
+	}
+	p.next()
+	return items
+}
+
+// skipItem skips a single token or a balanced group of tokens.
+func (p *parser) skipItem() {
+	switch t := p.tok(); {
+	case t.kind == tEOF:
+		p.errorf("unexpected end of file")
+	case p.is("{", "(", "[", "[["):
+		p.skipBalanced()
+	case p.is("}", ")", "]", "]]"):
+		p.errorf("unexpected %s", t)
+	default:
+		p.next()
+	}
+}
+
+func (p *parser) parseModule() *Module {
+	m := &Module{Pos: p.tok().pos}
+	m.Name = p.ident()
+	if p.is("{") {
+		start := p.tok()
+		p.skipBalanced()
+		m.OID = p.text(start)
+	}
+	if p.tok().kind == tCString {
+		p.next() // IRI value
+	}
+	p.expect("DEFINITIONS")
+	if p.peek(1).lit == "INSTRUCTIONS" {
+		p.next()
+		p.next()
+	}
+	if p.is("EXPLICIT", "IMPLICIT", "AUTOMATIC") {
+		m.TagDefault = p.tok().lit
+		p.next()
+		p.expect("TAGS")
+	}
+	if p.is("EXTENSIBILITY") {
+		p.next()
+		p.expect("IMPLIED")
+		m.ExtensibilityImplied = true
+	}
+	p.expect("::=")
+	p.expect("BEGIN")
+
+	if p.is("EXPORTS") {
+		p.next()
+		if p.is("ALL") {
+			p.next()
+		} else {
+			m.Exports = []string{}
+			for !p.is(";") {
+				m.Exports = append(m.Exports, p.symbol())
+				if !p.is(";") {
+					p.expect(",")
+				}
+			}
+		}
+		p.expect(";")
+	}
+
+	if p.is("IMPORTS") {
+		p.next()
+		for !p.is(";") {
+			m.Imports = append(m.Imports, p.parseImport())
+		}
+		p.expect(";")
+	}
+
+	for p.tok().kind != tEOF && !p.is("END") {
+		if a := p.parseAssignment(); a != nil {
+			m.Assignments = append(m.Assignments, a)
+		}
+	}
+	m.End = p.tok().pos
+	p.expect("END")
+	classify(m)
+	return m
+}
+
+// symbol parses an exported or imported symbol. Parameterised references are
+// written with an empty brace pair, like "T{}".
+func (p *parser) symbol() string {
+	name := p.ident()
+	if p.is("{") {
+		p.expect("{")
+		p.expect("}")
+	}
+	return name
+}
+
+func (p *parser) parseImport() *Import {
+	imp := &Import{Pos: p.tok().pos}
+	for {
+		imp.Symbols = append(imp.Symbols, p.symbol())
+		if !p.is(",") {
+			break
+		}
+		p.next()
+	}
+	p.expect("FROM")
+	imp.Module = p.ident()
+
+	// An assigned identifier, which is either an object identifier value or
+	// a value reference. A value reference followed by "," or "FROM" starts
+	// the next symbol list, though.
+	switch t := p.tok(); {
+	case p.is("{"):
+		p.skipBalanced()
+	case t.kind == tIdent && !isUpper(t.lit):
+		if n := p.peek(1); n.lit != "," && n.lit != "FROM" && n.lit != "{" {
+			p.next()
+		}
+	}
+	if p.is("WITH") {
+		p.next()
+		p.next() // SUCCESSORS or DESCENDANTS
+	}
+	return imp
+}
+
+// parseAssignment parses an assignment. On syntax errors the error is
+// recorded and tokens are skipped up to the next assignment.
+func (p *parser) parseAssignment() (a *Assignment) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+			a = nil
+			p.sync()
+		}
+	}()
+
+	a = &Assignment{Pos: p.tok().pos}
+	a.Name = p.ident()
+	if p.is("{") {
+		a.Params = p.parseParams()
+	}
+
+	if p.is("::=") {
+		p.next()
+		if !isUpper(a.Name) {
+			p.errorf("type expected for value %s", a.Name)
+		}
+		if p.is("CLASS") {
+			a.Kind = ClassAssignment
+			a.Class = p.parseClass()
+		} else {
+			a.Kind = TypeAssignment
+			a.Type = p.parseType()
+		}
+	} else {
+		a.Type = p.parseType()
+		p.expect("::=")
+		a.Value = p.parseValue()
+		a.Kind = ValueAssignment
+		if isUpper(a.Name) {
+			a.Kind = ValueSetAssignment
+		}
+	}
+	a.End = p.end()
+	return a
+}
+
+// sync skips tokens up to the next identifier at the beginning of a line,
+// which likely starts a new assignment.
+func (p *parser) sync() {
+	depth := 0
+	for p.tok().kind != tEOF {
+		if p.is("{", "(", "[", "[[") {
+			depth++
+		} else if p.is("}", ")", "]", "]]") {
+			depth--
+		}
+		p.next()
+		if t := p.tok(); depth <= 0 && t.bol && t.kind == tIdent {
+			return
+		}
+	}
+}
+
+func (p *parser) parseParams() []*Param {
+	var params []*Param
+	p.expect("{")
+	for {
+		t := p.parseType()
+		if p.is(":") {
+			p.next()
+			params = append(params, &Param{Governor: t, Name: p.ident()})
+		} else if t.Kind == ReferenceType && t.Module == "" && t.Args == nil && t.Constraints == nil {
+			params = append(params, &Param{Name: t.Name})
+		} else {
+			p.errorf("parameter expected")
+		}
+		if !p.is(",") {
+			break
+		}
+		p.next()
+	}
+	p.expect("}")
+	return params
+}
+
+func (p *parser) parseType() *Type {
+	pos := p.tok().pos
+	var tag *Tag
+	if p.is("[") {
+		tag = p.parseTag()
+	}
+	t := p.parseTypeBody()
+	t.Tag = tag
+	t.Pos = pos
+	for p.is("(") {
+		t.Constraints = append(t.Constraints, p.parseConstraint())
+	}
+	t.End = p.end()
+	return t
+}
+
+func (p *parser) parseTag() *Tag {
+	tag := &Tag{}
+	p.expect("[")
+	if p.peek(1).lit == ":" {
+		// Encoding reference, like "[XER:ATTRIBUTE]"
+		for !p.is("]") {
+			p.skipItem()
+		}
+		p.next()
+		return p.tagMode(tag)
+	}
+	if p.is("UNIVERSAL", "APPLICATION", "PRIVATE") {
+		tag.Class = p.tok().lit
+		p.next()
+	}
+	start := p.tok()
+	for !p.is("]") {
+		p.skipItem()
+	}
+	tag.Number = p.text(start)
+	p.next()
+	return p.tagMode(tag)
+}
+
+func (p *parser) tagMode(tag *Tag) *Tag {
+	if p.is("IMPLICIT", "EXPLICIT") {
+		tag.Mode = p.tok().lit
+		p.next()
+	}
+	return tag
+}
+
+func (p *parser) parseTypeBody() *Type {
+	t := &Type{}
+	tok := p.tok()
+	if tok.kind != tIdent {
+		p.errorf("type expected, found %s", tok)
+	}
+
+	switch lit := tok.lit; {
+	case lit == "BOOLEAN":
+		p.next()
+		t.Kind = BooleanType
+	case lit == "INTEGER":
+		p.next()
+		t.Kind = IntegerType
+		if p.is("{") {
+			p.parseNamedNumbers(t)
+		}
+	case lit == "REAL":
+		p.next()
+		t.Kind = RealType
+	case lit == "NULL":
+		p.next()
+		t.Kind = NullType
+	case lit == "ENUMERATED":
+		p.next()
+		t.Kind = EnumeratedType
+		p.parseNamedNumbers(t)
+	case lit == "BIT":
+		p.next()
+		p.expect("STRING")
+		t.Kind = BitStringType
+		if p.is("{") {
+			p.parseNamedNumbers(t)
+		}
+	case lit == "OCTET":
+		p.next()
+		p.expect("STRING")
+		t.Kind = OctetStringType
+	case lit == "OBJECT":
+		p.next()
+		p.expect("IDENTIFIER")
+		t.Kind = ObjectIdentifierType
+	case lit == "RELATIVE-OID":
+		p.next()
+		t.Kind = RelativeOIDType
+	case lit == "EXTERNAL":
+		p.next()
+		t.Kind = ExternalType
+		t.Name = lit
+	case lit == "EMBEDDED" || lit == "CHARACTER":
+		p.next()
+		t.Kind = ExternalType
+		t.Name = lit + " " + p.tok().lit
+		p.next()
+	case stringTypes[lit]:
+		p.next()
+		t.Kind = StringType
+		t.Name = lit
+	case lit == "SEQUENCE" || lit == "SET":
+		p.next()
+		t.Kind = SequenceType
+		if lit == "SET" {
+			t.Kind = SetType
+		}
+		if p.is("{") {
+			p.parseComponents(t)
+			break
+		}
+		t.Kind = SequenceOfType
+		if lit == "SET" {
+			t.Kind = SetOfType
+		}
+		switch {
+		case p.is("SIZE"):
+			pos := p.tok().pos
+			start := p.tok()
+			p.next()
+			c := p.parseConstraint()
+			t.Constraints = append(t.Constraints, &Constraint{Kind: SizeConstraint, Elems: []*Constraint{c}, Text: p.text(start), Pos: pos, End: p.end()})
+		case p.is("("):
+			t.Constraints = append(t.Constraints, p.parseConstraint())
+		}
+		p.expect("OF")
+		if n := p.tok(); n.kind == tIdent && !isUpper(n.lit) {
+			t.ElemName = n.lit
+			p.next()
+		}
+		t.Elem = p.parseType()
+	case lit == "CHOICE":
+		p.next()
+		t.Kind = ChoiceType
+		p.parseComponents(t)
+	case lit == "ANY":
+		p.next()
+		t.Kind = AnyType
+		if p.is("DEFINED") {
+			p.next()
+			p.expect("BY")
+			p.ident()
+		}
+	case lit == "INSTANCE":
+		p.next()
+		p.expect("OF")
+		t.Kind = InstanceOfType
+		t.Name = p.ident()
+	case isUpper(lit):
+		p.next()
+		t.Kind = ReferenceType
+		t.Name = lit
+		if p.is(".") && p.peek(1).kind == tIdent {
+			p.next()
+			t.Module = t.Name
+			t.Name = p.ident()
+		}
+		for p.is(".") && p.peek(1).kind == tField {
+			p.next()
+			t.Kind = FieldType
+			if t.Field != "" {
+				t.Field += "."
+			}
+			t.Field += p.tok().lit
+			p.next()
+		}
+		if t.Kind == ReferenceType && p.is("{") {
+			t.Args = p.list()
+		}
+	default:
+		p.errorf("type expected, found %s", tok)
+	}
+	return t
+}
+
+var stringTypes = map[string]bool{
+	"BMPString":        true,
+	"GeneralString":    true,
+	"GraphicString":    true,
+	"IA5String":        true,
+	"ISO646String":     true,
+	"NumericString":    true,
+	"PrintableString":  true,
+	"TeletexString":    true,
+	"T61String":        true,
+	"UniversalString":  true,
+	"UTF8String":       true,
+	"VideotexString":   true,
+	"VisibleString":    true,
+	"GeneralizedTime":  true,
+	"UTCTime":          true,
+	"ObjectDescriptor": true,
+	"DATE":             true,
+	"DATE-TIME":        true,
+	"DURATION":         true,
+	"TIME":             true,
+	"TIME-OF-DAY":      true,
+}
+
+// parseComponents parses the component list of SEQUENCE, SET and CHOICE
+// types, including extension markers and version brackets.
+func (p *parser) parseComponents(t *Type) {
+	p.expect("{")
+	ext := false
+	for !p.is("}") {
+		switch {
+		case p.is("..."):
+			p.next()
+			t.Extensible = true
+			ext = !ext
+			p.parseExceptionSpec()
+		case p.is("[["):
+			p.next()
+			if p.tok().kind == tNumber && p.peek(1).lit == ":" {
+				p.next()
+				p.next()
+			}
+			for {
+				c := p.parseComponent()
+				c.Extension = true
+				t.Components = append(t.Components, c)
+				if !p.is(",") {
+					break
+				}
+				p.next()
+			}
+			p.expect("]]")
+		default:
+			c := p.parseComponent()
+			c.Extension = ext
+			t.Components = append(t.Components, c)
+		}
+		if !p.is("}") {
+			p.expect(",")
+		}
+	}
+	p.next()
+}
+
+func (p *parser) parseComponent() *Component {
+	c := &Component{Pos: p.tok().pos}
+	if p.is("COMPONENTS") {
+		p.next()
+		p.expect("OF")
+		c.ComponentsOf = true
+		c.Type = p.parseType()
+		return c
+	}
+	if t := p.tok(); t.kind != tIdent || isUpper(t.lit) {
+		p.errorf("identifier expected, found %s", t)
+	}
+	c.Name = p.ident()
+	c.Type = p.parseType()
+	switch {
+	case p.is("OPTIONAL"):
+		p.next()
+		c.Optional = true
+	case p.is("DEFAULT"):
+		p.next()
+		c.Default = p.parseValue()
+	}
+	return c
+}
+
+func (p *parser) parseExceptionSpec() {
+	if p.is("!") {
+		p.next()
+		p.parseValue()
+	}
+}
+
+// parseNamedNumbers parses enumerations, named numbers and named bits.
+func (p *parser) parseNamedNumbers(t *Type) {
+	p.expect("{")
+	ext := false
+	for !p.is("}") {
+		if p.is("...") {
+			p.next()
+			t.Extensible = true
+			ext = true
+			p.parseExceptionSpec()
+		} else {
+			n := &NamedNumber{Pos: p.tok().pos, Extension: ext}
+			n.Name = p.ident()
+			if p.is("(") {
+				p.next()
+				n.Value = p.parseValue().Text
+				p.expect(")")
+			}
+			t.NamedNumbers = append(t.NamedNumbers, n)
+		}
+		if !p.is("}") {
+			p.expect(",")
+		}
+	}
+	p.next()
+}
+
+// parseValue parses values, value sets, objects and object sets.
+func (p *parser) parseValue() *Value {
+	start := p.tok()
+	p.skipValue()
+	return p.value(start)
+}
+
+func (p *parser) skipValue() {
+	switch t := p.tok(); t.kind {
+	case tNumber, tReal, tCString, tBString, tHString, tField:
+		p.next()
+	case tIdent:
+		p.next()
+		for p.is(".") && (p.peek(1).kind == tIdent || p.peek(1).kind == tField) {
+			p.next()
+			p.next()
+		}
+		if p.is("{") {
+			p.skipBalanced()
+		}
+		if p.is(":") {
+			p.next()
+			p.skipValue()
+		}
+	default:
+		switch {
+		case p.is("{"):
+			p.skipBalanced()
+		case p.is("-"):
+			p.next()
+			if k := p.tok().kind; k != tNumber && k != tReal {
+				p.errorf("number expected, found %s", p.tok())
+			}
+			p.next()
+		default:
+			p.errorf("value expected, found %s", t)
+		}
+	}
+}
+
+func (p *parser) parseConstraint() *Constraint {
+	pos := p.tok().pos
+	p.expect("(")
+	start := p.tok()
+
+	var c *Constraint
+	switch {
+	case p.is("CONSTRAINED"):
+		p.next()
+		p.expect("BY")
+		p.skipBalanced()
+		c = &Constraint{Kind: UserConstraint}
+	case p.is("{"):
+		c = &Constraint{Kind: TableConstraint, ObjectSet: p.braced()}
+		if p.is("{") {
+			for _, v := range p.list() {
+				c.AtNotations = append(c.AtNotations, strings.Join(strings.Fields(v.Text), ""))
+			}
+		}
+	case p.is("CONTAINING", "ENCODED"):
+		c = p.parseContents()
+	default:
+		c = p.parseElementSetSpecs()
+	}
+	p.parseExceptionSpec()
+	c.Text = p.text(start)
+	c.Pos = pos
+	p.expect(")")
+	c.End = p.end()
+	return c
+}
+
+func (p *parser) parseContents() *Constraint {
+	c := &Constraint{Kind: ContentsConstraint}
+	if p.is("CONTAINING") {
+		p.next()
+		c.Type = p.parseType()
+	}
+	if p.is("ENCODED") {
+		p.next()
+		p.expect("BY")
+		c.EncodedBy = p.parseValue()
+	}
+	return c
+}
+
+func (p *parser) parseElementSetSpecs() *Constraint {
+	var c *Constraint
+	if p.is("...") {
+		c = &Constraint{Kind: UnionConstraint}
+	} else {
+		c = p.parseElementSet()
+		if !p.is(",") {
+			return c
+		}
+		p.next()
+	}
+	p.expect("...")
+	c.Extensible = true
+	p.parseExceptionSpec()
+	if p.is(",") {
+		p.next()
+		c.Additional = p.parseElementSet()
+	}
+	return c
+}
+
+func (p *parser) parseElementSet() *Constraint {
+	if p.is("ALL") {
+		pos := p.tok().pos
+		start := p.tok()
+		p.next()
+		p.expect("EXCEPT")
+		e := p.parseElements()
+		return &Constraint{Kind: AllExceptConstraint, Elems: []*Constraint{e}, Text: p.text(start), Pos: pos, End: p.end()}
+	}
+	return p.parseBinary(UnionConstraint, []string{"|", "UNION"}, func() *Constraint {
+		return p.parseBinary(IntersectionConstraint, []string{"^", "INTERSECTION"}, p.parseIntersectionElements)
+	})
+}
+
+// parseBinary parses a list of operands separated by one of the given
+// operators.
+func (p *parser) parseBinary(kind ConstraintKind, ops []string, operand func() *Constraint) *Constraint {
+	start := p.tok()
+	elems := []*Constraint{operand()}
+	for p.is(ops...) {
+		p.next()
+		elems = append(elems, operand())
+	}
+	if len(elems) == 1 {
+		return elems[0]
+	}
+	return &Constraint{Kind: kind, Elems: elems, Text: p.text(start), Pos: start.pos, End: p.end()}
+}
+
+func (p *parser) parseIntersectionElements() *Constraint {
+	start := p.tok()
+	e := p.parseElements()
+	if !p.is("EXCEPT") {
+		return e
+	}
+	p.next()
+	x := p.parseElements()
+	return &Constraint{Kind: ExceptConstraint, Elems: []*Constraint{e, x}, Text: p.text(start), Pos: start.pos, End: p.end()}
+}
+
+func (p *parser) parseElements() *Constraint {
+	start := p.tok()
+	var c *Constraint
+	switch {
+	case p.is("("):
+		c = p.parseConstraint()
+	case p.is("SIZE"):
+		p.next()
+		c = &Constraint{Kind: SizeConstraint, Elems: []*Constraint{p.parseConstraint()}}
+	case p.is("FROM"):
+		p.next()
+		c = &Constraint{Kind: AlphabetConstraint, Elems: []*Constraint{p.parseConstraint()}}
+	case p.is("PATTERN"):
+		p.next()
+		c = &Constraint{Kind: PatternConstraint, Value: p.parseValue()}
+	case p.is("WITH"):
+		p.next()
+		switch {
+		case p.is("COMPONENT"):
+			p.next()
+			c = &Constraint{Kind: InnerTypeConstraint, Elems: []*Constraint{p.parseConstraint()}}
+		default:
+			p.expect("COMPONENTS")
+			p.skipBalanced()
+			c = &Constraint{Kind: InnerTypeConstraint}
+		}
+	case p.is("INCLUDES"):
+		p.next()
+		c = &Constraint{Kind: TypeConstraint, Type: p.parseType()}
+	case p.is("CONTAINING", "ENCODED"):
+		c = p.parseContents()
+	case p.is("MIN"):
+		p.next()
+		c = p.parseRange(nil)
+	case p.isTypeStart():
+		c = &Constraint{Kind: TypeConstraint, Type: p.parseType()}
+	default:
+		v := p.parseValue()
+		if p.is("<", "..") {
+			c = p.parseRange(v)
+		} else {
+			c = &Constraint{Kind: ValueConstraint, Value: v}
+		}
+	}
+	if c.Text == "" {
+		c.Text = p.text(start)
+		c.Pos = start.pos
+		c.End = p.end()
+	}
+	return c
+}
+
+// parseRange parses the remainder of a range, starting after the lower bound.
+func (p *parser) parseRange(lower *Value) *Constraint {
+	c := &Constraint{Kind: RangeConstraint, Lower: lower}
+	if p.is("<") {
+		p.next()
+		c.LowerOpen = true
+	}
+	p.expect("..")
+	if p.is("<") {
+		p.next()
+		c.UpperOpen = true
+	}
+	if p.is("MAX") {
+		p.next()
+	} else {
+		c.Upper = p.parseValue()
+	}
+	return c
+}
+
+// isTypeStart returns true if the current token starts a type rather than a
+// value.
+func (p *parser) isTypeStart() bool {
+	t := p.tok()
+	if p.is("[") {
+		return true
+	}
+	if t.kind != tIdent || !isUpper(t.lit) || valueKeywords[t.lit] {
+		return false
+	}
+	// External value reference, like "M.v".
+	if n := p.peek(1); n.lit == "." && p.peek(2).kind == tIdent && !isUpper(p.peek(2).lit) {
+		return false
+	}
+	// Open type value, like "INTEGER : 1".
+	return p.peek(1).lit != ":"
+}
+
+var valueKeywords = map[string]bool{
+	"TRUE":           true,
+	"FALSE":          true,
+	"NULL":           true,
+	"PLUS-INFINITY":  true,
+	"MINUS-INFINITY": true,
+	"NOT-A-NUMBER":   true,
+}
+
+func (p *parser) parseClass() *Class {
+	c := &Class{Pos: p.tok().pos}
+	p.expect("CLASS")
+	p.expect("{")
+	for !p.is("}") {
+		c.Fields = append(c.Fields, p.parseClassField())
+		if !p.is("}") {
+			p.expect(",")
+		}
+	}
+	p.next()
+	if p.is("WITH") && p.peek(1).lit == "SYNTAX" {
+		p.next()
+		p.next()
+		c.Syntax = p.braced().Text
+	}
+	c.End = p.end()
+	return c
+}
+
+func (p *parser) parseClassField() *ClassField {
+	f := &ClassField{Pos: p.tok().pos}
+	if p.tok().kind != tField {
+		p.errorf("field name expected, found %s", p.tok())
+	}
+	f.Name = p.tok().lit
+	p.next()
+
+	set := isUpper(f.Name)
+	switch {
+	case p.is(",", "}", "OPTIONAL", "DEFAULT"):
+		f.Kind = TypeField
+	case p.tok().kind == tField:
+		f.TypeField = p.tok().lit
+		p.next()
+		f.Kind = VariableTypeValueField
+		if set {
+			f.Kind = VariableTypeValueSetField
+		}
+	default:
+		f.Type = p.parseType()
+		f.Kind = FixedTypeValueField
+		if set {
+			f.Kind = FixedTypeValueSetField
+		}
+	}
+
+	if p.is("UNIQUE") {
+		p.next()
+		f.Unique = true
+	}
+	switch {
+	case p.is("OPTIONAL"):
+		p.next()
+		f.Optional = true
+	case p.is("DEFAULT"):
+		p.next()
+		f.Default = p.parseValue()
+	}
+	return f
+}
+
+// classify distinguishes objects from values and classes from types. The
+// syntax of those is ambiguous and depends on whether the governor is a
+// class. Classes are defined using CLASS, are builtin classes or are
+// referenced by all-uppercase names not defined as type in this module.
+func classify(m *Module) {
+	types := make(map[string]bool)
+	classes := map[string]bool{
+		"TYPE-IDENTIFIER": true,
+		"ABSTRACT-SYNTAX": true,
+	}
+	for _, a := range m.Assignments {
+		switch a.Kind {
+		case ClassAssignment:
+			classes[a.Name] = true
+		case TypeAssignment:
+			types[a.Name] = true
+		}
+	}
+
+	isClass := func(t *Type) bool {
+		if t == nil || t.Kind != ReferenceType || t.Args != nil || t.Constraints != nil {
+			return false
+		}
+		if t.Module == "" && classes[t.Name] {
+			return true
+		}
+		return (t.Module != "" || !types[t.Name]) && isAllUpper(t.Name)
+	}
+
+	// Class references may be chained, like "A ::= B; B ::= CLASS {...}".
+	for changed := true; changed; {
+		changed = false
+		for _, a := range m.Assignments {
+			if a.Kind == TypeAssignment && a.Params == nil && isAllUpper(a.Name) && isClass(a.Type) {
+				a.Kind = ClassAssignment
+				classes[a.Name] = true
+				delete(types, a.Name)
+				changed = true
+			}
+		}
+	}
+
+	for _, a := range m.Assignments {
+		switch {
+		case a.Kind == ValueAssignment && isClass(a.Type):
+			a.Kind = ObjectAssignment
+		case a.Kind == ValueSetAssignment && isClass(a.Type):
+			a.Kind = ObjectSetAssignment
+		case a.Kind == ClassAssignment && a.Class != nil:
+			for _, f := range a.Class.Fields {
+				switch {
+				case f.Kind == FixedTypeValueField && isClass(f.Type):
+					f.Kind = ObjectField
+				case f.Kind == FixedTypeValueSetField && isClass(f.Type):
+					f.Kind = ObjectSetField
+				}
+			}
+		}
+	}
+}
+
+// isUpper returns true if name, with an optional leading "&", starts with an
+// uppercase letter.
+func isUpper(name string) bool {
+	name = strings.TrimPrefix(name, "&")
+	return name != "" && 'A' <= name[0] && name[0] <= 'Z'
+}
+
+// isAllUpper returns true if name contains no lowercase letters, which is
+// required for class references.
+func isAllUpper(name string) bool {
+	return isUpper(name) && strings.ToUpper(name) == name
+}
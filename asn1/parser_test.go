@@ -0,0 +1,272 @@
+package asn1_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/asn1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, src string) *asn1.Module {
+	t.Helper()
+	mods, err := asn1.Parse("test.asn", []byte(src))
+	require.Nil(t, err)
+	require.Len(t, mods, 1)
+	return mods[0]
+}
+
+func TestModule(t *testing.T) {
+	m := parse(t, `
+		-- A comment -- Test-Module { itu-t(0) 1 } DEFINITIONS AUTOMATIC TAGS EXTENSIBILITY IMPLIED ::=
+		BEGIN
+		EXPORTS T, v;
+		IMPORTS
+			A, B{} FROM Other-Module { 1 2 }
+			C FROM Third modId
+			d FROM Fourth;
+		/* block /* nested */ comment */
+		T ::= INTEGER
+		v T ::= 5
+		END`)
+
+	assert.Equal(t, "Test-Module", m.Name)
+	assert.Equal(t, "{ itu-t(0) 1 }", m.OID)
+	assert.Equal(t, "AUTOMATIC", m.TagDefault)
+	assert.True(t, m.ExtensibilityImplied)
+	assert.Equal(t, []string{"T", "v"}, m.Exports)
+	if assert.Len(t, m.Imports, 3) {
+		assert.Equal(t, "Other-Module", m.Imports[0].Module)
+		assert.Equal(t, []string{"A", "B"}, m.Imports[0].Symbols)
+		assert.Equal(t, "Third", m.Imports[1].Module)
+		assert.Equal(t, []string{"C"}, m.Imports[1].Symbols)
+		assert.Equal(t, "Fourth", m.Imports[2].Module)
+		assert.Equal(t, []string{"d"}, m.Imports[2].Symbols)
+	}
+	if assert.Len(t, m.Assignments, 2) {
+		assert.Equal(t, asn1.TypeAssignment, m.Assignments[0].Kind)
+		assert.Equal(t, 10, m.Assignments[0].Pos.Line)
+		v := m.Lookup("v")
+		assert.Equal(t, asn1.ValueAssignment, v.Kind)
+		assert.Equal(t, "T", v.Type.Name)
+		assert.Equal(t, "5", v.Value.Text)
+	}
+}
+
+func TestTypes(t *testing.T) {
+	m := parse(t, `M DEFINITIONS ::= BEGIN
+		S ::= SEQUENCE {
+			a   [0] IMPLICIT INTEGER { low(0), high(10) } (0..10),
+			b   BIT STRING { x(0), y(1) } (SIZE(8)) OPTIONAL,
+			c   ENUMERATED { red, green(5), ..., blue } DEFAULT red,
+			...,
+			[[ 2: d OCTET STRING, e Other.T ]],
+			...,
+			f   SEQUENCE (SIZE(1..maxItems)) OF item IA5String,
+			COMPONENTS OF Base
+		}
+		C ::= [APPLICATION 3] CHOICE { x NULL, y SET OF BOOLEAN, z REAL }
+		O ::= OBJECT IDENTIFIER
+		END`)
+
+	s := m.Lookup("S").Type
+	assert.Equal(t, asn1.SequenceType, s.Kind)
+	assert.True(t, s.Extensible)
+	require.Len(t, s.Components, 7)
+
+	a := s.Components[0]
+	assert.Equal(t, "a", a.Name)
+	assert.Equal(t, asn1.IntegerType, a.Type.Kind)
+	assert.Equal(t, &asn1.Tag{Number: "0", Mode: "IMPLICIT"}, a.Type.Tag)
+	assert.Equal(t, "high", a.Type.NamedNumbers[1].Name)
+	assert.Equal(t, "10", a.Type.NamedNumbers[1].Value)
+	require.Len(t, a.Type.Constraints, 1)
+	r := a.Type.Constraints[0]
+	assert.Equal(t, asn1.RangeConstraint, r.Kind)
+	assert.Equal(t, "0", r.Lower.Text)
+	assert.Equal(t, "10", r.Upper.Text)
+
+	b := s.Components[1]
+	assert.Equal(t, asn1.BitStringType, b.Type.Kind)
+	assert.True(t, b.Optional)
+	assert.Equal(t, asn1.SizeConstraint, b.Type.Constraints[0].Kind)
+
+	c := s.Components[2]
+	assert.Equal(t, asn1.EnumeratedType, c.Type.Kind)
+	assert.True(t, c.Type.Extensible)
+	assert.Equal(t, "red", c.Default.Text)
+	assert.Len(t, c.Type.NamedNumbers, 3)
+	assert.True(t, c.Type.NamedNumbers[2].Extension)
+
+	assert.True(t, s.Components[3].Extension)
+	assert.Equal(t, "Other", s.Components[4].Type.Module)
+	assert.Equal(t, "T", s.Components[4].Type.Name)
+
+	f := s.Components[5]
+	assert.False(t, f.Extension)
+	assert.Equal(t, asn1.SequenceOfType, f.Type.Kind)
+	assert.Equal(t, "item", f.Type.ElemName)
+	assert.Equal(t, asn1.StringType, f.Type.Elem.Kind)
+	assert.Equal(t, "IA5String", f.Type.Elem.Name)
+	assert.Equal(t, "SIZE(1..maxItems)", f.Type.Constraints[0].Text)
+
+	assert.True(t, s.Components[6].ComponentsOf)
+	assert.Equal(t, "Base", s.Components[6].Type.Name)
+
+	ch := m.Lookup("C").Type
+	assert.Equal(t, asn1.ChoiceType, ch.Kind)
+	assert.Equal(t, &asn1.Tag{Class: "APPLICATION", Number: "3"}, ch.Tag)
+	assert.Equal(t, asn1.SetOfType, ch.Components[1].Type.Kind)
+
+	assert.Equal(t, asn1.ObjectIdentifierType, m.Lookup("O").Type.Kind)
+}
+
+func TestConstraints(t *testing.T) {
+	m := parse(t, `M DEFINITIONS ::= BEGIN
+		A ::= INTEGER (1 | 2 | 5<..<10, ...)
+		B ::= IA5String (FROM("a".."z") ^ SIZE(1..MAX))
+		C ::= INTEGER (ALL EXCEPT 0)
+		D ::= OCTET STRING (CONTAINING S ENCODED BY ber)
+		E ::= UTF8String (PATTERN "[a-z]+")
+		F ::= S (WITH COMPONENTS { ..., a PRESENT })
+		G ::= INTEGER (MIN..-1 | Small)
+		END`)
+
+	a := m.Lookup("A").Type.Constraints[0]
+	assert.Equal(t, asn1.UnionConstraint, a.Kind)
+	assert.True(t, a.Extensible)
+	require.Len(t, a.Elems, 3)
+	assert.Equal(t, asn1.ValueConstraint, a.Elems[0].Kind)
+	assert.True(t, a.Elems[2].LowerOpen)
+	assert.True(t, a.Elems[2].UpperOpen)
+
+	b := m.Lookup("B").Type.Constraints[0]
+	assert.Equal(t, asn1.IntersectionConstraint, b.Kind)
+	assert.Equal(t, asn1.AlphabetConstraint, b.Elems[0].Kind)
+	assert.Equal(t, asn1.SizeConstraint, b.Elems[1].Kind)
+	assert.Nil(t, b.Elems[1].Elems[0].Upper)
+
+	assert.Equal(t, asn1.AllExceptConstraint, m.Lookup("C").Type.Constraints[0].Kind)
+
+	d := m.Lookup("D").Type.Constraints[0]
+	assert.Equal(t, asn1.ContentsConstraint, d.Kind)
+	assert.Equal(t, "S", d.Type.Name)
+	assert.Equal(t, "ber", d.EncodedBy.Text)
+
+	assert.Equal(t, asn1.PatternConstraint, m.Lookup("E").Type.Constraints[0].Kind)
+	assert.Equal(t, asn1.InnerTypeConstraint, m.Lookup("F").Type.Constraints[0].Kind)
+
+	g := m.Lookup("G").Type.Constraints[0]
+	assert.Nil(t, g.Elems[0].Lower)
+	assert.Equal(t, "-1", g.Elems[0].Upper.Text)
+	assert.Equal(t, asn1.TypeConstraint, g.Elems[1].Kind)
+}
+
+func TestInformationObjects(t *testing.T) {
+	m := parse(t, `M DEFINITIONS ::= BEGIN
+		OPERATION ::= CLASS {
+			&ArgumentType OPTIONAL,
+			&code         INTEGER UNIQUE,
+			&Errors       ERROR OPTIONAL,
+			&value        &ArgumentType,
+			&Codes        INTEGER DEFAULT { 1 | 2 }
+		} WITH SYNTAX { [ARGUMENT &ArgumentType] CODE &code }
+
+		ERROR ::= CLASS { &errorCode INTEGER }
+		MY-ERROR ::= ERROR
+
+		get OPERATION ::= { ARGUMENT INTEGER CODE 1 }
+		Operations OPERATION ::= { get | put, ... }
+		Invoke ::= SEQUENCE {
+			code     OPERATION.&code ({Operations}),
+			argument OPERATION.&ArgumentType ({Operations}{@code})
+		}
+		Codes INTEGER ::= { 1 | 2 }
+		maxItems INTEGER ::= 10
+		END`)
+
+	op := m.Lookup("OPERATION")
+	assert.Equal(t, asn1.ClassAssignment, op.Kind)
+	require.Len(t, op.Class.Fields, 5)
+	kinds := []asn1.FieldKind{
+		asn1.TypeField,
+		asn1.FixedTypeValueField,
+		asn1.ObjectSetField,
+		asn1.VariableTypeValueField,
+		asn1.FixedTypeValueSetField,
+	}
+	for i, f := range op.Class.Fields {
+		assert.Equal(t, kinds[i], f.Kind, f.Name)
+	}
+	assert.True(t, op.Class.Lookup("&code").Unique)
+	assert.Equal(t, "&ArgumentType", op.Class.Lookup("&value").TypeField)
+	assert.Equal(t, "{ 1 | 2 }", op.Class.Lookup("&Codes").Default.Text)
+	assert.Equal(t, "[ARGUMENT &ArgumentType] CODE &code", op.Class.Syntax)
+
+	assert.Equal(t, asn1.ClassAssignment, m.Lookup("MY-ERROR").Kind)
+	assert.Equal(t, asn1.ObjectAssignment, m.Lookup("get").Kind)
+	assert.Equal(t, "{ ARGUMENT INTEGER CODE 1 }", m.Lookup("get").Value.Text)
+	assert.Equal(t, asn1.ObjectSetAssignment, m.Lookup("Operations").Kind)
+	assert.Equal(t, asn1.ValueSetAssignment, m.Lookup("Codes").Kind)
+	assert.Equal(t, asn1.ValueAssignment, m.Lookup("maxItems").Kind)
+
+	inv := m.Lookup("Invoke").Type
+	code := inv.Components[0].Type
+	assert.Equal(t, asn1.FieldType, code.Kind)
+	assert.Equal(t, "OPERATION", code.Name)
+	assert.Equal(t, "&code", code.Field)
+	arg := inv.Components[1].Type.Constraints[0]
+	assert.Equal(t, asn1.TableConstraint, arg.Kind)
+	assert.Equal(t, "Operations", arg.ObjectSet.Text)
+	assert.Equal(t, []string{"@code"}, arg.AtNotations)
+}
+
+func TestParameterisation(t *testing.T) {
+	m := parse(t, `M DEFINITIONS ::= BEGIN
+		Container {PROTOCOL-IES : IEsSetParam, INTEGER : ub} ::=
+			SEQUENCE (SIZE (0..ub)) OF Field {{IEsSetParam}}
+		Field {PROTOCOL-IES : IEsSetParam} ::= SEQUENCE {
+			id PROTOCOL-IES.&id ({IEsSetParam})
+		}
+		List ::= Container { {MyIEs}, 16 }
+		Wrapper {Type} ::= SEQUENCE { value Type }
+		END`)
+
+	c := m.Lookup("Container")
+	assert.Equal(t, asn1.TypeAssignment, c.Kind)
+	require.Len(t, c.Params, 2)
+	assert.Equal(t, "PROTOCOL-IES", c.Params[0].Governor.Name)
+	assert.Equal(t, "IEsSetParam", c.Params[0].Name)
+	assert.Equal(t, asn1.IntegerType, c.Params[1].Governor.Kind)
+	assert.Equal(t, "Field", c.Type.Elem.Name)
+	assert.Equal(t, "{IEsSetParam}", c.Type.Elem.Args[0].Text)
+
+	l := m.Lookup("List").Type
+	assert.Equal(t, "Container", l.Name)
+	if assert.Len(t, l.Args, 2) {
+		assert.Equal(t, "{MyIEs}", l.Args[0].Text)
+		assert.Equal(t, "16", l.Args[1].Text)
+	}
+
+	w := m.Lookup("Wrapper")
+	assert.Nil(t, w.Params[0].Governor)
+	assert.Equal(t, "Type", w.Params[0].Name)
+}
+
+func TestErrors(t *testing.T) {
+	mods, err := asn1.Parse("test.asn", []byte(`M DEFINITIONS ::= BEGIN
+A ::= SEQUENCE { a INTEGER,, }
+B ::= BOOLEAN
+END`))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "test.asn:2:28: identifier expected")
+	require.Len(t, mods, 1)
+	assert.Nil(t, mods[0].Lookup("A"))
+	assert.NotNil(t, mods[0].Lookup("B"))
+}
+
+func TestTTCN3Name(t *testing.T) {
+	assert.Equal(t, "S1AP_PDU", asn1.TTCN3Name("S1AP-PDU"))
+	assert.Equal(t, "record_", asn1.TTCN3Name("record"))
+	assert.Equal(t, "x", asn1.TTCN3Name("x"))
+}
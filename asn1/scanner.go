@@ -0,0 +1,266 @@
+package asn1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nokia/ntt/internal/loc"
+)
+
+// tokenKind classifies the lexical items of ASN.1 source.
+type tokenKind int
+
+const (
+	tEOF     tokenKind = iota
+	tIdent             // identifiers, references and reserved words
+	tField             // field references, like "&id" or "&Type"
+	tNumber            // 123
+	tReal              // 1.5, 2e10
+	tCString           // "abc"
+	tBString           // '0101'B
+	tHString           // 'CAFE'H
+	tSymbol            // ::=, {, .., ...
+)
+
+type token struct {
+	kind tokenKind
+	lit  string
+	pos  loc.Position
+	end  int // offset after the last byte of the token
+
+	// bol is true if the token is the first one in its line.
+	bol bool
+}
+
+func (t token) String() string {
+	if t.kind == tEOF {
+		return "end of file"
+	}
+	return fmt.Sprintf("%q", t.lit)
+}
+
+// scanner splits ASN.1 source into tokens. Comments and white space are
+// skipped.
+type scanner struct {
+	file string
+	src  []byte
+	off  int
+	line int
+	col  int
+	bol  bool
+	err  error
+}
+
+func newScanner(file string, src []byte) *scanner {
+	return &scanner{file: file, src: src, line: 1, col: 1, bol: true}
+}
+
+func (s *scanner) position() loc.Position {
+	return loc.Position{Filename: s.file, Offset: s.off, Line: s.line, Column: s.col}
+}
+
+func (s *scanner) peek(n int) byte {
+	if s.off+n < len(s.src) {
+		return s.src[s.off+n]
+	}
+	return 0
+}
+
+func (s *scanner) next() {
+	if s.off >= len(s.src) {
+		return
+	}
+	if s.src[s.off] == '\n' {
+		s.line++
+		s.col = 0
+		s.bol = true
+	}
+	s.off++
+	s.col++
+}
+
+func (s *scanner) error(pos loc.Position, msg string) {
+	if s.err == nil {
+		s.err = &Error{Pos: pos, Msg: msg}
+	}
+}
+
+// skip skips white space and comments. A comment starts with "--" and ends
+// with the next "--" or at the end of the line. Block comments "/* */" may be
+// nested.
+func (s *scanner) skip() {
+	for s.off < len(s.src) {
+		switch c := s.src[s.off]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\v' || c == '\f':
+			s.next()
+		case c == '-' && s.peek(1) == '-':
+			s.next()
+			s.next()
+			for s.off < len(s.src) && s.src[s.off] != '\n' {
+				if s.src[s.off] == '-' && s.peek(1) == '-' {
+					s.next()
+					s.next()
+					break
+				}
+				s.next()
+			}
+		case c == '/' && s.peek(1) == '*':
+			pos := s.position()
+			depth := 0
+			for s.off < len(s.src) {
+				if s.src[s.off] == '/' && s.peek(1) == '*' {
+					depth++
+					s.next()
+				} else if s.src[s.off] == '*' && s.peek(1) == '/' {
+					depth--
+					s.next()
+					if depth == 0 {
+						s.next()
+						break
+					}
+				}
+				s.next()
+			}
+			if depth > 0 {
+				s.error(pos, "comment not terminated")
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Scan returns the next token.
+func (s *scanner) scan() token {
+	s.skip()
+	tok := token{pos: s.position(), bol: s.bol}
+	s.bol = false
+
+	if s.off >= len(s.src) {
+		tok.end = s.off
+		return tok
+	}
+
+	switch c := s.src[s.off]; {
+	case isLetter(c):
+		tok.kind = tIdent
+		s.ident()
+	case c == '&' && isLetter(s.peek(1)):
+		tok.kind = tField
+		s.next()
+		s.ident()
+	case isDigit(c):
+		tok.kind = s.number()
+	case c == '"':
+		tok.kind = tCString
+		s.next()
+		for {
+			if s.off >= len(s.src) {
+				s.error(tok.pos, "string literal not terminated")
+				break
+			}
+			if s.src[s.off] == '"' {
+				s.next()
+				if s.peek(0) != '"' {
+					break
+				}
+			}
+			s.next()
+		}
+	case c == '\'':
+		s.next()
+		for s.off < len(s.src) && s.src[s.off] != '\'' {
+			s.next()
+		}
+		s.next()
+		switch s.peek(0) {
+		case 'B':
+			tok.kind = tBString
+		case 'H':
+			tok.kind = tHString
+		default:
+			s.error(tok.pos, "bstring or hstring expected")
+		}
+		s.next()
+	default:
+		tok.kind = tSymbol
+		for _, sym := range symbols {
+			if strings.HasPrefix(string(s.src[s.off:min(s.off+len(sym), len(s.src))]), sym) {
+				for range sym {
+					s.next()
+				}
+				break
+			}
+		}
+		if s.off == tok.pos.Offset {
+			s.error(tok.pos, fmt.Sprintf("unexpected character %q", c))
+			s.next()
+		}
+	}
+
+	tok.end = s.off
+	tok.lit = string(s.src[tok.pos.Offset:s.off])
+	return tok
+}
+
+// ident scans identifiers. Identifiers may contain hyphens, but not two
+// consecutive hyphens nor a trailing hyphen.
+func (s *scanner) ident() {
+	for s.off < len(s.src) {
+		c := s.src[s.off]
+		if c == '-' && s.peek(1) != '-' && (isLetter(s.peek(1)) || isDigit(s.peek(1))) {
+			s.next()
+			continue
+		}
+		if !isLetter(c) && !isDigit(c) {
+			return
+		}
+		s.next()
+	}
+}
+
+func (s *scanner) number() tokenKind {
+	kind := tNumber
+	for isDigit(s.peek(0)) {
+		s.next()
+	}
+	if s.peek(0) == '.' && isDigit(s.peek(1)) {
+		kind = tReal
+		s.next()
+		for isDigit(s.peek(0)) {
+			s.next()
+		}
+	}
+	if c := s.peek(0); c == 'e' || c == 'E' {
+		n := 1
+		if c := s.peek(1); c == '-' || c == '+' {
+			n++
+		}
+		if isDigit(s.peek(n)) {
+			kind = tReal
+			for i := 0; i < n; i++ {
+				s.next()
+			}
+			for isDigit(s.peek(0)) {
+				s.next()
+			}
+		}
+	}
+	return kind
+}
+
+// symbols lists multi-character symbols before single-character ones.
+var symbols = []string{
+	"::=", "...", "..", "[[", "]]",
+	"{", "}", "(", ")", "[", "]", ",", ";", ".", "|", "^", "@", "!", "<", ">", ":", "-", "=", "*", "+",
+}
+
+func isLetter(c byte) bool { return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' }
+func isDigit(c byte) bool  { return '0' <= c && c <= '9' }
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
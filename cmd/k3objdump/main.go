@@ -21,10 +21,12 @@ var (
 
 	Debug  bool
 	Legacy bool
+	JSON   bool
 )
 
 func main() {
 	rootCmd.PersistentFlags().BoolVarP(&Debug, "all", "a", false, "Verbose debug output.")
+	rootCmd.PersistentFlags().BoolVarP(&JSON, "json", "", false, "Output file information, TASM tables and symbols in JSON format.")
 	rootCmd.PersistentFlags().BoolVarP(&Legacy, "legacy", "", false, "Output format of old t3xf2text tool: T3xf offsets and instruction dumps are disabled. References are replaced with instruction line numbers.")
 	if err := rootCmd.Execute(); err != nil {
 		fatal(err)
@@ -41,6 +43,9 @@ func displayInfo(cmd *cobra.Command, args []string) error {
 	}
 
 	switch {
+	case JSON:
+		return p.PrintJSON()
+
 	case Debug:
 		p.printAddrs = true
 		p.printRaw = true
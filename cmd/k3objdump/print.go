@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/nokia/ntt/k3/t3xf"
 	"github.com/nokia/ntt/k3/t3xf/opcode"
@@ -16,6 +17,8 @@ type printer struct {
 	file *t3xf.File // T3XF file object
 
 	addrMap map[int]int
+	symbols map[int]string // Symbols of the T3XF section
+	labels  map[int]string // Symbols of the code currently printed
 	indent  int
 	pos     int
 
@@ -32,9 +35,10 @@ func NewPrinter(path string, w io.Writer) (*printer, error) {
 	}
 
 	return &printer{
-		name: path,
-		file: file,
-		w:    w,
+		name:    path,
+		file:    file,
+		w:       w,
+		symbols: file.Symbols(),
 	}, nil
 }
 
@@ -43,15 +47,25 @@ func (p *printer) Info() {
 }
 
 func (p *printer) Print() error {
-	if p.file.IsTASM() {
-		fmt.Fprintf(p.w, "%s: Warning: Output for TASM files is limited. Printing only T3XF section.\n", p.name)
+	p.labels = p.symbols
+	if err := p.printCode(p.file.Sections.T3XF); err != nil {
+		return err
 	}
 
-	s := t3xf.NewScanner(p.file.Sections.T3XF)
+	// t3xf2text does not know TASM tables.
+	if p.file.IsTASM() && !p.printLines {
+		p.printTables()
+	}
+	return nil
+}
+
+func (p *printer) printCode(b []byte) error {
+	s := t3xf.NewScanner(b)
 
 	// Build line map
+	p.addrMap = nil
 	if p.printLines {
-		p.addrMap = make(map[int]int, len(p.file.Sections.T3XF)/4)
+		p.addrMap = make(map[int]int, len(b)/4)
 		pos := 1
 		for s.Scan() {
 			p.addrMap[s.Offset()] = pos
@@ -61,6 +75,10 @@ func (p *printer) Print() error {
 	}
 
 	for s.Scan() {
+		if name, ok := p.labels[s.Offset()]; ok && !p.printLines {
+			fmt.Fprintf(p.w, "%*s<%s>:\n", p.indent*2, "", name)
+		}
+
 		switch op := s.Opcode(); op {
 		case opcode.SCAN, opcode.MARK:
 			p.printInstr(s, op.String())
@@ -74,13 +92,13 @@ func (p *printer) Print() error {
 			p.printInstr(s, "="+strconv.Itoa(s.Arg()))
 
 		case opcode.REF:
-			p.printInstr(s, "L"+strconv.Itoa(p.lookupAddr(s.Arg())))
+			p.printInstr(s, "L"+p.addr(s.Arg()))
 
 		case opcode.FROZEN_REF:
-			p.printInstr(s, "R"+strconv.Itoa(p.lookupAddr(s.Arg())))
+			p.printInstr(s, "R"+p.addr(s.Arg()))
 
 		case opcode.GOTO:
-			p.printInstr(s, "@"+strconv.Itoa(p.lookupAddr(s.Arg())))
+			p.printInstr(s, "@"+p.addr(s.Arg()))
 
 		case opcode.IDEF, opcode.IGET, opcode.IFIELD:
 			p.printInstr(s, op.String()+" "+strconv.Itoa(s.Arg()))
@@ -100,7 +118,13 @@ func (p *printer) Print() error {
 			p.printValue(s)
 
 		default:
-			p.printInstr(s, op.String())
+			// Print unusual arguments, like the NOP in the T3XF magic, to
+			// permit lossless assembly.
+			if arg := s.Arg(); arg != 0 && op != opcode.SCAN && op != opcode.BLOCK {
+				p.printInstr(s, op.String()+" "+strconv.Itoa(arg))
+			} else {
+				p.printInstr(s, op.String())
+			}
 		}
 		p.pos++
 	}
@@ -112,6 +136,16 @@ func (p *printer) Print() error {
 	return nil
 }
 
+// addr returns a printable address. If the address references a known symbol,
+// the symbol name is appended.
+func (p *printer) addr(addr int) string {
+	s := strconv.Itoa(p.lookupAddr(addr))
+	if name, ok := p.labels[addr]; ok && !p.printLines {
+		s += " <" + name + ">"
+	}
+	return s
+}
+
 func (p *printer) lookupAddr(addr int) int {
 	if p.addrMap != nil {
 		if i, ok := p.addrMap[addr]; ok {
@@ -153,24 +187,45 @@ func (p *printer) printValue(s *t3xf.Scanner) {
 	fmt.Fprintf(p.w, "%*s", p.indent*2, "")
 
 	switch s.Opcode() {
-	case opcode.ISTR, opcode.FSTR:
-		fmt.Fprintf(p.w, "%s\n", s.Bytes())
+	case opcode.ISTR:
+		fmt.Fprintf(p.w, "%qI\n", s.Bytes())
+	case opcode.FSTR:
+		fmt.Fprintf(p.w, "%qF\n", s.Bytes())
 	case opcode.UTF8:
-		fmt.Fprintf(p.w, "\"%s\"\n", s.Bytes())
+		fmt.Fprintf(p.w, "%q\n", s.Bytes())
 	case opcode.NAME:
 		fmt.Fprintf(p.w, "'%s'\n", s.Bytes())
 	case opcode.OCTETS:
 		fmt.Fprintf(p.w, "'%x'O\n", s.Bytes())
 	case opcode.NIBBLES:
-		fmt.Fprintf(p.w, "'%x'H\n", s.Bytes())
+		fmt.Fprintf(p.w, "'%s'H\n", fmt.Sprintf("%x", s.Bytes())[:s.Arg()/4])
 	case opcode.BITS:
-		fmt.Fprintf(p.w, "'%b'B\n", s.Bytes())
+		fmt.Fprintf(p.w, "'%s'B\n", bits(s.Bytes(), s.Arg()))
 
 	case opcode.IEEE754DP:
-		fmt.Fprintf(p.w, "%f\n", s.Float64())
+		fmt.Fprintf(p.w, "%s\n", float(s.Float64()))
 	case opcode.NATLONG:
 		fmt.Fprintf(p.w, "%d\n", s.Arg())
 	default:
 		fmt.Fprintf(p.w, "\n")
 	}
 }
+
+// bits returns the first n bits of b as string of '0' and '1'.
+func bits(b []byte, n int) string {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = '0' + (b[i/8]>>uint(7-i%8))&1
+	}
+	return string(s)
+}
+
+// float returns a lossless representation of f, which always contains a
+// decimal point or an exponent.
+func float(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/k3/t3xf"
+)
+
+// TestRoundTrip disassembles all test data files and verifies that assembling
+// the output results in the original file.
+//
+// The test data files are small synthetic files, not produced by k3c, because
+// k3c is not freely available. The round trip is therefore not verified
+// against code produced by k3c. Files produced by k3c can be copied into
+// testdata and are tested automatically.
+func TestRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("testdata/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test data files")
+	}
+
+	modes := map[string]func(p *printer){
+		"debug": func(p *printer) {
+			p.printAddrs = true
+			p.printRaw = true
+			p.printLiteralInstrs = true
+		},
+		"legacy": func(p *printer) {
+			p.printLines = true
+		},
+	}
+
+	for _, file := range files {
+		want, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for name, mode := range modes {
+			var text bytes.Buffer
+			p, err := NewPrinter(file, &text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mode(p)
+			if err := p.Print(); err != nil {
+				t.Errorf("%s: %s: %s", file, name, err.Error())
+				continue
+			}
+
+			// Legacy output has no TASM tables. Only the code
+			// section survives the round trip.
+			legacy := p.printLines && p.file.IsTASM()
+			if legacy && strings.Contains(text.String(), "Sections:") {
+				t.Errorf("%s: %s: unexpected TASM tables", file, name)
+			}
+
+			f, err := t3xf.Assemble(&text)
+			if err != nil {
+				t.Errorf("%s: %s: %s", file, name, err.Error())
+				continue
+			}
+
+			if legacy {
+				if !bytes.Equal(f.Sections.T3XF, p.file.Sections.T3XF) {
+					t.Errorf("%s: %s: round trip mismatch:\ngot  % x\nwant % x", file, name, f.Sections.T3XF, p.file.Sections.T3XF)
+				}
+				continue
+			}
+
+			var got bytes.Buffer
+			if _, err := f.WriteTo(&got); err != nil {
+				t.Errorf("%s: %s: %s", file, name, err.Error())
+				continue
+			}
+
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("%s: %s: round trip mismatch:\ngot  % x\nwant % x", file, name, got.Bytes(), want)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nokia/ntt/k3/t3xf"
+)
+
+// printTables prints all TASM tables. Fields referencing strings or modules are
+// annotated with their names. Blocks are disassembled.
+func (p *printer) printTables() {
+	fmt.Fprintf(p.w, "\nSections: T3XF=%d Text=%d Data=%d\n",
+		len(p.file.Sections.T3XF), len(p.file.Sections.Text), len(p.file.Sections.Data))
+
+	table := ""
+	for _, obj := range p.file.Objects() {
+		if obj.Table != table {
+			table = obj.Table
+			fmt.Fprintf(p.w, "\n%s:\n", table)
+		}
+
+		fmt.Fprintf(p.w, "%6d:", obj.Index)
+		if obj.Name != "" {
+			name := obj.Name
+			if obj.Module != "" {
+				name = obj.Module + "." + name
+			}
+			fmt.Fprintf(p.w, " %q", name)
+		}
+		fmt.Fprintf(p.w, " %s", p.fields(obj.Entry))
+		if c, ok := obj.Entry.(t3xf.Collection); ok {
+			if end := int(c.DataOffset + c.Length); end <= len(p.file.Sections.Data) {
+				fmt.Fprintf(p.w, " '%x'O", p.file.Sections.Data[c.DataOffset:end])
+			}
+		}
+		fmt.Fprintln(p.w)
+
+		if b, ok := obj.Entry.(t3xf.Block); ok {
+			p.labels = nil
+			p.indent = 4
+			if err := p.printCode(p.file.Block(uint32(obj.Index))); err != nil {
+				fmt.Fprintf(p.w, "        error decoding block at %d: %s\n", b.TextOffset, err.Error())
+			}
+			p.indent = 0
+		}
+	}
+}
+
+// fields returns a printable representation of all fields of entry.
+func (p *printer) fields(entry interface{}) string {
+	var ss []string
+	v := reflect.ValueOf(entry)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		val := v.Field(i).Interface()
+		s := fmt.Sprintf("%s=%v", name, val)
+		if strings.HasSuffix(name, "FileOffset") || name == "AssetOffset" {
+			if sym, ok := p.symbols[int(v.Field(i).Uint())]; ok {
+				s += fmt.Sprintf("<%s>", sym)
+			}
+		}
+		ss = append(ss, s)
+	}
+	return strings.Join(ss, " ")
+}
+
+// PrintJSON writes file information and all TASM tables in JSON format.
+func (p *printer) PrintJSON() error {
+	type object struct {
+		Index  int
+		Name   string `json:",omitempty"`
+		Module string `json:",omitempty"`
+		Offset *int   `json:",omitempty"`
+		Symbol string `json:",omitempty"`
+		Line   int    `json:",omitempty"`
+		Entry  interface{}
+	}
+
+	tables := make(map[string][]object)
+	for _, obj := range p.file.Objects() {
+		o := object{
+			Index:  obj.Index,
+			Name:   obj.Name,
+			Module: obj.Module,
+			Line:   obj.Line,
+			Entry:  obj.Entry,
+		}
+		if off := obj.Offset; off >= 0 {
+			o.Offset = &off
+			o.Symbol = p.symbols[off]
+		}
+		tables[obj.Table] = append(tables[obj.Table], o)
+	}
+
+	symbols := make(map[string]string, len(p.symbols))
+	for k, v := range p.symbols {
+		symbols[fmt.Sprint(k)] = v
+	}
+
+	b, err := json.MarshalIndent(struct {
+		File    string
+		Format  string
+		Size    map[string]int
+		Tables  map[string][]object
+		Symbols map[string]string
+	}{
+		File:   p.name,
+		Format: p.file.Info(),
+		Size: map[string]int{
+			"T3XF": len(p.file.Sections.T3XF),
+			"Text": len(p.file.Sections.Text),
+			"Data": len(p.file.Sections.Data),
+		},
+		Tables:  tables,
+		Symbols: symbols,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.w, string(b))
+	return err
+}
@@ -15,14 +15,21 @@ import (
 	"github.com/nokia/ntt/k3"
 	"github.com/spf13/cobra"
 
+	"github.com/nokia/ntt/internal/cmds/asm"
 	"github.com/nokia/ntt/internal/cmds/build"
+	"github.com/nokia/ntt/internal/cmds/codec"
+	"github.com/nokia/ntt/internal/cmds/deps"
+	"github.com/nokia/ntt/internal/cmds/doc"
 	"github.com/nokia/ntt/internal/cmds/dump"
 	"github.com/nokia/ntt/internal/cmds/langserver"
 	"github.com/nokia/ntt/internal/cmds/lint"
 	"github.com/nokia/ntt/internal/cmds/list"
 	"github.com/nokia/ntt/internal/cmds/locate_file"
+	"github.com/nokia/ntt/internal/cmds/manifest"
+	"github.com/nokia/ntt/internal/cmds/metrics"
 	"github.com/nokia/ntt/internal/cmds/report"
 	"github.com/nokia/ntt/internal/cmds/run"
+	"github.com/nokia/ntt/internal/cmds/serve"
 	"github.com/nokia/ntt/internal/cmds/tags"
 )
 
@@ -102,6 +109,13 @@ func init() {
 	rootCmd.AddCommand(tags.Command)
 	rootCmd.AddCommand(report.Command)
 	rootCmd.AddCommand(build.Command)
+	rootCmd.AddCommand(asm.Command)
+	rootCmd.AddCommand(doc.Command)
+	rootCmd.AddCommand(codec.Command)
+	rootCmd.AddCommand(serve.Command)
+	rootCmd.AddCommand(deps.Command)
+	rootCmd.AddCommand(manifest.Command)
+	rootCmd.AddCommand(metrics.Command)
 
 	useNokiaRunner := func() bool {
 		if s, ok := os.LookupEnv("K3_40_RUN_POLICY"); ok {
@@ -1,3 +1,9 @@
+// Command ttcn3c-gen-t3xf is the T3XF generator as external ttcn3c generator.
+// It is a thin wrapper around package k3/t3xf/gen, which ttcn3c also provides
+// built-in as generator t3xf.
+//
+// The generator parses the source files of the modules in the request and
+// responds with a single file, named by parameter output (default out.t3xf).
 package main
 
 import (
@@ -5,7 +11,9 @@ import (
 	"log"
 	"os"
 
+	"github.com/nokia/ntt/k3/t3xf/gen"
 	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/ttcn3"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -19,4 +27,40 @@ func main() {
 		log.Fatal(err)
 	}
 
+	b, err := proto.Marshal(generate(req))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := os.Stdout.Write(b); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(req *pb.GeneratorRequest) *pb.GeneratorResponse {
+	output := "out.t3xf"
+	if s, ok := req.Parameters["output"]; ok {
+		output = s
+	}
+	resp := &pb.GeneratorResponse{Parameters: map[string]string{"output": output}}
+
+	var (
+		trees []*ttcn3.Tree
+		seen  = make(map[string]bool)
+	)
+	for _, m := range req.Modules {
+		file := m.GetLocation().GetFile()
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+		trees = append(trees, ttcn3.ParseFile(file))
+	}
+
+	var t3xf bytes.Buffer
+	if err := gen.Generate(&t3xf, trees...); err != nil {
+		resp.Error = err.Error()
+		return resp
+	}
+	resp.Files = append(resp.Files, &pb.GeneratorResponse_File{Name: output, Content: t3xf.Bytes()})
+	return resp
 }
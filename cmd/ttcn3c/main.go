@@ -3,10 +3,17 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 
+	"github.com/nokia/ntt/internal/generator"
 	"github.com/nokia/ntt/internal/ntt"
+	"github.com/nokia/ntt/k3/t3xf/gen"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/doc"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/proto"
 )
@@ -15,14 +22,50 @@ var (
 	rootCmd = &cobra.Command{
 		Use:   "ttcn3c",
 		Short: "ttcn3c parses TTCN-3 files and generates output based on the options given",
-		RunE:  run,
+		Long: `ttcn3c parses TTCN-3 files and generates output based on the options given.
+
+Generators are selected by option -G. The t3xf generator is built-in and
+generates T3XF byte code for the core language subset. The doc generator is
+built-in and generates documentation pages (parameter format=markdown|html).
+For any other format ttcn3c runs the external generator ttcn3c-gen-<format>
+found in PATH.
+
+External generators read a protobuf encoded GeneratorRequest from stdin. The
+request describes the types, constants, templates, module parameters and
+behaviour signatures of all modules (see protobuf/module.proto). Parameters
+given with --param are passed along with the request.
+
+Generators write a protobuf encoded GeneratorResponse to stdout. ttcn3c writes
+the generated files into the directory given by --out and prints the
+diagnostics in the same format as ntt lint.
+`,
+		RunE:          run,
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 
 	format = "t3xf"
+	output = ""
+	outDir = "."
+	params = map[string]string{}
+
+	// builtins are generators compiled into ttcn3c.
+	builtins = map[string]func(w io.Writer, trees ...*ttcn3.Tree) error{
+		"t3xf": gen.Generate,
+	}
+
+	// responders are generators compiled into ttcn3c, which answer with
+	// a GeneratorResponse like external generators do.
+	responders = map[string]func(params map[string]string, trees ...*ttcn3.Tree) (*pb.GeneratorResponse, error){
+		"doc": doc.Generate,
+	}
 )
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&format, "generator", "G", "", "generator to use")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "write output of built-in generators to file instead of stdout")
+	rootCmd.PersistentFlags().StringVar(&outDir, "out", ".", "directory for generated files")
+	rootCmd.PersistentFlags().StringToStringVarP(&params, "param", "P", nil, "pass parameter key=value to the generator")
 	rootCmd.MarkPersistentFlagRequired("generator")
 }
 
@@ -38,33 +81,105 @@ func fatal(err error) {
 }
 
 func run(cmd *cobra.Command, args []string) error {
+	if generate, ok := builtins[format]; ok {
+		return runBuiltin(generate, args)
+	}
+	if generate, ok := responders[format]; ok {
+		trees, err := parseFiles(args)
+		if err != nil {
+			return err
+		}
+		resp, err := generate(params, trees...)
+		if err != nil {
+			return err
+		}
+		return finish(format, &pb.GeneratorRequest{Parameters: params}, resp)
+	}
 
 	name := fmt.Sprintf("ttcn3c-gen-%s", format)
-	generator, err := exec.LookPath(name)
+	plugin, err := exec.LookPath(name)
 	if err != nil {
-		fatal(fmt.Errorf("could not find generator %q", name))
+		return fmt.Errorf("could not find generator %q", name)
 	}
 
-	_, err = ntt.NewFromArgs(args...)
+	trees, err := parseFiles(args)
 	if err != nil {
-		fatal(err)
+		return err
 	}
 
-	req := request{}
-	out, err := proto.Marshal(&req.GeneratorRequest)
+	req, err := generator.NewRequest(trees...)
 	if err != nil {
-		fatal(err)
+		return err
 	}
+	req.Parameters = params
 
-	proc := exec.Command(generator)
-	proc.Stdin = bytes.NewBuffer(out)
-	proc.Stdout = os.Stdout
+	in, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	proc := exec.Command(plugin)
+	proc.Stdin = bytes.NewBuffer(in)
+	proc.Stdout = &out
 	proc.Stderr = os.Stderr
 
-	if err := proc.Start(); err != nil {
-		fatal(err)
+	if err := proc.Run(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
 	}
 
-	proc.Wait()
+	resp := &pb.GeneratorResponse{}
+	if err := proto.Unmarshal(out.Bytes(), resp); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", name, err)
+	}
+
+	return finish(name, req, resp)
+}
+
+// finish reports unused parameters and diagnostics and writes the generated
+// files.
+func finish(name string, req *pb.GeneratorRequest, resp *pb.GeneratorResponse) error {
+	for _, p := range generator.UnusedParameters(req, resp) {
+		fmt.Fprintf(os.Stderr, "%s: warning: parameter %q not used\n", name, p)
+	}
+	if err := generator.Finish(os.Stdout, outDir, resp); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
 	return nil
 }
+
+func runBuiltin(generate func(io.Writer, ...*ttcn3.Tree) error, args []string) error {
+	trees, err := parseFiles(args)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := generate(&b, trees...); err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(b.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(output, b.Bytes(), 0644)
+}
+
+// parseFiles parses all TTCN-3 files of the test suite specified by args.
+func parseFiles(args []string) ([]*ttcn3.Tree, error) {
+	suite, err := ntt.NewFromArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+	files, err := suite.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	trees := make([]*ttcn3.Tree, 0, len(files))
+	for _, file := range files {
+		trees = append(trees, ttcn3.ParseFile(file))
+	}
+	return trees, nil
+}
@@ -1,9 +0,0 @@
-package main
-
-import (
-	pb "github.com/nokia/ntt/protobuf"
-)
-
-type request struct {
-	pb.GeneratorRequest
-}
@@ -0,0 +1,352 @@
+// Package codec provides a registry of TTCN-3 codecs and a gRPC server
+// implementing the Codec service of package protobuf.
+//
+// Codecs are selected by the encode and variant attributes of the type to
+// encode or decode. The values exchanged with a codec are protobuf values,
+// which represent TTCN-3 values like this:
+//
+//	integer              int_value, or big_value for large numbers
+//	float                float_value
+//	boolean              bool_value
+//	verdicttype          verdict_value
+//	enumerated           string_value holding the enumerated name
+//	charstring           string_value
+//	bitstring, hexstring string_value holding the digits, like "0101"
+//	octetstring          byte_value
+//	record, set          composite_value with one value per field
+//	union                composite_value with one value per alternative
+//	record of, array     composite_value with one value per element
+//
+// Omitted optional fields and unselected union alternatives are represented
+// by values without kind.
+//
+// FromObject and ToObject convert between these values and the objects of
+// the TTCN-3 interpreter.
+package codec
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+
+	pb "github.com/nokia/ntt/protobuf"
+)
+
+// Codec encodes and decodes values of a type. Referenced types are resolved
+// using r.
+type Codec interface {
+	Encode(r Resolver, t *pb.Type, v *pb.Value) (*pb.BinaryString, error)
+
+	// Decode decodes b and returns the value and the number of bits
+	// consumed.
+	Decode(r Resolver, t *pb.Type, b *pb.BinaryString) (*pb.Value, int, error)
+}
+
+// Resolver resolves references to named types.
+type Resolver interface {
+	Resolve(t *pb.Type) (*pb.Type, error)
+
+	// Variants returns the variant attributes of the types from the resolved
+	// type of t up to t itself. Empty variants are omitted.
+	Variants(t *pb.Type) ([]string, error)
+}
+
+type key struct {
+	encode  string
+	variant string
+}
+
+// Registry is a set of codecs keyed by encode and variant attributes. It is
+// safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	codecs map[key]Codec
+	types  map[string]*pb.Type
+}
+
+// NewRegistry returns a new registry with the reference codecs JSON and RAW.
+func NewRegistry() *Registry {
+	r := &Registry{
+		codecs: make(map[key]Codec),
+		types:  make(map[string]*pb.Type),
+	}
+	r.Register("JSON", "", JSON{})
+	r.Register("RAW", "", RAW{})
+	return r
+}
+
+// Register registers codec c for types with the given encode and variant
+// attributes. A codec registered with an empty variant is used for all
+// variants without codec of their own.
+func (r *Registry) Register(encode string, variant string, c Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codecs[key{encode, variant}] = c
+}
+
+// AddModules makes the named types of mods available for resolving
+// references.
+func (r *Registry) AddModules(mods ...*pb.Module) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range mods {
+		for _, t := range m.Types {
+			r.types[m.Name+"."+t.Name] = t
+		}
+	}
+}
+
+// Resolve follows type references until a type definition is found.
+func (r *Registry) Resolve(t *pb.Type) (*pb.Type, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := 0; t != nil && t.GetReference() != ""; i++ {
+		name := t.GetReference()
+		if t = r.types[name]; t == nil || i > len(r.types) {
+			return nil, fmt.Errorf("unknown type %s", name)
+		}
+	}
+	if t == nil || t.Kind == nil {
+		return nil, fmt.Errorf("missing type")
+	}
+	return t, nil
+}
+
+// Variants returns the variant attributes of the types from the resolved type
+// of t up to t itself. Codecs apply them in this order, so that variants of
+// referencing types, like fields or aliases, take precedence.
+func (r *Registry) Variants(t *pb.Type) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var vs []string
+	for i := 0; t != nil; i++ {
+		if t.Variant != "" {
+			vs = append([]string{t.Variant}, vs...)
+		}
+		name := t.GetReference()
+		if name == "" {
+			break
+		}
+		if t = r.types[name]; t == nil || i > len(r.types) {
+			return nil, fmt.Errorf("unknown type %s", name)
+		}
+	}
+	return vs, nil
+}
+
+// Lookup returns the codec for type t. The encode and variant attributes of t
+// take precedence over the attributes of the type referenced by t.
+func (r *Registry) Lookup(t *pb.Type) (Codec, error) {
+	encode, variant := t.GetEncode(), t.GetVariant()
+	if encode == "" {
+		rt, err := r.Resolve(t)
+		if err != nil {
+			return nil, err
+		}
+		encode, variant = rt.Encode, rt.Variant
+	}
+	if encode == "" {
+		return nil, fmt.Errorf("type %s has no encode attribute", typeName(t))
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if c, ok := r.codecs[key{encode, variant}]; ok {
+		return c, nil
+	}
+	if c, ok := r.codecs[key{encode, ""}]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("no codec for encoding %q", encode)
+}
+
+// Encode encodes v using the codec registered for t.
+func (r *Registry) Encode(t *pb.Type, v *pb.Value) (*pb.BinaryString, error) {
+	c, err := r.Lookup(t)
+	if err != nil {
+		return nil, err
+	}
+	return c.Encode(r, t, v)
+}
+
+// Decode decodes b using the codec registered for t.
+func (r *Registry) Decode(t *pb.Type, b *pb.BinaryString) (*pb.Value, int, error) {
+	c, err := r.Lookup(t)
+	if err != nil {
+		return nil, 0, err
+	}
+	return c.Decode(r, t, b)
+}
+
+func typeName(t *pb.Type) string {
+	if ref := t.GetReference(); ref != "" {
+		return ref
+	}
+	if t.GetName() != "" {
+		return t.GetName()
+	}
+	return "<anonymous>"
+}
+
+// fields returns the fields of record, set, union and anytype types.
+func fields(t *pb.Type) ([]*pb.Field, bool) {
+	switch k := t.Kind.(type) {
+	case *pb.Type_Record:
+		return k.Record.Fields, true
+	case *pb.Type_Set:
+		return k.Set.Fields, true
+	case *pb.Type_Union:
+		return k.Union.Fields, true
+	case *pb.Type_Anytype:
+		return k.Anytype.Fields, true
+	}
+	return nil, false
+}
+
+// elements returns the element type of array, record of and set of types.
+func elements(t *pb.Type) (*pb.ListType, bool) {
+	switch k := t.Kind.(type) {
+	case *pb.Type_Array:
+		return k.Array, true
+	case *pb.Type_RecordOf:
+		return k.RecordOf, true
+	case *pb.Type_SetOf:
+		return k.SetOf, true
+	}
+	return nil, false
+}
+
+// isUnion reports whether only one field of t may be present.
+func isUnion(t *pb.Type) bool {
+	switch t.Kind.(type) {
+	case *pb.Type_Union, *pb.Type_Anytype:
+		return true
+	}
+	return false
+}
+
+// composite returns the values of a composite value, or an error if v is not
+// composite.
+func composite(t *pb.Type, v *pb.Value) ([]*pb.Value, error) {
+	c, ok := v.GetKind().(*pb.Value_CompositeValue)
+	if !ok {
+		return nil, fmt.Errorf("%s: composite value expected", typeName(t))
+	}
+	return c.CompositeValue.GetValues(), nil
+}
+
+// alternative returns the index of the selected union alternative.
+func alternative(t *pb.Type, vs []*pb.Value) (int, error) {
+	sel := -1
+	for i, v := range vs {
+		if v.GetKind() == nil {
+			continue
+		}
+		if sel >= 0 {
+			return -1, fmt.Errorf("%s: more than one alternative selected", typeName(t))
+		}
+		sel = i
+	}
+	if sel < 0 {
+		return -1, fmt.Errorf("%s: no alternative selected", typeName(t))
+	}
+	return sel, nil
+}
+
+// newComposite returns a composite value.
+func newComposite(vs []*pb.Value) *pb.Value {
+	return &pb.Value{Kind: &pb.Value_CompositeValue{CompositeValue: &pb.Composite{Values: vs}}}
+}
+
+// integer returns the integer of v.
+func integer(t *pb.Type, v *pb.Value) (*big.Int, error) {
+	switch k := v.GetKind().(type) {
+	case *pb.Value_IntValue:
+		return big.NewInt(int64(k.IntValue)), nil
+	case *pb.Value_BigValue:
+		if i, ok := new(big.Int).SetString(k.BigValue, 10); ok {
+			return i, nil
+		}
+		return nil, fmt.Errorf("%s: invalid integer %q", typeName(t), k.BigValue)
+	}
+	return nil, fmt.Errorf("%s: integer value expected", typeName(t))
+}
+
+// newInteger returns an integer value. Integers not fitting into 32 bits are
+// returned as big value.
+func newInteger(i *big.Int) *pb.Value {
+	if i.IsInt64() && i.Int64() >= math.MinInt32 && i.Int64() <= math.MaxInt32 {
+		return &pb.Value{Kind: &pb.Value_IntValue{IntValue: int32(i.Int64())}}
+	}
+	return &pb.Value{Kind: &pb.Value_BigValue{BigValue: i.String()}}
+}
+
+// enumValue returns the number of enumerated name.
+func enumValue(t *pb.Type, name string) (int64, error) {
+	for _, e := range t.GetEnumerated().GetValues() {
+		if e.Name == name {
+			return e.Value, nil
+		}
+	}
+	return 0, fmt.Errorf("%s: unknown enumerated value %q", typeName(t), name)
+}
+
+// enumName returns the name of enumerated number n.
+func enumName(t *pb.Type, n int64) (string, error) {
+	for _, e := range t.GetEnumerated().GetValues() {
+		if e.Value == n {
+			return e.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%s: unknown enumerated value %d", typeName(t), n)
+}
+
+// octets returns the bytes of b holding its bits.
+func octets(b *pb.BinaryString) []byte {
+	data := b.GetData()
+	if n := int(b.GetNbits()+7) / 8; b.GetNbits() > 0 && n < len(data) {
+		data = data[:n]
+	}
+	return data
+}
+
+// checkDigits verifies the digits of bitstring and hexstring values.
+func checkDigits(t *pb.Type, s string) error {
+	var digits string
+	switch t.Kind.(type) {
+	case *pb.Type_Bitstring:
+		digits = "01"
+	case *pb.Type_Hextstring:
+		digits = "0123456789ABCDEFabcdef"
+	default:
+		return nil
+	}
+	for _, c := range s {
+		if !strings.ContainsRune(digits, c) {
+			return fmt.Errorf("%s: invalid digit %q", typeName(t), c)
+		}
+	}
+	return nil
+}
+
+// fixedLength returns the length of t, if t has a length constraint with a
+// single value or if t is an array with constant bounds.
+func fixedLength(t *pb.Type) (int, bool) {
+	if l := t.GetConstraint().GetLength(); l != nil && !l.Infinite && l.Start == l.End {
+		return int(l.Start), true
+	}
+	list, ok := elements(t)
+	if !ok {
+		return 0, false
+	}
+	c := list.GetConstraint()
+	if l := c.GetLength(); l != nil && !l.Infinite && l.Start == l.End {
+		return int(l.Start), true
+	}
+	if _, ok := t.Kind.(*pb.Type_Array); ok && len(c.GetRanges()) == 1 && !c.Ranges[0].Infinite {
+		return int(c.Ranges[0].End - c.Ranges[0].Start + 1), true
+	}
+	return 0, false
+}
@@ -0,0 +1,243 @@
+package codec_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nokia/ntt/codec"
+	"github.com/nokia/ntt/internal/generator"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func newRegistry(t *testing.T, src string) *codec.Registry {
+	t.Helper()
+	req, err := generator.NewRequest(ttcn3.Parse(src))
+	require.Nil(t, err)
+	r := codec.NewRegistry()
+	r.AddModules(req.Modules...)
+	return r
+}
+
+func ref(name string) *pb.Type {
+	return &pb.Type{Kind: &pb.Type_Reference{Reference: name}}
+}
+
+func i(x int32) *pb.Value    { return &pb.Value{Kind: &pb.Value_IntValue{IntValue: x}} }
+func f(x float64) *pb.Value  { return &pb.Value{Kind: &pb.Value_FloatValue{FloatValue: x}} }
+func b(x bool) *pb.Value     { return &pb.Value{Kind: &pb.Value_BoolValue{BoolValue: x}} }
+func s(x string) *pb.Value   { return &pb.Value{Kind: &pb.Value_StringValue{StringValue: x}} }
+func o(x ...byte) *pb.Value  { return &pb.Value{Kind: &pb.Value_ByteValue{ByteValue: x}} }
+func big(x string) *pb.Value { return &pb.Value{Kind: &pb.Value_BigValue{BigValue: x}} }
+func omit() *pb.Value        { return &pb.Value{} }
+func c(vs ...*pb.Value) *pb.Value {
+	return &pb.Value{Kind: &pb.Value_CompositeValue{CompositeValue: &pb.Composite{Values: vs}}}
+}
+
+func TestLookup(t *testing.T) {
+	r := newRegistry(t, `
+		module M {
+			type integer I with { encode "JSON" }
+			type I J;
+			type integer K;
+			type integer L with { encode "XER" }
+			type integer V with { encode "RAW"; variant "FIELDLENGTH(16)" }
+		}`)
+
+	r.Register("RAW", "FIELDLENGTH(16)", codec.JSON{})
+
+	tests := []struct {
+		typ   string
+		codec codec.Codec
+		err   string
+	}{
+		{typ: "M.I", codec: codec.JSON{}},
+		{typ: "M.J", codec: codec.JSON{}},
+		{typ: "M.K", err: "type M.K has no encode attribute"},
+		{typ: "M.L", err: `no codec for encoding "XER"`},
+		{typ: "M.V", codec: codec.JSON{}},
+		{typ: "M.X", err: "unknown type M.X"},
+	}
+	for _, tt := range tests {
+		c, err := r.Lookup(ref(tt.typ))
+		if tt.err != "" {
+			if assert.NotNil(t, err, tt.typ) {
+				assert.Equal(t, tt.err, err.Error())
+			}
+			continue
+		}
+		assert.Nil(t, err, tt.typ)
+		assert.Equal(t, tt.codec, c, tt.typ)
+	}
+
+	// Attributes of the referencing type take precedence.
+	typ := ref("M.K")
+	typ.Encode = "RAW"
+	c, err := r.Lookup(typ)
+	assert.Nil(t, err)
+	assert.Equal(t, codec.RAW{}, c)
+}
+
+func TestJSON(t *testing.T) {
+	r := newRegistry(t, `
+		module M {
+			type record R {
+				integer  i,
+				float    f,
+				boolean  b optional,
+				E        e,
+				U        u,
+				L        l,
+				octetstring o,
+				bitstring bs
+			}
+			type enumerated E { red, green }
+			type union U { integer x, charstring y }
+			type record of integer L;
+		} with { encode "JSON" }`)
+
+	v := c(i(1), f(math.Inf(-1)), omit(), s("green"), c(omit(), s("ä\"")), c(i(1), big("12345678901234567890")), o(0xca, 0xfe), s("0101"))
+	out, err := r.Encode(ref("M.R"), v)
+	require.Nil(t, err)
+	want := `{"i":1,"f":"-infinity","e":"green","u":{"y":"ä\""},"l":[1,12345678901234567890],"o":"CAFE","bs":"0101"}`
+	assert.Equal(t, want, string(out.Data))
+	assert.Equal(t, int32(8*len(want)), out.Nbits)
+
+	got, n, err := r.Decode(ref("M.R"), out)
+	require.Nil(t, err)
+	assert.Equal(t, 8*len(want), n)
+	assert.True(t, proto.Equal(v, got), "got %v", got)
+}
+
+func TestJSONErrors(t *testing.T) {
+	r := newRegistry(t, `
+		module M {
+			type record R { integer a, integer b optional }
+			type enumerated E { red }
+			type bitstring B;
+		} with { encode "JSON" }`)
+
+	tests := []struct {
+		typ  string
+		data string
+		err  string
+	}{
+		{"M.R", `{"b":1}`, "R: missing field a"},
+		{"M.R", `{"a":1,"c":2}`, "R: unknown field c"},
+		{"M.R", `{"a":1.5}`, "<anonymous>: invalid integer 1.5"},
+		{"M.R", `{"a":1} 2`, "M.R: unexpected data after JSON value"},
+		{"M.R", `[]`, "R: JSON object expected"},
+		{"M.E", `"blue"`, `E: unknown enumerated value "blue"`},
+		{"M.B", `"012"`, `B: invalid digit '2'`},
+	}
+	for _, tt := range tests {
+		_, _, err := r.Decode(ref(tt.typ), &pb.BinaryString{Data: []byte(tt.data)})
+		if assert.NotNil(t, err, tt.data) {
+			assert.Equal(t, tt.err, err.Error())
+		}
+	}
+
+	_, err := r.Encode(ref("M.R"), c(omit(), i(1)))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "R: field a is not optional", err.Error())
+	}
+}
+
+func TestRAW(t *testing.T) {
+	r := newRegistry(t, `
+		module M {
+			type record R {
+				U8       a,
+				S4       b,
+				boolean  c,
+				bitstring d length(3),
+				E        e,
+				octetstring f length(2),
+				L        g,
+				U8       h optional
+			}
+			type integer U8;
+			type integer S4 with { variant "FIELDLENGTH(4), COMP(2scompl)" }
+			type enumerated E { x(1), y(2) }
+			type record of hexstring L;
+		} with { encode "RAW" }`)
+
+	v := c(i(255), i(-2), b(true), s("101"), s("y"), o(0xca, 0xfe), c(s("A")), omit())
+	out, err := r.Encode(ref("M.R"), v)
+	require.Nil(t, err)
+
+	// 11111111 1110 1 101 00000010 11001010 11111110 1010
+	assert.Equal(t, []byte{0xff, 0xed, 0x02, 0xca, 0xfe, 0xa0}, out.Data)
+	assert.Equal(t, int32(44), out.Nbits)
+
+	got, n, err := r.Decode(ref("M.R"), out)
+	require.Nil(t, err)
+	assert.Equal(t, 44, n)
+	assert.True(t, proto.Equal(v, got), "got %v", got)
+
+	_, err = r.Encode(ref("M.U8"), i(256))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "U8: 256 does not fit into 8 bits", err.Error())
+	}
+	_, _, err = r.Decode(ref("M.R"), &pb.BinaryString{Data: []byte{0xff}})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "S4: 4 bits expected, got 0", err.Error())
+	}
+}
+
+func TestRAWVariants(t *testing.T) {
+	r := newRegistry(t, `
+		module M {
+			type integer S8 with { variant "FIELDLENGTH(8), COMP(2scompl)" }
+			type S8 S4 with { variant "FIELDLENGTH(4)" }
+			type S4 A;
+		} with { encode "RAW" }`)
+
+	// Variants of aliases override the variants of the referenced type.
+	for _, name := range []string{"M.S4", "M.A"} {
+		out, err := r.Encode(ref(name), i(-2))
+		require.Nil(t, err)
+		assert.Equal(t, []byte{0xe0}, out.Data, name)
+		assert.Equal(t, int32(4), out.Nbits, name)
+	}
+
+	// And variants of fields override the variants of the field type.
+	field := ref("M.S4")
+	field.Variant = "FIELDLENGTH(16)"
+	out, err := r.Encode(field, i(-2))
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0xff, 0xfe}, out.Data)
+
+	v, n, err := r.Decode(field, out)
+	require.Nil(t, err)
+	assert.Equal(t, 16, n)
+	assert.True(t, proto.Equal(i(-2), v), "got %v", v)
+}
+
+func TestFormat(t *testing.T) {
+	r := newRegistry(t, `
+		module M {
+			type record R {
+				integer     i,
+				float       f,
+				boolean     b optional,
+				E           e,
+				U           u,
+				L           l,
+				bitstring   bs,
+				octetstring os,
+				charstring  cs
+			}
+			type enumerated E { red, green }
+			type union U { integer x, charstring y }
+			type record of integer L;
+		}`)
+
+	v := c(i(1), f(2), omit(), s("green"), c(omit(), s("y")), c(), s("01"), o(0xca, 0xfe), s(`a"b`))
+	got, err := codec.Format(r, ref("M.R"), v)
+	require.Nil(t, err)
+	assert.Equal(t, `{ i := 1, f := 2.0, b := omit, e := green, u := { y := "y" }, l := {}, bs := '01'B, os := 'CAFE'O, cs := "a""b" }`, got)
+}
@@ -0,0 +1,159 @@
+package codec
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	pb "github.com/nokia/ntt/protobuf"
+)
+
+// Format returns value v of type t in TTCN-3 value notation, for example
+// `{ a := 1, b := omit }` or `'0101'B`.
+func Format(r Resolver, t *pb.Type, v *pb.Value) (string, error) {
+	var b strings.Builder
+	if err := format(&b, r, t, v); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func format(w *strings.Builder, r Resolver, t *pb.Type, v *pb.Value) error {
+	if v.GetKind() == nil {
+		w.WriteString("omit")
+		return nil
+	}
+
+	t, err := r.Resolve(t)
+	if err != nil {
+		return err
+	}
+
+	if fs, ok := fields(t); ok {
+		vs, err := composite(t, v)
+		if err != nil {
+			return err
+		}
+		if len(vs) != len(fs) {
+			return fmt.Errorf("%s: %d values for %d fields", typeName(t), len(vs), len(fs))
+		}
+		sep := "{ "
+		for i, f := range fs {
+			if isUnion(t) && vs[i].GetKind() == nil {
+				continue
+			}
+			w.WriteString(sep)
+			w.WriteString(f.Name)
+			w.WriteString(" := ")
+			if err := format(w, r, f.Type, vs[i]); err != nil {
+				return err
+			}
+			sep = ", "
+		}
+		if sep == "{ " {
+			w.WriteString("{}")
+		} else {
+			w.WriteString(" }")
+		}
+		return nil
+	}
+
+	if l, ok := elements(t); ok {
+		vs, err := composite(t, v)
+		if err != nil {
+			return err
+		}
+		if len(vs) == 0 {
+			w.WriteString("{}")
+			return nil
+		}
+		w.WriteString("{ ")
+		for i, v := range vs {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			if err := format(w, r, l.Element, v); err != nil {
+				return err
+			}
+		}
+		w.WriteString(" }")
+		return nil
+	}
+
+	switch k := t.Kind.(type) {
+	case *pb.Type_Scalar:
+		switch x := v.Kind.(type) {
+		case *pb.Value_IntValue, *pb.Value_BigValue:
+			if k.Scalar == pb.ScalarType_INTEGER {
+				i, err := integer(t, v)
+				if err != nil {
+					return err
+				}
+				w.WriteString(i.String())
+				return nil
+			}
+		case *pb.Value_FloatValue:
+			if k.Scalar == pb.ScalarType_FLOAT {
+				w.WriteString(formatFloat(x.FloatValue))
+				return nil
+			}
+		case *pb.Value_BoolValue:
+			if k.Scalar == pb.ScalarType_BOOLEAN {
+				w.WriteString(strconv.FormatBool(x.BoolValue))
+				return nil
+			}
+		case *pb.Value_VerdictValue:
+			if k.Scalar == pb.ScalarType_VERDICT {
+				w.WriteString(strings.ToLower(x.VerdictValue.String()))
+				return nil
+			}
+		}
+
+	case *pb.Type_Octetstring:
+		if x, ok := v.Kind.(*pb.Value_ByteValue); ok {
+			fmt.Fprintf(w, "'%X'O", x.ByteValue)
+			return nil
+		}
+
+	case *pb.Type_Enumerated, *pb.Type_Charstring, *pb.Type_UniversalCharstring, *pb.Type_Bitstring, *pb.Type_Hextstring:
+		x, ok := v.Kind.(*pb.Value_StringValue)
+		if !ok {
+			break
+		}
+		if err := checkDigits(t, x.StringValue); err != nil {
+			return err
+		}
+		switch t.Kind.(type) {
+		case *pb.Type_Enumerated:
+			if _, err := enumValue(t, x.StringValue); err != nil {
+				return err
+			}
+			w.WriteString(x.StringValue)
+		case *pb.Type_Bitstring:
+			fmt.Fprintf(w, "'%s'B", x.StringValue)
+		case *pb.Type_Hextstring:
+			fmt.Fprintf(w, "'%s'H", x.StringValue)
+		default:
+			w.WriteString(`"` + strings.Replace(x.StringValue, `"`, `""`, -1) + `"`)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s: unexpected value %v", typeName(t), v)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "infinity"
+	case math.IsInf(f, -1):
+		return "-infinity"
+	case math.IsNaN(f):
+		return "not_a_number"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,342 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+
+	pb "github.com/nokia/ntt/protobuf"
+)
+
+// JSON is a reference codec for the TTCN-3 JSON encoding. Records, sets and
+// unions are encoded as objects, lists as arrays and octetstrings as strings
+// of hex digits. Special float values are encoded as the strings "infinity",
+// "-infinity" and "not_a_number".
+type JSON struct{}
+
+func (JSON) Encode(r Resolver, t *pb.Type, v *pb.Value) (*pb.BinaryString, error) {
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, r, t, v); err != nil {
+		return nil, err
+	}
+	return &pb.BinaryString{Data: buf.Bytes(), Nbits: int32(8 * buf.Len())}, nil
+}
+
+func (JSON) Decode(r Resolver, t *pb.Type, b *pb.BinaryString) (*pb.Value, int, error) {
+	data := octets(b)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		return nil, 0, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, 0, fmt.Errorf("%s: unexpected data after JSON value", typeName(t))
+	}
+
+	v, err := decodeJSON(r, t, x)
+	if err != nil {
+		return nil, 0, err
+	}
+	return v, 8 * len(data), nil
+}
+
+func encodeJSON(w *bytes.Buffer, r Resolver, t *pb.Type, v *pb.Value) error {
+	t, err := r.Resolve(t)
+	if err != nil {
+		return err
+	}
+
+	if fs, ok := fields(t); ok {
+		vs, err := composite(t, v)
+		if err != nil {
+			return err
+		}
+		if len(vs) != len(fs) {
+			return fmt.Errorf("%s: %d values for %d fields", typeName(t), len(vs), len(fs))
+		}
+		if isUnion(t) {
+			i, err := alternative(t, vs)
+			if err != nil {
+				return err
+			}
+			w.WriteByte('{')
+			writeJSON(w, fs[i].Name)
+			w.WriteByte(':')
+			if err := encodeJSON(w, r, fs[i].Type, vs[i]); err != nil {
+				return err
+			}
+			w.WriteByte('}')
+			return nil
+		}
+
+		w.WriteByte('{')
+		sep := ""
+		for i, f := range fs {
+			if vs[i].GetKind() == nil {
+				if !f.Optional {
+					return fmt.Errorf("%s: field %s is not optional", typeName(t), f.Name)
+				}
+				continue
+			}
+			w.WriteString(sep)
+			writeJSON(w, f.Name)
+			w.WriteByte(':')
+			if err := encodeJSON(w, r, f.Type, vs[i]); err != nil {
+				return err
+			}
+			sep = ","
+		}
+		w.WriteByte('}')
+		return nil
+	}
+
+	if l, ok := elements(t); ok {
+		vs, err := composite(t, v)
+		if err != nil {
+			return err
+		}
+		w.WriteByte('[')
+		for i, v := range vs {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			if err := encodeJSON(w, r, l.Element, v); err != nil {
+				return err
+			}
+		}
+		w.WriteByte(']')
+		return nil
+	}
+
+	switch k := t.Kind.(type) {
+	case *pb.Type_Scalar:
+		switch k.Scalar {
+		case pb.ScalarType_INTEGER:
+			i, err := integer(t, v)
+			if err != nil {
+				return err
+			}
+			w.WriteString(i.String())
+			return nil
+
+		case pb.ScalarType_FLOAT:
+			f, ok := v.GetKind().(*pb.Value_FloatValue)
+			if !ok {
+				return fmt.Errorf("%s: float value expected", typeName(t))
+			}
+			switch x := f.FloatValue; {
+			case math.IsInf(x, 1):
+				writeJSON(w, "infinity")
+			case math.IsInf(x, -1):
+				writeJSON(w, "-infinity")
+			case math.IsNaN(x):
+				writeJSON(w, "not_a_number")
+			default:
+				writeJSON(w, x)
+			}
+			return nil
+
+		case pb.ScalarType_BOOLEAN:
+			b, ok := v.GetKind().(*pb.Value_BoolValue)
+			if !ok {
+				return fmt.Errorf("%s: boolean value expected", typeName(t))
+			}
+			writeJSON(w, b.BoolValue)
+			return nil
+
+		case pb.ScalarType_VERDICT:
+			b, ok := v.GetKind().(*pb.Value_VerdictValue)
+			if !ok {
+				return fmt.Errorf("%s: verdict value expected", typeName(t))
+			}
+			writeJSON(w, strings.ToLower(b.VerdictValue.String()))
+			return nil
+
+		case pb.ScalarType_NULL:
+			w.WriteString("null")
+			return nil
+		}
+
+	case *pb.Type_Enumerated:
+		s, ok := v.GetKind().(*pb.Value_StringValue)
+		if !ok {
+			return fmt.Errorf("%s: enumerated value expected", typeName(t))
+		}
+		if _, err := enumValue(t, s.StringValue); err != nil {
+			return err
+		}
+		writeJSON(w, s.StringValue)
+		return nil
+
+	case *pb.Type_Charstring, *pb.Type_UniversalCharstring, *pb.Type_Bitstring, *pb.Type_Hextstring:
+		s, ok := v.GetKind().(*pb.Value_StringValue)
+		if !ok {
+			return fmt.Errorf("%s: string value expected", typeName(t))
+		}
+		writeJSON(w, s.StringValue)
+		return nil
+
+	case *pb.Type_Octetstring:
+		b, ok := v.GetKind().(*pb.Value_ByteValue)
+		if !ok {
+			return fmt.Errorf("%s: byte value expected", typeName(t))
+		}
+		writeJSON(w, strings.ToUpper(hex.EncodeToString(b.ByteValue)))
+		return nil
+	}
+
+	return fmt.Errorf("%s: type not supported by JSON codec", typeName(t))
+}
+
+// writeJSON writes the JSON encoding of a string, number or boolean.
+func writeJSON(w *bytes.Buffer, x interface{}) {
+	b, _ := json.Marshal(x)
+	w.Write(b)
+}
+
+func decodeJSON(r Resolver, t *pb.Type, x interface{}) (*pb.Value, error) {
+	t, err := r.Resolve(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs, ok := fields(t); ok {
+		obj, ok := x.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: JSON object expected", typeName(t))
+		}
+		if isUnion(t) && len(obj) != 1 {
+			return nil, fmt.Errorf("%s: JSON object with one member expected", typeName(t))
+		}
+		vs := make([]*pb.Value, len(fs))
+		for i, f := range fs {
+			x, ok := obj[f.Name]
+			if !ok {
+				if !isUnion(t) && !f.Optional {
+					return nil, fmt.Errorf("%s: missing field %s", typeName(t), f.Name)
+				}
+				vs[i] = &pb.Value{}
+				continue
+			}
+			if vs[i], err = decodeJSON(r, f.Type, x); err != nil {
+				return nil, err
+			}
+			delete(obj, f.Name)
+		}
+		for name := range obj {
+			return nil, fmt.Errorf("%s: unknown field %s", typeName(t), name)
+		}
+		return newComposite(vs), nil
+	}
+
+	if l, ok := elements(t); ok {
+		arr, ok := x.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: JSON array expected", typeName(t))
+		}
+		vs := make([]*pb.Value, len(arr))
+		for i, x := range arr {
+			if vs[i], err = decodeJSON(r, l.Element, x); err != nil {
+				return nil, err
+			}
+		}
+		return newComposite(vs), nil
+	}
+
+	switch k := t.Kind.(type) {
+	case *pb.Type_Scalar:
+		switch k.Scalar {
+		case pb.ScalarType_INTEGER:
+			n, ok := x.(json.Number)
+			if !ok {
+				return nil, fmt.Errorf("%s: JSON number expected", typeName(t))
+			}
+			i, ok := new(big.Int).SetString(n.String(), 10)
+			if !ok {
+				return nil, fmt.Errorf("%s: invalid integer %s", typeName(t), n)
+			}
+			return newInteger(i), nil
+
+		case pb.ScalarType_FLOAT:
+			var f float64
+			switch x {
+			case "infinity":
+				f = math.Inf(1)
+			case "-infinity":
+				f = math.Inf(-1)
+			case "not_a_number":
+				f = math.NaN()
+			default:
+				n, ok := x.(json.Number)
+				if !ok {
+					return nil, fmt.Errorf("%s: JSON number expected", typeName(t))
+				}
+				if f, err = n.Float64(); err != nil {
+					return nil, fmt.Errorf("%s: %w", typeName(t), err)
+				}
+			}
+			return &pb.Value{Kind: &pb.Value_FloatValue{FloatValue: f}}, nil
+
+		case pb.ScalarType_BOOLEAN:
+			b, ok := x.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%s: JSON boolean expected", typeName(t))
+			}
+			return &pb.Value{Kind: &pb.Value_BoolValue{BoolValue: b}}, nil
+
+		case pb.ScalarType_VERDICT:
+			s, _ := x.(string)
+			verdict, ok := pb.Verdict_value[strings.ToUpper(s)]
+			if !ok || s != strings.ToLower(s) {
+				return nil, fmt.Errorf("%s: invalid verdict %v", typeName(t), x)
+			}
+			return &pb.Value{Kind: &pb.Value_VerdictValue{VerdictValue: pb.Verdict(verdict)}}, nil
+
+		case pb.ScalarType_NULL:
+			if x != nil {
+				return nil, fmt.Errorf("%s: JSON null expected", typeName(t))
+			}
+			return &pb.Value{}, nil
+		}
+
+	case *pb.Type_Enumerated:
+		s, ok := x.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: JSON string expected", typeName(t))
+		}
+		if _, err := enumValue(t, s); err != nil {
+			return nil, err
+		}
+		return &pb.Value{Kind: &pb.Value_StringValue{StringValue: s}}, nil
+
+	case *pb.Type_Charstring, *pb.Type_UniversalCharstring, *pb.Type_Bitstring, *pb.Type_Hextstring:
+		s, ok := x.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: JSON string expected", typeName(t))
+		}
+		if err := checkDigits(t, s); err != nil {
+			return nil, err
+		}
+		return &pb.Value{Kind: &pb.Value_StringValue{StringValue: s}}, nil
+
+	case *pb.Type_Octetstring:
+		s, ok := x.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: JSON string expected", typeName(t))
+		}
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid octetstring %q", typeName(t), s)
+		}
+		return &pb.Value{Kind: &pb.Value_ByteValue{ByteValue: b}}, nil
+	}
+
+	return nil, fmt.Errorf("%s: type not supported by JSON codec", typeName(t))
+}
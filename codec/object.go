@@ -0,0 +1,252 @@
+package codec
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/runtime"
+)
+
+// FromObject converts the interpreter object obj of type t into a protobuf
+// value. A nil object is converted into a value without kind, which denotes
+// an omitted field.
+func FromObject(r Resolver, t *pb.Type, obj runtime.Object) (*pb.Value, error) {
+	if obj == nil {
+		return &pb.Value{}, nil
+	}
+
+	t, err := r.Resolve(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs, ok := fields(t); ok {
+		rec, ok := obj.(*runtime.Record)
+		if !ok {
+			return nil, objectError(t, obj)
+		}
+		vs := make([]*pb.Value, len(fs))
+		for i, f := range fs {
+			o, _ := rec.Get(f.Name)
+			if o == nil && !f.Optional && !isUnion(t) {
+				return nil, fmt.Errorf("%s: field %s is not optional", typeName(t), f.Name)
+			}
+			if vs[i], err = FromObject(r, f.Type, o); err != nil {
+				return nil, err
+			}
+		}
+		if isUnion(t) {
+			if _, err := alternative(t, vs); err != nil {
+				return nil, err
+			}
+		}
+		return newComposite(vs), nil
+	}
+
+	if l, ok := elements(t); ok {
+		list, ok := obj.(*runtime.List)
+		if !ok {
+			return nil, objectError(t, obj)
+		}
+		vs := make([]*pb.Value, len(list.Elements))
+		for i, o := range list.Elements {
+			if vs[i], err = FromObject(r, l.Element, o); err != nil {
+				return nil, err
+			}
+		}
+		return newComposite(vs), nil
+	}
+
+	switch k := t.Kind.(type) {
+	case *pb.Type_Scalar:
+		switch k.Scalar {
+		case pb.ScalarType_INTEGER:
+			if i, ok := obj.(runtime.Int); ok {
+				return newInteger(i.Int), nil
+			}
+		case pb.ScalarType_FLOAT:
+			if f, ok := obj.(runtime.Float); ok {
+				return &pb.Value{Kind: &pb.Value_FloatValue{FloatValue: float64(f)}}, nil
+			}
+		case pb.ScalarType_BOOLEAN:
+			if b, ok := obj.(runtime.Bool); ok {
+				return &pb.Value{Kind: &pb.Value_BoolValue{BoolValue: bool(b)}}, nil
+			}
+		case pb.ScalarType_VERDICT:
+			if v, ok := obj.(runtime.Verdict); ok {
+				if x, ok := pb.Verdict_value[strings.ToUpper(string(v))]; ok {
+					return &pb.Value{Kind: &pb.Value_VerdictValue{VerdictValue: pb.Verdict(x)}}, nil
+				}
+			}
+		}
+
+	case *pb.Type_Enumerated:
+		if s, ok := obj.(*runtime.String); ok {
+			if _, err := enumValue(t, s.Value); err != nil {
+				return nil, err
+			}
+			return &pb.Value{Kind: &pb.Value_StringValue{StringValue: s.Value}}, nil
+		}
+
+	case *pb.Type_Charstring, *pb.Type_UniversalCharstring:
+		if s, ok := obj.(*runtime.String); ok {
+			return &pb.Value{Kind: &pb.Value_StringValue{StringValue: s.Value}}, nil
+		}
+
+	case *pb.Type_Bitstring:
+		if b, ok := obj.(*runtime.Bitstring); ok && b.Unit == runtime.Bit {
+			return &pb.Value{Kind: &pb.Value_StringValue{StringValue: digits(b, 2)}}, nil
+		}
+
+	case *pb.Type_Hextstring:
+		if b, ok := obj.(*runtime.Bitstring); ok && b.Unit == runtime.Hex {
+			return &pb.Value{Kind: &pb.Value_StringValue{StringValue: digits(b, 16)}}, nil
+		}
+
+	case *pb.Type_Octetstring:
+		if b, ok := obj.(*runtime.Bitstring); ok && b.Unit == runtime.Octett {
+			n := (b.Length + 1) / 2
+			data := b.Value.Bytes()
+			if len(data) < n {
+				data = append(make([]byte, n-len(data)), data...)
+			}
+			return &pb.Value{Kind: &pb.Value_ByteValue{ByteValue: data}}, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("%s: type not supported", typeName(t))
+	}
+
+	return nil, objectError(t, obj)
+}
+
+// ToObject converts protobuf value v of type t into an interpreter object.
+// Values without kind are converted into nil objects.
+func ToObject(r Resolver, t *pb.Type, v *pb.Value) (runtime.Object, error) {
+	if v.GetKind() == nil {
+		return nil, nil
+	}
+
+	t, err := r.Resolve(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs, ok := fields(t); ok {
+		vs, err := composite(t, v)
+		if err != nil {
+			return nil, err
+		}
+		if len(vs) != len(fs) {
+			return nil, fmt.Errorf("%s: %d values for %d fields", typeName(t), len(vs), len(fs))
+		}
+		rec := runtime.NewRecord()
+		for i, f := range fs {
+			o, err := ToObject(r, f.Type, vs[i])
+			if err != nil {
+				return nil, err
+			}
+			if o != nil {
+				rec.Set(f.Name, o)
+			}
+		}
+		return rec, nil
+	}
+
+	if l, ok := elements(t); ok {
+		vs, err := composite(t, v)
+		if err != nil {
+			return nil, err
+		}
+		list := &runtime.List{Elements: make([]runtime.Object, len(vs))}
+		for i, v := range vs {
+			if list.Elements[i], err = ToObject(r, l.Element, v); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	}
+
+	switch k := t.Kind.(type) {
+	case *pb.Type_Scalar:
+		switch k.Scalar {
+		case pb.ScalarType_INTEGER:
+			i, err := integer(t, v)
+			if err != nil {
+				return nil, err
+			}
+			return runtime.Int{Int: i}, nil
+		case pb.ScalarType_FLOAT:
+			if f, ok := v.Kind.(*pb.Value_FloatValue); ok {
+				return runtime.Float(f.FloatValue), nil
+			}
+		case pb.ScalarType_BOOLEAN:
+			if b, ok := v.Kind.(*pb.Value_BoolValue); ok {
+				return runtime.Bool(b.BoolValue), nil
+			}
+		case pb.ScalarType_VERDICT:
+			if x, ok := v.Kind.(*pb.Value_VerdictValue); ok {
+				return runtime.Verdict(strings.ToLower(x.VerdictValue.String())), nil
+			}
+		}
+
+	case *pb.Type_Enumerated:
+		if s, ok := v.Kind.(*pb.Value_StringValue); ok {
+			if _, err := enumValue(t, s.StringValue); err != nil {
+				return nil, err
+			}
+			return &runtime.String{Value: s.StringValue}, nil
+		}
+
+	case *pb.Type_Charstring, *pb.Type_UniversalCharstring:
+		if s, ok := v.Kind.(*pb.Value_StringValue); ok {
+			return &runtime.String{Value: s.StringValue}, nil
+		}
+
+	case *pb.Type_Bitstring, *pb.Type_Hextstring:
+		if s, ok := v.Kind.(*pb.Value_StringValue); ok {
+			if err := checkDigits(t, s.StringValue); err != nil {
+				return nil, err
+			}
+			unit := runtime.Hex
+			if _, ok := t.Kind.(*pb.Type_Bitstring); ok {
+				unit = runtime.Bit
+			}
+			i := new(big.Int)
+			if s.StringValue != "" {
+				i.SetString(s.StringValue, unit.Base())
+			}
+			return &runtime.Bitstring{Value: i, Unit: unit, Length: len(s.StringValue)}, nil
+		}
+
+	case *pb.Type_Octetstring:
+		if b, ok := v.Kind.(*pb.Value_ByteValue); ok {
+			i := new(big.Int).SetBytes(b.ByteValue)
+			return &runtime.Bitstring{Value: i, Unit: runtime.Octett, Length: 2 * len(b.ByteValue)}, nil
+		}
+
+	default:
+		return nil, fmt.Errorf("%s: type not supported", typeName(t))
+	}
+
+	return nil, fmt.Errorf("%s: unexpected value %v", typeName(t), v)
+}
+
+func objectError(t *pb.Type, obj runtime.Object) error {
+	return fmt.Errorf("%s: unexpected %s %s", typeName(t), obj.Type(), obj.Inspect())
+}
+
+// digits returns the digits of b in the given base, padded with zeros to the
+// length of b.
+func digits(b *runtime.Bitstring, base int) string {
+	s := ""
+	if b.Value.Sign() != 0 {
+		s = strings.ToUpper(b.Value.Text(base))
+	}
+	if n := b.Length - len(s); n > 0 {
+		s = strings.Repeat("0", n) + s
+	}
+	return s
+}
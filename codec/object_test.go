@@ -0,0 +1,115 @@
+package codec_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nokia/ntt/codec"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestObjects(t *testing.T) {
+	r := newRegistry(t, `
+		module M {
+			type record R {
+				integer     i,
+				integer     big,
+				float       f,
+				boolean     b optional,
+				verdicttype v,
+				E           e,
+				U           u,
+				L           l,
+				bitstring   bs,
+				hexstring   hs,
+				octetstring os,
+				charstring  cs
+			}
+			type enumerated E { red, green }
+			type union U { integer x, charstring y }
+			type record of integer L;
+		}`)
+
+	bitstring := func(s string) runtime.Object {
+		b, err := runtime.NewBitstring(s)
+		require.Nil(t, err)
+		return b
+	}
+
+	u := runtime.NewRecord()
+	u.Set("y", &runtime.String{Value: "fnord"})
+	rec := runtime.NewRecord()
+	rec.Set("i", runtime.NewInt("-23"))
+	rec.Set("big", runtime.NewInt("12345678901234567890"))
+	rec.Set("f", runtime.Float(math.Inf(1)))
+	rec.Set("v", runtime.InconcVerdict)
+	rec.Set("e", &runtime.String{Value: "green"})
+	rec.Set("u", u)
+	rec.Set("l", &runtime.List{Elements: []runtime.Object{runtime.NewInt("1"), runtime.NewInt("2")}})
+	rec.Set("bs", bitstring("'0010'B"))
+	rec.Set("hs", bitstring("'00aF'H"))
+	rec.Set("os", bitstring("'00CAFE'O"))
+	rec.Set("cs", &runtime.String{Value: ""})
+
+	v, err := codec.FromObject(r, ref("M.R"), rec)
+	require.Nil(t, err)
+	want := c(
+		i(-23),
+		big("12345678901234567890"),
+		f(math.Inf(1)),
+		omit(),
+		&pb.Value{Kind: &pb.Value_VerdictValue{VerdictValue: pb.Verdict_INCONC}},
+		s("green"),
+		c(omit(), s("fnord")),
+		c(i(1), i(2)),
+		s("0010"),
+		s("00AF"),
+		o(0x00, 0xca, 0xfe),
+		s(""),
+	)
+	assert.True(t, proto.Equal(want, v), "got %v", v)
+
+	obj, err := codec.ToObject(r, ref("M.R"), v)
+	require.Nil(t, err)
+	assert.True(t, rec.Equal(obj), "got %s", obj.Inspect())
+
+	// Leading zeros are preserved.
+	v, err = codec.FromObject(r, ref("M.R"), obj)
+	require.Nil(t, err)
+	assert.True(t, proto.Equal(want, v), "got %v", v)
+}
+
+func TestObjectErrors(t *testing.T) {
+	r := newRegistry(t, `
+		module M {
+			type record R { integer a }
+			type union U { integer x, integer y }
+			type enumerated E { red }
+		}`)
+
+	tests := []struct {
+		typ string
+		obj runtime.Object
+		err string
+	}{
+		{"M.R", runtime.NewInt("1"), "R: unexpected integer 1"},
+		{"M.R", runtime.NewRecord(), "R: field a is not optional"},
+		{"M.U", runtime.NewRecord(), "U: no alternative selected"},
+		{"M.E", &runtime.String{Value: "blue"}, `E: unknown enumerated value "blue"`},
+	}
+	for _, tt := range tests {
+		_, err := codec.FromObject(r, ref(tt.typ), tt.obj)
+		if assert.NotNil(t, err, tt.typ) {
+			assert.Equal(t, tt.err, err.Error())
+		}
+	}
+
+	_, err := codec.ToObject(r, ref("M.R"), c(s("x")))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "<anonymous>: integer value expected", err.Error())
+	}
+}
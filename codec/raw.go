@@ -0,0 +1,513 @@
+package codec
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	pb "github.com/nokia/ntt/protobuf"
+)
+
+// RAW is a reference codec for plain binary encodings. Values are encoded
+// most significant bit first and without padding:
+//
+//	integer, enumerated  8 bits, unsigned
+//	float                64 bits IEEE 754
+//	boolean              1 bit
+//	verdicttype          8 bits
+//	strings              1, 4 or 8 bits per element
+//	record, set, lists   concatenation of their elements
+//	union                the selected alternative
+//
+// The variant attribute FIELDLENGTH(n) sets the number of bits of integer,
+// enumerated, float and boolean types and the number of elements of string
+// types. COMP(2scompl) encodes integers as two's complement. Strings and
+// lists without fixed length extend to the end of the data when decoded.
+// Optional fields are omitted when no data is left. Unions cannot be decoded.
+type RAW struct{}
+
+func (RAW) Encode(r Resolver, t *pb.Type, v *pb.Value) (*pb.BinaryString, error) {
+	var w bitWriter
+	if err := encodeRAW(&w, r, t, v); err != nil {
+		return nil, err
+	}
+	return &pb.BinaryString{Data: w.data, Nbits: int32(w.n)}, nil
+}
+
+func (RAW) Decode(r Resolver, t *pb.Type, b *pb.BinaryString) (*pb.Value, int, error) {
+	br := &bitReader{data: b.GetData(), end: 8 * len(b.GetData())}
+	if n := int(b.GetNbits()); n > 0 && n < br.end {
+		br.end = n
+	}
+	v, err := decodeRAW(br, r, t)
+	if err != nil {
+		return nil, 0, err
+	}
+	return v, br.pos, nil
+}
+
+type rawAttrs struct {
+	length int  // Value of FIELDLENGTH, or 0.
+	signed bool // Two's complement encoding.
+}
+
+// rawType resolves t and returns the RAW attributes of the variants along the
+// way. Attributes of referencing types, like fields or aliases, override the
+// attributes of the types they refer to.
+func rawType(r Resolver, t *pb.Type) (*pb.Type, rawAttrs, error) {
+	variants, err := r.Variants(t)
+	if err != nil {
+		return nil, rawAttrs{}, err
+	}
+	if t, err = r.Resolve(t); err != nil {
+		return nil, rawAttrs{}, err
+	}
+	var a rawAttrs
+	for _, v := range variants {
+		if err := a.parse(t, v); err != nil {
+			return nil, a, err
+		}
+	}
+	return t, a, nil
+}
+
+// parse parses variant attribute v of type t into a.
+func (a *rawAttrs) parse(t *pb.Type, v string) error {
+	for _, s := range strings.Split(v, ",") {
+		switch s = strings.TrimSpace(s); {
+		case s == "":
+		case strings.HasPrefix(s, "FIELDLENGTH(") && strings.HasSuffix(s, ")"):
+			n, err := strconv.Atoi(s[len("FIELDLENGTH(") : len(s)-1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("%s: invalid variant %q", typeName(t), s)
+			}
+			a.length = n
+		case s == "COMP(2scompl)":
+			a.signed = true
+		case s == "COMP(nosign)":
+			a.signed = false
+		default:
+			return fmt.Errorf("%s: unknown variant %q", typeName(t), s)
+		}
+	}
+	return nil
+}
+
+// elementWidth returns the number of bits per element of string types.
+func elementWidth(t *pb.Type) (int, bool) {
+	switch t.Kind.(type) {
+	case *pb.Type_Bitstring:
+		return 1, true
+	case *pb.Type_Hextstring:
+		return 4, true
+	case *pb.Type_Octetstring, *pb.Type_Charstring, *pb.Type_UniversalCharstring:
+		return 8, true
+	}
+	return 0, false
+}
+
+func encodeRAW(w *bitWriter, r Resolver, t *pb.Type, v *pb.Value) error {
+	t, a, err := rawType(r, t)
+	if err != nil {
+		return err
+	}
+
+	if fs, ok := fields(t); ok {
+		vs, err := composite(t, v)
+		if err != nil {
+			return err
+		}
+		if len(vs) != len(fs) {
+			return fmt.Errorf("%s: %d values for %d fields", typeName(t), len(vs), len(fs))
+		}
+		if isUnion(t) {
+			i, err := alternative(t, vs)
+			if err != nil {
+				return err
+			}
+			return encodeRAW(w, r, fs[i].Type, vs[i])
+		}
+		for i, f := range fs {
+			if vs[i].GetKind() == nil {
+				if !f.Optional {
+					return fmt.Errorf("%s: field %s is not optional", typeName(t), f.Name)
+				}
+				continue
+			}
+			if err := encodeRAW(w, r, f.Type, vs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if l, ok := elements(t); ok {
+		vs, err := composite(t, v)
+		if err != nil {
+			return err
+		}
+		if n, ok := fixedLength(t); ok && n != len(vs) {
+			return fmt.Errorf("%s: %d elements expected, got %d", typeName(t), n, len(vs))
+		}
+		for _, v := range vs {
+			if err := encodeRAW(w, r, l.Element, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if width, ok := elementWidth(t); ok {
+		digits, err := stringElements(t, v)
+		if err != nil {
+			return err
+		}
+		if n, ok := stringLength(t, a); ok && n != len(digits) {
+			return fmt.Errorf("%s: %d elements expected, got %d", typeName(t), n, len(digits))
+		}
+		for _, d := range digits {
+			w.writeUint(uint64(d), width)
+		}
+		return nil
+	}
+
+	switch k := t.Kind.(type) {
+	case *pb.Type_Scalar:
+		switch k.Scalar {
+		case pb.ScalarType_INTEGER:
+			i, err := integer(t, v)
+			if err != nil {
+				return err
+			}
+			return w.writeInt(t, i, a.width(8), a.signed)
+
+		case pb.ScalarType_FLOAT:
+			f, ok := v.GetKind().(*pb.Value_FloatValue)
+			if !ok {
+				return fmt.Errorf("%s: float value expected", typeName(t))
+			}
+			switch a.width(64) {
+			case 32:
+				w.writeUint(uint64(math.Float32bits(float32(f.FloatValue))), 32)
+			case 64:
+				w.writeUint(math.Float64bits(f.FloatValue), 64)
+			default:
+				return fmt.Errorf("%s: floats must have 32 or 64 bits", typeName(t))
+			}
+			return nil
+
+		case pb.ScalarType_BOOLEAN:
+			b, ok := v.GetKind().(*pb.Value_BoolValue)
+			if !ok {
+				return fmt.Errorf("%s: boolean value expected", typeName(t))
+			}
+			var x uint64
+			if b.BoolValue {
+				x = 1
+			}
+			w.writeUint(x, a.width(1))
+			return nil
+
+		case pb.ScalarType_VERDICT:
+			b, ok := v.GetKind().(*pb.Value_VerdictValue)
+			if !ok {
+				return fmt.Errorf("%s: verdict value expected", typeName(t))
+			}
+			w.writeUint(uint64(b.VerdictValue), 8)
+			return nil
+		}
+
+	case *pb.Type_Enumerated:
+		s, ok := v.GetKind().(*pb.Value_StringValue)
+		if !ok {
+			return fmt.Errorf("%s: enumerated value expected", typeName(t))
+		}
+		n, err := enumValue(t, s.StringValue)
+		if err != nil {
+			return err
+		}
+		return w.writeInt(t, big.NewInt(n), a.width(8), a.signed)
+	}
+
+	return fmt.Errorf("%s: type not supported by RAW codec", typeName(t))
+}
+
+func decodeRAW(br *bitReader, r Resolver, t *pb.Type) (*pb.Value, error) {
+	t, a, err := rawType(r, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs, ok := fields(t); ok {
+		if isUnion(t) {
+			return nil, fmt.Errorf("%s: RAW codec cannot decode unions", typeName(t))
+		}
+		vs := make([]*pb.Value, len(fs))
+		for i, f := range fs {
+			if f.Optional && br.remaining() == 0 {
+				vs[i] = &pb.Value{}
+				continue
+			}
+			if vs[i], err = decodeRAW(br, r, f.Type); err != nil {
+				return nil, err
+			}
+		}
+		return newComposite(vs), nil
+	}
+
+	if l, ok := elements(t); ok {
+		var vs []*pb.Value
+		n, fixed := fixedLength(t)
+		for i := 0; fixed && i < n || !fixed && br.remaining() > 0; i++ {
+			v, err := decodeRAW(br, r, l.Element)
+			if err != nil {
+				return nil, err
+			}
+			vs = append(vs, v)
+		}
+		return newComposite(vs), nil
+	}
+
+	if width, ok := elementWidth(t); ok {
+		n, ok := stringLength(t, a)
+		if !ok {
+			n = br.remaining() / width
+		}
+		digits := make([]byte, n)
+		for i := range digits {
+			d, err := br.readUint(t, width)
+			if err != nil {
+				return nil, err
+			}
+			digits[i] = byte(d)
+		}
+		return newString(t, digits)
+	}
+
+	switch k := t.Kind.(type) {
+	case *pb.Type_Scalar:
+		switch k.Scalar {
+		case pb.ScalarType_INTEGER:
+			i, err := br.readInt(t, a.width(8), a.signed)
+			if err != nil {
+				return nil, err
+			}
+			return newInteger(i), nil
+
+		case pb.ScalarType_FLOAT:
+			var f float64
+			switch a.width(64) {
+			case 32:
+				x, err := br.readUint(t, 32)
+				if err != nil {
+					return nil, err
+				}
+				f = float64(math.Float32frombits(uint32(x)))
+			case 64:
+				x, err := br.readUint(t, 64)
+				if err != nil {
+					return nil, err
+				}
+				f = math.Float64frombits(x)
+			default:
+				return nil, fmt.Errorf("%s: floats must have 32 or 64 bits", typeName(t))
+			}
+			return &pb.Value{Kind: &pb.Value_FloatValue{FloatValue: f}}, nil
+
+		case pb.ScalarType_BOOLEAN:
+			x, err := br.readUint(t, a.width(1))
+			if err != nil {
+				return nil, err
+			}
+			return &pb.Value{Kind: &pb.Value_BoolValue{BoolValue: x != 0}}, nil
+
+		case pb.ScalarType_VERDICT:
+			x, err := br.readUint(t, 8)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := pb.Verdict_name[int32(x)]; !ok {
+				return nil, fmt.Errorf("%s: invalid verdict %d", typeName(t), x)
+			}
+			return &pb.Value{Kind: &pb.Value_VerdictValue{VerdictValue: pb.Verdict(x)}}, nil
+		}
+
+	case *pb.Type_Enumerated:
+		i, err := br.readInt(t, a.width(8), a.signed)
+		if err != nil {
+			return nil, err
+		}
+		if !i.IsInt64() {
+			return nil, fmt.Errorf("%s: unknown enumerated value %s", typeName(t), i)
+		}
+		name, err := enumName(t, i.Int64())
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Value{Kind: &pb.Value_StringValue{StringValue: name}}, nil
+	}
+
+	return nil, fmt.Errorf("%s: type not supported by RAW codec", typeName(t))
+}
+
+func (a rawAttrs) width(def int) int {
+	if a.length > 0 {
+		return a.length
+	}
+	return def
+}
+
+// stringLength returns the number of elements of string type t, if fixed.
+func stringLength(t *pb.Type, a rawAttrs) (int, bool) {
+	if a.length > 0 {
+		return a.length, true
+	}
+	return fixedLength(t)
+}
+
+// stringElements returns the elements of string value v: bits, hex digits or
+// bytes.
+func stringElements(t *pb.Type, v *pb.Value) ([]byte, error) {
+	if _, ok := t.Kind.(*pb.Type_Octetstring); ok {
+		b, ok := v.GetKind().(*pb.Value_ByteValue)
+		if !ok {
+			return nil, fmt.Errorf("%s: byte value expected", typeName(t))
+		}
+		return b.ByteValue, nil
+	}
+
+	s, ok := v.GetKind().(*pb.Value_StringValue)
+	if !ok {
+		return nil, fmt.Errorf("%s: string value expected", typeName(t))
+	}
+	if err := checkDigits(t, s.StringValue); err != nil {
+		return nil, err
+	}
+	switch t.Kind.(type) {
+	case *pb.Type_Bitstring, *pb.Type_Hextstring:
+		digits := make([]byte, len(s.StringValue))
+		for i, c := range s.StringValue {
+			x, _ := strconv.ParseUint(string(c), 16, 8)
+			digits[i] = byte(x)
+		}
+		return digits, nil
+	case *pb.Type_Charstring:
+		for _, c := range s.StringValue {
+			if c >= utf8.RuneSelf {
+				return nil, fmt.Errorf("%s: invalid character %q", typeName(t), c)
+			}
+		}
+	}
+	return []byte(s.StringValue), nil
+}
+
+// newString returns the string value of elements digits.
+func newString(t *pb.Type, digits []byte) (*pb.Value, error) {
+	var s string
+	switch t.Kind.(type) {
+	case *pb.Type_Octetstring:
+		return &pb.Value{Kind: &pb.Value_ByteValue{ByteValue: digits}}, nil
+	case *pb.Type_Bitstring, *pb.Type_Hextstring:
+		var b strings.Builder
+		for _, d := range digits {
+			b.WriteString(strings.ToUpper(strconv.FormatUint(uint64(d), 16)))
+		}
+		s = b.String()
+	default:
+		s = string(digits)
+		if !utf8.ValidString(s) {
+			return nil, fmt.Errorf("%s: invalid UTF-8 string", typeName(t))
+		}
+	}
+	return &pb.Value{Kind: &pb.Value_StringValue{StringValue: s}}, nil
+}
+
+// bitWriter appends bits to a byte slice, most significant bit first.
+type bitWriter struct {
+	data []byte
+	n    int
+}
+
+func (w *bitWriter) writeBit(b uint) {
+	if w.n%8 == 0 {
+		w.data = append(w.data, 0)
+	}
+	if b != 0 {
+		w.data[w.n/8] |= 0x80 >> uint(w.n%8)
+	}
+	w.n++
+}
+
+func (w *bitWriter) writeUint(x uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.writeBit(uint(x>>uint(i)) & 1)
+	}
+}
+
+// writeInt writes i with n bits, using two's complement if signed is set.
+func (w *bitWriter) writeInt(t *pb.Type, i *big.Int, n int, signed bool) error {
+	min, max := big.NewInt(0), new(big.Int).Lsh(big.NewInt(1), uint(n))
+	if signed {
+		max.Rsh(max, 1)
+		min.Neg(max)
+	}
+	if i.Cmp(min) < 0 || i.Cmp(max) >= 0 {
+		return fmt.Errorf("%s: %s does not fit into %d bits", typeName(t), i, n)
+	}
+	x := i
+	if i.Sign() < 0 {
+		x = new(big.Int).Add(i, new(big.Int).Lsh(big.NewInt(1), uint(n)))
+	}
+	for j := n - 1; j >= 0; j-- {
+		w.writeBit(x.Bit(j))
+	}
+	return nil
+}
+
+// bitReader reads bits from a byte slice, most significant bit first.
+type bitReader struct {
+	data []byte
+	pos  int
+	end  int
+}
+
+func (r *bitReader) remaining() int {
+	return r.end - r.pos
+}
+
+func (r *bitReader) readBits(t *pb.Type, n int) (*big.Int, error) {
+	if n > r.remaining() {
+		return nil, fmt.Errorf("%s: %d bits expected, got %d", typeName(t), n, r.remaining())
+	}
+	x := new(big.Int)
+	for i := 0; i < n; i++ {
+		b := (r.data[r.pos/8] >> uint(7-r.pos%8)) & 1
+		x.Lsh(x, 1)
+		x.SetBit(x, 0, uint(b))
+		r.pos++
+	}
+	return x, nil
+}
+
+func (r *bitReader) readUint(t *pb.Type, n int) (uint64, error) {
+	x, err := r.readBits(t, n)
+	if err != nil {
+		return 0, err
+	}
+	return x.Uint64(), nil
+}
+
+// readInt reads an integer of n bits, using two's complement if signed is set.
+func (r *bitReader) readInt(t *pb.Type, n int, signed bool) (*big.Int, error) {
+	x, err := r.readBits(t, n)
+	if err != nil {
+		return nil, err
+	}
+	if signed && n > 0 && x.Bit(n-1) == 1 {
+		x.Sub(x, new(big.Int).Lsh(big.NewInt(1), uint(n)))
+	}
+	return x, nil
+}
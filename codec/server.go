@@ -0,0 +1,77 @@
+package codec
+
+import (
+	"context"
+	"net"
+	"os"
+
+	pb "github.com/nokia/ntt/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the gRPC Codec service using the codecs of a registry.
+type Server struct {
+	pb.UnimplementedCodecServer
+	Registry *Registry
+}
+
+// NewServer returns a new gRPC server with the Codec service registered.
+func NewServer(r *Registry) *grpc.Server {
+	s := grpc.NewServer()
+	pb.RegisterCodecServer(s, &Server{Registry: r})
+	return s
+}
+
+func (s *Server) Encode(ctx context.Context, req *pb.EncodeRequest) (*pb.BinaryString, error) {
+	c, err := s.Registry.Lookup(req.Type)
+	if err != nil {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	b, err := c.Encode(s.Registry, req.Type, req.Value)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return b, nil
+}
+
+func (s *Server) Decode(ctx context.Context, req *pb.DecodeRequest) (*pb.DecodeResponse, error) {
+	c, err := s.Registry.Lookup(req.Type)
+	if err != nil {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	v, n, err := c.Decode(s.Registry, req.Type, req.Bytes)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &pb.DecodeResponse{Nbits: int32(n), Value: v}, nil
+}
+
+// Listen listens on the Unix socket at path. A stale socket file is removed
+// first.
+func Listen(path string) (net.Listener, error) {
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if c, err := net.Dial("unix", path); err == nil {
+			c.Close()
+		} else {
+			os.Remove(path)
+		}
+	}
+	return net.Listen("unix", path)
+}
+
+// Dial connects to a codec server listening on the Unix socket at path.
+func Dial(ctx context.Context, path string) (pb.CodecClient, *grpc.ClientConn, error) {
+	conn, err := grpc.DialContext(ctx, path,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", addr)
+		}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pb.NewCodecClient(conn), conn, nil
+}
@@ -0,0 +1,57 @@
+package codec_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/codec"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "codec")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	r := newRegistry(t, `
+		module M {
+			type record R { integer a, charstring b }
+			type integer I with { encode "XER" }
+		} with { encode "JSON" }`)
+
+	socket := filepath.Join(dir, "codec.sock")
+	l, err := codec.Listen(socket)
+	require.Nil(t, err)
+	srv := codec.NewServer(r)
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	ctx := context.Background()
+	client, conn, err := codec.Dial(ctx, socket)
+	require.Nil(t, err)
+	defer conn.Close()
+
+	v := c(i(1), s("x"))
+	b, err := client.Encode(ctx, &pb.EncodeRequest{Type: ref("M.R"), Value: v})
+	require.Nil(t, err)
+	assert.Equal(t, `{"a":1,"b":"x"}`, string(b.Data))
+
+	resp, err := client.Decode(ctx, &pb.DecodeRequest{Type: ref("M.R"), Bytes: b})
+	require.Nil(t, err)
+	assert.Equal(t, b.Nbits, resp.Nbits)
+	assert.True(t, proto.Equal(v, resp.Value))
+
+	_, err = client.Encode(ctx, &pb.EncodeRequest{Type: ref("M.I"), Value: i(1)})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, err = client.Decode(ctx, &pb.DecodeRequest{Type: ref("M.R"), Bytes: &pb.BinaryString{Data: []byte("{}")}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Entry records how a build output was produced: the command line and the
+// content hashes of all inputs.
+type Entry struct {
+	Command string            `json:"command"`
+	Inputs  map[string]string `json:"inputs"`
+}
+
+// Hashes is a persistent store of build entries, keyed by output path. It is
+// used to decide whether an output is up to date by comparing content hashes
+// instead of modification times.
+type Hashes struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+// OpenHashes loads the store from file path. A missing file results in an
+// empty store.
+func OpenHashes(path string) (*Hashes, error) {
+	h := &Hashes{path: path, entries: make(map[string]*Entry)}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &h.entries); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Get returns the entry of output key or nil.
+func (h *Hashes) Get(key string) *Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.entries[key]
+}
+
+// Put stores the entry of output key.
+func (h *Hashes) Put(key string, e *Entry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries[key] = e
+}
+
+// Delete removes the entry of output key.
+func (h *Hashes) Delete(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.entries, key)
+}
+
+// Save writes the store back to its file. The file is replaced atomically.
+func (h *Hashes) Save() error {
+	h.mu.Lock()
+	b, err := json.MarshalIndent(h.entries, "", "  ")
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), h.path)
+}
+
+// HashFile returns the hex encoded SHA-256 sum of the content of file path.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
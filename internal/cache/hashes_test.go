@@ -0,0 +1,49 @@
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hashes")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "hashes.json")
+	h, err := cache.OpenHashes(path)
+	require.Nil(t, err)
+	assert.Nil(t, h.Get("a.out"))
+
+	e := &cache.Entry{Command: "cc -o a.out a.c", Inputs: map[string]string{"a.c": "1234"}}
+	h.Put("a.out", e)
+	require.Nil(t, h.Save())
+
+	h, err = cache.OpenHashes(path)
+	require.Nil(t, err)
+	assert.Equal(t, e, h.Get("a.out"))
+
+	h.Delete("a.out")
+	assert.Nil(t, h.Get("a.out"))
+}
+
+func TestHashFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hashes")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file")
+	require.Nil(t, ioutil.WriteFile(path, []byte("hello\n"), 0644))
+	sum, err := cache.HashFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03", sum)
+
+	_, err = cache.HashFile(filepath.Join(dir, "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,64 @@
+package asm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nokia/ntt/k3/t3xf"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "asm <file>",
+		Short: "assemble textual t3xf code into a T3XF or TASM file",
+		Long: `assemble textual t3xf code into a T3XF or TASM file.
+
+The asm command reads the output of 'k3objdump -a' or 'k3objdump --legacy' and
+writes the binary representation. If the input contains TASM tables, a TASM
+file is written. This is useful for patching or synthesising small T3XF
+fixtures without a TTCN-3 compiler.
+
+Example:
+
+	k3objdump -a suite.t3xf > suite.txt
+	vim suite.txt
+	ntt asm -o suite.t3xf suite.txt
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: assemble,
+	}
+
+	output = ""
+)
+
+func init() {
+	Command.Flags().StringVarP(&output, "output", "o", "", "write output to `file` (default: input file with .t3xf extension)")
+}
+
+func assemble(cmd *cobra.Command, args []string) error {
+	in, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	f, err := t3xf.Assemble(in)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		output = strings.TrimSuffix(args[0], filepath.Ext(args[0])) + ".t3xf"
+	}
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteTo(out); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
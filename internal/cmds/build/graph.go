@@ -0,0 +1,289 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/nokia/ntt/internal/cache"
+)
+
+// A Step produces output files from input files by running a command.
+type Step struct {
+	// Command is the expanded command line. It is part of the fingerprint of
+	// the step: changing compiler flags triggers a rebuild.
+	Command []string
+
+	// Inputs and Outputs of the step. The first output identifies the step
+	// in the hash store.
+	Inputs  []string
+	Outputs []string
+
+	// Deps are the steps which need to finish before this step starts,
+	// usually because they generate some of its inputs.
+	Deps []*Step
+
+	// Run executes the step. If nil, Command is executed.
+	Run func() error
+}
+
+// NewStep returns a step which runs command name with args. Variables like
+// "$CC" are expanded.
+func NewStep(inputs, outputs []string, name string, args ...string) *Step {
+	return &Step{
+		Command: expand(name, args...),
+		Inputs:  inputs,
+		Outputs: outputs,
+	}
+}
+
+func (s *Step) String() string {
+	return strings.Join(s.Command, " ")
+}
+
+func (s *Step) run() error {
+	if s.Run != nil {
+		return s.Run()
+	}
+	return command(s.Command).Run()
+}
+
+// Graph executes steps in dependency order. Independent steps run in
+// parallel. A step is skipped when its outputs exist and neither its command
+// line nor the content of its inputs changed since it was last run.
+type Graph struct {
+	Steps  []*Step
+	Hashes *cache.Hashes
+
+	// Jobs limits the number of steps running in parallel. Zero means the
+	// number of CPUs.
+	Jobs int
+
+	// DryRun prints the commands of outdated steps to Stdout instead of
+	// running them.
+	DryRun bool
+
+	// Explain, if not nil, receives the reason why a step is run or skipped.
+	Explain io.Writer
+
+	// Stdout receives the commands printed by DryRun.
+	Stdout io.Writer
+
+	mu sync.Mutex // serializes explanations
+}
+
+// Add adds steps to the graph and returns the last one.
+func (g *Graph) Add(steps ...*Step) *Step {
+	g.Steps = append(g.Steps, steps...)
+	return steps[len(steps)-1]
+}
+
+// state tracks the execution of a single step.
+type state struct {
+	done    chan struct{}
+	err     error
+	outdate bool // the step ran or, with DryRun, would have run
+}
+
+// Run executes all steps. Steps depending on failed steps are not run. The
+// hash store is saved, even if some steps failed. Dry runs are executed
+// sequentially to print commands in a stable order.
+func (g *Graph) Run() error {
+	jobs := g.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	if g.DryRun {
+		jobs = 1
+	}
+
+	// Order steps topologically, keeping the order they were added.
+	var order []*Step
+	states := make(map[*Step]*state)
+	var add func(s *Step)
+	add = func(s *Step) {
+		if _, ok := states[s]; ok {
+			return
+		}
+		states[s] = &state{done: make(chan struct{})}
+		for _, d := range s.Deps {
+			add(d)
+		}
+		order = append(order, s)
+	}
+	for _, s := range g.Steps {
+		add(s)
+	}
+
+	visit := func(s *Step) {
+		st := states[s]
+		defer close(st.done)
+
+		var changed []*Step
+		for _, d := range s.Deps {
+			ds := states[d]
+			<-ds.done
+			if ds.err != nil {
+				st.err = fmt.Errorf("%s: dependency %s failed", s.Outputs[0], d.Outputs[0])
+				return
+			}
+			if ds.outdate {
+				changed = append(changed, d)
+			}
+		}
+		st.outdate, st.err = g.execute(s, changed)
+	}
+
+	if jobs == 1 {
+		for _, s := range order {
+			visit(s)
+		}
+	} else {
+		sem := make(chan struct{}, jobs)
+		var wg sync.WaitGroup
+		for _, s := range order {
+			wg.Add(1)
+			go func(s *Step) {
+				defer wg.Done()
+				for _, d := range s.Deps {
+					<-states[d].done
+				}
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				visit(s)
+			}(s)
+		}
+		wg.Wait()
+	}
+
+	var errs *multierror.Error
+	for _, s := range order {
+		if err := states[s].err; err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	if g.Hashes != nil && !g.DryRun {
+		if err := g.Hashes.Save(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// execute runs step s if it is outdated. Changed lists dependencies of s
+// which ran or would have run. It returns true if s was (or would be) run.
+func (g *Graph) execute(s *Step, changed []*Step) (bool, error) {
+	key := s.Outputs[0]
+
+	// Inputs generated by dependencies do not exist during a dry run.
+	if g.DryRun && len(changed) > 0 {
+		g.explain("%s: dependency %s is outdated\n", key, changed[0].Outputs[0])
+		fmt.Fprintln(g.stdout(), s)
+		return true, nil
+	}
+
+	entry, reason := g.check(s)
+	if reason == "" {
+		g.explain("%s: up to date\n", key)
+		return false, nil
+	}
+	g.explain("%s: %s\n", key, reason)
+
+	if g.DryRun {
+		fmt.Fprintln(g.stdout(), s)
+		return true, nil
+	}
+
+	if err := s.run(); err != nil {
+		// Outputs of a failed step may be incomplete. Forget the
+		// previous build and remove them, so the next build does not
+		// consider them up to date.
+		if g.Hashes != nil {
+			g.Hashes.Delete(key)
+		}
+		for _, out := range s.Outputs {
+			os.Remove(out)
+		}
+		return true, fmt.Errorf("%s: %w", key, err)
+	}
+	if g.Hashes != nil && entry != nil {
+		g.Hashes.Put(key, entry)
+	}
+	return true, nil
+}
+
+// check compares the current state of s against the hash store. It returns
+// the new entry of s and the reason why s is outdated, or an empty reason if
+// s is up to date.
+func (g *Graph) check(s *Step) (*cache.Entry, string) {
+	entry := &cache.Entry{Command: s.String(), Inputs: make(map[string]string)}
+	for _, in := range s.Inputs {
+		sum, err := cache.HashFile(in)
+		if err != nil {
+			// Let the command report missing inputs.
+			return nil, fmt.Sprintf("input %q not readable", in)
+		}
+		entry.Inputs[in] = sum
+	}
+
+	var prev *cache.Entry
+	if g.Hashes != nil {
+		prev = g.Hashes.Get(s.Outputs[0])
+	}
+	switch {
+	case prev == nil:
+		return entry, "no previous build"
+	case prev.Command != entry.Command:
+		return entry, "command line changed"
+	}
+
+	for _, out := range s.Outputs {
+		if _, err := os.Stat(out); err != nil {
+			return entry, fmt.Sprintf("output %q missing", out)
+		}
+	}
+
+	var ins []string
+	for in := range entry.Inputs {
+		ins = append(ins, in)
+	}
+	for in := range prev.Inputs {
+		if _, ok := entry.Inputs[in]; !ok {
+			ins = append(ins, in)
+		}
+	}
+	sort.Strings(ins)
+	for _, in := range ins {
+		old, ok := prev.Inputs[in]
+		sum, present := entry.Inputs[in]
+		switch {
+		case !ok:
+			return entry, fmt.Sprintf("input %q added", in)
+		case !present:
+			return entry, fmt.Sprintf("input %q removed", in)
+		case old != sum:
+			return entry, fmt.Sprintf("input %q changed", in)
+		}
+	}
+	return entry, ""
+}
+
+func (g *Graph) explain(format string, v ...interface{}) {
+	if g.Explain != nil {
+		g.mu.Lock()
+		fmt.Fprintf(g.Explain, format, v...)
+		g.mu.Unlock()
+	}
+}
+
+func (g *Graph) stdout() io.Writer {
+	if g.Stdout != nil {
+		return g.Stdout
+	}
+	return os.Stdout
+}
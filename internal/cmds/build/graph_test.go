@@ -0,0 +1,203 @@
+package build
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nokia/ntt/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGraph provides a graph with a hash store in a temporary directory and
+// steps which record their execution.
+type testGraph struct {
+	*Graph
+	dir string
+
+	mu  sync.Mutex
+	ran []string
+}
+
+func newTestGraph(t *testing.T) *testGraph {
+	dir, err := ioutil.TempDir("", "graph")
+	require.Nil(t, err)
+
+	hashes, err := cache.OpenHashes(filepath.Join(dir, "hashes.json"))
+	require.Nil(t, err)
+	return &testGraph{Graph: &Graph{Hashes: hashes}, dir: dir}
+}
+
+func (g *testGraph) path(name string) string {
+	return filepath.Join(g.dir, name)
+}
+
+func (g *testGraph) write(t *testing.T, name string, content string) {
+	require.Nil(t, ioutil.WriteFile(g.path(name), []byte(content), 0644))
+}
+
+// step returns a step concatenating inputs into output.
+func (g *testGraph) step(output string, inputs ...string) *Step {
+	s := &Step{Command: append([]string{"cat"}, inputs...), Outputs: []string{g.path(output)}}
+	for _, in := range inputs {
+		s.Inputs = append(s.Inputs, g.path(in))
+	}
+	s.Run = func() error {
+		g.mu.Lock()
+		g.ran = append(g.ran, output)
+		g.mu.Unlock()
+		var b []byte
+		for _, in := range s.Inputs {
+			c, err := ioutil.ReadFile(in)
+			if err != nil {
+				return err
+			}
+			b = append(b, c...)
+		}
+		return ioutil.WriteFile(s.Outputs[0], b, 0644)
+	}
+	return s
+}
+
+func (g *testGraph) run(t *testing.T) []string {
+	g.ran = nil
+	require.Nil(t, g.Run())
+	return g.ran
+}
+
+func TestGraphUpToDate(t *testing.T) {
+	g := newTestGraph(t)
+	defer os.RemoveAll(g.dir)
+	g.write(t, "a.c", "a")
+	g.write(t, "b.c", "b")
+	a := g.Add(g.step("a.o", "a.c"))
+	b := g.Add(g.step("b.o", "b.c"))
+	lib := g.Add(g.step("lib.so", "a.o", "b.o"))
+	lib.Deps = []*Step{a, b}
+
+	assert.ElementsMatch(t, []string{"a.o", "b.o", "lib.so"}, g.run(t))
+	assert.Empty(t, g.run(t))
+
+	// Touching a file without changing its content does not trigger a rebuild.
+	g.write(t, "a.c", "a")
+	assert.Empty(t, g.run(t))
+
+	g.write(t, "b.c", "B")
+	assert.Equal(t, []string{"b.o", "lib.so"}, g.run(t))
+
+	os.Remove(g.path("lib.so"))
+	assert.Equal(t, []string{"lib.so"}, g.run(t))
+
+	a.Command = []string{"cat", "-v"}
+	assert.Equal(t, []string{"a.o"}, g.run(t))
+}
+
+func TestGraphOrder(t *testing.T) {
+	g := newTestGraph(t)
+	defer os.RemoveAll(g.dir)
+	g.write(t, "a.c", "a")
+	a := g.step("a.o", "a.c")
+	b := g.step("b.o", "a.o")
+	c := g.step("c.o", "b.o")
+	b.Deps = []*Step{a}
+	c.Deps = []*Step{b}
+
+	// Dependencies are run first, even if they are added last.
+	g.Add(c, b, a)
+	assert.Equal(t, []string{"a.o", "b.o", "c.o"}, g.run(t))
+}
+
+func TestGraphFailure(t *testing.T) {
+	g := newTestGraph(t)
+	defer os.RemoveAll(g.dir)
+	g.write(t, "a.c", "a")
+	g.write(t, "b.c", "b")
+	a := g.Add(g.step("a.o", "a.c"))
+	a.Run = func() error { return errors.New("failed") }
+	b := g.Add(g.step("b.o", "b.c"))
+	lib := g.Add(g.step("lib.so", "a.o", "b.o"))
+	lib.Deps = []*Step{a, b}
+
+	err := g.Run()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "a.o: failed")
+	assert.Contains(t, err.Error(), "dependency "+g.path("a.o")+" failed")
+
+	// Only successful steps are recorded.
+	hashes, err := cache.OpenHashes(filepath.Join(g.dir, "hashes.json"))
+	require.Nil(t, err)
+	assert.NotNil(t, hashes.Get(g.path("b.o")))
+	assert.Nil(t, hashes.Get(g.path("a.o")))
+
+	// A failing rebuild removes the previous entry and the outputs.
+	a.Run = g.step("a.o", "a.c").Run
+	require.Nil(t, g.Run())
+	g.write(t, "a.c", "A")
+	a.Run = func() error {
+		g.write(t, "a.o", "partial")
+		return errors.New("failed")
+	}
+	require.NotNil(t, g.Run())
+	hashes, err = cache.OpenHashes(filepath.Join(g.dir, "hashes.json"))
+	require.Nil(t, err)
+	assert.Nil(t, hashes.Get(g.path("a.o")))
+	_, err = os.Stat(g.path("a.o"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGraphDryRun(t *testing.T) {
+	g := newTestGraph(t)
+	defer os.RemoveAll(g.dir)
+	g.write(t, "a.c", "a")
+	g.write(t, "b.c", "b")
+	a := g.Add(g.step("a.o", "a.c"))
+	b := g.Add(g.step("b.o", "b.c"))
+	lib := g.Add(g.step("lib.so", "a.o", "b.o"))
+	lib.Deps = []*Step{a, b}
+	g.run(t)
+
+	var stdout, explain bytes.Buffer
+	g.DryRun = true
+	g.Stdout = &stdout
+	g.Explain = &explain
+	g.write(t, "a.c", "A")
+
+	assert.Empty(t, g.run(t))
+	assert.Equal(t, "cat a.c\ncat a.o b.o\n", stdout.String())
+	assert.Equal(t, g.path("a.o")+": input \""+g.path("a.c")+"\" changed\n"+
+		g.path("b.o")+": up to date\n"+
+		g.path("lib.so")+": dependency "+g.path("a.o")+" is outdated\n", explain.String())
+}
+
+func TestGraphExplain(t *testing.T) {
+	g := newTestGraph(t)
+	defer os.RemoveAll(g.dir)
+	g.write(t, "a.c", "a")
+	g.write(t, "b.c", "b")
+	a := g.Add(g.step("a.o", "a.c"))
+
+	var explain bytes.Buffer
+	g.Explain = &explain
+	reason := func() string {
+		explain.Reset()
+		g.run(t)
+		return explain.String()
+	}
+
+	assert.Equal(t, g.path("a.o")+": no previous build\n", reason())
+	assert.Equal(t, g.path("a.o")+": up to date\n", reason())
+
+	a.Inputs = append(a.Inputs, g.path("b.c"))
+	assert.Equal(t, g.path("a.o")+": input \""+g.path("b.c")+"\" added\n", reason())
+
+	a.Inputs = a.Inputs[:1]
+	assert.Equal(t, g.path("a.o")+": input \""+g.path("b.c")+"\" removed\n", reason())
+
+	os.Remove(g.path("a.o"))
+	assert.Equal(t, g.path("a.o")+": output \""+g.path("a.o")+"\" missing\n", reason())
+}
@@ -7,7 +7,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/magefile/mage/target"
 	"github.com/nokia/ntt/internal/cache"
 	"github.com/nokia/ntt/internal/fs"
 	"github.com/nokia/ntt/internal/log"
@@ -21,6 +20,11 @@ var (
 	Command = &cobra.Command{
 		Use:   "build",
 		Short: "Builds compiles TTCN-3 source and imports specified by the import paths.",
+		Long: `Builds compiles TTCN-3 source and imports specified by the import paths.
+
+Build steps are only run if their outputs are missing, or if the content of
+their inputs or their command line changed since the last build. Content hashes
+are stored in the cache directory. Independent steps run in parallel.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			suite, err := ntt.NewFromArgs(args...)
 			if err != nil {
@@ -45,9 +49,29 @@ var (
 		"ASN1CFLAGS": "-reservedWords ffs -c -charIntegers -listingFile -messageFormat emacs -noDefines -valuerefs -debug -root -soed",
 		"ASN2TTCN":   "asn1tottcn3",
 	}
+
+	dryRun  = false
+	explain = false
+	jobs    = 0
 )
 
+func init() {
+	Command.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "print the commands that would be executed, but do not execute them")
+	Command.Flags().BoolVarP(&explain, "explain", "", false, "explain why each build step is run or skipped")
+	Command.Flags().IntVarP(&jobs, "jobs", "j", 0, "number of build steps to run in parallel (default number of CPUs)")
+}
+
 func build(name string, p project.Interface) error {
+	hashes, err := cache.OpenHashes(Outf("%s.hashes.json", name))
+	if err != nil {
+		return err
+	}
+
+	g := &Graph{Hashes: hashes, Jobs: jobs, DryRun: dryRun}
+	if explain {
+		g.Explain = os.Stderr
+	}
+
 	srcs, err := p.Sources()
 	if err != nil {
 		return err
@@ -57,24 +81,24 @@ func build(name string, p project.Interface) error {
 	if err != nil {
 		return err
 	}
+	var deps []*Step
 	for _, dir := range imports {
-		files, err := buildImport(dir)
+		files, steps, err := buildImport(g, dir)
 		if err != nil {
 			return err
 		}
 		srcs = append(srcs, files...)
+		deps = append(deps, steps...)
 	}
 
-	return buildTTCN3(name, srcs...)
+	s := buildTTCN3(name, srcs...)
+	s.Deps = deps
+	g.Add(s)
+	return g.Run()
 }
 
-func buildTTCN3(name string, srcs ...string) error {
+func buildTTCN3(name string, srcs ...string) *Step {
 	out := Outf("%s.t3xf", name)
-	b, err := target.Path(out, srcs...)
-	if !b || err != nil {
-		return err
-	}
-
 	args := []string{"-o", out}
 	if env := Env("K3CFLAGS"); env != nil {
 		args = append(args, env...)
@@ -92,17 +116,20 @@ func buildTTCN3(name string, srcs ...string) error {
 		args = append(args, "-I", dir)
 	}
 
-	return Exec("$K3C", args...)
+	return NewStep(srcs, []string{out}, "$K3C", args...)
 }
 
-func buildImport(dir string) ([]string, error) {
+// buildImport adds the steps required to build import directory dir to graph
+// g. It returns the TTCN-3 files of dir and the steps generating them.
+func buildImport(g *Graph, dir string) ([]string, []*Step, error) {
 	files, err := os.ReadDir(dir)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var (
 		asn1Files, ttcn3Files, cFiles []string
+		steps                         []*Step
 		processed                     int
 	)
 
@@ -124,53 +151,27 @@ func buildImport(dir string) ([]string, error) {
 	}
 
 	if processed == 0 {
-		return nil, fmt.Errorf("%s: %w", dir, ErrNoSources)
+		return nil, nil, fmt.Errorf("%s: %w", dir, ErrNoSources)
 	}
 
 	name := fs.Slugify(fs.Stem(dir))
 	if len(asn1Files) > 0 {
-		codec, err := asn1Generate(name, asn1Files...)
-		if err != nil {
-			return nil, err
-		}
-
-		lib, err := asn1Build(name, codec...)
-		if err != nil {
-			return nil, err
-		}
-
-		mods, err := asn1Modules(name, lib...)
-		if err != nil {
-			return nil, err
-		}
-
-		ttcn3Files = append(ttcn3Files, mods...)
+		codec := g.Add(asn1Generate(name, asn1Files...))
+		lib := g.Add(asn1Build(name, codec))
+		mod := g.Add(asn1Modules(name, lib))
+		ttcn3Files = append(ttcn3Files, mod.Outputs...)
+		steps = append(steps, mod)
 	}
 
 	if len(cFiles) > 0 {
-		out, err := buildAdapter(name, cFiles...)
-		if err != nil {
-			return nil, err
-		}
-		for _, f := range out {
-			if fs.HasTTCN3Extension(f) {
-				ttcn3Files = append(ttcn3Files, f)
-			}
-
-		}
+		g.Add(buildAdapter(name, cFiles...))
 	}
-	return ttcn3Files, nil
+	return ttcn3Files, steps, nil
 }
 
-func asn1Generate(name string, srcs ...string) ([]string, error) {
+func asn1Generate(name string, srcs ...string) *Step {
 	c := Outf("%s.enc.c", name)
 	h := Outf("%s.enc.h", name)
-	if ok, err := target.Path(c, srcs...); !ok || err != nil {
-		return []string{c, h}, err
-	}
-	if ok, err := target.Path(h, srcs...); !ok || err != nil {
-		return []string{c, h}, err
-	}
 
 	var args []string
 	args = append(args, fmt.Sprintf("-%s", Encoding(name)))
@@ -178,15 +179,11 @@ func asn1Generate(name string, srcs ...string) ([]string, error) {
 	args = append(args, "-output", strings.TrimSuffix(c, ".c"), "-prefix", strings.TrimSuffix(c, ".enc.c"))
 	args = append(args, "$OSSINFO/asn1dflt.linux-x86_64")
 	args = append(args, srcs...)
-	return []string{c, h}, Exec("$ASN1C", args...)
-
+	return NewStep(srcs, []string{c, h}, "$ASN1C", args...)
 }
 
-func asn1Build(name string, srcs ...string) ([]string, error) {
+func asn1Build(name string, codec *Step) *Step {
 	out := Outf("%slib.so", name)
-	if b, err := target.Path(out, srcs...); !b || err != nil {
-		return []string{out}, err
-	}
 	var args []string
 	args = append(args, "-fPIC", "-shared")
 	args = append(args, "-D_OSSGETHEADER", "-DOSSPRINT")
@@ -200,23 +197,24 @@ func asn1Build(name string, srcs ...string) ([]string, error) {
 		args = append(args, env...)
 	}
 	args = append(args, "-l:libasn1code.a", "-Wl,-Bdynamic", "-o", out)
-	args = append(args, srcs...)
-	return []string{out}, Exec("$CC", args...)
+	args = append(args, codec.Outputs...)
+	s := NewStep(codec.Outputs, []string{out}, "$CC", args...)
+	s.Deps = []*Step{codec}
+	return s
 }
 
-func asn1Modules(name string, srcs ...string) ([]string, error) {
+func asn1Modules(name string, lib *Step) *Step {
 	out := Outf("%smod.ttcn3", name)
 	args := []string{"-o", out}
-	args = append(args, srcs...)
+	args = append(args, lib.Outputs...)
 	args = append(args, fmt.Sprintf("%smod", name), Encoding(name))
-	return nil, Exec("$ASN2TTCN", args...)
+	s := NewStep(lib.Outputs, []string{out}, "$ASN2TTCN", args...)
+	s.Deps = []*Step{lib}
+	return s
 }
 
-func buildAdapter(name string, srcs ...string) ([]string, error) {
+func buildAdapter(name string, srcs ...string) *Step {
 	out := Outf("k3r-%s-plugin.so", name)
-	if b, err := target.Path(out, srcs...); !b || err != nil {
-		return []string{out}, err
-	}
 	var args []string
 	if env := Env("CXXFLAGS"); env != nil {
 		args = append(args, env...)
@@ -229,7 +227,7 @@ func buildAdapter(name string, srcs ...string) ([]string, error) {
 	}
 	args = append(args, "-lk3-plugin", "-shared", "-fPIC", "-o", out)
 	args = append(args, srcs...)
-	return []string{out}, Exec("$CXX", args...)
+	return NewStep(srcs, []string{out}, "$CXX", args...)
 }
 
 func Encoding(name string) string {
@@ -250,6 +248,12 @@ func Env(key string) []string {
 }
 
 func Exec(name string, args ...string) error {
+	return command(expand(name, args...)).Run()
+}
+
+// expand returns the command line of name and args with environment variables
+// expanded. Variables not set in the environment default to DefaultEnv.
+func expand(name string, args ...string) []string {
 	expand := func(key string) string {
 		if v, ok := os.LookupEnv(key); ok {
 			return v
@@ -257,23 +261,24 @@ func Exec(name string, args ...string) error {
 		return DefaultEnv[key]
 	}
 
-	name = os.Expand(name, expand)
-	if f := strings.Fields(name); len(f) > 0 {
-		name = f[0]
-		args = append(f[1:], args...)
+	argv := strings.Fields(os.Expand(name, expand))
+	for _, arg := range args {
+		argv = append(argv, os.Expand(arg, expand))
 	}
+	return argv
+}
 
-	for i := range args {
-		args[i] = os.Expand(args[i], expand)
+func command(argv []string) *exec.Cmd {
+	if len(argv) == 0 {
+		argv = []string{""}
 	}
-
-	cmd := exec.Command(name, args...)
+	cmd := exec.Command(argv[0], argv[1:]...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Env = os.Environ()
 
 	log.Debugln("+", cmd.String())
-	return cmd.Run()
+	return cmd
 }
 
 func Outf(f string, v ...interface{}) string {
@@ -46,7 +46,7 @@ func TestImports(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result, err := buildImport(tt.path)
+		result, _, err := buildImport(&Graph{}, tt.path)
 		if !errors.Is(err, tt.err) {
 			t.Errorf("%v: %v, want %v", tt.path, err, tt.err)
 		}
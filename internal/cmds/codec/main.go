@@ -0,0 +1,76 @@
+package codec
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nokia/ntt/codec"
+	"github.com/nokia/ntt/internal/generator"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/ntt"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "codec",
+		Short: "Serve the Codec gRPC service on a Unix socket",
+		Long: `Serve the Codec gRPC service on a Unix socket.
+
+The codec command loads the types of the test suite and serves the Codec
+service defined in protobuf/codec.proto. Adapters and the runtime send values
+together with their TTCN-3 type and get them encoded or decoded by the codec
+matching the encode and variant attributes of the type. References to named
+types, like "M.T", are resolved using the types of the test suite.
+
+Built-in codecs are JSON and RAW.
+
+Example:
+
+	ntt codec --socket /tmp/codec.sock
+`,
+		RunE: serve,
+	}
+
+	socket = "ntt-codec.sock"
+)
+
+func init() {
+	Command.Flags().StringVarP(&socket, "socket", "s", socket, "listen on Unix socket `path`")
+}
+
+func serve(cmd *cobra.Command, args []string) error {
+	suite, err := ntt.NewFromArgs(args...)
+	if err != nil {
+		return err
+	}
+	files, err := suite.Files()
+	if err != nil {
+		return err
+	}
+
+	trees := make([]*ttcn3.Tree, 0, len(files))
+	for _, file := range files {
+		trees = append(trees, ttcn3.ParseFile(file))
+	}
+
+	// Types with errors are still usable. They only fail when referencing
+	// undefined types.
+	req, err := generator.NewRequest(trees...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
+
+	r := codec.NewRegistry()
+	r.AddModules(req.Modules...)
+
+	l, err := codec.Listen(socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(socket)
+
+	log.Verbosef("codec: listening on %s\n", socket)
+	return codec.NewServer(r).Serve(l)
+}
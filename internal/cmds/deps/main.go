@@ -0,0 +1,158 @@
+package deps
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/nokia/ntt/internal/env"
+	"github.com/nokia/ntt/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "deps",
+		Short: "Manage test suite dependencies",
+		Long: `Manage test suite dependencies.
+
+Imports in package.yml are either plain directories or dependencies with a
+name, an optional version constraint and a source: a local path, a git
+repository pinned by commit or an archive:
+
+	imports:
+	  - ../common
+	  - name: codecs
+	    version: ^1.2
+	    git: https://example.com/codecs.git
+	    commit: 3f2a9c0
+	  - name: sctlib
+	    archive: https://example.com/sctlib-1.4.tar.gz
+	    sha256: 9b2c...
+
+"ntt deps fetch" resolves all imports, including the imports of dependencies,
+stores git and archive dependencies in .ntt/deps and records the result in
+package.lock next to package.yml. Git commits are recorded by their full hash.
+Builds fail if package.yml changed since the lock file was written.
+
+Dependencies are taken from a vendor directory (--vendor or NTT_VENDOR) first,
+if available. The vendor directory contains git repositories named after the
+dependency ("codecs.git" or "codecs") and archives named like the archive
+file ("sctlib-1.4.tar.gz"). Use --offline to fetch from local paths and the
+vendor directory only.
+`,
+	}
+
+	fetchCmd = &cobra.Command{
+		Use:   "fetch [dir]",
+		Short: "Fetch dependencies and write the lock file",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  fetch,
+	}
+
+	listCmd = &cobra.Command{
+		Use:   "list [dir]",
+		Short: "List locked dependencies",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  list,
+	}
+
+	graphCmd = &cobra.Command{
+		Use:   "graph [dir]",
+		Short: "Print the dependency graph in DOT format",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  graph,
+	}
+
+	vendor  = ""
+	offline = false
+)
+
+func init() {
+	fetchCmd.Flags().StringVarP(&vendor, "vendor", "", "", "directory with local copies of dependencies (default $NTT_VENDOR)")
+	fetchCmd.Flags().BoolVarP(&offline, "offline", "", false, "do not access remote repositories or archives")
+	Command.AddCommand(fetchCmd, listCmd, graphCmd)
+}
+
+func root(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return "."
+}
+
+func fetch(cmd *cobra.Command, args []string) error {
+	p, err := project.Open(root(args))
+	if err != nil {
+		return err
+	}
+	if vendor == "" {
+		vendor = env.Getenv("NTT_VENDOR")
+	}
+	f := &project.Fetcher{Vendor: vendor, Offline: offline}
+	lock, err := f.Fetch(p)
+	if err != nil {
+		return err
+	}
+	return project.WriteLock(p.Root(), lock)
+}
+
+func readLock(args []string) (*project.Lock, error) {
+	lock, err := project.ReadLock(root(args))
+	if err == nil && lock == nil {
+		err = fmt.Errorf("%s not found. Run 'ntt deps fetch' first", project.LockFile)
+	}
+	return lock, err
+}
+
+func list(cmd *cobra.Command, args []string) error {
+	lock, err := readLock(args)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, dep := range lock.Dependencies {
+		version := dep.Version
+		if version == "" {
+			version = "-"
+		}
+		source := dep.Source
+		if dep.Commit != "" {
+			source += "@" + dep.Commit
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", dep.Name, version, source, dep.Dir)
+	}
+	return w.Flush()
+}
+
+func graph(cmd *cobra.Command, args []string) error {
+	lock, err := readLock(args)
+	if err != nil {
+		return err
+	}
+	p, err := project.Open(root(args))
+	if err != nil {
+		return err
+	}
+	name := p.Manifest.Name
+	if name == "" {
+		name = "."
+	}
+
+	fmt.Println("digraph deps {")
+	for _, dep := range lock.Dependencies {
+		if dep.Version != "" {
+			fmt.Printf("\t%q [label=%q];\n", dep.Name, dep.Name+" "+dep.Version)
+		}
+	}
+	for _, req := range lock.Requires {
+		fmt.Printf("\t%q -> %q;\n", name, req)
+	}
+	for _, dep := range lock.Dependencies {
+		for _, req := range dep.Requires {
+			fmt.Printf("\t%q -> %q;\n", dep.Name, req)
+		}
+	}
+	fmt.Println("}")
+	return nil
+}
@@ -0,0 +1,64 @@
+package doc
+
+import (
+	"os"
+
+	"github.com/nokia/ntt/internal/generator"
+	"github.com/nokia/ntt/internal/ntt"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/doc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "doc",
+		Short: "Generate documentation from TTCN-3 sources",
+		Long: `Generate documentation from TTCN-3 sources.
+
+The doc command writes one page per module into the output directory. A page
+lists the types, templates, functions and testcases of a module, with their
+comments, documentation tags and links to referenced definitions. The pages
+index.md and tags.md list all modules and all definitions by tag, like
+@author, @purpose or @verdict.
+
+Use --format=html to generate a static HTML site instead of Markdown files.
+The same generator is available as ttcn3c -G doc.
+
+Example:
+
+	ntt doc --format=html -o site
+`,
+		RunE: run,
+	}
+
+	format = "markdown"
+	outDir = "doc"
+)
+
+func init() {
+	Command.Flags().StringVarP(&format, "format", "f", format, "output format (markdown, html)")
+	Command.Flags().StringVarP(&outDir, "output", "o", outDir, "output `directory`")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	suite, err := ntt.NewFromArgs(args...)
+	if err != nil {
+		return err
+	}
+	files, err := suite.Files()
+	if err != nil {
+		return err
+	}
+
+	trees := make([]*ttcn3.Tree, 0, len(files))
+	for _, file := range files {
+		trees = append(trees, ttcn3.ParseFile(file))
+	}
+
+	resp, err := doc.Generate(map[string]string{"format": format}, trees...)
+	if err != nil {
+		return err
+	}
+	return generator.Finish(os.Stdout, outDir, resp)
+}
@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// applyFixes applies the first suggested fix of every diagnostic. If preview
+// is set, a unified diff is written to w instead of modifying files. The
+// diagnostics without applied fix are returned.
+func applyFixes(w io.Writer, diags []analysis.Diagnostic, preview bool) ([]analysis.Diagnostic, error) {
+	var (
+		files  []string
+		byFile = make(map[string][]int)
+	)
+	for i, d := range diags {
+		if len(d.SuggestedFixes) == 0 {
+			continue
+		}
+		file := d.Pos.Filename
+		if _, ok := byFile[file]; !ok {
+			files = append(files, file)
+		}
+		byFile[file] = append(byFile[file], i)
+	}
+
+	fixed := make(map[int]bool)
+	for _, file := range files {
+		src, err := fs.Content(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var fixes []analysis.SuggestedFix
+		for _, i := range byFile[file] {
+			fixes = append(fixes, diags[i].SuggestedFixes[0])
+		}
+		result, applied := analysis.ApplyFixes(src, fixes)
+		for j, ok := range applied {
+			if ok {
+				fixed[byFile[file][j]] = true
+			}
+		}
+
+		if preview {
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        difflib.SplitLines(string(src)),
+				B:        difflib.SplitLines(string(result)),
+				FromFile: file,
+				ToFile:   file,
+				Context:  3,
+			})
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprint(w, diff)
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(file, result, info.Mode()); err != nil {
+			return nil, err
+		}
+	}
+
+	var remaining []analysis.Diagnostic
+	for i, d := range diags {
+		if !fixed[i] {
+			remaining = append(remaining, d)
+		}
+	}
+	return remaining, nil
+}
@@ -0,0 +1,307 @@
+package lint
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/ttcn3/analysis"
+)
+
+// A formatter writes diagnostics to w. Analyzers are passed to describe the
+// rules. Root is the root directory of the test suite; formats referring to
+// files relative to a base directory use it.
+type formatter func(w io.Writer, diags []analysis.Diagnostic, analyzers []*analysis.Analyzer, root string) error
+
+var formats = map[string]formatter{
+	"text":       formatText,
+	"json":       formatJSON,
+	"sarif":      formatSARIF,
+	"checkstyle": formatCheckstyle,
+	"github":     formatGitHub,
+}
+
+func formatNames() string {
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func formatText(w io.Writer, diags []analysis.Diagnostic, _ []*analysis.Analyzer, _ string) error {
+	for _, d := range diags {
+		if _, err := fmt.Fprintln(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type jsonFinding struct {
+	Rule     string        `json:"rule"`
+	Severity string        `json:"severity"`
+	Message  string        `json:"message"`
+	File     string        `json:"file"`
+	Begin    *jsonPosition `json:"begin,omitempty"`
+	End      *jsonPosition `json:"end,omitempty"`
+}
+
+func jsonPos(pos loc.Position) *jsonPosition {
+	if !pos.IsValid() {
+		return nil
+	}
+	return &jsonPosition{Line: pos.Line, Column: pos.Column}
+}
+
+func formatJSON(w io.Writer, diags []analysis.Diagnostic, _ []*analysis.Analyzer, _ string) error {
+	findings := []jsonFinding{}
+	for _, d := range diags {
+		findings = append(findings, jsonFinding{
+			Rule:     d.Analyzer,
+			Severity: string(d.Severity),
+			Message:  d.Message,
+			File:     d.Pos.Filename,
+			Begin:    jsonPos(d.Pos),
+			End:      jsonPos(d.End),
+		})
+	}
+	b, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// SARIF 2.1.0, as consumed by GitHub code scanning.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool               sarifTool                `json:"tool"`
+		OriginalURIBaseIDs map[string]sarifArtifact `json:"originalUriBaseIds,omitempty"`
+		Results            []sarifResult            `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+		FullDescription  sarifMessage `json:"fullDescription"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifact `json:"artifactLocation"`
+		Region           *sarifRegion  `json:"region,omitempty"`
+	}
+	sarifArtifact struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+)
+
+// sarifRootID is the URI base id of the test suite root directory.
+const sarifRootID = "SRCROOT"
+
+func formatSARIF(w io.Writer, diags []analysis.Diagnostic, analyzers []*analysis.Analyzer, root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	driver := sarifDriver{
+		Name:           "ntt",
+		InformationURI: "https://github.com/nokia/ntt",
+		Rules:          []sarifRule{},
+	}
+	for _, a := range analyzers {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               a.Name,
+			ShortDescription: sarifMessage{Text: strings.SplitN(a.Doc, "\n", 2)[0]},
+			FullDescription:  sarifMessage{Text: a.Doc},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, d := range diags {
+		loc := sarifPhysicalLocation{ArtifactLocation: artifactLocation(root, d.Pos.Filename)}
+		if d.Pos.IsValid() {
+			loc.Region = &sarifRegion{StartLine: d.Pos.Line, StartColumn: d.Pos.Column}
+			if d.End.IsValid() {
+				loc.Region.EndLine = d.End.Line
+				loc.Region.EndColumn = d.End.Column
+			}
+		}
+		results = append(results, sarifResult{
+			RuleID:    d.Analyzer,
+			Level:     sarifLevel(d.Severity),
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	b, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:               sarifTool{Driver: driver},
+			OriginalURIBaseIDs: map[string]sarifArtifact{sarifRootID: {URI: fileURI(root) + "/"}},
+			Results:            results,
+		}},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+// artifactLocation returns the location of file. Files below root are referred
+// to relative to the root directory, other files by absolute URI.
+func artifactLocation(root string, file string) sarifArtifact {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return sarifArtifact{URI: fileURI(file)}
+	}
+	return sarifArtifact{URI: (&url.URL{Path: filepath.ToSlash(rel)}).String(), URIBaseID: sarifRootID}
+}
+
+// fileURI returns the file URI of absolute path.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // Windows drive letters
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+func sarifLevel(s analysis.Severity) string {
+	switch s {
+	case analysis.Warning:
+		return "warning"
+	case analysis.Info:
+		return "note"
+	}
+	return "error"
+}
+
+// Checkstyle XML, as consumed by Jenkins and most review bots.
+type (
+	checkstyleReport struct {
+		XMLName xml.Name         `xml:"checkstyle"`
+		Version string           `xml:"version,attr"`
+		Files   []checkstyleFile `xml:"file"`
+	}
+	checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+	checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr,omitempty"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+)
+
+func formatCheckstyle(w io.Writer, diags []analysis.Diagnostic, _ []*analysis.Analyzer, _ string) error {
+	report := checkstyleReport{Version: "4.3"}
+	for _, d := range diags {
+		if n := len(report.Files); n == 0 || report.Files[n-1].Name != d.Pos.Filename {
+			report.Files = append(report.Files, checkstyleFile{Name: d.Pos.Filename})
+		}
+		f := &report.Files[len(report.Files)-1]
+		f.Errors = append(f.Errors, checkstyleError{
+			Line:     d.Pos.Line,
+			Column:   d.Pos.Column,
+			Severity: string(d.Severity),
+			Message:  d.Message,
+			Source:   "ntt." + d.Analyzer,
+		})
+	}
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, b)
+	return err
+}
+
+// formatGitHub writes GitHub Actions workflow commands, which annotate pull
+// requests.
+func formatGitHub(w io.Writer, diags []analysis.Diagnostic, _ []*analysis.Analyzer, _ string) error {
+	for _, d := range diags {
+		cmd := "error"
+		switch d.Severity {
+		case analysis.Warning:
+			cmd = "warning"
+		case analysis.Info:
+			cmd = "notice"
+		}
+
+		props := []string{"file=" + githubEscape(d.Pos.Filename, true)}
+		if d.Pos.IsValid() {
+			props = append(props, fmt.Sprintf("line=%d", d.Pos.Line), fmt.Sprintf("col=%d", d.Pos.Column))
+			if d.End.IsValid() {
+				props = append(props, fmt.Sprintf("endLine=%d", d.End.Line), fmt.Sprintf("endColumn=%d", d.End.Column))
+			}
+		}
+		props = append(props, "title="+githubEscape(d.Analyzer, true))
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", cmd, strings.Join(props, ","), githubEscape(d.Message, false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func githubEscape(s string, property bool) string {
+	s = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+	if property {
+		s = strings.NewReplacer(":", "%3A", ",", "%2C").Replace(s)
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDiags = []analysis.Diagnostic{
+	{
+		Pos:      loc.Position{Filename: "a.ttcn3", Line: 2, Column: 3},
+		End:      loc.Position{Filename: "a.ttcn3", Line: 4, Column: 1},
+		Analyzer: "naming",
+		Severity: analysis.Error,
+		Message:  "bad name, really: bad",
+	},
+	{
+		Pos:      loc.Position{Filename: "b.ttcn3"},
+		Analyzer: "unused",
+		Severity: analysis.Warning,
+		Message:  "unused module",
+	},
+}
+
+var testAnalyzers = []*analysis.Analyzer{{Name: "naming", Doc: "Identifiers must follow naming conventions.\n\nMore."}}
+
+func render(t *testing.T, f formatter) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.Nil(t, f(&buf, testDiags, testAnalyzers, "."))
+	return buf.String()
+}
+
+func TestFormatText(t *testing.T) {
+	assert.Equal(t, "a.ttcn3:2:3: error: bad name, really: bad\nb.ttcn3: warning: unused module\n", render(t, formatText))
+}
+
+func TestFormatJSON(t *testing.T) {
+	assert.JSONEq(t, `[
+		{"rule": "naming", "severity": "error", "message": "bad name, really: bad", "file": "a.ttcn3",
+		 "begin": {"line": 2, "column": 3}, "end": {"line": 4, "column": 1}},
+		{"rule": "unused", "severity": "warning", "message": "unused module", "file": "b.ttcn3"}
+	]`, render(t, formatJSON))
+}
+
+func TestFormatSARIF(t *testing.T) {
+	var log sarifLog
+	require.Nil(t, json.Unmarshal([]byte(render(t, formatSARIF)), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "Identifiers must follow naming conventions.", run.Tool.Driver.Rules[0].ShortDescription.Text)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "naming", run.Results[0].RuleID)
+	assert.Equal(t, &sarifRegion{StartLine: 2, StartColumn: 3, EndLine: 4, EndColumn: 1}, run.Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Nil(t, run.Results[1].Locations[0].PhysicalLocation.Region)
+
+	wd, err := os.Getwd()
+	require.Nil(t, err)
+	assert.Equal(t, fileURI(wd)+"/", run.OriginalURIBaseIDs["SRCROOT"].URI)
+	assert.Equal(t, sarifArtifact{URI: "a.ttcn3", URIBaseID: "SRCROOT"}, run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation)
+}
+
+func TestSARIFArtifactLocation(t *testing.T) {
+	root := filepath.FromSlash("/suite")
+	tests := []struct {
+		file string
+		want sarifArtifact
+	}{
+		{"/suite/a.ttcn3", sarifArtifact{URI: "a.ttcn3", URIBaseID: "SRCROOT"}},
+		{"/suite/src/my file.ttcn3", sarifArtifact{URI: "src/my%20file.ttcn3", URIBaseID: "SRCROOT"}},
+		{"/suite/..lib/b.ttcn3", sarifArtifact{URI: "..lib/b.ttcn3", URIBaseID: "SRCROOT"}},
+		{"/libs/c.ttcn3", sarifArtifact{URI: "file:///libs/c.ttcn3"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, artifactLocation(root, filepath.FromSlash(tt.file)), tt.file)
+	}
+}
+
+func TestFormatCheckstyle(t *testing.T) {
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="a.ttcn3">
+    <error line="2" column="3" severity="error" message="bad name, really: bad" source="ntt.naming"></error>
+  </file>
+  <file name="b.ttcn3">
+    <error line="0" severity="warning" message="unused module" source="ntt.unused"></error>
+  </file>
+</checkstyle>
+`, render(t, formatCheckstyle))
+}
+
+func TestFormatGitHub(t *testing.T) {
+	assert.Equal(t, "::error file=a.ttcn3,line=2,col=3,endLine=4,endColumn=1,title=naming::bad name, really: bad\n"+
+		"::warning file=b.ttcn3,title=unused::unused module\n", render(t, formatGitHub))
+}
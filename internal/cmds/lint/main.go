@@ -2,23 +2,18 @@ package lint
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
-	"sync"
+	"text/tabwriter"
 
-	"github.com/nokia/ntt/internal/errors"
 	"github.com/nokia/ntt/internal/fs"
-	"github.com/nokia/ntt/internal/loc"
 	"github.com/nokia/ntt/internal/log"
 	"github.com/nokia/ntt/internal/ntt"
 	"github.com/nokia/ntt/project"
-	"github.com/nokia/ntt/ttcn3"
-	"github.com/nokia/ntt/ttcn3/ast"
-	"github.com/nokia/ntt/ttcn3/doc"
-	"github.com/nokia/ntt/ttcn3/token"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	_ "github.com/nokia/ntt/ttcn3/analysis/passes"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 )
 
 var (
@@ -30,7 +25,63 @@ It may find problems not caught by the compiler, but also constructs
 considered "bad style".
 
 Lint's exit code is non-zero for erroneous invocation of the tool or if a
-problem was reported.
+problem with severity error was reported.
+
+
+Configuration Files
+
+Unless --config is given, every file is checked with the configuration file
+.ntt-lint.yml of its directory. Files without configuration file in their
+directory or any parent directory, like imported libraries outside of the
+working directory, use the configuration file of the test suite root or of the
+working directory. Configuration files inherit from the
+configuration files of parent directories: sections and severities replace
+those of the parent, ignore patterns and overrides are appended. A
+configuration with "root: true" does not inherit.
+
+    root        Stop inheritance from parent directories
+    severity    Severity of an analyzer: error (default), warning, info or off
+    overrides   Severities for files matching regular expressions
+
+
+Silencing Findings
+
+A comment "NOLINT(name, ...)" before a module, definition, statement or
+block silences the named analyzers in the whole construct. A comment
+"NOLINTFILE(name, ...)" silences them in the whole file.
+
+
+Baselines
+
+Legacy code often has more findings than can be fixed at once. The
+--write-baseline flag records the current findings in a baseline file
+(default .ntt-lint-baseline.json). Later runs report and fail only on findings
+not recorded in the baseline. Findings are identified by check, file, enclosing
+definition and the offending source text, so moving code does not invalidate
+the baseline, but changing it does. Of definitions like functions only the
+header is considered, so editing the body of a function does not bring back
+its max_lines or complexity findings. Together with usage limits this allows
+gradual refactoring.
+
+
+Fixes
+
+Some findings have mechanical fixes: unaligned closing braces, missing case
+else, unused imports and missing prefixes of local variables. Parameters are
+not renamed, because named actual parameters of callers would break. The --fix
+flag applies them and reports the remaining findings. The --diff flag prints
+the fixes as unified diff without modifying any file. A missing case else
+copies the style of the preceding clause, other new code is rendered by the
+TTCN-3 printer; formatting and comments elsewhere are preserved.
+
+
+Output Formats
+
+Findings are printed as text by default. The --format flag selects structured
+output: json, sarif (for GitHub code scanning), checkstyle (for Jenkins and
+review bots) or github (workflow commands annotating pull requests). SARIF
+locations are relative to the test suite root, which is given as uriBaseId
+SRCROOT.
 
 
 Formatting Checks
@@ -78,15 +129,58 @@ legacy code are faster added than one can remove them. This check helps with a
 warning, as soon as the usage of a symbol exceed a defined limit.
 
 
+Behaviour Checks
+
+    require_alt_guard        Every alt-statement must have a timeout- or else-guard.
+    require_port_connection  Ports must be mapped or connected before receiving from them.
+    require_verdict          Every testcase must set a verdict.
+    unreachable_code         Statements must be reachable.
+    require_deactivate       Activated defaults must be deactivated.
+    require_timer_handling   Started timers must be stopped or awaited.
+
+
+Duplicate Code
+
+    clones.min_size  Number of syntax tree nodes a template or behaviour must
+                     have to be checked for clones. Identifiers and literals
+                     are ignored, so definitions differing only in field values
+                     are reported, too. Templates must have the same type.
+                     Only complete definitions with identical structure are
+                     found, not similar or partially duplicated code.
+
+
+Import Architecture
+
+    imports.forbidden    Forbidden imports between modules
+    imports.cycles       Checks for import cycles
+    imports.max_fan_in   Number of modules a module may be imported by
+    imports.max_fan_out  Number of modules a module may import
+
+Run "ntt list imports --graph" to inspect the module dependency graph.
+
+
 Unused Symbols
 
-    unused.modules    Checks for unused modules
+    unused.modules      Checks for unused modules
+    unused.imports      Checks for unused imports
+    unused.definitions  Checks for definitions not reachable from any
+                        test case or control part
 
 
 Example configuration file:
 
+	severity:
+	  max_lines: warning
+	  unused: info
+
+	overrides:
+	  - files: ["generated/", "_Templates.ttcn3$"]
+	    severity:
+	      clones: off
+
 	aligned_braces: true
 	require_case_else: true
+	require_alt_guard: true
 	max_lines: 40
 
 	usage:
@@ -97,6 +191,12 @@ Example configuration file:
 	unused:
 	  modules: true
 
+	imports:
+	  forbidden:
+	    "^Lib_":
+	      "^TC_": "library modules must not import test modules"
+	  cycles: true
+
 	complexity:
 	  max: 15
 	  ignore_guards: true
@@ -126,645 +226,134 @@ Example configuration file:
 	    # Ignore all files from generated folders
 	    - "generated/"
 
-For information on writing new checks, see <TBD>.
+Each check is an analyzer of package github.com/nokia/ntt/ttcn3/analysis and
+its configuration section has the name of the analyzer. Run "ntt lint --list"
+to show the available analyzers. New checks are added by registering an
+analyzer with analysis.Register.
 `,
 
 		RunE: lint,
 	}
 
-	config  string
-	regexes = make(map[string]*regexp.Regexp)
-	issues  = 0
-
-	usedModules  = make(map[string]Import)
-	usedModuleMu sync.Mutex
-
-	style = struct {
-		MaxLines        int  `yaml:"max_lines"`
-		AlignedBraces   bool `yaml:"aligned_braces"`
-		RequireCaseElse bool `yaml:"require_case_else"`
-		Complexity      struct {
-			Max          int
-			IgnoreGuards bool `yaml:"ignore_guards"`
-		}
-		Naming struct {
-			Modules         map[string]string
-			Tests           map[string]string
-			Functions       map[string]string
-			Altsteps        map[string]string
-			Parameters      map[string]string
-			ComponentVars   map[string]string `yaml:"component_vars"`
-			VarTemplates    map[string]string `yaml:"var_templates"`
-			PortTypes       map[string]string `yaml:"port_types"`
-			Ports           map[string]string
-			GlobalConsts    map[string]string `yaml:"global_consts"`
-			ComponentConsts map[string]string `yaml:"component_consts"`
-			Templates       map[string]string
-			Locals          map[string]string
-		}
-		Tags struct {
-			Tests map[string]string
-		}
-		Ignore struct {
-			Modules []string
-			Files   []string
-		}
-		Usage map[string]*struct {
-			Text  string
-			Limit int
-			count int
-		}
-		Unused struct {
-			Modules bool
-		}
-	}{}
+	config        string
+	list          bool
+	format        string
+	baseline      string
+	writeBaseline bool
+	fix           bool
+	diff          bool
 )
 
-type Import struct {
-	Fset     *loc.FileSet
-	Node     *ast.ImportDecl
-	Path     string
-	From     string
-	Imported string
-}
-
 func init() {
 	Command.PersistentFlags().StringVarP(&config, "config", "c", ".ntt-lint.yml", "path to YAML formatted file containing linter configuration")
+	Command.Flags().BoolVar(&list, "list", false, "list available analyzers")
+	Command.Flags().StringVar(&baseline, "baseline", analysis.BaselineFile, "path to baseline file of accepted findings")
+	Command.Flags().BoolVar(&writeBaseline, "write-baseline", false, "record current findings in the baseline file")
+	Command.Flags().BoolVar(&fix, "fix", false, "apply suggested fixes and report remaining findings")
+	Command.Flags().BoolVar(&diff, "diff", false, "print suggested fixes as unified diff instead of applying them")
+	Command.Flags().StringVar(&format, "format", "text", "output format of findings ("+formatNames()+")")
 }
 
 func lint(cmd *cobra.Command, args []string) error {
-	suite, err := ntt.NewFromArgs(args...)
-	if err != nil {
-		return err
-	}
-
-	c := fs.Open(config)
-	b, err := c.Bytes()
-	if err != nil {
-		log.Verbose(err.Error())
-		return nil
-	}
-
-	if err := yaml.UnmarshalStrict(b, &style); err != nil {
-		return err
+	analyzers := analysis.All()
+	if list {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		for _, a := range analyzers {
+			fmt.Fprintf(w, "%s\t%s\n", a.Name, strings.SplitN(a.Doc, "\n", 2)[0])
+		}
+		return w.Flush()
 	}
 
-	if err := buildRegexCache(); err != nil {
-		return err
+	output, ok := formats[format]
+	if !ok {
+		return fmt.Errorf("unknown format %q. Supported formats: %s", format, formatNames())
 	}
 
-	files, err := project.Files(suite)
+	suite, err := ntt.NewFromArgs(args...)
 	if err != nil {
 		return err
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(files))
-
-	for i := range files {
-		go func(i int) {
-			defer wg.Done()
-
-			if isWhiteListed(style.Ignore.Files, files[i]) {
-				return
-			}
-
-			tree := ttcn3.ParseFile(files[i])
-			if tree.Err != nil {
-				switch e := tree.Err.(type) {
-				case *errors.ErrorList:
-					for _, e := range e.List() {
-						report(e)
-					}
-				default:
-					report(e)
-				}
-				return
-			}
-
-			for _, def := range tree.Modules() {
-				mod := def.Node.(*ast.Module)
-
-				if isWhiteListed(style.Ignore.Modules, ast.Name(mod.Name)) {
-					continue
-				}
-
-				stack := make([]ast.Node, 1, 64)
-				cc := make(map[ast.Node]int)
-				ccID := ast.Node(mod)
-
-				caseElse := make(map[ast.Node]int)
-				var selectID *ast.SelectStmt
-
-				ast.Inspect(mod, func(n ast.Node) bool {
-					if n == nil {
-						stack = stack[:len(stack)-1]
-						return false
-					}
-
-					stack = append(stack, n)
-					fset := tree.FileSet
-
-					switch n := n.(type) {
-					case *ast.Ident:
-						checkUsage(fset, n)
-
-					case *ast.Module:
-						checkNaming(fset, n, style.Naming.Modules)
-						checkBraces(fset, n.LBrace, n.RBrace)
-
-					case *ast.FuncDecl:
-						ccID = n
-						cc[ccID] = 1 // Intial McCabe value
-
-						switch n.Kind.Kind {
-						case token.TESTCASE:
-							checkNaming(fset, n, style.Naming.Tests)
-							checkTags(fset, n, style.Tags.Tests)
-						case token.FUNCTION:
-							checkNaming(fset, n, style.Naming.Functions)
-						case token.ALTSTEP:
-							checkNaming(fset, n, style.Naming.Altsteps)
-						}
-
-						checkLines(fset, n)
-
-					case *ast.FormalPar:
-						checkNaming(fset, n, style.Naming.Parameters)
-
-						// We do not descent any further,
-						// because we do not want to count
-						// cyclomatic complexity for default
-						// values.
-						return false
-
-					case *ast.PortTypeDecl:
-						checkNaming(fset, n, style.Naming.PortTypes)
-						checkBraces(fset, n.LBrace, n.RBrace)
-
-					case *ast.Declarator:
-						if len(stack) <= 2 {
-							return true
-						}
-
-						// The parent of a declarator should
-						// always be a ValueDecl.  If not, we
-						// have some internal issues, it's okay
-						// to panic then.
-						parent := stack[len(stack)-2].(*ast.ValueDecl)
-						scope := stack[:len(stack)-2]
-
-						switch {
-						case isPort(parent):
-							checkNaming(fset, n, style.Naming.Ports)
-						case isConst(parent):
-							switch {
-							case inGlobalScope(scope):
-								checkNaming(fset, n, style.Naming.GlobalConsts)
-							case inComponentScope(scope):
-								checkNaming(fset, n, style.Naming.ComponentConsts)
-							}
-						case isVarTemplate(parent):
-							checkNaming(fset, n, style.Naming.VarTemplates)
-						case isVar(parent):
-							switch {
-							case inComponentScope(scope):
-								checkNaming(fset, n, style.Naming.ComponentVars)
-							default:
-								checkNaming(fset, n, style.Naming.Locals)
-							}
-						}
-
-						return true
-
-					case *ast.TemplateDecl:
-						checkNaming(fset, n, style.Naming.Templates)
-
-					case *ast.BlockStmt:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.CompositeLiteral:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.ExceptExpr:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.SelectStmt:
-						selectID = n
-						caseElse[selectID] = 0
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.StructSpec:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.EnumSpec:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.ModuleParameterGroup:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.StructTypeDecl:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.EnumTypeDecl:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.ImportDecl:
-						checkBraces(fset, n.LBrace, n.RBrace)
-						checkImport(fset, n, mod)
-					case *ast.GroupDecl:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.WithSpec:
-						checkBraces(fset, n.LBrace, n.RBrace)
-					case *ast.ParenExpr:
-						if n.LParen.Kind == token.LBRACE {
-							checkBraces(fset, n.LParen, n.RParen)
-						}
-
-					case *ast.ModuleDef:
-						// Reset ID for counting cyclomatic complexity.
-						ccID = mod
-
-					case *ast.BinaryExpr:
-						if n.Op.Kind == token.AND || n.Op.Kind == token.OR {
-							cc[ccID]++
-						}
-
-					case *ast.IfStmt:
-						cc[ccID]++
-
-					case *ast.CaseClause:
-						if isCaseElse(n) {
-							caseElse[selectID]++
-						} else {
-							// Do not count case else for complexity
-							cc[ccID]++
-						}
-
-					case *ast.CommClause:
-						if style.Complexity.IgnoreGuards {
-							return true
-						}
-
-						// Do not count else-guards
-						if n.Else.IsValid() {
-							return true
-						}
-						// Every AltGuard increases cyclomatic complexity.
-						cc[ccID]++
-
-						// Every AltGuard expressions also increases complexity.
-						if n.X != nil {
-							cc[ccID]++
-						}
-
-					}
-					return true
-				})
-
-				checkComplexity(tree.FileSet, cc)
-				checkCaseElse(tree.FileSet, caseElse)
-			}
-		}(i)
-	}
-
-	wg.Wait()
-
-	checkSuite(suite)
-
-	switch issues {
-	case 0:
-		return nil
-	case 1:
-		return fmt.Errorf("1 issue found.")
-	default:
-		return fmt.Errorf("%d issues found.", issues)
-	}
-
-}
-
-func checkNaming(fset *loc.FileSet, n ast.Node, patterns map[string]string) {
-	checkPatterns(fset, n, patterns, ast.Name(n))
-}
-
-func checkTags(fset *loc.FileSet, n ast.Node, patterns map[string]string) {
-	if len(patterns) == 0 {
-		return
-	}
-
-	var tags []string
-	for _, t := range doc.FindAllTags(ast.FirstToken(n).Comments()) {
-		tags = append(tags, strings.Join(t, ":"))
-	}
-
-	checkPatterns(fset, n, patterns, tags...)
-}
-
-func checkPatterns(fset *loc.FileSet, n ast.Node, patterns map[string]string, ss ...string) {
-next:
-	for p, msg := range patterns {
-		expect := true
-		if strings.HasPrefix(p, "!") {
-			expect = false
-			p = p[1:]
-		}
-
-		// Match any.
-		for _, s := range ss {
-			if regexes[p].MatchString(s) == expect {
-				continue next
-			}
-		}
-
-		// If we could not match any, we report an error
-		report(&errPattern{fset: fset, node: n, msg: msg})
-	}
-}
-
-func checkLines(fset *loc.FileSet, n ast.Node) {
-	if style.MaxLines == 0 {
-		return
-	}
-
-	begin := fset.Position(n.Pos())
-	end := fset.Position(n.End())
-	lines := end.Line - begin.Line
-	if lines > style.MaxLines {
-		report(&errLines{fset: fset, node: n, lines: lines})
-	}
-
-}
-
-func checkBraces(fset *loc.FileSet, left ast.Node, right ast.Node) {
-	if !style.AlignedBraces {
-		return
-	}
-
-	p1 := fset.Position(left.Pos())
-	p2 := fset.Position(right.Pos())
-	if p1.Line != p2.Line && p1.Column != p2.Column {
-		report(&errBraces{fset: fset, left: left, right: right})
-	}
-}
-
-func checkComplexity(fset *loc.FileSet, cc map[ast.Node]int) {
-	if style.Complexity.Max == 0 {
-		return
+	r := analysis.Runner{
+		Analyzers: analyzers,
+		Project:   suite,
 	}
-
-	for n, v := range cc {
-		if v > style.Complexity.Max {
-			report(&errComplexity{fset: fset, node: n, complexity: v})
+	if cmd.Flags().Changed("config") {
+		b, err := fs.Open(config).Bytes()
+		if err != nil {
+			return err
 		}
-
-	}
-}
-
-func checkCaseElse(fset *loc.FileSet, caseElse map[ast.Node]int) {
-	if !style.RequireCaseElse {
-		return
-	}
-	for n, v := range caseElse {
-		if v == 0 {
-			report(&errMissingCaseElse{fset: fset, node: n})
+		if r.Config, err = analysis.ParseConfig(b, analyzers...); err != nil {
+			return err
 		}
-	}
-}
-
-func checkUsage(fset *loc.FileSet, n *ast.Ident) {
+	} else {
+		r.Configs = &analysis.Hierarchy{Analyzers: analyzers}
 
-	if style.Usage == nil {
-		return
-	}
-	id := n.String()
-	u, ok := style.Usage[id]
-	if !ok {
-		return
-	}
-	u.count++
-	if u.count >= u.Limit {
-		report(&errUsageExceedsLimit{
-			fset:  fset,
-			node:  n,
-			usage: u.count,
-			limit: u.Limit,
-			text:  u.Text})
-	}
-}
-
-func checkImport(fset *loc.FileSet, n *ast.ImportDecl, mod *ast.Module) {
-	if !style.Unused.Modules {
-		return
-	}
-
-	imported := ast.Name(n.Module)
-	importing := ast.Name(mod.Name)
-
-	usedModuleMu.Lock()
-	usedModules[imported] = Import{
-		Node:     n,
-		Fset:     fset,
-		Path:     fset.Position(n.Pos()).Filename,
-		From:     importing,
-		Imported: imported,
-	}
-	usedModuleMu.Unlock()
-
-}
-
-func checkSuite(suite *ntt.Suite) {
-
-	if !style.Unused.Modules {
-		return
-	}
-
-	pkgs, _ := suite.Imports()
-	for _, pkg := range pkgs {
-		files, _ := filepath.Glob(pkg + "/*.ttcn3")
-		for _, file := range files {
-			if isWhiteListed(style.Ignore.Files, file) {
-				continue
+		// Files outside of the directory tree, like imported
+		// libraries, use the configuration of the test suite root or
+		// the working directory.
+		for _, dir := range []string{suite.Root(), "."} {
+			c, err := analysis.ReadConfig(filepath.Join(dir, analysis.ConfigFile), analyzers)
+			if err != nil {
+				return err
 			}
-
-			mod := filepath.Base(file)
-			mod = strings.TrimSuffix(mod, filepath.Ext(mod))
-
-			if isWhiteListed(style.Ignore.Modules, mod) {
-				return
+			if c != nil {
+				r.Config = c
+				break
 			}
-
-			if _, found := usedModules[mod]; !found {
-				report(&errUnusedModule{file: file})
-			}
-		}
-	}
-}
-
-func matchAny(patterns []string, s string) bool {
-	for _, p := range patterns {
-
-		expect := true
-		if strings.HasPrefix(p, "!") {
-			expect = false
-			p = p[1:]
-		}
-
-		if regexes[p].MatchString(s) == expect {
-			return true
 		}
 	}
-	return false
-}
-
-func report(e error) {
 
-	// Check if this error is silenced (with a NOLINT-directive for example).
-	type silencer interface {
-		IsSilent() bool
-	}
-	if e, ok := e.(silencer); ok && e.IsSilent() {
-		return
-	}
-
-	issues++
-	fmt.Println(e.Error())
-}
-
-func isWhiteListed(list []string, s string) bool {
-	if len(list) == 0 {
-		return false
-	}
-	return matchAny(list, s)
-}
-
-func inComponentScope(stack []ast.Node) bool {
-	for _, n := range stack {
-		if _, ok := n.(*ast.ComponentTypeDecl); ok {
-			return true
-		}
+	files, err := project.Files(suite)
+	if err != nil {
+		return err
 	}
-	return false
-}
 
-func inGlobalScope(stack []ast.Node) bool {
-	for _, n := range stack {
-		switch n.(type) {
-		case *ast.Module, *ast.ModuleDef, *ast.GroupDecl, *ast.ModuleParameterGroup:
-		default:
-			return false
-		}
+	diags, err := r.Run(files...)
+	if err != nil {
+		return err
 	}
 
-	return true
-}
-
-func isPort(d *ast.ValueDecl) bool {
-	return d.Kind.Kind == token.PORT
-}
-
-func isConst(d *ast.ValueDecl) bool {
-	return d.Kind.Kind == token.CONST
-}
-
-func isVar(d *ast.ValueDecl) bool {
-	return !isVarTemplate(d) && d.Kind.Kind == token.VAR
-}
-
-func isVarTemplate(d *ast.ValueDecl) bool {
-	return d.TemplateRestriction != nil
-}
-
-func isCaseElse(n *ast.CaseClause) bool {
-	return n.Case == nil
-}
-
-func buildRegexCache() error {
-
-	for p := range style.Naming.Modules {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.Tests {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.Functions {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.Altsteps {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.Parameters {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.ComponentVars {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.PortTypes {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.Ports {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.GlobalConsts {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.ComponentConsts {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.Templates {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.VarTemplates {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
-	}
-	for p := range style.Naming.Locals {
-		if err := cacheRegex(p); err != nil {
+	if writeBaseline {
+		if err := analysis.NewBaseline(baseline, diags).Write(baseline); err != nil {
 			return err
 		}
+		log.Printf("Wrote %d findings to %s.\n", len(diags), baseline)
+		return nil
 	}
-	for p := range style.Tags.Tests {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
+
+	base, err := analysis.ReadBaseline(baseline)
+	if err != nil {
+		return fmt.Errorf("%s: %w", baseline, err)
 	}
-	for _, p := range style.Ignore.Modules {
-		if err := cacheRegex(p); err != nil {
-			return err
-		}
+	if base != nil {
+		all := len(diags)
+		diags = base.Filter(diags)
+		log.Verbosef("%d findings hidden by baseline %s.\n", all-len(diags), baseline)
 	}
-	for _, p := range style.Ignore.Files {
-		if err := cacheRegex(p); err != nil {
+
+	if fix || diff {
+		diags, err = applyFixes(os.Stdout, diags, diff)
+		if err != nil || diff {
 			return err
 		}
 	}
-	return nil
-}
-
-func cacheRegex(p string) error {
-	if strings.HasPrefix(p, "!") {
-		p = p[1:]
+	if err := output(os.Stdout, diags, analyzers, fs.Path(suite.Root())); err != nil {
+		return err
 	}
 
-	if _, ok := regexes[p]; !ok {
-		r, err := regexp.Compile(p)
-		if err != nil {
-			return err
+	errors := 0
+	for _, d := range diags {
+		if d.Severity == analysis.Error {
+			errors++
 		}
-		regexes[p] = r
 	}
-	return nil
+	switch errors {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("1 issue found.")
+	default:
+		return fmt.Errorf("%d issues found.", errors)
+	}
 }
@@ -2,6 +2,7 @@ package list
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
@@ -12,6 +13,7 @@ import (
 	"github.com/nokia/ntt/internal/ntt"
 	"github.com/nokia/ntt/project"
 	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/analysis"
 	"github.com/nokia/ntt/ttcn3/ast"
 	"github.com/nokia/ntt/ttcn3/doc"
 	"github.com/nokia/ntt/ttcn3/token"
@@ -28,6 +30,15 @@ var (
 List control parts, modules, imports or tests. The list command without any explicit
 sub-commands will output tests.
 
+The imports sub-command prints the module dependency graph with flag --graph.
+The graph is printed in DOT format (--graph=dot, the default) or as JSON object
+mapping module names to the names of imported modules (--graph=json):
+
+    ntt list imports --graph | dot -Tsvg > imports.svg
+
+The unused sub-command lists functions, altsteps, templates, constants, types and
+module parameters, which are not reachable from any test case or control part.
+
 List will ignore imported directories when printing tests. If you need to list all
 tests from a testsuite you'll have to pass .ttcn3 files as arguments.
 Example:
@@ -143,11 +154,13 @@ If a basket is not defined by an environment variable, it's equivalent to a
 	listImportsCmd    = &cobra.Command{Use: `imports`, RunE: listImports}
 	listControlsCmd   = &cobra.Command{Use: `controls`, RunE: listControls}
 	listModuleParsCmd = &cobra.Command{Use: `modulepars`, RunE: listModulePars}
+	listUnusedCmd     = &cobra.Command{Use: `unused`, RunE: listUnused}
 
 	w = bufio.NewWriter(os.Stdout)
 
 	showTags = false
 	verbose  = false
+	graph    = ""
 	trees    []*ttcn3.Tree
 
 	baskets = []basket{{name: "default"}}
@@ -159,7 +172,9 @@ func init() {
 	Command.PersistentFlags().StringSliceVarP(&baskets[0].nameExclude, "exclude", "x", []string{}, "exclude objects matching regular * expresion.")
 	Command.PersistentFlags().StringSliceVarP(&baskets[0].tagsRegex, "tags-regex", "R", []string{}, "list objects with tags matching regular * expression")
 	Command.PersistentFlags().StringSliceVarP(&baskets[0].tagsExclude, "tags-exclude", "X", []string{}, "exclude objects with tags matching * regular expression")
-	Command.AddCommand(listTestsCmd, listModulesCmd, listImportsCmd, listControlsCmd, listModuleParsCmd)
+	listImportsCmd.Flags().StringVar(&graph, "graph", "", "output module dependency graph (dot, json)")
+	listImportsCmd.Flags().Lookup("graph").NoOptDefVal = "dot"
+	Command.AddCommand(listTestsCmd, listModulesCmd, listImportsCmd, listControlsCmd, listModuleParsCmd, listUnusedCmd)
 }
 
 type basket struct {
@@ -295,6 +310,9 @@ func listModules(cmd *cobra.Command, args []string) error {
 }
 
 func listImports(cmd *cobra.Command, args []string) error {
+	if graph != "" {
+		return printGraph(graph)
+	}
 	for _, tree := range trees {
 		if tree.Err != nil {
 			return tree.Err
@@ -326,6 +344,48 @@ func listImports(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printGraph prints the import graph of all modules. Imported modules are
+// filtered like in a regular list.
+func printGraph(format string) error {
+	var mods []string
+	seen := make(map[string]bool)
+	for _, tree := range trees {
+		if tree.Err != nil {
+			return tree.Err
+		}
+		for _, mod := range tree.Modules() {
+			if name := mod.Ident.String(); !seen[name] {
+				seen[name] = true
+				mods = append(mods, name)
+			}
+		}
+	}
+	imports := analysis.NewImportGraph(func(imp *ast.ImportDecl) bool {
+		return match(imp.Module.String(), doc.FindAllTags(imp.ImportTok.Comments()))
+	}, trees...)
+
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(imports, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\n", b)
+	case "dot":
+		fmt.Fprintln(w, "digraph imports {")
+		for _, mod := range mods {
+			fmt.Fprintf(w, "\t%q;\n", mod)
+			for _, imported := range imports[mod] {
+				fmt.Fprintf(w, "\t%q -> %q;\n", mod, imported)
+			}
+		}
+		fmt.Fprintln(w, "}")
+	default:
+		return fmt.Errorf("unknown graph format %q. Supported formats: dot, json", format)
+	}
+	return nil
+}
+
 func listControls(cmd *cobra.Command, args []string) error {
 	for _, tree := range trees {
 		if tree.Err != nil {
@@ -396,6 +456,37 @@ func listModulePars(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func listUnused(cmd *cobra.Command, args []string) error {
+	var files []string
+	for _, tree := range trees {
+		if tree.Err != nil {
+			return tree.Err
+		}
+		files = append(files, tree.Filename())
+	}
+
+	db := &ttcn3.DB{}
+	db.Index(files...)
+	for _, d := range ttcn3.NewRefGraph(db, trees...).Unused() {
+		name := moduleOf(d) + "." + d.Ident.String()
+		tags := doc.FindAllTags(ast.FirstToken(d.Node).Comments())
+		if match(name, tags) {
+			printItem(d.Tree.FileSet, d.Ident.Pos(), tags, name)
+		}
+	}
+	return nil
+}
+
+// moduleOf returns the name of the module containing definition d.
+func moduleOf(d *ttcn3.Definition) string {
+	for _, mod := range d.Tree.Modules() {
+		if mod.Node.Pos() <= d.Ident.Pos() && d.Ident.End() <= mod.Node.End() {
+			return mod.Ident.String()
+		}
+	}
+	return ""
+}
+
 func printItem(fset *loc.FileSet, pos loc.Pos, tags [][]string, fields ...string) {
 
 	s := strings.Join(fields, "\t")
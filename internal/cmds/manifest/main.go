@@ -0,0 +1,67 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "manifest",
+		Short: "Inspect test suite manifests",
+	}
+
+	checkCmd = &cobra.Command{
+		Use:   "check [dir|file]...",
+		Short: "Validate package.yml against the manifest schema",
+		Long: `Validate package.yml against the manifest schema.
+
+check reports unknown fields, values of the wrong type and incomplete imports
+with their line and column. Arguments are test suite directories or manifest
+files. Without arguments the manifest of the current directory is checked.`,
+		RunE: check,
+	}
+
+	schemaCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON schema of package.yml",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Println(project.ManifestSchema)
+		},
+	}
+)
+
+func init() {
+	Command.AddCommand(checkCmd, schemaCmd)
+}
+
+func check(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	failed := false
+	for _, path := range args {
+		if fs.IsDir(path) {
+			path = filepath.Join(path, project.ManifestFile)
+		}
+		b, err := fs.Content(path)
+		if err != nil {
+			return err
+		}
+		for _, e := range project.ValidateManifest(path, b) {
+			fmt.Fprintln(os.Stderr, e)
+			failed = true
+		}
+	}
+	if failed {
+		return fmt.Errorf("manifest check failed")
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nokia/ntt/ttcn3/metrics"
+)
+
+// A formatter writes the metrics of modules to w.
+type formatter func(w io.Writer, mods []*metrics.Module) error
+
+var formats = map[string]formatter{
+	"json": formatJSON,
+	"csv":  formatCSV,
+	"html": formatHTML,
+}
+
+func formatNames() string {
+	var names []string
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func formatJSON(w io.Writer, mods []*metrics.Module) error {
+	if mods == nil {
+		mods = []*metrics.Module{}
+	}
+	for _, m := range mods {
+		if m.Behaviours == nil {
+			m.Behaviours = []*metrics.Metrics{}
+		}
+	}
+	b, err := json.MarshalIndent(mods, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
+
+var csvHeader = []string{"kind", "module", "name", "file", "line", "loc", "complexity", "nesting", "params", "fan_in", "fan_out", "alts", "templates"}
+
+func formatCSV(w io.Writer, mods []*metrics.Module) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	row := func(m *metrics.Metrics) {
+		cw.Write([]string{
+			m.Kind, m.Module, m.Name, m.File,
+			strconv.Itoa(m.Line),
+			strconv.Itoa(m.LOC),
+			strconv.Itoa(m.Complexity),
+			strconv.Itoa(m.Nesting),
+			strconv.Itoa(m.Params),
+			strconv.Itoa(m.FanIn),
+			strconv.Itoa(m.FanOut),
+			strconv.Itoa(m.Alts),
+			strconv.Itoa(m.Templates),
+		})
+	}
+	for _, m := range mods {
+		row(&m.Metrics)
+		for _, b := range m.Behaviours {
+			row(b)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Size of the treemap in pixels.
+const (
+	treemapWidth  = 1200
+	treemapHeight = 800
+)
+
+// A tile is a rectangle of the treemap.
+type tile struct {
+	X, Y, W, H float64
+	Label      string
+	Title      string
+	Color      string
+}
+
+func formatHTML(w io.Writer, mods []*metrics.Module) error {
+	var tiles []tile
+
+	sizes := make([]float64, len(mods))
+	for i, m := range mods {
+		sizes[i] = float64(m.LOC)
+	}
+	for i, r := range squarify(sizes, rect{0, 0, treemapWidth, treemapHeight}) {
+		m := mods[i]
+		tiles = append(tiles, tile{
+			X: r.X, Y: r.Y, W: r.W, H: r.H,
+			Label: m.Name,
+			Title: describe(&m.Metrics),
+			Color: "#ddd",
+		})
+
+		sizes := make([]float64, len(m.Behaviours))
+		for j, b := range m.Behaviours {
+			sizes[j] = float64(b.LOC)
+		}
+		for j, r := range squarify(sizes, r.inset(2, 16)) {
+			b := m.Behaviours[j]
+			tiles = append(tiles, tile{
+				X: r.X, Y: r.Y, W: r.W, H: r.H,
+				Label: b.Name,
+				Title: describe(b),
+				Color: color(b.Complexity),
+			})
+		}
+	}
+
+	return htmlTemplate.Execute(w, struct {
+		Width, Height int
+		Tiles         []tile
+	}{treemapWidth, treemapHeight, tiles})
+}
+
+func describe(m *metrics.Metrics) string {
+	return fmt.Sprintf("%s %s.%s (%s:%d)\nloc: %d\ncomplexity: %d\nnesting: %d\nparams: %d\nfan_in: %d\nfan_out: %d\nalts: %d\ntemplates: %d",
+		m.Kind, m.Module, m.Name, m.File, m.Line, m.LOC, m.Complexity, m.Nesting, m.Params, m.FanIn, m.FanOut, m.Alts, m.Templates)
+}
+
+// color returns a colour from green (low complexity) over yellow to red
+// (complexity of 20 and more).
+func color(complexity int) string {
+	scale := [][3]float64{{0x63, 0xbe, 0x7b}, {0xff, 0xeb, 0x84}, {0xf8, 0x69, 0x6b}}
+	t := float64(complexity) / 10
+	if t > 2 {
+		t = 2
+	}
+	i := int(t)
+	if i == 2 {
+		i, t = 1, 1
+	} else {
+		t -= float64(i)
+	}
+	var c [3]int
+	for k := range c {
+		c[k] = int(scale[i][k] + t*(scale[i+1][k]-scale[i][k]) + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", c[0], c[1], c[2])
+}
+
+var htmlTemplate = template.Must(template.New("metrics").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>ntt metrics</title>
+<style>
+body { font-family: sans-serif; }
+.map { position: relative; width: {{.Width}}px; height: {{.Height}}px; }
+.tile { position: absolute; box-sizing: border-box; border: 1px solid #fff; overflow: hidden; font-size: 11px; padding: 1px 2px; }
+</style>
+</head>
+<body>
+<p>Area is lines of code, colour is cyclomatic complexity (green is low, red is 20 and more).</p>
+<div class="map">
+{{- range .Tiles}}
+<div class="tile" style="left: {{printf "%.1f" .X}}px; top: {{printf "%.1f" .Y}}px; width: {{printf "%.1f" .W}}px; height: {{printf "%.1f" .H}}px; background: {{.Color}};" title="{{.Title}}">{{.Label}}</div>
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+type rect struct {
+	X, Y, W, H float64
+}
+
+// inset returns r shrunk by d on each side and additionally by top at the top.
+// The result is empty if r is too small.
+func (r rect) inset(d, top float64) rect {
+	r = rect{r.X + d, r.Y + d + top, r.W - 2*d, r.H - 2*d - top}
+	if r.W < 0 || r.H < 0 {
+		return rect{r.X, r.Y, 0, 0}
+	}
+	return r
+}
+
+// squarify divides r into rectangles with areas proportional to sizes, using
+// the squarified treemap algorithm by Bruls, Huizing and van Wijk. The result
+// has the same order as sizes.
+func squarify(sizes []float64, r rect) []rect {
+	result := make([]rect, len(sizes))
+
+	var total float64
+	idx := make([]int, len(sizes))
+	for i, s := range sizes {
+		idx[i] = i
+		total += s
+	}
+	if total <= 0 || r.W <= 0 || r.H <= 0 {
+		return result
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return sizes[idx[i]] > sizes[idx[j]] })
+
+	scale := r.W * r.H / total
+	area := func(i int) float64 { return sizes[i] * scale }
+
+	// worst returns the highest aspect ratio of a row laid out along a side
+	// of length side.
+	worst := func(row []int, side float64) float64 {
+		var sum, max float64
+		min := area(row[0])
+		for _, i := range row {
+			a := area(i)
+			sum += a
+			if a > max {
+				max = a
+			}
+			if a < min {
+				min = a
+			}
+		}
+		if min == 0 {
+			min = sum / float64(len(row)) / 1e9
+		}
+		return maxf(side*side*max/(sum*sum), sum*sum/(side*side*min))
+	}
+
+	// layout places row along the shorter side of r and returns the
+	// remaining rectangle.
+	layout := func(row []int, r rect) rect {
+		var sum float64
+		for _, i := range row {
+			sum += area(i)
+		}
+		if sum == 0 {
+			return r
+		}
+		if r.W >= r.H {
+			w := sum / r.H
+			y := r.Y
+			for _, i := range row {
+				h := area(i) / w
+				result[i] = rect{r.X, y, w, h}
+				y += h
+			}
+			return rect{r.X + w, r.Y, r.W - w, r.H}
+		}
+		h := sum / r.W
+		x := r.X
+		for _, i := range row {
+			w := area(i) / h
+			result[i] = rect{x, r.Y, w, h}
+			x += w
+		}
+		return rect{r.X, r.Y + h, r.W, r.H - h}
+	}
+
+	var row []int
+	for k := 0; k < len(idx); {
+		side := r.W
+		if r.H < side {
+			side = r.H
+		}
+		next := append(row[:len(row):len(row)], idx[k])
+		if len(row) == 0 || worst(next, side) <= worst(row, side) {
+			row = next
+			k++
+			continue
+		}
+		r = layout(row, r)
+		row = nil
+	}
+	if len(row) > 0 {
+		layout(row, r)
+	}
+	return result
+}
+
+func maxf(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
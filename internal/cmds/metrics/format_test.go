@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/ttcn3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testModules = []*metrics.Module{
+	{
+		Metrics: metrics.Metrics{Kind: "module", Module: "A", Name: "A", File: "a.ttcn3", Line: 1, LOC: 30, Complexity: 4, FanOut: 1, Templates: 2},
+		Behaviours: []*metrics.Metrics{
+			{Kind: "testcase", Module: "A", Name: "tc", File: "a.ttcn3", Line: 2, LOC: 20, Complexity: 3, Nesting: 1, Alts: 2},
+			{Kind: "function", Module: "A", Name: "f", File: "a.ttcn3", Line: 23, LOC: 5, Complexity: 1, Params: 2, FanIn: 1},
+		},
+	},
+	{
+		Metrics: metrics.Metrics{Kind: "module", Module: "B", Name: "B", File: "b.ttcn3", Line: 1, LOC: 10, FanIn: 1},
+	},
+}
+
+func render(t *testing.T, f formatter) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.Nil(t, f(&buf, testModules))
+	return buf.String()
+}
+
+func TestFormatJSON(t *testing.T) {
+	var v []map[string]interface{}
+	require.Nil(t, json.Unmarshal([]byte(render(t, formatJSON)), &v))
+	require.Len(t, v, 2)
+	assert.Equal(t, "A", v[0]["name"])
+	assert.Equal(t, float64(30), v[0]["loc"])
+	assert.Len(t, v[0]["behaviours"], 2)
+	assert.Equal(t, []interface{}{}, v[1]["behaviours"])
+}
+
+func TestFormatCSV(t *testing.T) {
+	assert.Equal(t, `kind,module,name,file,line,loc,complexity,nesting,params,fan_in,fan_out,alts,templates
+module,A,A,a.ttcn3,1,30,4,0,0,0,1,0,2
+testcase,A,tc,a.ttcn3,2,20,3,1,0,0,0,2,0
+function,A,f,a.ttcn3,23,5,1,0,2,1,0,0,0
+module,B,B,b.ttcn3,1,10,0,0,0,1,0,0,0
+`, render(t, formatCSV))
+}
+
+func TestFormatHTML(t *testing.T) {
+	s := render(t, formatHTML)
+	assert.Equal(t, 4, strings.Count(s, `<div class="tile"`))
+	assert.Contains(t, s, `title="testcase A.tc (a.ttcn3:2)`)
+	assert.Contains(t, s, "background: "+color(3)+";")
+}
+
+func TestColor(t *testing.T) {
+	assert.Equal(t, "#63be7b", color(0))
+	assert.Equal(t, "#b1d580", color(5))
+	assert.Equal(t, "#ffeb84", color(10))
+	assert.Equal(t, "#f8696b", color(20))
+	assert.Equal(t, "#f8696b", color(42))
+}
+
+func TestSquarify(t *testing.T) {
+	rs := squarify([]float64{1, 6, 2, 3}, rect{0, 0, 6, 2})
+	var area float64
+	for i, r := range rs {
+		area += r.W * r.H
+		assert.True(t, r.X >= 0 && r.Y >= 0 && r.X+r.W <= 6.0001 && r.Y+r.H <= 2.0001, "%d: %v", i, r)
+	}
+	assert.InDelta(t, 12, area, 0.0001)
+	assert.InDelta(t, 6, rs[1].W*rs[1].H, 0.0001)
+	assert.InDelta(t, 1, rs[0].W*rs[0].H, 0.0001)
+
+	assert.Equal(t, []rect{{}, {}}, squarify([]float64{0, 0}, rect{0, 0, 6, 2}))
+	assert.Empty(t, squarify(nil, rect{0, 0, 6, 2}))
+}
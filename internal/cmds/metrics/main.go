@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/ntt"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/metrics"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "metrics",
+		Short: "Report code metrics of modules and behaviours",
+		Long: `Report code metrics of modules and behaviours.
+
+Metrics are computed for every module of the test suite and for every
+testcase, function, altstep and control part:
+
+    loc         Number of lines, including comments and empty lines
+    complexity  Cyclomatic complexity (McCabe). The complexity of a module is
+                the sum of the complexities of its behaviours.
+    nesting     Maximum depth of nested if, select, loop, alt and interleave
+                statements
+    params      Number of formal parameters
+    fan_in      Number of behaviours calling a behaviour, or number of modules
+                importing a module
+    fan_out     Number of behaviours called by a behaviour, or number of
+                modules imported by a module
+    alts        Number of alt- and interleave branches
+    templates   Number of template definitions
+
+Calls and imports are resolved by name. Files with syntax errors are skipped.
+
+The --format flag selects the output: json (default), csv with one row per
+module and behaviour, or html, a self-contained treemap where the area of a
+behaviour is its size in lines and its colour is its complexity:
+
+    ntt metrics --format html > metrics.html
+`,
+		RunE: run,
+	}
+
+	format string
+)
+
+func init() {
+	Command.Flags().StringVar(&format, "format", "json", "output format ("+formatNames()+")")
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	output, ok := formats[format]
+	if !ok {
+		return fmt.Errorf("unknown format %q. Supported formats: %s", format, formatNames())
+	}
+
+	suite, err := ntt.NewFromArgs(args...)
+	if err != nil {
+		return err
+	}
+	srcs, err := suite.Sources()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(srcs))
+	trees := make([]*ttcn3.Tree, len(srcs))
+	for i, src := range srcs {
+		go func(i int, src string) {
+			defer wg.Done()
+			trees[i] = ttcn3.ParseFile(src)
+		}(i, src)
+	}
+	wg.Wait()
+
+	for _, tree := range trees {
+		if tree.Err != nil {
+			log.Println(tree.Err.Error())
+		}
+	}
+
+	return output(os.Stdout, metrics.Compute(trees...))
+}
@@ -0,0 +1,107 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/nokia/ntt/internal/ntt"
+	"github.com/nokia/ntt/internal/results"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ImportCommand = &cobra.Command{
+		Use:   "import [--from junit|tap|json] file...",
+		Short: "import test results from other test runners",
+		Long: `import test results from other test runners.
+
+The import command converts test results from other tools, like TITAN, into
+ntt runs and appends them as new session to test_results.json. Afterwards the
+results are available to all ntt report templates.
+
+Supported formats are:
+
+  junit:  JUnit XML reports
+  tap:    Test Anything Protocol
+  json:   test_results.json of other ntt runs or a plain list of runs
+
+Tool specific states are mapped to TTCN-3 verdicts. For example JUnit failures
+become "fail" and skipped tests become "none". Use option --verdict to change
+the mapping or to map unknown states, which are reported as error otherwise.
+Subtests of TAP files are not imported. Example:
+
+	ntt report import --from junit --verdict skipped=inconc TITAN_report.xml
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: importResults,
+	}
+
+	importFormat   = "junit"
+	importVerdicts []string
+)
+
+func init() {
+	ImportCommand.Flags().StringVarP(&importFormat, "from", "", "junit", "format of imported files (junit, tap, json)")
+	ImportCommand.Flags().StringSliceVarP(&importVerdicts, "verdict", "", nil, "map a tool specific state to a TTCN-3 verdict (state=verdict)")
+	Command.AddCommand(ImportCommand)
+}
+
+func importResults(cmd *cobra.Command, args []string) error {
+	m := make(results.VerdictMap)
+	for _, s := range importVerdicts {
+		f := strings.SplitN(s, "=", 2)
+		if len(f) != 2 {
+			return fmt.Errorf("invalid verdict mapping %q", s)
+		}
+		m[strings.ToLower(f[0])] = f[1]
+	}
+
+	suite, err := ntt.NewFromArgs()
+	if err != nil {
+		return err
+	}
+	db, err := suite.LatestResults()
+	if err != nil {
+		return err
+	}
+	if db == nil {
+		db = &results.DB{}
+	}
+
+	for _, path := range args {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		runs, err := results.Import(importFormat, f, m)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		// Some formats, like TAP, do not provide timing information. We
+		// use the modification time of the file instead.
+		if info, err := os.Stat(path); err == nil {
+			for i := range runs {
+				if runs[i].Begin.IsZero() {
+					d := runs[i].Duration()
+					runs[i].Begin.Time = info.ModTime()
+					runs[i].End.Time = info.ModTime().Add(d)
+				}
+			}
+		}
+		db.Sessions = append(db.Sessions, results.Session{
+			Id:   path,
+			Runs: runs,
+		})
+	}
+
+	b, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile("test_results.json", b, 0644)
+}
@@ -0,0 +1,206 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxLogLines is the maximum number of log lines per test case included in
+// the system-out element of a JUnit test suite.
+const MaxLogLines = 100
+
+// JUnitTestSuites is the root element of a JUnit XML report. The layout
+// follows the common JUnit XSD used by Jenkins and other CI systems.
+type JUnitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite describes all tests of a single TTCN-3 module.
+type JUnitTestSuite struct {
+	XMLName    xml.Name        `xml:"testsuite"`
+	ID         int             `xml:"id,attr"`
+	Name       string          `xml:"name,attr"`
+	Package    string          `xml:"package,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Hostname   string          `xml:"hostname,attr"`
+	Properties JUnitProperties `xml:"properties"`
+	TestCases  []JUnitTestCase `xml:"testcase"`
+	SystemOut  string          `xml:"system-out"`
+	SystemErr  string          `xml:"system-err"`
+}
+
+// JUnitProperties is the list of properties of a test suite. The schema
+// requires the element even if there are no properties.
+type JUnitProperties struct {
+	Property []JUnitProperty `xml:"property"`
+}
+
+// JUnitProperty is a name-value pair, usually an environment variable.
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitTestCase describes the final result of a single test case.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Error     *JUnitFailure `xml:"error,omitempty"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure describes why a test case did not pass.
+type JUnitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// NewJUnit creates a JUnit report from a collection of test runs. Every module
+// becomes a test suite and env is attached as properties to every test suite.
+// The log excerpts of the test cases are collected in the system-out element
+// of their test suite, because the schema does not allow it per test case.
+func NewJUnit(c Collection, env []string) *JUnitTestSuites {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+
+	var props []JUnitProperty
+	for _, kv := range env {
+		f := strings.SplitN(kv, "=", 2)
+		p := JUnitProperty{Name: f[0]}
+		if len(f) == 2 {
+			p.Value = f[1]
+		}
+		props = append(props, p)
+	}
+
+	modules := c.Modules()
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+
+	ret := &JUnitTestSuites{}
+	for i, m := range modules {
+		tests := m.FixedTests()
+		ts := JUnitTestSuite{
+			ID:         i,
+			Name:       m.Name,
+			Package:    c.Name,
+			Tests:      len(tests),
+			Time:       seconds(tests.Total()),
+			Timestamp:  tests.First().Begin.UTC().Format("2006-01-02T15:04:05"),
+			Hostname:   hostname,
+			Properties: JUnitProperties{props},
+		}
+
+		var out strings.Builder
+		for _, t := range tests {
+			ts.TestCases = append(ts.TestCases, newJUnitTestCase(t))
+			if s := t.LogExcerpt(); s != "" {
+				fmt.Fprintf(&out, "=== %s\n%s\n", t.Name, s)
+			}
+			switch t.Verdict {
+			case "pass", "unstable":
+			case "error":
+				ts.Errors++
+			default:
+				ts.Failures++
+			}
+		}
+		ts.SystemOut = out.String()
+		ret.Suites = append(ret.Suites, ts)
+	}
+	return ret
+}
+
+func newJUnitTestCase(r Run) JUnitTestCase {
+	tc := JUnitTestCase{
+		Name:      r.Testcase(),
+		Classname: r.Module(),
+		Time:      seconds(r.Duration()),
+	}
+
+	if r.Verdict == "pass" || r.Verdict == "unstable" {
+		return tc
+	}
+
+	f := &JUnitFailure{
+		Message: "Verdict: " + r.Verdict,
+		Type:    r.Verdict,
+	}
+	if r.Reason != "" {
+		f.Message += " (" + r.Reason + ")"
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, f.Message)
+	if files, err := r.ReasonFiles(); err == nil {
+		for _, file := range files {
+			fmt.Fprintf(&b, "%s: %s\n", file.Name, file.Content)
+		}
+	}
+	f.Contents = b.String()
+
+	if r.Verdict == "error" {
+		tc.Error = f
+	} else {
+		tc.Failure = f
+	}
+	return tc
+}
+
+// WriteJUnit writes the report r in JUnit XML format to w.
+func WriteJUnit(w io.Writer, r *Report) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(NewJUnit(r.Collection, r.Environ())); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// LogExcerpt returns the last lines of the log files found in the working
+// directory of the run.
+func (r Run) LogExcerpt() string {
+	if r.WorkingDir == "" {
+		return ""
+	}
+	paths, err := filepath.Glob(filepath.Join(r.WorkingDir, "*.log"))
+	if err != nil {
+		return ""
+	}
+
+	var lines []string
+	for _, p := range paths {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		lines = append(lines, strings.Split(strings.TrimRight(string(b), "\n"), "\n")...)
+	}
+	if len(lines) > MaxLogLines {
+		lines = lines[len(lines)-MaxLogLines:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
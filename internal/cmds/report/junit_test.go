@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nokia/ntt/internal/results"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJUnit(t *testing.T) {
+	c, dir := testCollection(t)
+	defer os.RemoveAll(dir)
+
+	j := NewJUnit(*c, []string{"FOO=bar=1", "EMPTY"})
+	if assert.Len(t, j.Suites, 2) {
+		m1 := j.Suites[0]
+		assert.Equal(t, "M1", m1.Name)
+		assert.Equal(t, "suite", m1.Package)
+		assert.Equal(t, "3.000", m1.Time)
+		assert.Equal(t, "2020-01-01T12:00:00", m1.Timestamp)
+		assert.Equal(t, []JUnitProperty{{"FOO", "bar=1"}, {"EMPTY", ""}}, m1.Properties.Property)
+		assert.Equal(t, 2, m1.Tests)
+		assert.Equal(t, 1, m1.Failures)
+		assert.Equal(t, 0, m1.Errors)
+		assert.Equal(t, "=== M1.A\nline1\nline2\n", m1.SystemOut)
+		assert.Contains(t, m1.TestCases[0].Failure.Contents, "a < b & c")
+		assert.Nil(t, m1.TestCases[1].Failure)
+
+		m2 := j.Suites[1]
+		assert.Equal(t, 1, m2.Errors)
+		assert.NotNil(t, m2.TestCases[0].Error)
+		assert.Equal(t, "", m2.SystemOut)
+	}
+
+	b, err := xml.Marshal(j)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "a &lt; b &amp; c")
+	assert.Contains(t, string(b), `errors="1"`)
+
+	b, err = xml.Marshal(NewJUnit(*c, nil))
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "<properties></properties>")
+}
+
+// TestJUnitSchema validates the JUnit output against the vendored common JUnit
+// XSD (testdata/junit.xsd) using xmllint.
+func TestJUnitSchema(t *testing.T) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not found")
+	}
+
+	c, dir := testCollection(t)
+	defer os.RemoveAll(dir)
+
+	for _, env := range [][]string{nil, {"FOO=bar", "EMPTY="}} {
+		b, err := xml.MarshalIndent(NewJUnit(*c, env), "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		file := filepath.Join(dir, "junit.xml")
+		if err := ioutil.WriteFile(file, append([]byte(xml.Header), b...), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := exec.Command(xmllint, "--noout", "--schema", filepath.Join("testdata", "junit.xsd"), file).CombinedOutput()
+		if err != nil {
+			t.Errorf("env %v: %s: %s", env, err, out)
+		}
+	}
+}
+
+// testCollection returns a collection of three runs. The failing run M1.A has
+// a working directory with a reason and a log file, which the caller must
+// remove.
+func testCollection(t *testing.T) (*Collection, string) {
+	dir, err := ioutil.TempDir("", "ntt-junit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "A.reason"), []byte("a < b & c"), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "A.log"), []byte("line1\nline2\n"), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	begin := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	run := func(name string, verdict string, dir string) results.Run {
+		return results.Run{
+			Name:       name,
+			Verdict:    verdict,
+			WorkingDir: dir,
+			Begin:      results.Timestamp{Time: begin},
+			End:        results.Timestamp{Time: begin.Add(1500 * time.Millisecond)},
+		}
+	}
+
+	c := NewCollection("suite",
+		run("M2.C", "error", ""),
+		run("M1.A", "fail", dir),
+		run("M1.B", "pass", ""),
+	)
+	return c, dir
+}
@@ -24,10 +24,14 @@ The report command shows a summary of the latest test run. The summary includes
 information such as a list of tests which did not pass, average run times, CPU
 load, etc.
 Command line options '--json' and '--junit' show similar output, but with JSON
-or JUNIT formatting.
+or JUNIT formatting. The JUnit output follows the common JUnit XSD and includes
+the environment as properties and an excerpt of the test logs as system-out
+of each test suite.
 
 Use environment variable 'NTT_COLORS=never' to disable colors.
 
+Results from other test runners can be added with 'ntt report import'.
+
 Templating
 ----------
 
@@ -81,6 +85,7 @@ Available Objects
   .Run.MaxMem:      the maximum memory used when the test ended
   .Run.Reason:      optional reason for verdicts
   .Run.ReasonFiles: content of *.reason files
+  .Run.LogExcerpt:  last lines of *.log files
   .Run.RunnerID:    the ID of the runner exeuting the run
   .Run.WorkingDir:  working Directory of the test
 
@@ -109,10 +114,6 @@ Summary template:
 ` + SummaryTemplate + `
 
 
-JUnit template:
-` + JUnitTemplate + `
-
-
 JSON template:
 ` + JSONTemplate + `
 
@@ -148,20 +149,6 @@ const (
 {{bold}}==============================================================================={{off}}
 {{bold}}Final Result: {{.Tests.Result | colorize}}{{off}}
 {{bold}}==============================================================================={{off}}
-`
-
-	JUnitTemplate = `<?xml version="1.0" encoding="UTF-8"?>
-<testsuites>{{range .Modules}}
-
-<testsuite name="{{.Name}}" tests="{{len .FixedTests}}" failures="{{len .FixedTests.Failed}}" errors="" time="{{.FixedTests.Total.Seconds}}">
-{{range .FixedTests}}<testcase name="{{.Testcase}}" time="{{.Duration.Seconds}}">
-  {{if and (ne .Verdict "unstable") (ne .Verdict "pass")}}<failure>Verdict: {{.Verdict}} {{with .Reason}}({{. | html }}){{end}}
-{{range .ReasonFiles}}{{.Name}}: {{.Content}}{{end}}
-  </failure>
-{{end}}</testcase>
-
-{{end}}</testsuite>
-{{end}}</testsuites>
 `
 
 	JSONTemplate = `{
@@ -208,7 +195,11 @@ func report(cmd *cobra.Command, args []string) error {
 	case useJSON:
 		templateText = JSONTemplate
 	case useJUnit:
-		templateText = JUnitTemplate
+		r, err := NewReport(suite)
+		if err != nil {
+			return err
+		}
+		return WriteJUnit(os.Stdout, r)
 	}
 
 	if templateText == "" {
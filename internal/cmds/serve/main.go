@@ -0,0 +1,102 @@
+package serve
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/nokia/ntt/codec"
+	"github.com/nokia/ntt/internal/generator"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/ntt"
+	"github.com/nokia/ntt/runner"
+	"github.com/nokia/ntt/runner/k3s"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Command = &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the Runtime and Codec gRPC services",
+		Long: `Serve the Runtime and Codec gRPC services.
+
+The serve command builds the test suite and serves the Runtime service defined
+in protobuf/runtime.proto. Clients run tests with Runtime.Run. The parameters
+of a run request override module parameters of the same qualified name, like
+"M.p". The output of the test is streamed back while the test is running.
+The last response carries the verdict and the out and inout parameters.
+Their values are read back from the parameters file passed to the test with
+NTT_PARAMETERS_FILE; the last assignment of a parameter wins.
+
+The Codec service is served on the same address, see 'ntt codec'.
+
+The listen address is either a Unix socket path or a TCP address like
+"localhost:8080".
+
+Example:
+
+	ntt serve --listen localhost:8080
+`,
+		RunE: serve,
+	}
+
+	listen = "ntt.sock"
+)
+
+func init() {
+	Command.Flags().StringVarP(&listen, "listen", "l", listen, "listen on Unix socket path or TCP `address`")
+}
+
+func serve(cmd *cobra.Command, args []string) error {
+	suite, err := ntt.NewFromArgs(args...)
+	if err != nil {
+		return err
+	}
+	files, err := suite.Files()
+	if err != nil {
+		return err
+	}
+
+	trees := make([]*ttcn3.Tree, 0, len(files))
+	for _, file := range files {
+		trees = append(trees, ttcn3.ParseFile(file))
+	}
+
+	// Module parameters with errors are still usable. They only fail when
+	// referencing undefined types.
+	req, err := generator.NewRequest(trees...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
+
+	r, err := k3s.New(os.Stderr, suite)
+	if err != nil {
+		return err
+	}
+
+	l, err := listener(listen)
+	if err != nil {
+		return err
+	}
+	if l.Addr().Network() == "unix" {
+		defer os.Remove(listen)
+	}
+
+	s := runner.NewServer(r, req.Modules...)
+	g := codec.NewServer(s.Types)
+	s.Register(g)
+
+	log.Verbosef("serve: listening on %s\n", l.Addr())
+	return g.Serve(l)
+}
+
+// listener returns a TCP listener if addr contains a port and a Unix socket
+// listener otherwise.
+func listener(addr string) (net.Listener, error) {
+	if strings.Contains(addr, ":") && !strings.ContainsRune(addr, os.PathSeparator) {
+		return net.Listen("tcp", addr)
+	}
+	return codec.Listen(addr)
+}
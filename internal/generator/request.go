@@ -0,0 +1,650 @@
+// Package generator implements the protocol between ttcn3c and its generator
+// plugins.
+//
+// ttcn3c parses and resolves the TTCN-3 sources and sends a GeneratorRequest
+// to the plugin. The request describes every module with its types,
+// constants, templates, module parameters and behaviour signatures. Type
+// references are resolved with package types and qualified by the name of
+// the module they are defined in, for example "M.T".
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/printer"
+	"github.com/nokia/ntt/ttcn3/token"
+	"github.com/nokia/ntt/types"
+)
+
+// Version is the version of the generator protocol.
+var Version = pb.Version{Major: 1}
+
+// NewRequest returns a generator request describing all modules found in
+// trees. Modules defined in multiple trees are merged into a single module.
+// Syntax errors and unresolved type references are returned as multierror,
+// together with the request built from the remaining definitions.
+func NewRequest(trees ...*ttcn3.Tree) (*pb.GeneratorRequest, error) {
+	b := &builder{
+		global:  &global{names: make(map[string]types.Object)},
+		info:    &types.Info{},
+		modules: make(map[string]*pb.Module),
+	}
+
+	for _, tree := range trees {
+		if tree.Err != nil {
+			b.errs = multierror.Append(b.errs, tree.Err)
+			continue
+		}
+		// Errors of the type system are not reported. Unsupported
+		// definitions show up as unresolved references below.
+		b.info.InsertTree(tree.Root, b.global)
+	}
+
+	req := &pb.GeneratorRequest{
+		Version: &pb.Version{Major: Version.Major, Minor: Version.Minor, Patch: Version.Patch},
+	}
+	for _, tree := range trees {
+		if tree.Err != nil {
+			continue
+		}
+		b.tree = tree
+		ast.Inspect(tree.Root, func(n ast.Node) bool {
+			m, ok := n.(*ast.Module)
+			if !ok {
+				return true
+			}
+			if mod := b.module(m); mod != nil {
+				req.Modules = append(req.Modules, mod)
+			}
+			return false
+		})
+	}
+	return req, b.errs.ErrorOrNil()
+}
+
+// global is the scope all modules are inserted into.
+type global struct {
+	names map[string]types.Object
+}
+
+func (g *global) EnclosingScope() types.Scope { return nil }
+func (g *global) Lookup(name string) types.Object {
+	return g.names[name]
+}
+
+func (g *global) Insert(name string, obj types.Object) types.Object {
+	if alt, ok := g.names[name]; ok {
+		return alt
+	}
+	g.names[name] = obj
+	return nil
+}
+
+func (g *global) Names() []string {
+	var names []string
+	for name := range g.names {
+		names = append(names, name)
+	}
+	return names
+}
+
+type builder struct {
+	global  *global
+	info    *types.Info
+	modules map[string]*pb.Module
+
+	tree  *ttcn3.Tree
+	mod   *pb.Module
+	scope types.Scope // Scope of the current module
+	errs  *multierror.Error
+}
+
+// module returns the description of module n. If the module was already seen
+// in another tree, the definitions are appended to the existing description
+// and nil is returned.
+func (b *builder) module(n *ast.Module) *pb.Module {
+	name := n.Name.String()
+	b.scope, _ = b.global.Lookup(name).(*types.Module)
+	if b.scope == nil {
+		b.scope = b.global
+	}
+
+	mod, seen := b.modules[name]
+	if !seen {
+		mod = &pb.Module{
+			Name:     name,
+			Location: b.location(n),
+			Comment:  n.Tok.Comments(),
+		}
+		b.modules[name] = mod
+	}
+	b.mod = mod
+	b.defs(n.Defs, b.attrs(nil, n.With))
+	if seen {
+		return nil
+	}
+	return mod
+}
+
+func (b *builder) defs(defs []*ast.ModuleDef, inherited *attrs) {
+	for _, d := range defs {
+		comment := ast.FirstToken(d).Comments()
+		switch n := d.Def.(type) {
+		case *ast.GroupDecl:
+			b.defs(n.Defs, b.attrs(inherited, n.With))
+
+		case *ast.ImportDecl:
+			b.imports(n.Module.String())
+
+		case *ast.SubTypeDecl:
+			if n.Field == nil || n.Field.Name == nil {
+				continue
+			}
+			t := b.field(n.Field).Type
+			t.Name = n.Field.Name.String()
+			b.addType(t, n, comment, inherited, n.With)
+
+		case *ast.StructTypeDecl:
+			t := b.structSpec(n.Kind.Kind, n.Fields)
+			t.Name = n.Name.String()
+			b.addType(t, n, comment, inherited, n.With)
+
+		case *ast.EnumTypeDecl:
+			t := b.enumSpec(n.Enums)
+			t.Name = n.Name.String()
+			b.addType(t, n, comment, inherited, n.With)
+
+		case *ast.ComponentTypeDecl:
+			t := b.component(n)
+			t.Name = n.Name.String()
+			b.addType(t, n, comment, inherited, n.With)
+
+		case *ast.PortTypeDecl:
+			t := b.port(n)
+			t.Name = n.Name.String()
+			b.addType(t, n, comment, inherited, n.With)
+
+		case *ast.ValueDecl:
+			b.valueDecl(n, comment)
+
+		case *ast.ModuleParameterGroup:
+			for _, vd := range n.Decls {
+				b.valueDecl(vd, comment)
+			}
+
+		case *ast.TemplateDecl:
+			b.mod.Templates = append(b.mod.Templates, &pb.Definition{
+				Name:       n.Name.String(),
+				Location:   b.location(n.Name),
+				Comment:    comment,
+				Type:       b.typ(n.Type),
+				Value:      b.text(n.Value),
+				Parameters: b.formalPars(n.Params),
+			})
+
+		case *ast.FuncDecl:
+			b.mod.Behaviours = append(b.mod.Behaviours, b.behaviour(n, comment))
+		}
+	}
+}
+
+func (b *builder) imports(name string) {
+	for _, imp := range b.mod.Imports {
+		if imp == name {
+			return
+		}
+	}
+	b.mod.Imports = append(b.mod.Imports, name)
+}
+
+func (b *builder) addType(t *pb.Type, n ast.Node, comment string, inherited *attrs, with *ast.WithSpec) {
+	a := b.attrs(inherited, with)
+	t.Encode = a.encode
+	t.Variant = a.variant
+	t.Extension = a.extension
+	t.Location = b.location(n)
+	t.Comment = comment
+	b.mod.Types = append(b.mod.Types, t)
+}
+
+func (b *builder) valueDecl(n *ast.ValueDecl, comment string) {
+	var defs *[]*pb.Definition
+	switch n.Kind.Kind {
+	case token.CONST:
+		defs = &b.mod.Constants
+	case token.MODULEPAR:
+		defs = &b.mod.ModuleParameters
+	case token.TEMPLATE:
+		defs = &b.mod.Templates
+	default:
+		return
+	}
+
+	typ := b.typ(n.Type)
+	for _, d := range n.Decls {
+		*defs = append(*defs, &pb.Definition{
+			Name:     d.Name.String(),
+			Location: b.location(d.Name),
+			Comment:  comment,
+			Type:     b.arrays(typ, d.ArrayDef),
+			Value:    b.text(d.Value),
+		})
+	}
+}
+
+func (b *builder) behaviour(n *ast.FuncDecl, comment string) *pb.Behaviour {
+	f := &pb.Behaviour{
+		Name:       n.Name.String(),
+		Location:   b.location(n.Name),
+		Comment:    comment,
+		External:   n.External.IsValid(),
+		Parameters: b.formalPars(n.Params),
+	}
+	switch n.Kind.Kind {
+	case token.ALTSTEP:
+		f.Kind = pb.Behaviour_ALTSTEP
+	case token.TESTCASE:
+		f.Kind = pb.Behaviour_TESTCASE
+	}
+	if n.Return != nil {
+		f.ReturnType = b.typ(n.Return.Type)
+		f.ReturnTemplate = n.Return.Restriction != nil
+	}
+	if n.RunsOn != nil {
+		f.RunsOn = b.typ(n.RunsOn.Comp)
+	}
+	if n.Mtc != nil {
+		f.Mtc = b.typ(n.Mtc.Comp)
+	}
+	if n.System != nil {
+		f.System = b.typ(n.System.Comp)
+	}
+	return f
+}
+
+func (b *builder) formalPars(n *ast.FormalPars) []*pb.FormalParameter {
+	if n == nil {
+		return nil
+	}
+	var pars []*pb.FormalParameter
+	for _, p := range n.List {
+		par := &pb.FormalParameter{
+			Name:         p.Name.String(),
+			Type:         b.arrays(b.typ(p.Type), p.ArrayDef),
+			Template:     p.TemplateRestriction != nil,
+			DefaultValue: b.text(p.Value),
+		}
+		switch p.Direction.Kind {
+		case token.OUT:
+			par.Direction = pb.Parameter_OUT
+		case token.INOUT:
+			par.Direction = pb.Parameter_INOUT
+		}
+		pars = append(pars, par)
+	}
+	return pars
+}
+
+func (b *builder) component(n *ast.ComponentTypeDecl) *pb.Type {
+	c := &pb.ComponentType{}
+	for _, x := range n.Extends {
+		c.Extends = append(c.Extends, b.typ(x))
+	}
+	if n.Body != nil {
+		for _, s := range n.Body.Stmts {
+			ds, ok := s.(*ast.DeclStmt)
+			if !ok {
+				continue
+			}
+			vd, ok := ds.Decl.(*ast.ValueDecl)
+			if !ok {
+				continue
+			}
+			typ := &pb.Type{Kind: &pb.Type_Timer{Timer: &pb.TimerType{}}}
+			if vd.Type != nil {
+				typ = b.typ(vd.Type)
+			}
+			for _, d := range vd.Decls {
+				c.Definitions = append(c.Definitions, &pb.Field{
+					Name: d.Name.String(),
+					Type: b.arrays(typ, d.ArrayDef),
+				})
+			}
+		}
+	}
+	return &pb.Type{Kind: &pb.Type_Component{Component: c}}
+}
+
+func (b *builder) port(n *ast.PortTypeDecl) *pb.Type {
+	p := &pb.PortType{}
+	switch n.Kind.Kind {
+	case token.PROCEDURE:
+		p.Kind = pb.PortType_PROCEDURE
+	case token.MIXED:
+		p.Kind = pb.PortType_MIXED
+	}
+	for _, attr := range n.Attrs {
+		a, ok := attr.(*ast.PortAttribute)
+		if !ok {
+			continue
+		}
+		var list *[]*pb.Type
+		switch a.Kind.Kind {
+		case token.IN:
+			list = &p.In
+		case token.OUT:
+			list = &p.Out
+		case token.INOUT:
+			list = &p.Inout
+		default:
+			continue
+		}
+		for _, x := range a.Types {
+			*list = append(*list, b.typ(x))
+		}
+	}
+	return &pb.Type{Kind: &pb.Type_Port{Port: p}}
+}
+
+// field returns the description of a record member or sub-type definition,
+// including array dimensions and constraints.
+func (b *builder) field(n *ast.Field) *pb.Field {
+	t := b.arrays(b.typeSpec(n.Type), n.ArrayDef)
+	if n.ValueConstraint != nil || n.LengthConstraint != nil {
+		c := &pb.Constraint{}
+		if n.ValueConstraint != nil {
+			for _, x := range n.ValueConstraint.List {
+				if bounds := b.bounds(x); bounds != nil {
+					c.Ranges = append(c.Ranges, bounds)
+				}
+			}
+		}
+		if n.LengthConstraint != nil {
+			c.Length = b.length(n.LengthConstraint)
+		}
+		if c.Length != nil || len(c.Ranges) > 0 {
+			t.Constraint = c
+		}
+	}
+	return &pb.Field{
+		Name:     n.Name.String(),
+		Type:     t,
+		Optional: n.Optional.IsValid(),
+	}
+}
+
+func (b *builder) typeSpec(n ast.TypeSpec) *pb.Type {
+	switch n := n.(type) {
+	case *ast.RefSpec:
+		return b.typ(n.X)
+	case *ast.StructSpec:
+		return b.structSpec(n.Kind.Kind, n.Fields)
+	case *ast.EnumSpec:
+		return b.enumSpec(n.Enums)
+	case *ast.ListSpec:
+		l := &pb.ListType{Element: b.typeSpec(n.ElemType)}
+		if n.Length != nil {
+			l.Constraint = &pb.Constraint{Length: b.length(n.Length)}
+		}
+		if n.Kind.Kind == token.SET {
+			return &pb.Type{Kind: &pb.Type_SetOf{SetOf: l}}
+		}
+		return &pb.Type{Kind: &pb.Type_RecordOf{RecordOf: l}}
+	}
+	b.errorf(n, "unsupported type specification")
+	return &pb.Type{}
+}
+
+func (b *builder) structSpec(kind token.Kind, fields []*ast.Field) *pb.Type {
+	s := &pb.StructType{}
+	for _, f := range fields {
+		s.Fields = append(s.Fields, b.field(f))
+	}
+	switch kind {
+	case token.SET:
+		return &pb.Type{Kind: &pb.Type_Set{Set: s}}
+	case token.UNION:
+		return &pb.Type{Kind: &pb.Type_Union{Union: s}}
+	default:
+		return &pb.Type{Kind: &pb.Type_Record{Record: s}}
+	}
+}
+
+// enumSpec assigns values to enumerated items. Items without explicit value
+// get the smallest non-negative integer not used by other items.
+func (b *builder) enumSpec(enums []ast.Expr) *pb.Type {
+	used := make(map[int64]bool)
+	for _, e := range enums {
+		if c, ok := e.(*ast.CallExpr); ok && c.Args != nil && len(c.Args.List) == 1 {
+			if v, ok := intValue(c.Args.List[0]); ok {
+				used[v] = true
+			}
+		}
+	}
+
+	var next int64
+	t := &pb.EnumType{}
+	for _, e := range enums {
+		item := &pb.EnumType_Enum{Name: ast.Name(e)}
+		if c, ok := e.(*ast.CallExpr); ok && c.Args != nil && len(c.Args.List) == 1 {
+			if v, ok := intValue(c.Args.List[0]); ok {
+				item.Value = v
+				t.Values = append(t.Values, item)
+				continue
+			}
+		}
+		for used[next] {
+			next++
+		}
+		item.Value = next
+		used[next] = true
+		t.Values = append(t.Values, item)
+	}
+	return &pb.Type{Kind: &pb.Type_Enumerated{Enumerated: t}}
+}
+
+// typ returns the description of a type expression. Predefined types are
+// described directly, named types by a qualified reference.
+func (b *builder) typ(n ast.Expr) *pb.Type {
+	if n == nil {
+		return nil
+	}
+
+	name := ast.Name(n)
+	if t, ok := predefined(name); ok {
+		return t
+	}
+
+	obj := types.Resolve(&types.Ref{Expr: n, Scp: b.scope})
+	var scp types.Scope
+	switch obj := obj.(type) {
+	case *types.NamedType:
+		name, scp = obj.Name, obj.Scope
+	case *types.Func:
+		name, scp = obj.Name, obj.Scope
+	default:
+		b.errorf(n, "undefined type: %s", name)
+		return &pb.Type{Kind: &pb.Type_Reference{Reference: name}}
+	}
+	if m, ok := scp.(*types.Module); ok {
+		name = m.Name + "." + name
+	}
+	return &pb.Type{Kind: &pb.Type_Reference{Reference: name}}
+}
+
+func predefined(name string) (*pb.Type, bool) {
+	scalar := func(s pb.ScalarType) *pb.Type {
+		return &pb.Type{Kind: &pb.Type_Scalar{Scalar: s}}
+	}
+	switch name {
+	case "boolean":
+		return scalar(pb.ScalarType_BOOLEAN), true
+	case "integer":
+		return scalar(pb.ScalarType_INTEGER), true
+	case "float":
+		return scalar(pb.ScalarType_FLOAT), true
+	case "verdicttype":
+		return scalar(pb.ScalarType_VERDICT), true
+	case "default":
+		return scalar(pb.ScalarType_DEFAULT), true
+	case "bitstring":
+		return &pb.Type{Kind: &pb.Type_Bitstring{Bitstring: &pb.StringType{ElementWidth: 1}}}, true
+	case "hexstring":
+		return &pb.Type{Kind: &pb.Type_Hextstring{Hextstring: &pb.StringType{ElementWidth: 4}}}, true
+	case "octetstring":
+		return &pb.Type{Kind: &pb.Type_Octetstring{Octetstring: &pb.StringType{ElementWidth: 8}}}, true
+	case "charstring":
+		return &pb.Type{Kind: &pb.Type_Charstring{Charstring: &pb.StringType{ElementWidth: 8}}}, true
+	case "universal charstring":
+		return &pb.Type{Kind: &pb.Type_UniversalCharstring{UniversalCharstring: &pb.StringType{ElementWidth: 32}}}, true
+	case "anytype":
+		return &pb.Type{Kind: &pb.Type_Anytype{Anytype: &pb.StructType{}}}, true
+	case "timer":
+		return &pb.Type{Kind: &pb.Type_Timer{Timer: &pb.TimerType{}}}, true
+	}
+	return nil, false
+}
+
+// arrays wraps typ into array types. The innermost dimension is the last
+// one: integer a[2][3] is an array of two arrays of three integers. Array
+// dimensions are described by a length constraint; index ranges like [1..3]
+// by a range constraint.
+func (b *builder) arrays(typ *pb.Type, dims []*ast.ParenExpr) *pb.Type {
+	for i := len(dims) - 1; i >= 0; i-- {
+		l := &pb.ListType{Element: typ, Constraint: &pb.Constraint{}}
+		if len(dims[i].List) == 1 {
+			x := dims[i].List[0]
+			if v, ok := intValue(x); ok {
+				l.Constraint.Length = &pb.Constraint_Bounds{Start: v, End: v}
+			} else if r := b.bounds(x); r != nil {
+				l.Constraint.Ranges = append(l.Constraint.Ranges, r)
+			}
+		}
+		typ = &pb.Type{Kind: &pb.Type_Array{Array: l}}
+	}
+	return typ
+}
+
+// length returns the bounds of a length constraint, or nil if the bounds are
+// not constant.
+func (b *builder) length(n *ast.LengthExpr) *pb.Constraint_Bounds {
+	if n.Size == nil || len(n.Size.List) != 1 {
+		return nil
+	}
+	return b.bounds(n.Size.List[0])
+}
+
+// bounds returns the bounds of a single value or a range expression like
+// (0..infinity). It returns nil if the bounds are not constant.
+func (b *builder) bounds(x ast.Expr) *pb.Constraint_Bounds {
+	if v, ok := intValue(x); ok {
+		return &pb.Constraint_Bounds{Start: v, End: v}
+	}
+
+	r, ok := x.(*ast.BinaryExpr)
+	if !ok || r.Op.Kind != token.RANGE {
+		return nil
+	}
+	start, ok := intValue(r.X)
+	if !ok {
+		return nil
+	}
+	if ast.Name(r.Y) == "infinity" {
+		return &pb.Constraint_Bounds{Start: start, Infinite: true}
+	}
+	end, ok := intValue(r.Y)
+	if !ok {
+		return nil
+	}
+	return &pb.Constraint_Bounds{Start: start, End: end}
+}
+
+// intValue returns the value of integer literals and negated integer
+// literals.
+func intValue(x ast.Expr) (int64, bool) {
+	switch x := x.(type) {
+	case *ast.ValueLiteral:
+		if x.Tok.Kind == token.INT {
+			v, err := strconv.ParseInt(x.Tok.Lit, 10, 64)
+			return v, err == nil
+		}
+	case *ast.UnaryExpr:
+		if x.Op.Kind == token.SUB {
+			v, ok := intValue(x.X)
+			return -v, ok
+		}
+	case *ast.ParenExpr:
+		if len(x.List) == 1 {
+			return intValue(x.List[0])
+		}
+	}
+	return 0, false
+}
+
+// attrs are the encoding attributes of a definition.
+type attrs struct {
+	encode, variant, extension string
+}
+
+// attrs returns the attributes of with. Encode attributes are inherited from
+// the enclosing group or module.
+func (b *builder) attrs(inherited *attrs, with *ast.WithSpec) *attrs {
+	a := &attrs{}
+	if inherited != nil {
+		a.encode = inherited.encode
+	}
+	if with == nil {
+		return a
+	}
+	for _, s := range with.List {
+		// Attributes for specific fields, like variant (f) "...",
+		// are not supported.
+		if len(s.List) > 0 {
+			continue
+		}
+		v, err := strconv.Unquote(b.text(s.Value))
+		if err != nil {
+			continue
+		}
+		switch s.Kind.Kind {
+		case token.ENCODE:
+			a.encode = v
+		case token.VARIANT:
+			a.variant = v
+		case token.EXTENSION:
+			a.extension = v
+		}
+	}
+	return a
+}
+
+// text returns the source text of n. It returns an empty string if n is nil.
+func (b *builder) text(n ast.Node) string {
+	if n == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	printer.Print(&buf, b.tree.FileSet, n)
+	return strings.TrimSpace(buf.String())
+}
+
+func (b *builder) location(n ast.Node) *pb.Location {
+	pos := b.tree.Position(n.Pos())
+	return &pb.Location{
+		File:   b.tree.Filename(),
+		Line:   int32(pos.Line),
+		Column: int32(pos.Column),
+	}
+}
+
+func (b *builder) errorf(n ast.Node, format string, args ...interface{}) {
+	err := fmt.Errorf("%s: %s", b.tree.Position(n.Pos()), fmt.Sprintf(format, args...))
+	b.errs = multierror.Append(b.errs, err)
+}
@@ -0,0 +1,156 @@
+package generator_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nokia/ntt/internal/generator"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(t *testing.T, srcs ...string) *pb.GeneratorRequest {
+	t.Helper()
+	var trees []*ttcn3.Tree
+	for _, src := range srcs {
+		trees = append(trees, ttcn3.Parse(src))
+	}
+	req, err := generator.NewRequest(trees...)
+	require.Nil(t, err)
+	return req
+}
+
+func TestRequestTypes(t *testing.T) {
+	req := newRequest(t, `
+		module A {
+			import from B all;
+
+			// R is a record.
+			type record R {
+				B.I a,
+				I b optional,
+				charstring c length(1..infinity)
+			}
+			type set of integer S length(3);
+			type integer Arr[2][1..3];
+			type enumerated E { e1, e2(0), e3 }
+			type component C { var R v; timer t; port P p }
+		} with { encode "JSON" }
+
+		module B {
+			type integer I (0..255);
+			type port P message { in integer; out charstring }
+		}`)
+
+	require.Len(t, req.Modules, 2)
+	a := req.Modules[0]
+	assert.Equal(t, "A", a.Name)
+	assert.Equal(t, []string{"B"}, a.Imports)
+	require.Len(t, a.Types, 5)
+
+	R := a.Types[0]
+	assert.Equal(t, "R", R.Name)
+	assert.Equal(t, "JSON", R.Encode)
+	assert.Equal(t, "// R is a record.\n", R.Comment)
+	assert.Equal(t, int32(6), R.Location.Line)
+	fields := R.GetRecord().Fields
+	require.Len(t, fields, 3)
+	assert.Equal(t, "B.I", fields[0].Type.GetReference())
+	assert.Equal(t, "B.I", fields[1].Type.GetReference())
+	assert.True(t, fields[1].Optional)
+	assert.Equal(t, int32(8), fields[2].Type.GetCharstring().ElementWidth)
+	assert.Equal(t, int64(1), fields[2].Type.Constraint.Length.Start)
+	assert.True(t, fields[2].Type.Constraint.Length.Infinite)
+
+	S := a.Types[1].GetSetOf()
+	assert.Equal(t, pb.ScalarType_INTEGER, S.Element.GetScalar())
+	assert.Equal(t, int64(3), a.Types[1].Constraint.Length.Start)
+
+	Arr := a.Types[2].GetArray()
+	assert.Equal(t, int64(2), Arr.Constraint.Length.End)
+	assert.Equal(t, int64(3), Arr.Element.GetArray().Constraint.Ranges[0].End)
+
+	var enums []string
+	for _, e := range a.Types[3].GetEnumerated().Values {
+		enums = append(enums, fmt.Sprintf("%s(%d)", e.Name, e.Value))
+	}
+	assert.Equal(t, []string{"e1(1)", "e2(0)", "e3(2)"}, enums)
+
+	C := a.Types[4].GetComponent()
+	require.Len(t, C.Definitions, 3)
+	assert.Equal(t, "A.R", C.Definitions[0].Type.GetReference())
+	assert.NotNil(t, C.Definitions[1].Type.GetTimer())
+	assert.Equal(t, "B.P", C.Definitions[2].Type.GetReference())
+
+	b := req.Modules[1]
+	I := b.Types[0]
+	assert.Equal(t, pb.ScalarType_INTEGER, I.GetScalar())
+	assert.Equal(t, int64(255), I.Constraint.Ranges[0].End)
+	P := b.Types[1].GetPort()
+	assert.Equal(t, pb.PortType_MESSAGE, P.Kind)
+	assert.Len(t, P.In, 1)
+	assert.Len(t, P.Out, 1)
+}
+
+func TestRequestDefinitions(t *testing.T) {
+	req := newRequest(t, `
+		module M {
+			type component C {}
+			type record R { integer a }
+
+			const integer x := 23, y[2] := { 1, 2 };
+			modulepar R p;
+			template R t(integer v := 1) := { a := v };
+
+			function f(in integer a, out R b, inout template R c) runs on C return template integer {}
+			external function g() return charstring;
+			altstep as() {}
+			testcase tc() runs on C system C {}
+		}`)
+
+	m := req.Modules[0]
+	require.Len(t, m.Constants, 2)
+	assert.Equal(t, "x", m.Constants[0].Name)
+	assert.Equal(t, "23", m.Constants[0].Value)
+	assert.Equal(t, pb.ScalarType_INTEGER, m.Constants[1].Type.GetArray().Element.GetScalar())
+
+	require.Len(t, m.ModuleParameters, 1)
+	assert.Equal(t, "M.R", m.ModuleParameters[0].Type.GetReference())
+	assert.Equal(t, "", m.ModuleParameters[0].Value)
+
+	require.Len(t, m.Templates, 1)
+	tmpl := m.Templates[0]
+	assert.Equal(t, "M.R", tmpl.Type.GetReference())
+	require.Len(t, tmpl.Parameters, 1)
+	assert.Equal(t, "1", tmpl.Parameters[0].DefaultValue)
+
+	require.Len(t, m.Behaviours, 4)
+	f := m.Behaviours[0]
+	assert.Equal(t, pb.Behaviour_FUNCTION, f.Kind)
+	assert.Equal(t, "M.C", f.RunsOn.GetReference())
+	assert.True(t, f.ReturnTemplate)
+	require.Len(t, f.Parameters, 3)
+	assert.Equal(t, pb.Parameter_IN, f.Parameters[0].Direction)
+	assert.Equal(t, pb.Parameter_OUT, f.Parameters[1].Direction)
+	assert.Equal(t, pb.Parameter_INOUT, f.Parameters[2].Direction)
+	assert.True(t, f.Parameters[2].Template)
+
+	g := m.Behaviours[1]
+	assert.True(t, g.External)
+	assert.NotNil(t, g.ReturnType.GetCharstring())
+
+	assert.Equal(t, pb.Behaviour_ALTSTEP, m.Behaviours[2].Kind)
+
+	tc := m.Behaviours[3]
+	assert.Equal(t, pb.Behaviour_TESTCASE, tc.Kind)
+	assert.Equal(t, "M.C", tc.System.GetReference())
+}
+
+func TestRequestErrors(t *testing.T) {
+	_, err := generator.NewRequest(ttcn3.Parse(`module M { const X x := 1 }`))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "1:18: undefined type: X")
+	}
+}
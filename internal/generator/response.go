@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pb "github.com/nokia/ntt/protobuf"
+)
+
+// WriteFiles writes the files of resp into directory dir. Insertion points are
+// resolved in the order the files appear in the response. Nothing is written
+// if a file name is invalid or an insertion point could not be found.
+func WriteFiles(dir string, resp *pb.GeneratorResponse) error {
+	var (
+		names    []string
+		contents = make(map[string][]byte)
+	)
+
+	for _, f := range resp.Files {
+		name, err := cleanName(f.Name)
+		if err != nil {
+			return err
+		}
+
+		if f.InsertionPoint == "" {
+			if _, ok := contents[name]; !ok {
+				names = append(names, name)
+			}
+			contents[name] = f.Content
+			continue
+		}
+
+		b, ok := contents[name]
+		if !ok {
+			if b, err = ioutil.ReadFile(filepath.Join(dir, name)); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		if contents[name], err = insert(b, f.InsertionPoint, f.Content); err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+	}
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, contents[name], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanName returns the cleaned file name. Absolute names and names referring
+// to parent directories are rejected.
+func cleanName(name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if name == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid file name %q", name)
+	}
+	return clean, nil
+}
+
+// insert inserts content before the line containing the insertion point
+// marker. Every inserted line is indented like the marker line.
+func insert(b []byte, point string, content []byte) ([]byte, error) {
+	marker := []byte(fmt.Sprintf("@@ttcn3c_insertion_point(%s)", point))
+	i := bytes.Index(b, marker)
+	if i < 0 {
+		return nil, fmt.Errorf("insertion point %q not found", point)
+	}
+
+	start := bytes.LastIndexByte(b[:i], '\n') + 1
+	prefix := b[start:i]
+	indent := prefix[:len(prefix)-len(bytes.TrimLeft(prefix, " \t"))]
+
+	var buf bytes.Buffer
+	buf.Write(b[:start])
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] != '\n' {
+			buf.Write(indent)
+		}
+		buf.Write(line)
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	buf.Write(b[start:])
+	return buf.Bytes(), nil
+}
+
+// FormatDiagnostic formats d like the messages of ntt lint:
+//
+//	file:line:column: severity: message
+func FormatDiagnostic(d *pb.Diagnostic) string {
+	severity := strings.ToLower(d.Severity.String())
+	loc := d.Location
+	switch {
+	case loc == nil || loc.File == "" && loc.Line == 0:
+		return fmt.Sprintf("%s: %s", severity, d.Message)
+	case loc.Line == 0:
+		return fmt.Sprintf("%s: %s: %s", loc.File, severity, d.Message)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %s: %s", loc.File, loc.Line, loc.Column, severity, d.Message)
+	}
+}
+
+// UnusedParameters returns the sorted names of the request parameters not
+// reported as used by the response.
+func UnusedParameters(req *pb.GeneratorRequest, resp *pb.GeneratorResponse) []string {
+	var unused []string
+	for name := range req.Parameters {
+		if _, ok := resp.Parameters[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// Finish prints the diagnostics of resp to w and writes the generated files
+// into directory dir. Files are not written if the response reports an error
+// or contains error diagnostics.
+func Finish(w io.Writer, dir string, resp *pb.GeneratorResponse) error {
+	errs := 0
+	for _, d := range resp.Diagnostics {
+		fmt.Fprintln(w, FormatDiagnostic(d))
+		if d.Severity == pb.Diagnostic_ERROR {
+			errs++
+		}
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	if errs > 0 {
+		return fmt.Errorf("%d errors", errs)
+	}
+	return WriteFiles(dir, resp)
+}
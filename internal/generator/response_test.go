@@ -0,0 +1,119 @@
+package generator_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/internal/generator"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ttcn3c")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "existing.h"), []byte("// @@ttcn3c_insertion_point(types)\n"), 0644))
+
+	resp := &pb.GeneratorResponse{
+		Files: []*pb.GeneratorResponse_File{
+			{Name: "gen/a.cc", Content: []byte("int main() {\n\t// @@ttcn3c_insertion_point(body)\n}\n")},
+			{Name: "gen/a.cc", InsertionPoint: "body", Content: []byte("foo();\n\nbar();")},
+			{Name: "existing.h", InsertionPoint: "types", Content: []byte("typedef int I;\n")},
+		},
+	}
+	require.Nil(t, generator.WriteFiles(dir, resp))
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "gen", "a.cc"))
+	require.Nil(t, err)
+	assert.Equal(t, "int main() {\n\tfoo();\n\n\tbar();\n\t// @@ttcn3c_insertion_point(body)\n}\n", string(b))
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "existing.h"))
+	require.Nil(t, err)
+	assert.Equal(t, "typedef int I;\n// @@ttcn3c_insertion_point(types)\n", string(b))
+}
+
+func TestWriteFilesErrors(t *testing.T) {
+	tests := []struct {
+		file *pb.GeneratorResponse_File
+		err  string
+	}{
+		{&pb.GeneratorResponse_File{Name: "/etc/passwd"}, `invalid file name "/etc/passwd"`},
+		{&pb.GeneratorResponse_File{Name: "../x"}, `invalid file name "../x"`},
+		{&pb.GeneratorResponse_File{Name: ""}, `invalid file name ""`},
+		{&pb.GeneratorResponse_File{Name: "x", InsertionPoint: "p"}, "no such file"},
+	}
+
+	for _, tt := range tests {
+		dir, err := ioutil.TempDir("", "ttcn3c")
+		require.Nil(t, err)
+		defer os.RemoveAll(dir)
+
+		err = generator.WriteFiles(dir, &pb.GeneratorResponse{Files: []*pb.GeneratorResponse_File{tt.file}})
+		if assert.NotNil(t, err, tt.file.Name) {
+			assert.Contains(t, err.Error(), tt.err)
+		}
+	}
+
+	resp := &pb.GeneratorResponse{Files: []*pb.GeneratorResponse_File{
+		{Name: "x", Content: []byte("no marker")},
+		{Name: "x", InsertionPoint: "p"},
+	}}
+	err := generator.WriteFiles("", resp)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `x: insertion point "p" not found`, err.Error())
+	}
+}
+
+func TestFinish(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ttcn3c")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	resp := &pb.GeneratorResponse{
+		Files: []*pb.GeneratorResponse_File{{Name: "a.t3xf", Content: []byte{0xff, 0x00}}},
+		Diagnostics: []*pb.Diagnostic{
+			{Severity: pb.Diagnostic_WARNING, Message: "w"},
+			{Severity: pb.Diagnostic_ERROR, Message: "e"},
+		},
+	}
+	var out bytes.Buffer
+	err = generator.Finish(&out, dir, resp)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "1 errors", err.Error())
+	}
+	assert.Equal(t, "warning: w\nerror: e\n", out.String())
+	_, err = os.Stat(filepath.Join(dir, "a.t3xf"))
+	assert.True(t, os.IsNotExist(err), "files must not be written on errors")
+
+	resp.Diagnostics = resp.Diagnostics[:1]
+	require.Nil(t, generator.Finish(&out, dir, resp))
+	b, err := ioutil.ReadFile(filepath.Join(dir, "a.t3xf"))
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0xff, 0x00}, b)
+}
+
+func TestFormatDiagnostic(t *testing.T) {
+	tests := []struct {
+		diag *pb.Diagnostic
+		want string
+	}{
+		{&pb.Diagnostic{Message: "boom"}, "error: boom"},
+		{&pb.Diagnostic{Severity: pb.Diagnostic_WARNING, Location: &pb.Location{File: "a.ttcn3"}, Message: "m"}, "a.ttcn3: warning: m"},
+		{&pb.Diagnostic{Severity: pb.Diagnostic_INFO, Location: &pb.Location{File: "a.ttcn3", Line: 2, Column: 5}, Message: "m"}, "a.ttcn3:2:5: info: m"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, generator.FormatDiagnostic(tt.diag))
+	}
+}
+
+func TestUnusedParameters(t *testing.T) {
+	req := &pb.GeneratorRequest{Parameters: map[string]string{"b": "1", "a": "2", "c": "3"}}
+	resp := &pb.GeneratorResponse{Parameters: map[string]string{"c": "3", "d": "default"}}
+	assert.Equal(t, []string{"a", "b"}, generator.UnusedParameters(req, resp))
+}
@@ -0,0 +1,128 @@
+package lsp
+
+import (
+	"sync"
+
+	"github.com/nokia/ntt/asn1"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/lsp/protocol"
+	"github.com/nokia/ntt/internal/ntt"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/types"
+)
+
+// asn1Modules returns all ASN.1 modules found in the import directories of
+// the suite.
+func asn1Modules(suite *ntt.Suite) []*asn1.Module {
+	var mods []*asn1.Module
+	files, _ := project.ASN1Files(suite)
+	for _, file := range files {
+		m, err := asn1.ParseFile(file)
+		if err != nil {
+			log.Debugf("%s\n", err.Error())
+		}
+		mods = append(mods, m...)
+	}
+	return mods
+}
+
+// asn1Scopes caches the TTCN-3 representation of parsed ASN.1 modules. The
+// parser cache returns the same modules until a file changes, hence the
+// modules are suitable keys.
+var (
+	asn1ScopesMu sync.Mutex
+	asn1Scopes   = make(map[*asn1.Module]*types.Module)
+)
+
+// findASN1Module returns the ASN.1 module with the given TTCN-3 name and its
+// TTCN-3 representation, or nil if there is no such module.
+func findASN1Module(suite *ntt.Suite, name string) (*asn1.Module, *types.Module) {
+	mods := asn1Modules(suite)
+	for _, m := range mods {
+		if asn1.TTCN3Name(m.Name) != name {
+			continue
+		}
+		return m, asn1Scope(m, mods)
+	}
+	return nil, nil
+}
+
+// asn1Scope returns the TTCN-3 representation of module m. Cached entries of
+// modules not in mods anymore are removed.
+func asn1Scope(m *asn1.Module, mods []*asn1.Module) *types.Module {
+	asn1ScopesMu.Lock()
+	defer asn1ScopesMu.Unlock()
+
+	if mod, ok := asn1Scopes[m]; ok {
+		return mod
+	}
+
+	current := make(map[*asn1.Module]bool, len(mods))
+	for _, m := range mods {
+		current[m] = true
+	}
+	for m := range asn1Scopes {
+		if !current[m] {
+			delete(asn1Scopes, m)
+		}
+	}
+
+	scp := make(scope)
+	(&types.Info{}).InsertASN1(m, scp)
+	mod, _ := scp.Lookup(asn1.TTCN3Name(m.Name)).(*types.Module)
+	asn1Scopes[m] = mod
+	return mod
+}
+
+// getASN1Definitions returns the TTCN-3 names of the types (or constants)
+// defined by ASN.1 module mname.
+func getASN1Definitions(suite *ntt.Suite, mname string, consts bool) []string {
+	var list []string
+	if _, mod := findASN1Module(suite, mname); mod != nil {
+		for _, name := range mod.Names() {
+			_, isConst := mod.Lookup(name).(*types.Var)
+			if isConst == consts {
+				list = append(list, name)
+			}
+		}
+	}
+	return list
+}
+
+// asn1Definitions returns the locations of ASN.1 assignments with the given
+// TTCN-3 name in module mname.
+func asn1Definitions(suite *ntt.Suite, mname string, name string) []protocol.Location {
+	var ret []protocol.Location
+	if m, _ := findASN1Module(suite, mname); m != nil {
+		for _, a := range m.Assignments {
+			if asn1.TTCN3Name(a.Name) == name {
+				ret = append(ret, location(a.Pos))
+			}
+		}
+	}
+	return ret
+}
+
+// scope is a global scope for ASN.1 modules.
+type scope map[string]types.Object
+
+func (s scope) EnclosingScope() types.Scope { return nil }
+func (s scope) Lookup(name string) types.Object {
+	return s[name]
+}
+
+func (s scope) Insert(name string, obj types.Object) types.Object {
+	if alt, ok := s[name]; ok {
+		return alt
+	}
+	s[name] = obj
+	return nil
+}
+
+func (s scope) Names() []string {
+	var names []string
+	for name := range s {
+		names = append(names, name)
+	}
+	return names
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/nokia/ntt/asn1"
 	"github.com/nokia/ntt/internal/loc"
 	"github.com/nokia/ntt/internal/log"
 	"github.com/nokia/ntt/internal/lsp/protocol"
@@ -163,6 +164,9 @@ func getAllValueDeclsFromModule(suite *ntt.Suite, mname string, kind token.Kind)
 				return true
 			}
 		})
+	} else if kind == token.CONST {
+		// ASN.1 values are imported as constants.
+		list = append(list, getASN1Definitions(suite, mname, true)...)
 	}
 	return list
 }
@@ -201,6 +205,8 @@ func getAllTypesFromModule(suite *ntt.Suite, mname string) []string {
 				return true
 			}
 		})
+	} else {
+		list = append(list, getASN1Definitions(suite, mname, false)...)
 	}
 	return list
 }
@@ -391,6 +397,14 @@ func moduleNameListFromSuite(suite *ntt.Suite, ownModName string, sortPref strin
 			}
 		}
 	}
+	for _, m := range asn1Modules(suite) {
+		name := asn1.TTCN3Name(m.Name)
+		if len(sortPref) > 0 {
+			list = append(list, protocol.CompletionItem{Label: name, Kind: protocol.ModuleCompletion, SortText: sortPref + name})
+		} else {
+			list = append(list, protocol.CompletionItem{Label: name, Kind: protocol.ModuleCompletion})
+		}
+	}
 	return list
 }
 
@@ -2,6 +2,8 @@ package lsp_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -1095,6 +1097,36 @@ func TestFuncComplInsideConstDeclBody(t *testing.T) {
 		list)
 }
 
+func TestASN1ModuleDotType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "asn1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv("NTT_IMPORTS", dir)
+	defer os.Unsetenv("NTT_IMPORTS")
+
+	err = ioutil.WriteFile(filepath.Join(dir, "types.asn"), []byte(`
+		My-Types DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		Message-Type ::= SEQUENCE { id INTEGER }
+		max-len INTEGER ::= 255
+		END`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := `module Test
+    {
+		import from My_Types language "ASN.1:2002" all;
+		template My_Types.//
+	}`
+	suite := buildSuite(t, src)
+
+	list := completionAt(t, suite, loc.Pos(strings.Index(src, "//")))
+	assert.Equal(t, []protocol.CompletionItem{
+		{Label: "Message_Type ", Kind: protocol.StructCompletion, Detail: "My_Types.Message_Type"}}, list)
+}
+
 // TODO: fixing this issue requires more effort.
 /*
 func TestSyntaxErrorProvokingInvalidPos(t *testing.T) {
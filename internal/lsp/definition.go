@@ -73,6 +73,7 @@ func cTags(suite *ntt.Suite, file string, line int, col int) []protocol.Location
 	for _, mod := range tree.ImportedModules() {
 		file, _ := suite.FindModule(mod)
 		if file == "" {
+			ret = append(ret, asn1Definitions(suite, mod, ast.Name(id))...)
 			continue
 		}
 		tree, tags := suite.Tags(file)
@@ -2,10 +2,14 @@ package lsp
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/nokia/ntt/internal/errors"
 	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/log"
 	"github.com/nokia/ntt/internal/lsp/protocol"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	_ "github.com/nokia/ntt/ttcn3/analysis/passes"
 )
 
 // Diagnose runs various checks over a ttcn3 test suite.
@@ -31,7 +35,90 @@ func (s *Server) Diagnose(uris ...protocol.DocumentURI) {
 	s.diags = make(map[string][]protocol.Diagnostic)
 	defer s.syncDiagnostics()
 
-	// TODO(5nord): Run linter against uris
+	for _, uri := range uris {
+		path := fs.Open(string(uri.SpanURI())).Path()
+
+		// An empty list clears former diagnostics.
+		s.diags[path] = []protocol.Diagnostic{}
+
+		r := analysis.Runner{
+			Analyzers: analysis.All(),
+			Partial:   true,
+		}
+		// Configuration files next to the file take precedence over the
+		// configuration of the test suite root.
+		h := analysis.Hierarchy{Analyzers: r.Analyzers}
+		cfg, err := h.Config(path)
+		if err != nil {
+			log.Verbose(err.Error())
+		}
+		r.Config = cfg
+		for _, suite := range s.Owners(uri) {
+			r.Project = suite
+			if r.Config != nil {
+				break
+			}
+			if cfg := lintConfig(suite.Root(), r.Analyzers); cfg != nil {
+				r.Config = cfg
+				break
+			}
+		}
+
+		diags, err := r.Run(path)
+		if err != nil {
+			log.Verbose(err.Error())
+		}
+		for _, d := range diags {
+			s.diags[path] = append(s.diags[path], AnalysisDiagnostic(d))
+		}
+	}
+}
+
+// lintConfig returns the lint configuration of the test suite in directory
+// root or nil if there is none.
+func lintConfig(root string, analyzers []*analysis.Analyzer) *analysis.Config {
+	b, err := fs.Open(filepath.Join(root, analysis.ConfigFile)).Bytes()
+	if err != nil {
+		return nil
+	}
+	cfg, err := analysis.ParseConfig(b, analyzers...)
+	if err != nil {
+		log.Verbose(err.Error())
+		return nil
+	}
+	return cfg
+}
+
+// AnalysisDiagnostic converts an analyzer diagnostic to a protocol
+// diagnostic.
+func AnalysisDiagnostic(d analysis.Diagnostic) protocol.Diagnostic {
+	begin, end := d.Pos, d.End
+	if !begin.IsValid() {
+		begin.Line, begin.Column = 1, 1
+	}
+	if !end.IsValid() {
+		end = begin
+	}
+	return protocol.Diagnostic{
+		Range: protocol.Range{
+			Start: position(begin.Line, begin.Column),
+			End:   position(end.Line, end.Column),
+		},
+		Severity: severity(d.Severity),
+		Source:   "ntt",
+		Code:     d.Analyzer,
+		Message:  d.Message,
+	}
+}
+
+func severity(s analysis.Severity) protocol.DiagnosticSeverity {
+	switch s {
+	case analysis.Warning:
+		return protocol.SeverityWarning
+	case analysis.Info:
+		return protocol.SeverityInformation
+	}
+	return protocol.SeverityError
 }
 
 func (s *Server) reportError(err error) {
@@ -39,7 +126,7 @@ func (s *Server) reportError(err error) {
 
 	// Errors with a location will become diagnostics
 	case errors.Error:
-		uri := string(fs.Open(err.Pos.Filename).URI())
+		path := fs.Open(err.Pos.Filename).Path()
 		diag := protocol.Diagnostic{
 			Range: protocol.Range{
 				Start: protocol.Position{
@@ -55,7 +142,7 @@ func (s *Server) reportError(err error) {
 			Source:   err.Pos.Filename,
 			Message:  err.Msg,
 		}
-		s.diags[uri] = append(s.diags[uri], diag)
+		s.diags[path] = append(s.diags[path], diag)
 
 	// Expand error lists (like syntax error)
 	case errors.ErrorList:
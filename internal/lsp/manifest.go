@@ -0,0 +1,62 @@
+package lsp
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/lsp/protocol"
+	"github.com/nokia/ntt/project"
+)
+
+// isManifest returns true if uri is a test suite manifest.
+func isManifest(uri protocol.DocumentURI) bool {
+	return filepath.Base(uri.SpanURI().Filename()) == project.ManifestFile
+}
+
+// diagnoseManifest publishes the schema violations of manifest uri. An empty
+// list is published for valid manifests to clear previous diagnostics.
+func (s *Server) diagnoseManifest(uri protocol.DocumentURI) {
+	f := fs.Open(string(uri.SpanURI()))
+	b, err := f.Bytes()
+	if err != nil {
+		return
+	}
+	s.client.PublishDiagnostics(context.TODO(), &protocol.PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: ManifestDiagnostics(f.Path(), b),
+	})
+}
+
+// ManifestDiagnostics validates manifest content b and returns the violations
+// as diagnostics. The range of a diagnostic covers the offending word.
+func ManifestDiagnostics(file string, b []byte) []protocol.Diagnostic {
+	lines := bytes.Split(b, []byte("\n"))
+	diags := []protocol.Diagnostic{}
+	for _, e := range project.ValidateManifest(file, b) {
+		line, col := e.Pos.Line-1, e.Pos.Column-1
+		if line < 0 {
+			line = 0
+		}
+		if col < 0 {
+			col = 0
+		}
+		end := col
+		if line < len(lines) {
+			for end < len(lines[line]) && !bytes.ContainsAny(lines[line][end:end+1], " \t:#") {
+				end++
+			}
+		}
+		diags = append(diags, protocol.Diagnostic{
+			Range: protocol.Range{
+				Start: protocol.Position{Line: uint32(line), Character: uint32(col)},
+				End:   protocol.Position{Line: uint32(line), Character: uint32(end)},
+			},
+			Severity: protocol.SeverityError,
+			Source:   "ntt",
+			Message:  e.Msg,
+		})
+	}
+	return diags
+}
@@ -0,0 +1,22 @@
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/internal/lsp"
+	"github.com/nokia/ntt/internal/lsp/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifestDiagnostics(t *testing.T) {
+	assert.Empty(t, lsp.ManifestDiagnostics("package.yml", []byte("name: suite\n")))
+
+	diags := lsp.ManifestDiagnostics("package.yml", []byte("name: suite\nparameter_file: x\n"))
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, protocol.Range{
+			Start: protocol.Position{Line: 1, Character: 0},
+			End:   protocol.Position{Line: 1, Character: 14},
+		}, diags[0].Range)
+		assert.Equal(t, `unknown field "parameter_file". Did you mean "parameters_file"?`, diags[0].Message)
+	}
+}
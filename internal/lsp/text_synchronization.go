@@ -12,6 +12,12 @@ import (
 )
 
 func (s *Server) didOpen(ctx context.Context, params *protocol.DidOpenTextDocumentParams) error {
+	if isManifest(params.TextDocument.URI) {
+		s.registerFile(params.TextDocument)
+		s.diagnoseManifest(params.TextDocument.URI)
+		return nil
+	}
+
 	if !strings.HasPrefix(strings.ToLower(params.TextDocument.LanguageID), "ttcn") {
 		return nil
 	}
@@ -48,7 +54,13 @@ func (s *Server) didChange(ctx context.Context, params *protocol.DidChangeTextDo
 		f.SetBytes([]byte(ch.Text))
 	}
 
+	if isManifest(params.TextDocument.URI) {
+		s.diagnoseManifest(params.TextDocument.URI)
+		return nil
+	}
+
 	s.db.Index(uri)
+	s.Diagnose(params.TextDocument.URI)
 	return nil
 }
 
@@ -337,8 +337,9 @@ func fileExists(path string) (bool, error) {
 type manifest struct {
 	// Static configuration
 	Name      string
+	Version   string
 	Sources   []string
-	Imports   []string
+	Imports   []project.Import
 	Variables map[string]string
 
 	// Runtime configuration
@@ -376,6 +377,9 @@ func (suite *Suite) parseManifest() (*manifest, error) {
 	f.Handle = suite.store.Bind(f.ID(), func(ctx context.Context) interface{} {
 		data := manifestData{}
 		data.err = yaml.UnmarshalStrict(b, &data.manifest)
+		if errs := project.ValidateManifest(f.Path(), b); len(errs) > 0 {
+			data.err = errs
+		}
 		return &data
 	})
 
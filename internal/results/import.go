@@ -0,0 +1,274 @@
+package results
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An Importer reads test results produced by other tools and converts them
+// into runs. The verdicts of the returned runs are the tool specific states and
+// need to be mapped to TTCN-3 verdicts using a VerdictMap.
+type Importer func(r io.Reader) ([]Run, error)
+
+// Importers is a list of available importers by format name.
+var Importers = map[string]Importer{
+	"junit": ImportJUnit,
+	"tap":   ImportTAP,
+	"json":  ImportJSON,
+}
+
+// VerdictMap maps tool specific test states to TTCN-3 verdicts.
+type VerdictMap map[string]string
+
+// DefaultVerdictMap maps common JUnit and TAP states to TTCN-3 verdicts.
+var DefaultVerdictMap = VerdictMap{
+	"passed":  "pass",
+	"ok":      "pass",
+	"failure": "fail",
+	"failed":  "fail",
+	"not ok":  "fail",
+	"skipped": "none",
+	"skip":    "none",
+	"todo":    "inconc",
+}
+
+// Verdict returns the TTCN-3 verdict for state s. TTCN-3 verdicts are
+// returned unchanged. Unknown states and mappings to anything but a TTCN-3
+// verdict are reported as error.
+func (m VerdictMap) Verdict(s string) (string, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	v, ok := m[s]
+	if !ok {
+		v, ok = DefaultVerdictMap[s]
+	}
+	if !ok {
+		v = s
+	}
+	switch v {
+	case "none", "pass", "inconc", "fail", "error":
+		return v, nil
+	}
+	if ok {
+		return "", fmt.Errorf("state %q mapped to %q, which is not a verdict", s, v)
+	}
+	return "", fmt.Errorf("unknown state %q", s)
+}
+
+// Import reads test results in format from r and maps their verdicts using m.
+func Import(format string, r io.Reader, m VerdictMap) ([]Run, error) {
+	imp, ok := Importers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown result format %q", format)
+	}
+	runs, err := imp(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		v, err := m.Verdict(runs[i].Verdict)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", runs[i].Name, err)
+		}
+		runs[i].Verdict = v
+	}
+	return runs, nil
+}
+
+type junitSuites struct {
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string       `xml:"name,attr"`
+	Timestamp string       `xml:"timestamp,attr"`
+	Hostname  string       `xml:"hostname,attr"`
+	Cases     []junitCase  `xml:"testcase"`
+	Suites    []junitSuite `xml:"testsuite"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	Error     *junitFailure `xml:"error"`
+	Skipped   *junitFailure `xml:"skipped"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ImportJUnit reads JUnit XML reports, like those produced by TITAN or ntt
+// itself. The root element may either be testsuites or testsuite.
+func ImportJUnit(r io.Reader) ([]Run, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var suites junitSuites
+	if err := xml.Unmarshal(b, &suites); err != nil {
+		return nil, err
+	}
+	if len(suites.Suites) == 0 {
+		var s junitSuite
+		if err := xml.Unmarshal(b, &s); err != nil {
+			return nil, err
+		}
+		suites.Suites = []junitSuite{s}
+	}
+
+	var runs []Run
+	for _, s := range suites.Suites {
+		runs = append(runs, s.runs()...)
+	}
+	return runs, nil
+}
+
+func (s junitSuite) runs() []Run {
+	var (
+		runs  []Run
+		begin time.Time
+	)
+
+	for _, layout := range []string{"2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, s.Timestamp); err == nil {
+			begin = t
+			break
+		}
+	}
+
+	for _, c := range s.Cases {
+		name := c.Name
+		switch {
+		case c.Classname != "":
+			name = c.Classname + "." + c.Name
+		case s.Name != "":
+			name = s.Name + "." + c.Name
+		}
+
+		run := Run{
+			Name:     name,
+			Verdict:  "passed",
+			RunnerID: s.Hostname,
+		}
+
+		if f := c.result(); f != nil {
+			run.Reason = strings.TrimSpace(f.Message)
+			if run.Reason == "" {
+				run.Reason = strings.TrimSpace(f.Text)
+			}
+		}
+
+		switch {
+		case c.Error != nil:
+			run.Verdict = verdictOr(c.Error.Type, "error")
+		case c.Failure != nil:
+			run.Verdict = verdictOr(c.Failure.Type, "failure")
+		case c.Skipped != nil:
+			run.Verdict = verdictOr(c.Skipped.Text, "skipped")
+		}
+
+		d, _ := strconv.ParseFloat(c.Time, 64)
+		run.Begin = Timestamp{begin}
+		run.End = Timestamp{begin.Add(time.Duration(d * float64(time.Second)))}
+		if !begin.IsZero() {
+			begin = run.End.Time
+		}
+
+		runs = append(runs, run)
+	}
+
+	for _, sub := range s.Suites {
+		runs = append(runs, sub.runs()...)
+	}
+	return runs
+}
+
+func (c junitCase) result() *junitFailure {
+	switch {
+	case c.Error != nil:
+		return c.Error
+	case c.Failure != nil:
+		return c.Failure
+	case c.Skipped != nil:
+		return c.Skipped
+	}
+	return nil
+}
+
+var verdictWord = regexp.MustCompile(`(?i)\b(pass|inconc|fail|error|none)\b`)
+
+// verdictOr returns the TTCN-3 verdict found in s, like TITAN reports do for
+// failure types, otherwise it returns def.
+func verdictOr(s string, def string) string {
+	if m := verdictWord.FindStringSubmatch(s); m != nil {
+		return strings.ToLower(m[1])
+	}
+	return def
+}
+
+var tapLine = regexp.MustCompile(`^(ok|not ok)\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(\w+)\b\s*(.*))?$`)
+
+// ImportTAP reads results in Test Anything Protocol format. TAP does not
+// provide any timing information. Indented subtests are skipped, because
+// their results are summarized by the test point of the enclosing test.
+func ImportTAP(r io.Reader) ([]Run, error) {
+	var runs []Run
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		m := tapLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		run := Run{
+			Name:    m[3],
+			Verdict: m[1],
+			Reason:  m[5],
+		}
+		if run.Name == "" {
+			run.Name = "test" + m[2]
+		}
+		if d := strings.ToLower(m[4]); d == "skip" || d == "todo" {
+			run.Verdict = d
+		}
+		runs = append(runs, run)
+	}
+	return runs, s.Err()
+}
+
+// ImportJSON reads results in ntt's test_results.json format. A plain list of
+// runs is accepted, too.
+func ImportJSON(r io.Reader) ([]Run, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []Run
+	if err := json.Unmarshal(b, &runs); err == nil {
+		return runs, nil
+	}
+
+	var db DB
+	if err := json.Unmarshal(b, &db); err != nil {
+		return nil, err
+	}
+	return db.Runs(), nil
+}
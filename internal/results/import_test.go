@@ -0,0 +1,85 @@
+package results
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportJUnit(t *testing.T) {
+	input := `<?xml version="1.0"?>
+<testsuites>
+  <testsuite name="M1" timestamp="2020-01-01T12:00:00" hostname="host">
+    <testcase classname="M1" name="A" time="1.5"/>
+    <testcase classname="M1" name="B" time="0.5"><failure message="boom" type="java.lang.AssertionError"/></testcase>
+    <testcase classname="M1" name="C"><failure type="inconc-verdict">text</failure></testcase>
+    <testcase classname="M1" name="D"><error/></testcase>
+    <testcase classname="M1" name="E"><skipped/></testcase>
+  </testsuite>
+</testsuites>`
+
+	runs, err := Import("junit", strings.NewReader(input), VerdictMap{"skipped": "inconc"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"pass	M1.A-0	1.5s",
+		"fail	M1.B-0	500ms",
+		"inconc	M1.C-0	0s",
+		"error	M1.D-0	0s",
+		"inconc	M1.E-0	0s",
+	}, stringSlice(runs))
+	assert.Equal(t, "boom", runs[1].Reason)
+	assert.Equal(t, "text", runs[2].Reason)
+	assert.Equal(t, "host", runs[0].RunnerID)
+	assert.Equal(t, runs[0].End, runs[1].Begin)
+}
+
+func TestImportJUnitSingleSuite(t *testing.T) {
+	input := `<testsuite name="M1"><testcase name="A"/></testsuite>`
+	runs, err := Import("junit", strings.NewReader(input), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pass	M1.A-0	0s"}, stringSlice(runs))
+}
+
+func TestImportTAP(t *testing.T) {
+	input := `TAP version 13
+1..4
+ok 1 - M1.A
+    # Subtest: M1.B
+    ok 1 - step 1
+    not ok 2 - step 2
+    1..2
+not ok 2 - M1.B
+ok 3 M1.C # SKIP not supported
+not ok 4 # TODO later
+`
+	runs, err := Import("tap", strings.NewReader(input), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"pass	M1.A-0	0s",
+		"fail	M1.B-0	0s",
+		"none	M1.C-0	0s",
+		"inconc	test4-0	0s",
+	}, stringSlice(runs))
+	assert.Equal(t, "not supported", runs[2].Reason)
+
+	_, err = Import("tap", strings.NewReader("ok 1 - M1.A # SKIP\n"), VerdictMap{"skip": "skipped"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `M1.A: state "skip" mapped to "skipped", which is not a verdict`, err.Error())
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	input := `{"sessions": [{"expected_verdict": "pass", "runs": [{"name": "M1.A", "verdict": "fail", "begin": 1000, "end": 3000}]}]}`
+	runs, err := Import("json", strings.NewReader(input), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"fail	M1.A-0	2s"}, stringSlice(runs))
+
+	_, err = Import("json", strings.NewReader(`[{"name": "M1.A", "verdict": "flaky"}]`), nil)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `M1.A: unknown state "flaky"`, err.Error())
+	}
+
+	_, err = Import("xunit", strings.NewReader(input), nil)
+	assert.NotNil(t, err)
+}
@@ -439,7 +439,7 @@ func evalUnary(n *ast.UnaryExpr, env runtime.Scope) runtime.Object {
 		}
 	case token.NOT4B:
 		if b, ok := val.(*runtime.Bitstring); ok {
-			return &runtime.Bitstring{Value: new(big.Int).Abs(new(big.Int).Not(b.Value)), Unit: b.Unit}
+			return &runtime.Bitstring{Value: new(big.Int).Abs(new(big.Int).Not(b.Value)), Unit: b.Unit, Length: b.Length}
 		}
 	}
 
@@ -598,18 +598,25 @@ func evalStringBinary(x string, y string, op token.Kind, env runtime.Scope) runt
 func evalBitstringBinary(x *runtime.Bitstring, y *runtime.Bitstring, op token.Kind, env runtime.Scope) runtime.Object {
 	switch op {
 	case token.AND4B:
-		return &runtime.Bitstring{Value: new(big.Int).And(x.Value, y.Value), Unit: x.Unit}
+		return &runtime.Bitstring{Value: new(big.Int).And(x.Value, y.Value), Unit: x.Unit, Length: maxLength(x, y)}
 
 	case token.OR4B:
-		return &runtime.Bitstring{Value: new(big.Int).Or(x.Value, y.Value), Unit: x.Unit}
+		return &runtime.Bitstring{Value: new(big.Int).Or(x.Value, y.Value), Unit: x.Unit, Length: maxLength(x, y)}
 
 	case token.XOR4B:
-		return &runtime.Bitstring{Value: new(big.Int).Xor(x.Value, y.Value), Unit: x.Unit}
+		return &runtime.Bitstring{Value: new(big.Int).Xor(x.Value, y.Value), Unit: x.Unit, Length: maxLength(x, y)}
 
 	}
 	return runtime.Errorf("unknown operator: bitstring %s bitstring", op)
 }
 
+func maxLength(x *runtime.Bitstring, y *runtime.Bitstring) int {
+	if x.Length > y.Length {
+		return x.Length
+	}
+	return y.Length
+}
+
 func evalBoolExpr(n ast.Expr, env runtime.Scope) (bool, runtime.Object) {
 	val := eval(n, env)
 	if runtime.IsError(val) {
@@ -0,0 +1,466 @@
+package t3xf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nokia/ntt/k3/t3xf/opcode"
+)
+
+// Assemble reads the textual representation of t3xf code, as printed by
+// k3objdump, and returns the assembled file.
+//
+// Instruction addresses and raw instruction dumps are optional. If addresses
+// are present, references are interpreted as byte offsets, otherwise as
+// instruction numbers (k3objdump --legacy). If the input contains TASM tables,
+// a TASM file is returned.
+func Assemble(r io.Reader) (*File, error) {
+	a := assembler{
+		file: File{
+			version: 2,
+			width:   4,
+			order:   binary.LittleEndian,
+		},
+	}
+	a.code = &a.t3xf
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for s.Scan() {
+		a.line++
+		if err := a.parseLine(s.Text()); err != nil {
+			return nil, fmt.Errorf("%d: %w", a.line, err)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if err := a.flush(); err != nil {
+		return nil, err
+	}
+	return &a.file, nil
+}
+
+var (
+	addrPrefix  = regexp.MustCompile(`^\s*\d+: `)
+	rawPrefix   = regexp.MustCompile(`^\s*(?:[0-9a-f]{2} [0-9a-f]{2} [0-9a-f]{2} [0-9a-f]{2}\s*)+`)
+	labelLine   = regexp.MustCompile(`^\s*<[^>]*>:$`)
+	tableHeader = regexp.MustCompile(`^(\w+):$`)
+	sectionLine = regexp.MustCompile(`^Sections: T3XF=(\d+) Text=(\d+) Data=(\d+)$`)
+	tableEntry  = regexp.MustCompile(`^\s*(\d+):(?: ("(?:[^"\\]|\\.)*"))?((?: [A-Z]\w*=\S+)+)(?: '([0-9a-f]*)'O)?$`)
+	fieldSymbol = regexp.MustCompile(`<[^>]*>$`)
+)
+
+// code is a sequence of instructions, which are encoded when all references
+// are known.
+type code struct {
+	instrs  []asmInstr
+	offsets bool // true, if references are byte offsets.
+}
+
+type asmInstr struct {
+	op   opcode.Opcode
+	arg  int
+	data []byte
+	n    int
+	f    float64
+}
+
+type assembler struct {
+	file File
+	line int
+
+	t3xf    code          // code of the T3XF section
+	code    *code         // code currently assembled
+	pending opcode.Opcode // literal opcode waiting for its value
+
+	table    string        // current TASM table, if any
+	blocks   map[int]*code // code of TASM blocks by index
+	data     []byte
+	textSize int
+	dataSize int
+}
+
+func (a *assembler) parseLine(line string) error {
+	if strings.TrimSpace(line) == "" || labelLine.MatchString(line) {
+		return nil
+	}
+
+	if m := sectionLine.FindStringSubmatch(line); m != nil {
+		a.file.tasm = true
+		a.file.version = 1
+		a.textSize, _ = strconv.Atoi(m[2])
+		a.dataSize, _ = strconv.Atoi(m[3])
+		a.code = &code{}
+		return nil
+	}
+
+	if m := tableHeader.FindStringSubmatch(line); m != nil {
+		if _, ok := reflect.TypeOf(Tables{}).FieldByName(m[1]); !ok {
+			return fmt.Errorf("unknown table %q", m[1])
+		}
+		a.file.tasm = true
+		a.file.version = 1
+		a.table = m[1]
+		return nil
+	}
+
+	if a.table != "" {
+		if m := tableEntry.FindStringSubmatch(line); m != nil {
+			return a.parseEntry(m)
+		}
+		if a.table != "Blocks" {
+			return fmt.Errorf("unexpected line in table %s", a.table)
+		}
+	}
+
+	if loc := addrPrefix.FindStringIndex(line); loc != nil {
+		a.code.offsets = true
+		line = line[loc[1]:]
+	}
+	line = rawPrefix.ReplaceAllString(line, "")
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+
+	if a.pending != 0 {
+		op := a.pending
+		a.pending = 0
+		return a.parseValue(op, line)
+	}
+
+	if isValue(line) {
+		return a.parseValue(0, line)
+	}
+
+	f := strings.Fields(line)
+	instr := asmInstr{}
+	switch c := f[0][0]; {
+	case c == '=':
+		instr.op = opcode.LINE
+		return a.emitArg(instr, f[0][1:])
+	case c == 'L' && isNumber(f[0][1:]):
+		instr.op = opcode.REF
+		return a.emitArg(instr, f[0][1:])
+	case c == 'R' && isNumber(f[0][1:]):
+		instr.op = opcode.FROZEN_REF
+		return a.emitArg(instr, f[0][1:])
+	case c == '@':
+		instr.op = opcode.GOTO
+		return a.emitArg(instr, f[0][1:])
+	}
+
+	op, ok := opcode.Lookup(f[0])
+	if !ok {
+		return fmt.Errorf("unknown instruction %q", f[0])
+	}
+	instr.op = op
+
+	switch op {
+	case opcode.UTF8, opcode.NAME, opcode.OCTETS, opcode.NIBBLES, opcode.BITS,
+		opcode.ISTR, opcode.FSTR, opcode.NATLONG, opcode.IEEE754DP:
+		if len(f) == 1 {
+			a.pending = op
+			return nil
+		}
+		return a.parseValue(op, strings.TrimSpace(line[len(f[0]):]))
+	}
+
+	if len(f) > 1 {
+		return a.emitArg(instr, f[1])
+	}
+	a.code.instrs = append(a.code.instrs, instr)
+	return nil
+}
+
+func (a *assembler) emitArg(instr asmInstr, s string) error {
+	arg, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	instr.arg = arg
+	a.code.instrs = append(a.code.instrs, instr)
+	return nil
+}
+
+// parseValue parses a literal value. If op is zero, the instruction is derived
+// from the literal syntax.
+func (a *assembler) parseValue(op opcode.Opcode, s string) error {
+	instr := asmInstr{op: op}
+
+	switch {
+	case strings.HasPrefix(s, `"`) && (strings.HasSuffix(s, `"I`) || strings.HasSuffix(s, `"F`)):
+		v, err := strconv.Unquote(s[:len(s)-1])
+		if err != nil {
+			return err
+		}
+		if instr.op == 0 {
+			instr.op = opcode.ISTR
+			if s[len(s)-1] == 'F' {
+				instr.op = opcode.FSTR
+			}
+		}
+		instr.data, instr.n = []byte(v), len(v)
+
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return err
+		}
+		if instr.op == 0 {
+			instr.op = opcode.UTF8
+		}
+		instr.data, instr.n = []byte(v), len(v)
+
+	case strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'O"):
+		b, err := hex.DecodeString(s[1 : len(s)-2])
+		if err != nil {
+			return err
+		}
+		if instr.op == 0 {
+			instr.op = opcode.OCTETS
+		}
+		instr.data, instr.n = b, len(b)
+
+	case strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'H"):
+		h := s[1 : len(s)-2]
+		instr.n = len(h)
+		if len(h)%2 == 1 {
+			h += "0"
+		}
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return err
+		}
+		if instr.op == 0 {
+			instr.op = opcode.NIBBLES
+		}
+		instr.data = b
+
+	case strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'B"):
+		bits := s[1 : len(s)-2]
+		instr.n = len(bits)
+		instr.data = make([]byte, (len(bits)+7)/8)
+		for i, c := range bits {
+			switch c {
+			case '1':
+				instr.data[i/8] |= 0x80 >> uint(i%8)
+			case '0':
+			default:
+				return fmt.Errorf("invalid bitstring %s", s)
+			}
+		}
+		if instr.op == 0 {
+			instr.op = opcode.BITS
+		}
+
+	case strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'"):
+		if instr.op == 0 {
+			instr.op = opcode.NAME
+		}
+		v := s[1 : len(s)-1]
+		instr.data, instr.n = []byte(v), len(v)
+
+	case instr.op == opcode.IEEE754DP || (instr.op == 0 && isFloat(s)):
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		instr.op = opcode.IEEE754DP
+		instr.f = f
+
+	default:
+		i, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return err
+		}
+		instr.op = opcode.NATLONG
+		instr.arg = int(i)
+	}
+
+	a.code.instrs = append(a.code.instrs, instr)
+	return nil
+}
+
+func (a *assembler) parseEntry(m []string) error {
+	tables := reflect.ValueOf(&a.file.Tables).Elem()
+	table := tables.FieldByName(a.table)
+	entry := reflect.New(table.Type().Elem()).Elem()
+
+	for _, kv := range strings.Fields(m[3]) {
+		f := strings.SplitN(kv, "=", 2)
+		fv := entry.FieldByName(f[0])
+		if !fv.IsValid() {
+			return fmt.Errorf("unknown field %s in table %s", f[0], a.table)
+		}
+		val := fieldSymbol.ReplaceAllString(f[1], "")
+		switch fv.Kind() {
+		case reflect.Int32:
+			v, err := strconv.ParseInt(val, 10, 32)
+			if err != nil {
+				return err
+			}
+			fv.SetInt(v)
+		default:
+			v, err := strconv.ParseUint(val, 10, fv.Type().Bits())
+			if err != nil {
+				return err
+			}
+			fv.SetUint(v)
+		}
+	}
+	table.Set(reflect.Append(table, entry))
+
+	switch e := entry.Interface().(type) {
+	case String:
+		s, err := strconv.Unquote(m[2])
+		if err != nil {
+			return err
+		}
+		a.putData(int(e.DataOffset), int(e.Length), []byte(s))
+	case Collection:
+		b, err := hex.DecodeString(m[4])
+		if err != nil {
+			return err
+		}
+		a.putData(int(e.DataOffset), int(e.Length), b)
+	case Block:
+		if a.blocks == nil {
+			a.blocks = make(map[int]*code)
+		}
+		a.code = &code{}
+		a.blocks[table.Len()-1] = a.code
+	}
+	return nil
+}
+
+func (a *assembler) putData(offset int, n int, b []byte) {
+	if end := offset + n; end > len(a.data) {
+		a.data = append(a.data, make([]byte, end-len(a.data))...)
+	}
+	copy(a.data[offset:offset+n], b)
+}
+
+// flush encodes all code and builds the file sections.
+func (a *assembler) flush() error {
+	if a.pending != 0 {
+		return fmt.Errorf("missing value for %s", a.pending)
+	}
+
+	var err error
+	if a.file.Sections.T3XF, err = a.t3xf.encode(); err != nil {
+		return err
+	}
+
+	for i, b := range a.file.Tables.Blocks {
+		c, ok := a.blocks[i]
+		if !ok {
+			continue
+		}
+		buf, err := c.encode()
+		if err != nil {
+			return fmt.Errorf("block %d: %w", i, err)
+		}
+		if end := int(b.TextOffset) + len(buf); end > len(a.file.Sections.Text) {
+			a.file.Sections.Text = append(a.file.Sections.Text, make([]byte, end-len(a.file.Sections.Text))...)
+		}
+		copy(a.file.Sections.Text[b.TextOffset:], buf)
+	}
+
+	if a.file.tasm {
+		a.file.Sections.Text = pad(a.file.Sections.Text, a.textSize)
+		a.file.Sections.Data = pad(a.data, a.dataSize)
+	}
+	return nil
+}
+
+// pad appends zeros to b until it has length n.
+func pad(b []byte, n int) []byte {
+	if len(b) >= n {
+		return b
+	}
+	return append(b, make([]byte, n-len(b))...)
+}
+
+// encode returns the binary t3xf representation of the code.
+func (c *code) encode() ([]byte, error) {
+	offsets := make([]int, len(c.instrs))
+	n := 0
+	for i, instr := range c.instrs {
+		offsets[i] = n
+		n += instr.size()
+	}
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	for _, instr := range c.instrs {
+		switch instr.op {
+		case opcode.REF, opcode.FROZEN_REF, opcode.GOTO:
+			arg := instr.arg
+			if !c.offsets {
+				if arg < 1 || arg > len(offsets) {
+					return nil, fmt.Errorf("invalid instruction number %d", arg)
+				}
+				arg = offsets[arg-1]
+			}
+			e.Encode(instr.op, arg)
+		case opcode.NATLONG:
+			e.EncodeNatLong(instr.arg)
+		case opcode.IEEE754DP:
+			e.EncodeFloat64(instr.f)
+		case opcode.UTF8, opcode.NAME, opcode.OCTETS, opcode.NIBBLES, opcode.BITS, opcode.ISTR, opcode.FSTR:
+			e.EncodeString(instr.op, instr.data, instr.n)
+		default:
+			e.Encode(instr.op, instr.arg)
+		}
+	}
+	return buf.Bytes(), e.Err()
+}
+
+// size returns the size of the encoded instruction in bytes.
+func (instr asmInstr) size() int {
+	switch instr.op {
+	case opcode.NATLONG:
+		return 8
+	case opcode.IEEE754DP:
+		return 12
+	case opcode.NIBBLES:
+		return 8 + (instr.n*4+31)/32*4
+	case opcode.BITS:
+		return 8 + (instr.n+31)/32*4
+	case opcode.UTF8, opcode.NAME, opcode.OCTETS, opcode.ISTR, opcode.FSTR:
+		return 8 + (instr.n*8+31)/32*4
+	default:
+		return 4
+	}
+}
+
+// isValue returns true if s is a literal value.
+func isValue(s string) bool {
+	switch s[0] {
+	case '"', '\'', '-', '+':
+		return true
+	}
+	return isNumber(s) || isFloat(s)
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+func isFloat(s string) bool {
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return false
+	}
+	return strings.ContainsAny(s, ".eE") || strings.Contains(s, "Inf") || s == "NaN"
+}
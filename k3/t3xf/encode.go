@@ -0,0 +1,138 @@
+package t3xf
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+
+	"github.com/nokia/ntt/k3/t3xf/opcode"
+)
+
+// Encoder writes t3xf instructions to an output stream.
+//
+// Like binaryReader, Encoder records the first error and ignores all
+// subsequent writes. The error is available through the Err method.
+type Encoder struct {
+	w   io.Writer
+	n   int
+	err error
+}
+
+// NewEncoder returns an Encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Offset returns the number of bytes written so far, which is the offset of the
+// next instruction.
+func (e *Encoder) Offset() int { return e.n }
+
+// Err returns the first error that occurred while encoding.
+func (e *Encoder) Err() error { return e.err }
+
+// Encode writes an instruction without literal data. For pseudo opcodes GOTO,
+// REF and FROZEN_REF arg is an address, for LINE it's the line number.
+func (e *Encoder) Encode(op opcode.Opcode, arg int) {
+	e.write(opcode.Pack(op, arg))
+}
+
+// EncodeNatLong writes a NATLONG instruction.
+func (e *Encoder) EncodeNatLong(v int) {
+	e.Encode(opcode.NATLONG, 0)
+	e.write(int32(v))
+}
+
+// EncodeFloat64 writes an IEEE754DP instruction.
+func (e *Encoder) EncodeFloat64(v float64) {
+	e.Encode(opcode.IEEE754DP, 0)
+	e.write(math.Float64bits(v))
+}
+
+// EncodeString writes a string instruction (UTF8, NAME, OCTETS, NIBBLES, BITS,
+// ISTR, FSTR). The length n is given in units of the instruction, for example
+// nibbles for NIBBLES and bits for BITS. Data b is padded to a multiple of four
+// bytes.
+func (e *Encoder) EncodeString(op opcode.Opcode, b []byte, n int) {
+	factor := 8
+	switch op {
+	case opcode.NIBBLES:
+		factor = 4
+	case opcode.BITS:
+		factor = 1
+	}
+
+	words := (n*factor + 31) / 32
+	data := make([]byte, words*4)
+	copy(data, b)
+
+	e.Encode(op, 0)
+	e.write(uint32(n))
+	e.write(data)
+}
+
+func (e *Encoder) write(v interface{}) {
+	if e.err != nil {
+		return
+	}
+	e.err = binary.Write(e.w, binary.LittleEndian, v)
+	if e.err == nil {
+		e.n += binary.Size(v)
+	}
+}
+
+// WriteTo writes the file in its binary format to w. It implements the
+// io.WriterTo interface.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	e := NewEncoder(w)
+	if !f.tasm {
+		e.write(f.Sections.T3XF)
+		return int64(e.n), e.err
+	}
+
+	size := func(v interface{}) uint32 { return uint32(binary.Size(v)) }
+
+	h := tasmHeader{
+		T3xfSection:          size(f.Sections.T3XF),
+		TextSection:          size(f.Sections.Text),
+		Int32Table:           size(f.Tables.Ints),
+		NameTable:            size(f.Tables.Names),
+		ModuleTable:          size(f.Tables.Modules),
+		TypeAliasTable:       size(f.Tables.TypeAliases),
+		RecordTypeTable:      size(f.Tables.RecordTypes),
+		SetTypeTable:         size(f.Tables.SetTypes),
+		RecordOfTypeTable:    size(f.Tables.RecordOfTypes),
+		SetOfTypeTable:       size(f.Tables.SetOfTypes),
+		UnionTypeTable:       size(f.Tables.UnionTypes),
+		EnumeratedTypeTable:  size(f.Tables.EnumeratedTypes),
+		ArrayTypeTable:       size(f.Tables.ArrayTypes),
+		ClosureTypeTable:     size(f.Tables.ClosureTypes),
+		MessagePortTypeTable: size(f.Tables.MessagePortTypes),
+		ComponentTypeTable:   size(f.Tables.ComponentTypes),
+		ConstTable:           size(f.Tables.Consts),
+		ModuleParTable:       size(f.Tables.ModulePars),
+		TemplateTable:        size(f.Tables.Templates),
+		TestcaseTable:        size(f.Tables.Testcases),
+		FunctionTable:        size(f.Tables.Functions),
+		ExtFunctionTable:     size(f.Tables.ExtFunctions),
+		AltstepTable:         size(f.Tables.Altsteps),
+		BlockTable:           size(f.Tables.Blocks),
+		ControlTable:         size(f.Tables.Controls),
+		StringTable:          size(f.Tables.Strings),
+		CollectionTable:      size(f.Tables.Collections),
+		DataSection:          size(f.Sections.Data),
+	}
+
+	// Tables are stored in the order of their fields.
+	tables := reflect.ValueOf(f.Tables)
+
+	e.write([]byte("T3XFASM\x00"))
+	e.write(h)
+	e.write(f.Sections.T3XF)
+	e.write(f.Sections.Text)
+	for i := 0; i < tables.NumField(); i++ {
+		e.write(tables.Field(i).Interface())
+	}
+	e.write(f.Sections.Data)
+	return int64(e.n), e.err
+}
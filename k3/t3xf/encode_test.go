@@ -0,0 +1,106 @@
+package t3xf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/k3/t3xf/opcode"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWriteTo verifies that encoding a decoded file results in the original
+// file. The test data files are small synthetic files, not produced by k3c.
+func TestWriteTo(t *testing.T) {
+	files, err := filepath.Glob("testdata/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		want, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := read(bytes.NewReader(want))
+		if err != nil {
+			t.Fatalf("%s: %s", file, err.Error())
+		}
+		var got bytes.Buffer
+		n, err := f.WriteTo(&got)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(len(want)), n)
+		assert.Equal(t, want, got.Bytes(), file)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	e.Encode(opcode.SCAN, 0)
+	e.Encode(opcode.IDEF, 3)
+	e.Encode(opcode.LINE, 42)
+	e.Encode(opcode.GOTO, 8)
+	e.Encode(opcode.REF, 4)
+	e.Encode(opcode.FROZEN_REF, 4)
+	e.EncodeNatLong(-2)
+	e.EncodeFloat64(23.5)
+	e.EncodeString(opcode.NIBBLES, []byte{0xab, 0xc0}, 3)
+	e.Encode(opcode.BLOCK, 0)
+	assert.Nil(t, e.Err())
+	assert.Equal(t, b.Len(), e.Offset())
+
+	s := NewScanner(b.Bytes())
+	expect := func(op opcode.Opcode, arg int) {
+		assert.True(t, s.Scan())
+		assert.Equal(t, op, s.Opcode())
+		assert.Equal(t, arg, s.Arg())
+	}
+	expect(opcode.SCAN, 56)
+	expect(opcode.IDEF, 3)
+	expect(opcode.LINE, 42)
+	expect(opcode.GOTO, 8)
+	expect(opcode.REF, 4)
+	expect(opcode.FROZEN_REF, 4)
+	expect(opcode.NATLONG, -2)
+	expect(opcode.IEEE754DP, 0)
+	assert.Equal(t, 23.5, s.Float64())
+	expect(opcode.NIBBLES, 12)
+	assert.Equal(t, []byte{0xab, 0xc0}, s.Bytes())
+	expect(opcode.BLOCK, 0)
+	assert.False(t, s.Scan())
+	assert.Nil(t, s.Err())
+}
+
+func TestAssemble(t *testing.T) {
+	t.Run("legacy references", func(t *testing.T) {
+		f, err := Assemble(strings.NewReader("scan\n  'x'\n  L2\n  @1\nblock\n"))
+		assert.Nil(t, err)
+		assert.False(t, f.IsTASM())
+
+		var b bytes.Buffer
+		e := NewEncoder(&b)
+		e.Encode(opcode.SCAN, 0)
+		e.EncodeString(opcode.NAME, []byte("x"), 1)
+		e.Encode(opcode.REF, 4)
+		e.Encode(opcode.GOTO, 0)
+		e.Encode(opcode.BLOCK, 0)
+		assert.Equal(t, b.Bytes(), f.Sections.T3XF)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, input := range []string{
+			"foobar",
+			"natlong",
+			"'xyz'O",
+			"'102'B",
+			"L5",
+			"Foobars:\n",
+			"Modules:\n     0: Foo=1",
+		} {
+			_, err := Assemble(strings.NewReader(input))
+			assert.NotNil(t, err, input)
+		}
+	})
+}
@@ -0,0 +1,439 @@
+package gen
+
+import (
+	"github.com/nokia/ntt/k3/t3xf/opcode"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// predefinedTypes maps the names of TTCN-3 predefined types to their opcodes.
+var predefinedTypes = map[string]opcode.Opcode{
+	"address":              opcode.ADDRESS,
+	"bitstring":            opcode.BITSTRING,
+	"boolean":              opcode.BOOLEAN,
+	"charstring":           opcode.CHARSTRING,
+	"default":              opcode.DEFAULT,
+	"float":                opcode.FLOAT,
+	"hexstring":            opcode.HEXSTRING,
+	"integer":              opcode.INTEGER,
+	"octetstring":          opcode.OCTETSTRING,
+	"universal charstring": opcode.CHARSTRINGU,
+	"verdicttype":          opcode.VERDICTTYPE,
+}
+
+// module emits: scan <defs> block name '<name>' module
+func (g *generator) module(m *module) {
+	g.cur = m
+	g.scope = m.scope
+	g.line = 0
+
+	g.block(func() {
+		g.defs(m.node.Defs)
+	})
+	g.name(m.node.Name.String())
+	g.define(m.node)
+	g.enc.Encode(opcode.MODULE, 0)
+}
+
+func (g *generator) defs(defs []*ast.ModuleDef) {
+	for _, d := range defs {
+		g.def(d.Def)
+	}
+}
+
+func (g *generator) def(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.ImportDecl:
+		// Imports are resolved by the generator. Only the dependency is
+		// recorded: name '<module>' import
+		g.lineOf(n)
+		g.name(n.Module.String())
+		g.enc.Encode(opcode.IMPORT, 0)
+
+	case *ast.GroupDecl:
+		g.defs(n.Defs)
+
+	case *ast.ValueDecl:
+		g.valueDecl(n)
+
+	case *ast.ModuleParameterGroup:
+		for _, vd := range n.Decls {
+			g.valueDecl(vd)
+		}
+
+	case *ast.FuncDecl:
+		g.funcDecl(n)
+
+	case *ast.ControlPart:
+		g.lineOf(n)
+		g.pushScope()
+		g.block(func() { g.stmts(n.Body.Stmts) })
+		g.popScope()
+		g.define(n)
+		g.enc.Encode(opcode.CONTROL, 0)
+
+	case *ast.SubTypeDecl:
+		g.lineOf(n)
+		if n.Field == nil || n.Field.Name == nil {
+			g.unsupported(n, "anonymous type")
+			return
+		}
+		g.field(n.Field)
+		g.name(n.Field.Name.String())
+		g.define(n)
+		g.enc.Encode(opcode.TYPE, 0)
+
+	case *ast.StructTypeDecl:
+		g.lineOf(n)
+		if n.TypePars != nil {
+			g.unsupported(n.TypePars, "type parameterization")
+		}
+		g.structSpec(n.Kind, n.Fields)
+		g.name(n.Name.String())
+		g.define(n)
+		g.enc.Encode(opcode.TYPE, 0)
+
+	case *ast.EnumTypeDecl:
+		g.lineOf(n)
+		g.enumSpec(n.Enums)
+		g.name(n.Name.String())
+		g.define(n)
+		g.enc.Encode(opcode.TYPE, 0)
+
+	case *ast.ComponentTypeDecl:
+		g.lineOf(n)
+		if len(n.Extends) > 0 {
+			g.unsupported(n.Extends[0], "component extension")
+		}
+		g.pushScope()
+		g.block(func() { g.stmts(n.Body.Stmts) })
+		g.popScope()
+		g.enc.Encode(opcode.COMPONENT, 0)
+		g.name(n.Name.String())
+		g.define(n)
+		g.enc.Encode(opcode.TYPE, 0)
+
+	case *ast.TemplateDecl:
+		g.unsupported(n, "template")
+	case *ast.PortTypeDecl:
+		g.unsupported(n, "port type")
+	case *ast.SignatureDecl:
+		g.unsupported(n, "signature")
+	case *ast.BehaviourTypeDecl:
+		g.unsupported(n, "behaviour type")
+	case *ast.FriendDecl:
+		g.unsupported(n, "friend module")
+	default:
+		g.unsupported(n, "definition")
+	}
+}
+
+// valueDecl emits variables, timers, constants and module parameters:
+//
+//	<type> name 'x' var [ref x <value> assign]
+//	<type> <value> name 'x' const
+//	<type> name 'x' mpar
+//	<type> <value> name 'x' mpard
+func (g *generator) valueDecl(n *ast.ValueDecl) {
+	kind := n.Kind.String()
+	switch kind {
+	case "var", "const", "modulepar", "timer":
+	case "template":
+		g.unsupported(n, "template")
+		return
+	default:
+		g.unsupported(n, kind+" declaration")
+		return
+	}
+	if n.TemplateRestriction != nil {
+		g.unsupported(n, "template variable")
+		return
+	}
+
+	for _, decl := range n.Decls {
+		g.lineOf(decl)
+		if len(decl.ArrayDef) > 0 {
+			g.unsupported(decl.ArrayDef[0], "array definition")
+			continue
+		}
+
+		if g.scope != g.cur.scope {
+			g.scope.insert(decl.Name.String(), &symbol{node: decl, kind: valueKind(n)})
+		}
+
+		if kind == "timer" {
+			g.enc.Encode(opcode.TIMER, 0)
+		} else {
+			g.typ(n.Type)
+		}
+
+		switch {
+		case kind == "const":
+			if decl.Value == nil {
+				g.errorf(g.cur.tree, decl, "constant %s without value", decl.Name.String())
+				continue
+			}
+			g.expr(decl.Value)
+			g.name(decl.Name.String())
+			g.define(decl)
+			g.enc.Encode(opcode.CONST, 0)
+
+		case kind == "modulepar" && decl.Value != nil:
+			g.expr(decl.Value)
+			g.name(decl.Name.String())
+			g.define(decl)
+			g.enc.Encode(opcode.MPARD, 0)
+
+		case kind == "modulepar":
+			g.name(decl.Name.String())
+			g.define(decl)
+			g.enc.Encode(opcode.MPAR, 0)
+
+		default:
+			g.name(decl.Name.String())
+			g.define(decl)
+			g.enc.Encode(opcode.VAR, 0)
+			if decl.Value != nil {
+				g.ref(opcode.REF, decl, decl)
+				g.expr(decl.Value)
+				g.enc.Encode(opcode.ASSIGN, 0)
+			}
+		}
+	}
+}
+
+// funcDecl emits functions and testcases:
+//
+//	scan <params> block [<return type>] [<runs on>] scan <body> block name 'f' function[v][b]
+//	scan <params> block [<return type>] name 'f' functionx[v]
+//	scan <params> block <runs on> [<system>] scan <body> block name 't' testcase[s]
+func (g *generator) funcDecl(n *ast.FuncDecl) {
+	g.lineOf(n)
+
+	switch n.Kind.Kind {
+	case token.FUNCTION, token.TESTCASE:
+	default:
+		g.unsupported(n, n.Kind.String())
+		return
+	}
+	if n.TypePars != nil {
+		g.unsupported(n.TypePars, "type parameterization")
+		return
+	}
+	if n.Mtc != nil {
+		g.unsupported(n.Mtc, "mtc clause")
+	}
+
+	g.pushScope()
+	defer g.popScope()
+
+	g.block(func() { g.formalPars(n.Params) })
+
+	if n.Return != nil {
+		if n.Return.Restriction != nil {
+			g.unsupported(n.Return, "template return")
+		}
+		g.typ(n.Return.Type)
+	}
+
+	if n.RunsOn != nil {
+		g.typ(n.RunsOn.Comp)
+		g.componentScope(n.RunsOn.Comp)
+	}
+
+	var op opcode.Opcode
+	switch {
+	case n.Kind.Kind == token.TESTCASE:
+		if n.RunsOn == nil {
+			g.errorf(g.cur.tree, n, "testcase %s requires runs on clause", n.Name.String())
+		}
+		op = opcode.TESTCASE
+		if n.System != nil {
+			g.typ(n.System.Comp)
+			op = opcode.TESTCASES
+		}
+
+	case n.External.IsValid():
+		op = opcode.FUNCTIONX
+		if n.Return != nil {
+			op = opcode.FUNCTIONXV
+		}
+
+	default:
+		op = opcode.FUNCTION
+		switch {
+		case n.Return != nil && n.RunsOn != nil:
+			op = opcode.FUNCTIONVB
+		case n.Return != nil:
+			op = opcode.FUNCTIONV
+		case n.RunsOn != nil:
+			op = opcode.FUNCTIONB
+		}
+	}
+
+	if n.Body != nil {
+		g.block(func() { g.stmts(n.Body.Stmts) })
+	}
+	g.name(n.Name.String())
+	g.define(n)
+	g.enc.Encode(op, 0)
+}
+
+// formalPars emits: <type> name 'p' in|out|inout
+func (g *generator) formalPars(n *ast.FormalPars) {
+	if n == nil {
+		return
+	}
+	for _, p := range n.List {
+		switch {
+		case p.TemplateRestriction != nil:
+			g.unsupported(p, "template parameter")
+			continue
+		case len(p.ArrayDef) > 0:
+			g.unsupported(p, "array parameter")
+			continue
+		case p.Value != nil:
+			g.unsupported(p.Value, "default parameter value")
+		}
+
+		var op opcode.Opcode = opcode.IN
+		switch p.Direction.Kind {
+		case token.OUT:
+			op = opcode.OUT
+		case token.INOUT:
+			op = opcode.INOUT
+		}
+
+		g.scope.insert(p.Name.String(), &symbol{node: p, kind: valueSym})
+		g.typ(p.Type)
+		g.name(p.Name.String())
+		g.define(p)
+		g.enc.Encode(op, 0)
+	}
+}
+
+// componentScope makes the variables, constants and timers of a component
+// type visible to the current behaviour.
+func (g *generator) componentScope(x ast.Expr) {
+	sym := g.lookupExpr(x)
+	if sym == nil {
+		return
+	}
+	c, ok := sym.node.(*ast.ComponentTypeDecl)
+	if !ok {
+		g.errorf(g.cur.tree, x, "%s is not a component type", ast.Name(x))
+		return
+	}
+	for _, s := range c.Body.Stmts {
+		if d, ok := s.(*ast.DeclStmt); ok {
+			if vd, ok := d.Decl.(*ast.ValueDecl); ok {
+				for _, decl := range vd.Decls {
+					g.scope.insert(decl.Name.String(), &symbol{node: decl, kind: valueKind(vd)})
+				}
+			}
+		}
+	}
+}
+
+// typ emits a type reference. Predefined types are emitted as their opcode,
+// user defined types as reference to their TYPE instruction.
+func (g *generator) typ(x ast.Expr) {
+	if x == nil {
+		return
+	}
+	if op, ok := predefinedTypes[ast.Name(x)]; ok {
+		if _, ok := x.(*ast.Ident); ok {
+			g.enc.Encode(op, 0)
+			return
+		}
+	}
+	sym := g.lookupExpr(x)
+	if sym == nil {
+		return
+	}
+	if sym.kind != typeSym {
+		g.errorf(g.cur.tree, x, "%s is not a type", ast.Name(x))
+		return
+	}
+	g.ref(opcode.REF, sym.node, x)
+}
+
+// typeSpec emits anonymous type specifications, like nested records.
+func (g *generator) typeSpec(n ast.TypeSpec) {
+	switch n := n.(type) {
+	case *ast.RefSpec:
+		g.typ(n.X)
+	case *ast.StructSpec:
+		g.structSpec(n.Kind, n.Fields)
+	case *ast.EnumSpec:
+		g.enumSpec(n.Enums)
+	case *ast.ListSpec:
+		if n.Length != nil {
+			g.unsupported(n.Length, "length restriction")
+		}
+		g.typeSpec(n.ElemType)
+		if n.Kind.Kind == token.SET {
+			g.enc.Encode(opcode.SETOF, 0)
+		} else {
+			g.enc.Encode(opcode.RECORDOF, 0)
+		}
+	default:
+		g.unsupported(n, "type specification")
+	}
+}
+
+// structSpec emits: scan <fields> block record|set|union
+func (g *generator) structSpec(kind ast.Token, fields []*ast.Field) {
+	g.block(func() {
+		for _, f := range fields {
+			g.field(f)
+			g.name(f.Name.String())
+			if f.Optional.IsValid() {
+				g.enc.Encode(opcode.FIELDO, 0)
+			} else {
+				g.enc.Encode(opcode.FIELD, 0)
+			}
+		}
+	})
+	switch kind.Kind {
+	case token.SET:
+		g.enc.Encode(opcode.SET, 0)
+	case token.UNION:
+		g.enc.Encode(opcode.UNION, 0)
+	default:
+		g.enc.Encode(opcode.RECORD, 0)
+	}
+}
+
+// field emits the type of a field or sub type definition.
+func (g *generator) field(f *ast.Field) {
+	switch {
+	case f.DefaultTok.IsValid():
+		g.unsupported(f, "@default modifier")
+	case len(f.ArrayDef) > 0:
+		g.unsupported(f.ArrayDef[0], "array definition")
+	case f.TypePars != nil:
+		g.unsupported(f.TypePars, "type parameterization")
+	case f.ValueConstraint != nil:
+		g.unsupported(f.ValueConstraint, "value constraint")
+	case f.LengthConstraint != nil:
+		g.unsupported(f.LengthConstraint, "length constraint")
+	}
+	g.typeSpec(f.Type)
+}
+
+// enumSpec emits: scan name 'e1' name 'e2' ... block enumerated
+func (g *generator) enumSpec(enums []ast.Expr) {
+	g.block(func() {
+		for _, e := range enums {
+			id, ok := e.(*ast.Ident)
+			if !ok {
+				g.unsupported(e, "enumerated value with explicit number")
+				continue
+			}
+			g.define(id)
+			g.name(id.String())
+		}
+	})
+	g.enc.Encode(opcode.ENUMERATED, 0)
+}
@@ -0,0 +1,490 @@
+package gen
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/nokia/ntt/k3/t3xf/opcode"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// binaryOps maps TTCN-3 operators to opcodes. Bitwise string operators share
+// the opcodes of their boolean counterparts.
+var binaryOps = map[token.Kind]opcode.Opcode{
+	token.ADD:    opcode.ADD,
+	token.SUB:    opcode.SUB,
+	token.MUL:    opcode.MUL,
+	token.DIV:    opcode.DIV,
+	token.MOD:    opcode.MOD,
+	token.REM:    opcode.REM,
+	token.AND:    opcode.AND,
+	token.OR:     opcode.OR,
+	token.XOR:    opcode.XOR,
+	token.AND4B:  opcode.AND,
+	token.OR4B:   opcode.OR,
+	token.XOR4B:  opcode.XOR,
+	token.SHL:    opcode.SHL,
+	token.SHR:    opcode.SHR,
+	token.ROL:    opcode.ROL,
+	token.ROR:    opcode.ROR,
+	token.CONCAT: opcode.CAT,
+	token.EQ:     opcode.EQ,
+	token.NE:     opcode.NE,
+	token.LT:     opcode.LT,
+	token.LE:     opcode.LE,
+	token.GT:     opcode.GT,
+	token.GE:     opcode.GE,
+}
+
+// literals maps keyword literals to opcodes.
+var literals = map[token.Kind]opcode.Opcode{
+	token.TRUE:   opcode.TRUE,
+	token.FALSE:  opcode.FALSE,
+	token.NONE:   opcode.NONE,
+	token.PASS:   opcode.PASS,
+	token.INCONC: opcode.INCONC,
+	token.FAIL:   opcode.FAIL,
+	token.ERROR:  opcode.ERROR,
+	token.OMIT:   opcode.OMIT,
+	token.NULL:   opcode.OPNULL,
+	token.SUB:    opcode.SKIP,
+}
+
+// predefinedFuncs maps TTCN-3 predefined functions to opcodes. Their
+// arguments are pushed in order.
+var predefinedFuncs = map[string]opcode.Opcode{
+	"int2char":     opcode.INT2CHAR,
+	"int2unichar":  opcode.INT2UNICHAR,
+	"int2bit":      opcode.INT2BIT,
+	"int2hex":      opcode.INT2HEX,
+	"int2oct":      opcode.INT2OCT,
+	"int2str":      opcode.INT2STR,
+	"int2float":    opcode.INT2FLOAT,
+	"float2int":    opcode.FLOAT2INT,
+	"char2int":     opcode.CHAR2INT,
+	"char2oct":     opcode.CHAR2OCT,
+	"unichar2int":  opcode.UNICHAR2INT,
+	"bit2int":      opcode.BIT2INT,
+	"bit2hex":      opcode.BIT2HEX,
+	"bit2oct":      opcode.BIT2OCT,
+	"bit2str":      opcode.BIT2STR,
+	"hex2int":      opcode.HEX2INT,
+	"hex2bit":      opcode.HEX2BIT,
+	"hex2oct":      opcode.HEX2OCT,
+	"hex2str":      opcode.HEX2STR,
+	"oct2int":      opcode.OCT2INT,
+	"oct2bit":      opcode.OCT2BIT,
+	"oct2hex":      opcode.OCT2HEX,
+	"oct2str":      opcode.OCT2STR,
+	"oct2char":     opcode.OCT2CHR,
+	"str2int":      opcode.STR2INT,
+	"str2oct":      opcode.STR2OCT,
+	"str2float":    opcode.STR2FLOAT,
+	"str2hex":      opcode.STR2HEX,
+	"lengthof":     opcode.LENGTHOF,
+	"sizeof":       opcode.SIZEOF,
+	"ispresent":    opcode.ISPRESENT,
+	"ischosen":     opcode.ISCHOSEN,
+	"isvalue":      opcode.ISVALUE,
+	"isbound":      opcode.ISBOUND,
+	"regexp":       opcode.REGEXP,
+	"substr":       opcode.SUBSTR,
+	"replace":      opcode.REPLACE,
+	"enum2int":     opcode.ENUM2INT,
+	"int2enum":     opcode.INT2ENUM,
+	"encvalue":     opcode.ENCVALUE,
+	"decvalue":     opcode.DECVALUE,
+	"testcasename": opcode.TESTCASENAME,
+}
+
+// expr emits code pushing the value of x.
+func (g *generator) expr(x ast.Expr) {
+	switch x := x.(type) {
+	case *ast.ValueLiteral:
+		g.literal(x)
+
+	case *ast.Ident:
+		g.ident(x)
+
+	case *ast.SelectorExpr:
+		if sym, ok := g.qualified(x); ok {
+			if sym != nil {
+				g.symbol(sym, x)
+			}
+			return
+		}
+		id, ok := x.Sel.(*ast.Ident)
+		if !ok {
+			g.unsupported(x.Sel, "selector")
+			return
+		}
+		g.expr(x.X)
+		g.name(id.String())
+		g.enc.Encode(opcode.GET, 0)
+
+	case *ast.IndexExpr:
+		g.expr(x.X)
+		g.expr(x.Index)
+		g.enc.Encode(opcode.GET, 0)
+
+	case *ast.ParenExpr:
+		if len(x.List) != 1 {
+			g.unsupported(x, "value list")
+			return
+		}
+		g.expr(x.List[0])
+
+	case *ast.UnaryExpr:
+		if id, ok := x.X.(*ast.Ident); ok && x.Op.Kind == token.SUB && id.String() == "infinity" {
+			g.enc.Encode(opcode.INFINITYN, 0)
+			return
+		}
+		g.expr(x.X)
+		switch x.Op.Kind {
+		case token.SUB:
+			g.enc.Encode(opcode.NEG, 0)
+		case token.ADD:
+			g.enc.Encode(opcode.POS, 0)
+		case token.NOT, token.NOT4B:
+			g.enc.Encode(opcode.NOT, 0)
+		default:
+			g.unsupported(x, "operator "+x.Op.String())
+		}
+
+	case *ast.BinaryExpr:
+		op, ok := binaryOps[x.Op.Kind]
+		if !ok {
+			g.unsupported(x, "operator "+x.Op.String())
+			return
+		}
+		g.expr(x.X)
+		g.expr(x.Y)
+		g.enc.Encode(op, 0)
+
+	// mark <values> vlist
+	case *ast.CompositeLiteral:
+		g.enc.Encode(opcode.MARK, 0)
+		for _, e := range x.List {
+			if b, ok := e.(*ast.BinaryExpr); ok && b.Op.Kind == token.ASSIGN {
+				g.unsupported(e, "assignment notation")
+				continue
+			}
+			g.expr(e)
+		}
+		g.enc.Encode(opcode.VLIST, 0)
+
+	case *ast.CallExpr:
+		if !g.call(x) {
+			g.errorf(g.cur.tree, x, "%s does not return a value", ast.Name(x.Fun))
+		}
+
+	default:
+		g.unsupported(x, "expression")
+	}
+}
+
+// lvalue emits a reference to an assignable object.
+func (g *generator) lvalue(x ast.Expr) {
+	var sym *symbol
+	switch x := x.(type) {
+	case *ast.Ident:
+		sym = g.lookupExpr(x)
+	case *ast.SelectorExpr:
+		sym, _ = g.qualified(x)
+	}
+	if sym != nil && sym.kind != valueSym {
+		g.errorf(g.cur.tree, x, "cannot assign to %s", ast.Name(x))
+		return
+	}
+	g.expr(x)
+}
+
+func (g *generator) literal(x *ast.ValueLiteral) {
+	tok := x.Tok
+	if op, ok := literals[tok.Kind]; ok {
+		g.enc.Encode(op, 0)
+		return
+	}
+
+	switch tok.Kind {
+	case token.INT:
+		i, err := strconv.ParseInt(tok.Lit, 10, 32)
+		if err != nil {
+			// Large integers are encoded as decimal string.
+			g.enc.EncodeString(opcode.ISTR, []byte(tok.Lit), len(tok.Lit))
+			return
+		}
+		g.enc.EncodeNatLong(int(i))
+
+	case token.FLOAT:
+		f, err := strconv.ParseFloat(tok.Lit, 64)
+		if err != nil {
+			g.errorf(g.cur.tree, x, "invalid float %s", tok.Lit)
+			return
+		}
+		g.enc.EncodeFloat64(f)
+
+	case token.NAN:
+		g.enc.EncodeFloat64(math.NaN())
+
+	case token.STRING:
+		s := strings.TrimSuffix(strings.TrimPrefix(tok.Lit, `"`), `"`)
+		s = strings.Replace(s, `""`, `"`, -1)
+		g.enc.EncodeString(opcode.UTF8, []byte(s), len(s))
+
+	case token.BSTRING:
+		g.bstring(x)
+
+	default:
+		g.unsupported(x, "literal "+tok.String())
+	}
+}
+
+// bstring emits bitstring, hexstring and octetstring literals. The data is
+// packed most significant bit first.
+func (g *generator) bstring(x *ast.ValueLiteral) {
+	lit := x.Tok.Lit
+	if len(lit) < 3 || lit[0] != '\'' || lit[len(lit)-2] != '\'' {
+		g.errorf(g.cur.tree, x, "invalid string %s", lit)
+		return
+	}
+	digits := strings.Join(strings.Fields(lit[1:len(lit)-2]), "")
+
+	var (
+		op   opcode.Opcode
+		bits int
+	)
+	switch lit[len(lit)-1] {
+	case 'B', 'b':
+		op, bits = opcode.BITS, 1
+	case 'H', 'h':
+		op, bits = opcode.NIBBLES, 4
+	case 'O', 'o':
+		op, bits = opcode.OCTETS, 4
+	default:
+		g.errorf(g.cur.tree, x, "invalid string %s", lit)
+		return
+	}
+
+	b := make([]byte, (len(digits)*bits+7)/8)
+	for i, c := range digits {
+		v, err := strconv.ParseUint(string(c), 1<<bits, 8)
+		if err != nil {
+			g.unsupported(x, "string pattern")
+			return
+		}
+		pos := i * bits
+		b[pos/8] |= byte(v) << (8 - bits - pos%8)
+	}
+
+	n := len(digits)
+	if op == opcode.OCTETS {
+		if n%2 != 0 {
+			g.errorf(g.cur.tree, x, "odd number of digits in octetstring %s", lit)
+			return
+		}
+		n /= 2
+	}
+	g.enc.EncodeString(op, b, n)
+}
+
+func (g *generator) ident(x *ast.Ident) {
+	switch x.Tok.Kind {
+	case token.MTC:
+		g.enc.Encode(opcode.MTC, 0)
+		return
+	case token.SYSTEM:
+		g.enc.Encode(opcode.SYSTEM, 0)
+		return
+	}
+
+	if sym := g.lookup(x.String()); sym != nil {
+		g.symbol(sym, x)
+		return
+	}
+
+	switch x.String() {
+	case "self":
+		g.enc.Encode(opcode.SELF, 0)
+	case "infinity":
+		g.enc.Encode(opcode.INFINITYP, 0)
+	case "getverdict":
+		g.enc.Encode(opcode.GETVERDICT, 0)
+	default:
+		g.errorf(g.cur.tree, x, "undefined: %s", x.String())
+	}
+}
+
+// symbol emits a reference to sym.
+func (g *generator) symbol(sym *symbol, x ast.Expr) {
+	switch sym.kind {
+	case frozenSym:
+		g.ref(opcode.FROZEN_REF, sym.node, x)
+	case typeSym:
+		g.errorf(g.cur.tree, x, "type %s used as value", ast.Name(x))
+	default:
+		g.ref(opcode.REF, sym.node, x)
+	}
+}
+
+// qualified resolves module qualified references, like "M.x". The boolean
+// result reports whether x is qualified by a module name. Undefined names are
+// reported as error.
+func (g *generator) qualified(x *ast.SelectorExpr) (*symbol, bool) {
+	mid, ok := x.X.(*ast.Ident)
+	if !ok || g.lookup(mid.String()) != nil {
+		return nil, false
+	}
+	m, ok := g.modules[mid.String()]
+	if !ok {
+		return nil, false
+	}
+	sym := m.scope.lookup(ast.Name(x.Sel))
+	if sym == nil {
+		g.errorf(g.cur.tree, x, "undefined: %s", ast.Name(x))
+	}
+	return sym, true
+}
+
+// lookupExpr resolves identifiers and module qualified identifiers. Errors are
+// reported for undefined names.
+func (g *generator) lookupExpr(x ast.Expr) *symbol {
+	switch x := x.(type) {
+	case *ast.Ident:
+		if sym := g.lookup(x.String()); sym != nil {
+			return sym
+		}
+	case *ast.SelectorExpr:
+		if sym, ok := g.qualified(x); ok {
+			return sym
+		}
+	default:
+		g.unsupported(x, "reference")
+		return nil
+	}
+	g.errorf(g.cur.tree, x, "undefined: %s", ast.Name(x))
+	return nil
+}
+
+// call emits a function call and returns true if the call returns a value:
+//
+//	<args> ref f apply
+//	<args> <predefined function>
+//	mark <args> vlist log|action|setverdict
+//	<args> ref tc [<timeout>] execute[l]
+func (g *generator) call(x *ast.CallExpr) bool {
+	var args []ast.Expr
+	if x.Args != nil {
+		args = x.Args.List
+	}
+
+	if _, ok := x.Fun.(*ast.Ident); !ok {
+		if _, ok := x.Fun.(*ast.SelectorExpr); !ok {
+			g.unsupported(x, "call")
+			return true
+		}
+	}
+
+	name := ast.Name(x.Fun)
+	if sym := g.lookupCallee(x.Fun); sym != nil {
+		f, ok := sym.node.(*ast.FuncDecl)
+		if !ok || f.Kind.Kind != token.FUNCTION {
+			g.errorf(g.cur.tree, x, "cannot call %s", name)
+			return true
+		}
+		g.args(args)
+		g.ref(opcode.REF, f, x.Fun)
+		g.enc.Encode(opcode.APPLY, 0)
+		return f.Return != nil
+	}
+
+	if op, ok := predefinedFuncs[name]; ok {
+		g.args(args)
+		g.enc.Encode(op, 0)
+		return true
+	}
+
+	switch name {
+	case "rnd":
+		g.args(args)
+		if len(args) > 0 {
+			g.enc.Encode(opcode.RNDS, 0)
+		} else {
+			g.enc.Encode(opcode.RND, 0)
+		}
+		return true
+
+	case "log", "action", "setverdict":
+		op, _ := opcode.Lookup(name)
+		g.enc.Encode(opcode.MARK, 0)
+		g.args(args)
+		g.enc.Encode(opcode.VLIST, 0)
+		g.enc.Encode(op, 0)
+		return false
+
+	case "execute":
+		if len(args) == 0 || len(args) > 2 {
+			g.errorf(g.cur.tree, x, "execute requires a testcase and an optional timeout")
+			return true
+		}
+		tc, ok := args[0].(*ast.CallExpr)
+		sym := (*symbol)(nil)
+		if ok {
+			sym = g.lookupExpr(tc.Fun)
+		}
+		if f, isFunc := symNode(sym).(*ast.FuncDecl); !isFunc || f.Kind.Kind != token.TESTCASE {
+			g.errorf(g.cur.tree, args[0], "execute requires a testcase")
+			return true
+		}
+		if tc.Args != nil {
+			g.args(tc.Args.List)
+		}
+		g.ref(opcode.REF, sym.node, tc.Fun)
+		if len(args) == 2 {
+			g.expr(args[1])
+			g.enc.Encode(opcode.EXECUTEL, 0)
+		} else {
+			g.enc.Encode(opcode.EXECUTE, 0)
+		}
+		return true
+	}
+
+	if _, ok := x.Fun.(*ast.SelectorExpr); ok {
+		g.unsupported(x, "operation "+name)
+	} else {
+		g.errorf(g.cur.tree, x, "undefined: %s", name)
+	}
+	return true
+}
+
+// lookupCallee resolves user defined functions without reporting errors, so
+// predefined functions can be looked up afterwards.
+func (g *generator) lookupCallee(x ast.Expr) *symbol {
+	switch x := x.(type) {
+	case *ast.Ident:
+		return g.lookup(x.String())
+	case *ast.SelectorExpr:
+		sym, _ := g.qualified(x)
+		return sym
+	}
+	return nil
+}
+
+func (g *generator) args(list []ast.Expr) {
+	for _, a := range list {
+		if b, ok := a.(*ast.BinaryExpr); ok && b.Op.Kind == token.ASSIGN {
+			g.unsupported(a, "named argument")
+			continue
+		}
+		g.expr(a)
+	}
+}
+
+func symNode(sym *symbol) ast.Node {
+	if sym == nil {
+		return nil
+	}
+	return sym.node
+}
@@ -0,0 +1,256 @@
+// Package gen lowers TTCN-3 syntax trees into T3XF byte code.
+//
+// The generator supports the core language subset: modules, imports, groups,
+// constants, module parameters, component, record, set, union, record of and
+// enumerated types, functions, testcases and control parts with basic
+// statements and expressions. Constructs outside this subset, like templates,
+// ports, altsteps and alternatives, are reported as errors.
+//
+// T3XF is a postfix notation. Operands are emitted before their operator and
+// nested code, like function bodies or loop conditions, is enclosed in
+// SCAN/BLOCK pairs. Definitions end with a NAME instruction followed by the
+// defining instruction:
+//
+//	scan ... block name 'M' module
+//	integer natlong 23 name 'x' const
+//	scan <params> block integer scan <body> block name 'f' functionv
+//
+// References point to the defining instruction of the referenced object, for
+// example the CONST or FUNCTION instruction. Constants and module parameters
+// are referenced by FROZEN_REF, all other objects by REF. LINE instructions
+// precede every statement whose line differs from the previous one.
+package gen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/k3/t3xf"
+	"github.com/nokia/ntt/k3/t3xf/opcode"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+)
+
+// Version is the T3XF version of the generated code.
+const Version = 2
+
+// Generate lowers all modules found in trees into a single T3XF file and
+// writes it to w. Syntax errors and unsupported language constructs are
+// returned as a multierror and nothing is written.
+func Generate(w io.Writer, trees ...*ttcn3.Tree) error {
+	g := newGenerator()
+	for _, tree := range trees {
+		if tree.Err != nil {
+			g.errs = multierror.Append(g.errs, tree.Err)
+			continue
+		}
+		g.declareModules(tree)
+	}
+	if err := g.errs.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	g.header()
+	for _, m := range g.order {
+		g.module(m)
+	}
+	g.resolve()
+
+	if err := g.errs.ErrorOrNil(); err != nil {
+		return err
+	}
+	if err := g.enc.Err(); err != nil {
+		return err
+	}
+	_, err := w.Write(g.buf.Bytes())
+	return err
+}
+
+// module is a TTCN-3 module to be generated.
+type module struct {
+	tree  *ttcn3.Tree
+	node  *ast.Module
+	scope *scope
+}
+
+// fixup is a reference to a definition whose address was not known when the
+// reference was emitted.
+type fixup struct {
+	offset int
+	op     opcode.Opcode
+	def    ast.Node
+	pos    loc.Pos
+	tree   *ttcn3.Tree
+}
+
+type generator struct {
+	buf bytes.Buffer
+	enc *t3xf.Encoder
+
+	modules map[string]*module
+	order   []*module
+
+	cur    *module          // Module currently generated
+	scope  *scope           // Current scope
+	addrs  map[ast.Node]int // Addresses of defining instructions
+	fixups []fixup
+	line   int // Line of the most recent LINE instruction
+	errs   *multierror.Error
+}
+
+func newGenerator() *generator {
+	g := &generator{
+		modules: make(map[string]*module),
+		addrs:   make(map[ast.Node]int),
+	}
+	g.enc = t3xf.NewEncoder(&g.buf)
+	return g
+}
+
+// header writes the T3XF magic: NOP 0x1300, NATLONG 2, VERSION.
+func (g *generator) header() {
+	g.enc.Encode(opcode.NOP, 0x1300)
+	g.enc.EncodeNatLong(Version)
+	g.enc.Encode(opcode.VERSION, 0)
+}
+
+// declareModules registers all modules of tree, so imports and qualified
+// references can be resolved independent of file order.
+func (g *generator) declareModules(tree *ttcn3.Tree) {
+	ast.Inspect(tree.Root, func(n ast.Node) bool {
+		m, ok := n.(*ast.Module)
+		if !ok {
+			return true
+		}
+		name := m.Name.String()
+		if _, ok := g.modules[name]; ok {
+			g.errorf(tree, m.Name, "module %q redefined", name)
+			return false
+		}
+		mod := &module{tree: tree, node: m, scope: newScope(nil)}
+		g.declareDefs(mod, m.Defs)
+		g.modules[name] = mod
+		g.order = append(g.order, mod)
+		return false
+	})
+}
+
+func (g *generator) declareDefs(m *module, defs []*ast.ModuleDef) {
+	for _, d := range defs {
+		switch n := d.Def.(type) {
+		case *ast.GroupDecl:
+			g.declareDefs(m, n.Defs)
+		case *ast.ValueDecl:
+			for _, decl := range n.Decls {
+				m.scope.insert(decl.Name.String(), &symbol{node: decl, kind: valueKind(n)})
+			}
+		case *ast.ModuleParameterGroup:
+			for _, vd := range n.Decls {
+				for _, decl := range vd.Decls {
+					m.scope.insert(decl.Name.String(), &symbol{node: decl, kind: frozenSym})
+				}
+			}
+		case *ast.FuncDecl:
+			m.scope.insert(n.Name.String(), &symbol{node: n, kind: funcSym})
+		case *ast.StructTypeDecl:
+			m.scope.insert(n.Name.String(), &symbol{node: n, kind: typeSym})
+		case *ast.EnumTypeDecl:
+			m.scope.insert(n.Name.String(), &symbol{node: n, kind: typeSym})
+			g.declareEnums(m.scope, n.Enums)
+		case *ast.ComponentTypeDecl:
+			m.scope.insert(n.Name.String(), &symbol{node: n, kind: typeSym})
+		case *ast.SubTypeDecl:
+			if n.Field != nil && n.Field.Name != nil {
+				m.scope.insert(n.Field.Name.String(), &symbol{node: n, kind: typeSym})
+				if e, ok := n.Field.Type.(*ast.EnumSpec); ok {
+					g.declareEnums(m.scope, e.Enums)
+				}
+			}
+		}
+	}
+}
+
+// declareEnums makes enumerated values visible in the module scope. They
+// reference the NAME instruction inside the ENUMERATED block.
+func (g *generator) declareEnums(s *scope, enums []ast.Expr) {
+	for _, e := range enums {
+		if id, ok := e.(*ast.Ident); ok {
+			s.insert(id.String(), &symbol{node: id, kind: enumSym})
+		}
+	}
+}
+
+// define records the current offset as address of definition n.
+func (g *generator) define(n ast.Node) {
+	g.addrs[n] = g.enc.Offset()
+}
+
+// ref emits a reference to definition n. References to definitions not
+// generated yet are resolved later.
+func (g *generator) ref(op opcode.Opcode, n ast.Node, pos ast.Node) {
+	if addr, ok := g.addrs[n]; ok {
+		g.enc.Encode(op, addr)
+		return
+	}
+	g.fixups = append(g.fixups, fixup{
+		offset: g.enc.Offset(),
+		op:     op,
+		def:    n,
+		pos:    pos.Pos(),
+		tree:   g.cur.tree,
+	})
+	g.enc.Encode(op, 0)
+}
+
+// resolve patches all forward references.
+func (g *generator) resolve() {
+	b := g.buf.Bytes()
+	for _, f := range g.fixups {
+		addr, ok := g.addrs[f.def]
+		if !ok {
+			g.errs = multierror.Append(g.errs, fmt.Errorf("%s: reference to %s could not be resolved",
+				f.tree.Position(f.pos), ast.Name(f.def)))
+			continue
+		}
+		binary.LittleEndian.PutUint32(b[f.offset:], opcode.Pack(f.op, addr))
+	}
+}
+
+// name emits a NAME instruction.
+func (g *generator) name(s string) {
+	g.enc.EncodeString(opcode.NAME, []byte(s), len(s))
+}
+
+// block emits a SCAN/BLOCK pair around the code generated by fn.
+func (g *generator) block(fn func()) {
+	g.enc.Encode(opcode.SCAN, 0)
+	fn()
+	g.enc.Encode(opcode.BLOCK, 0)
+}
+
+// lineOf emits a LINE instruction if n starts on a different line than the
+// previous LINE instruction.
+func (g *generator) lineOf(n ast.Node) {
+	line := g.cur.tree.Position(n.Pos()).Line
+	if line > 0 && line != g.line {
+		g.enc.Encode(opcode.LINE, line)
+		g.line = line
+	}
+}
+
+func (g *generator) errorf(tree *ttcn3.Tree, n ast.Node, format string, args ...interface{}) {
+	err := fmt.Errorf("%s: %s", tree.Position(n.Pos()), fmt.Sprintf(format, args...))
+	g.errs = multierror.Append(g.errs, err)
+}
+
+// unsupported reports a language construct not supported by the generator.
+func (g *generator) unsupported(n ast.Node, what string) {
+	g.errorf(g.cur.tree, n, "%s not supported by t3xf generator", what)
+}
+
+func (g *generator) pushScope() { g.scope = newScope(g.scope) }
+func (g *generator) popScope()  { g.scope = g.scope.parent }
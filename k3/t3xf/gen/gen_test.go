@@ -0,0 +1,221 @@
+package gen_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/k3/t3xf"
+	"github.com/nokia/ntt/k3/t3xf/gen"
+	"github.com/nokia/ntt/k3/t3xf/opcode"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/stretchr/testify/assert"
+)
+
+// generate generates code for the given modules and returns a compact
+// disassembly without the T3XF header. References are printed with the
+// instruction they point to, for example "ref(const)".
+func generate(t *testing.T, srcs ...string) (string, error) {
+	t.Helper()
+	var trees []*ttcn3.Tree
+	for _, src := range srcs {
+		trees = append(trees, ttcn3.Parse(src))
+	}
+
+	var b bytes.Buffer
+	if err := gen.Generate(&b, trees...); err != nil {
+		return "", err
+	}
+
+	ops := make(map[int]opcode.Opcode)
+	s := t3xf.NewScanner(b.Bytes())
+	for s.Scan() {
+		ops[s.Offset()] = s.Opcode()
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []string
+	s.Reset()
+	for i := 0; s.Scan(); i++ {
+		if i < 3 {
+			continue
+		}
+		switch op := s.Opcode(); op {
+		case opcode.LINE:
+			out = append(out, fmt.Sprintf("line(%d)", s.Arg()))
+		case opcode.REF, opcode.FROZEN_REF:
+			out = append(out, fmt.Sprintf("%s(%s)", op, ops[s.Arg()]))
+		case opcode.NATLONG:
+			out = append(out, fmt.Sprintf("%d", s.Arg()))
+		case opcode.IEEE754DP:
+			out = append(out, fmt.Sprintf("%g", s.Float64()))
+		case opcode.NAME:
+			out = append(out, fmt.Sprintf("'%s'", s.Bytes()))
+		case opcode.UTF8:
+			out = append(out, fmt.Sprintf("%q", s.Bytes()))
+		case opcode.BITS, opcode.NIBBLES, opcode.OCTETS:
+			out = append(out, fmt.Sprintf("%s(%x/%d)", op, s.Bytes(), s.Arg()))
+		default:
+			out = append(out, op.String())
+		}
+	}
+	return strings.Join(out, " "), nil
+}
+
+func TestGenerateHeader(t *testing.T) {
+	var b bytes.Buffer
+	err := gen.Generate(&b, ttcn3.Parse("module M {}"))
+	assert.Nil(t, err)
+
+	f, err := t3xf.Assemble(strings.NewReader("nop 4864\n2\nversion\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, f.Sections.T3XF, b.Bytes()[:16])
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: `module M {}`,
+			want:  `scan block 'M' module`,
+		},
+		{
+			input: `module M { const integer x := 23; modulepar float p; }`,
+			want:  `scan line(1) integer 23 'x' const float 'p' mpar block 'M' module`,
+		},
+		{
+			input: `module M {
+			          type record R { integer a, boolean b optional }
+			          type record of R L;
+			          type enumerated E { e1, e2 }
+			          const E c := e2;
+			        }`,
+			want: `scan ` +
+				`line(2) scan integer 'a' field boolean 'b' fieldo block record 'R' type ` +
+				`line(3) ref(type) recordof 'L' type ` +
+				`line(4) scan 'e1' 'e2' block enumerated 'E' type ` +
+				`line(5) ref(type) ref(name) 'c' const ` +
+				`block 'M' module`,
+		},
+		{
+			input: `module M {
+			          function f(in integer x) return integer {
+			            var integer y := x * 2;
+			            return g(y);
+			          }
+			          function g(integer x) return integer { return -x }
+			        }`,
+			want: `scan ` +
+				`line(2) scan integer 'x' in block integer scan ` +
+				`line(3) integer 'y' var ref(var) ref(in) 2 mul assign ` +
+				`line(4) ref(var) ref(functionv) apply return ` +
+				`block 'f' functionv ` +
+				`line(6) scan integer 'x' in block integer scan ref(in) neg return block 'g' functionv ` +
+				`block 'M' module`,
+		},
+		{
+			input: `module M {
+			          type component C { var integer v; timer t }
+			          testcase tc() runs on C {
+			            v := 1;
+			            if (v > 0) { setverdict(pass, "ok") } else { log(v) }
+			            stop;
+			          }
+			          control { execute(tc(), 5.0); }
+			        }`,
+			want: `scan ` +
+				`line(2) scan integer 'v' var timer 't' var block component 'C' type ` +
+				`line(3) scan block ref(type) scan ` +
+				`line(4) ref(var) 1 assign ` +
+				`line(5) ref(var) 0 gt scan mark pass "ok" vlist setverdict block scan mark ref(var) vlist log block ifelse ` +
+				`line(6) stopi ` +
+				`block 'tc' testcase ` +
+				`line(8) scan ref(testcase) 5 executel drop block control ` +
+				`block 'M' module`,
+		},
+		{
+			input: `module M {
+			          function f() {
+			            for (var integer i := 0; i < 10; i := i + 1) { continue }
+			            while (true) { break }
+			            do { f() } while (false)
+			          }
+			        }`,
+			want: `scan ` +
+				`line(2) scan block scan ` +
+				`line(3) scan integer 'i' var ref(var) 0 assign block scan ref(var) 10 lt block ` +
+				`scan ref(var) ref(var) 1 add assign block scan continue block for ` +
+				`line(4) scan true block scan break block while ` +
+				`line(5) scan ref(function) apply block scan false block dowhile ` +
+				`block 'f' function ` +
+				`block 'M' module`,
+		},
+		{
+			input: `module M { const bitstring b := '101000001'B; const hexstring h := 'abc'H; const octetstring o := 'DEAD'O; }`,
+			want: `scan line(1) ` +
+				`bitstring bits(a080/9) 'b' const ` +
+				`hexstring nibbles(abc0/12) 'h' const ` +
+				`octetstring octets(dead/16) 'o' const ` +
+				`block 'M' module`,
+		},
+		{
+			input: `module M { const charstring s := "a""b" & int2str(1); }`,
+			want:  `scan line(1) charstring "a\"b" 1 int2str cat 's' const block 'M' module`,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := generate(t, tt.input)
+		if err != nil {
+			t.Errorf("%s: %s", tt.input, err.Error())
+			continue
+		}
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+}
+
+func TestGenerateImports(t *testing.T) {
+	got, err := generate(t,
+		`module A { import from B all; const integer x := B.y + z; }`,
+		`module B { const integer y := 1, z := 2; }`,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, `scan line(1) 'B' import integer frozen_ref(const) frozen_ref(const) add 'x' const block 'A' module `+
+		`scan line(1) integer 1 'y' const integer 2 'z' const block 'B' module`, got)
+
+	// Imports inside groups.
+	got, err = generate(t,
+		`module A { group G { import from B all; } const integer x := z; }`,
+		`module B { const integer z := 2; }`,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, `scan line(1) 'B' import integer frozen_ref(const) 'x' const block 'A' module `+
+		`scan line(1) integer 2 'z' const block 'B' module`, got)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{`module M { template integer t := ?; }`, "1:12: template not supported by t3xf generator"},
+		{`module M { function f() { x := 1 } }`, "1:27: undefined: x"},
+		{`module M { const integer x := 1; function f() { x := 2 } }`, "1:49: cannot assign to x"},
+		{`module M { function f() { alt {} } }`, "1:27: alt statement not supported by t3xf generator"},
+		{`module M { testcase tc() {} }`, "1:12: testcase tc requires runs on clause"},
+		{`module M { function f() { var integer x := f() } }`, "1:44: f does not return a value"},
+		{`module M {} module M {}`, `1:20: module "M" redefined`},
+	}
+
+	for _, tt := range tests {
+		_, err := generate(t, tt.input)
+		if assert.NotNil(t, err, tt.input) {
+			assert.Contains(t, err.Error(), tt.err, tt.input)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package gen
+
+import (
+	"github.com/nokia/ntt/ttcn3/ast"
+)
+
+type symbolKind int
+
+const (
+	valueSym  symbolKind = iota // Variables, timers and formal parameters
+	frozenSym                   // Constants and module parameters
+	funcSym                     // Functions, testcases
+	typeSym                     // Named types
+	enumSym                     // Enumerated values
+)
+
+// symbol is a named object. The node identifies its definition.
+type symbol struct {
+	node ast.Node
+	kind symbolKind
+}
+
+type scope struct {
+	parent *scope
+	names  map[string]*symbol
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, names: make(map[string]*symbol)}
+}
+
+func (s *scope) insert(name string, sym *symbol) {
+	s.names[name] = sym
+}
+
+func (s *scope) lookup(name string) *symbol {
+	for s := s; s != nil; s = s.parent {
+		if sym, ok := s.names[name]; ok {
+			return sym
+		}
+	}
+	return nil
+}
+
+func valueKind(n *ast.ValueDecl) symbolKind {
+	switch n.Kind.String() {
+	case "const", "modulepar":
+		return frozenSym
+	}
+	return valueSym
+}
+
+// lookup resolves a name in the current scope, the module scope and the
+// scopes of all imported modules, including imports inside groups.
+func (g *generator) lookup(name string) *symbol {
+	if sym := g.scope.lookup(name); sym != nil {
+		return sym
+	}
+	return g.lookupImports(g.cur.node.Defs, name)
+}
+
+func (g *generator) lookupImports(defs []*ast.ModuleDef, name string) *symbol {
+	for _, d := range defs {
+		switch n := d.Def.(type) {
+		case *ast.ImportDecl:
+			if m, ok := g.modules[n.Module.String()]; ok {
+				if sym := m.scope.lookup(name); sym != nil {
+					return sym
+				}
+			}
+		case *ast.GroupDecl:
+			if sym := g.lookupImports(n.Defs, name); sym != nil {
+				return sym
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package gen
+
+import (
+	"github.com/nokia/ntt/k3/t3xf/opcode"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+func (g *generator) stmts(list []ast.Stmt) {
+	for _, s := range list {
+		g.stmt(s)
+	}
+}
+
+func (g *generator) stmt(n ast.Stmt) {
+	if _, ok := n.(*ast.DeclStmt); !ok {
+		g.lineOf(n)
+	}
+
+	switch n := n.(type) {
+	case *ast.DeclStmt:
+		if vd, ok := n.Decl.(*ast.ValueDecl); ok {
+			g.valueDecl(vd)
+		} else {
+			g.unsupported(n, "local definition")
+		}
+
+	case *ast.BlockStmt:
+		g.pushScope()
+		g.stmts(n.Stmts)
+		g.popScope()
+
+	case *ast.ExprStmt:
+		g.exprStmt(n.Expr)
+
+	case *ast.ReturnStmt:
+		if n.Result != nil {
+			g.expr(n.Result)
+		}
+		g.enc.Encode(opcode.RETURN, 0)
+
+	// cond scan <then> block [scan <else> block] if|ifelse
+	case *ast.IfStmt:
+		g.expr(n.Cond)
+		g.body(n.Then)
+		if n.Else == nil {
+			g.enc.Encode(opcode.IF, 0)
+			return
+		}
+		g.body(n.Else)
+		g.enc.Encode(opcode.IFELSE, 0)
+
+	// scan <cond> block scan <body> block while
+	case *ast.WhileStmt:
+		g.block(func() { g.expr(n.Cond) })
+		g.body(n.Body)
+		g.enc.Encode(opcode.WHILE, 0)
+
+	// scan <body> block scan <cond> block dowhile
+	case *ast.DoWhileStmt:
+		g.body(n.Body)
+		g.block(func() { g.expr(n.Cond) })
+		g.enc.Encode(opcode.DOWHILE, 0)
+
+	// scan <init> block scan <cond> block scan <post> block scan <body> block for
+	case *ast.ForStmt:
+		g.pushScope()
+		g.block(func() {
+			if n.Init != nil {
+				g.stmt(n.Init)
+			}
+		})
+		g.block(func() { g.expr(n.Cond) })
+		g.block(func() {
+			if n.Post != nil {
+				g.stmt(n.Post)
+			}
+		})
+		g.body(n.Body)
+		g.popScope()
+		g.enc.Encode(opcode.FOR, 0)
+
+	case *ast.BranchStmt:
+		switch n.Tok.Kind {
+		case token.BREAK:
+			g.enc.Encode(opcode.BREAK, 0)
+		case token.CONTINUE:
+			g.enc.Encode(opcode.CONTINUE, 0)
+		case token.REPEAT:
+			g.enc.Encode(opcode.REPEAT, 0)
+		default:
+			g.unsupported(n, n.Tok.String()+" statement")
+		}
+
+	case *ast.AltStmt:
+		g.unsupported(n, n.Tok.String()+" statement")
+	case *ast.CallStmt:
+		g.unsupported(n, "call statement")
+	case *ast.SelectStmt:
+		g.unsupported(n, "select statement")
+	default:
+		g.unsupported(n, "statement")
+	}
+}
+
+// body emits a statement enclosed in a SCAN/BLOCK pair with its own scope.
+func (g *generator) body(n ast.Stmt) {
+	g.pushScope()
+	g.block(func() {
+		if b, ok := n.(*ast.BlockStmt); ok {
+			g.stmts(b.Stmts)
+		} else {
+			g.stmt(n)
+		}
+	})
+	g.popScope()
+}
+
+// exprStmt emits assignments, function calls and stop statements. Values of
+// function calls are dropped.
+func (g *generator) exprStmt(x ast.Expr) {
+	switch x := x.(type) {
+	case *ast.BinaryExpr:
+		if x.Op.Kind == token.ASSIGN {
+			g.lvalue(x.X)
+			g.expr(x.Y)
+			g.enc.Encode(opcode.ASSIGN, 0)
+			return
+		}
+	case *ast.Ident:
+		if x.String() == "stop" && g.lookup("stop") == nil {
+			g.enc.Encode(opcode.STOPI, 0)
+			return
+		}
+	case *ast.CallExpr:
+		if g.call(x) {
+			g.enc.Encode(opcode.DROP, 0)
+		}
+		return
+	}
+	g.expr(x)
+	g.enc.Encode(opcode.DROP, 0)
+}
@@ -0,0 +1,117 @@
+package t3xf
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Object is a decoded TASM table entry with resolved names.
+type Object struct {
+	Table  string      // Name of the table, e.g. "Testcases".
+	Index  int         // Index of the entry in its table.
+	Name   string      // Name of the object, if available.
+	Module string      // Name of the module the object belongs to, if available.
+	Offset int         // T3XF section offset of the object or -1.
+	Line   int         // Source line of the object or 0.
+	Entry  interface{} // The raw table entry.
+}
+
+// String returns the string with given id from the TASM string table. An empty
+// string is returned if id is invalid.
+func (f *File) String(id uint32) string {
+	if int(id) >= len(f.Tables.Strings) {
+		return ""
+	}
+	s := f.Tables.Strings[id]
+	end := int(s.DataOffset) + int(s.Length)
+	if end > len(f.Sections.Data) {
+		return ""
+	}
+	return strings.TrimRight(string(f.Sections.Data[s.DataOffset:end]), "\x00")
+}
+
+// ModuleName returns the name of module with given id.
+func (f *File) ModuleName(id uint16) string {
+	if int(id) >= len(f.Tables.Modules) {
+		return ""
+	}
+	return f.String(f.Tables.Modules[id].ModuleStringId)
+}
+
+// Block returns the code of block with given id from the text section.
+func (f *File) Block(id uint32) []byte {
+	if int(id) >= len(f.Tables.Blocks) {
+		return nil
+	}
+	b := f.Tables.Blocks[id]
+	end := int(b.TextOffset) + int(b.Length)
+	if end > len(f.Sections.Text) {
+		return nil
+	}
+	return f.Sections.Text[b.TextOffset:end]
+}
+
+// Objects returns the entries of all TASM tables in file order.
+func (f *File) Objects() []Object {
+	var ret []Object
+
+	tables := reflect.ValueOf(f.Tables)
+	for i := 0; i < tables.NumField(); i++ {
+		name := tables.Type().Field(i).Name
+		table := tables.Field(i)
+		for j := 0; j < table.Len(); j++ {
+			ret = append(ret, f.newObject(name, j, table.Index(j)))
+		}
+	}
+	return ret
+}
+
+func (f *File) newObject(table string, idx int, v reflect.Value) Object {
+	obj := Object{
+		Table:  table,
+		Index:  idx,
+		Offset: -1,
+		Entry:  v.Interface(),
+	}
+
+	if fv := v.FieldByName("AssetOffset"); fv.IsValid() {
+		obj.Offset = int(fv.Uint())
+	}
+	if fv := v.FieldByName("Line"); fv.IsValid() {
+		obj.Line = int(fv.Uint())
+	}
+	if fv := v.FieldByName("ModuleId"); fv.IsValid() {
+		obj.Module = f.ModuleName(uint16(fv.Uint()))
+	}
+
+	switch e := obj.Entry.(type) {
+	case Module:
+		obj.Name = f.String(e.ModuleStringId)
+	case Name:
+		obj.Name = f.String(e.StringId)
+	case String:
+		obj.Name = f.String(uint32(idx))
+	default:
+		if fv := v.FieldByName("NameStringId"); fv.IsValid() {
+			obj.Name = f.String(uint32(fv.Uint()))
+		}
+	}
+	return obj
+}
+
+// Symbols returns a map from T3XF section offsets to the names of the objects
+// defined there. Names of definitions take precedence over the name table.
+func (f *File) Symbols() map[int]string {
+	m := make(map[int]string)
+	for _, obj := range f.Objects() {
+		if obj.Offset < 0 || obj.Name == "" {
+			continue
+		}
+		name := obj.Name
+		if obj.Module != "" {
+			name = obj.Module + "." + name
+		}
+		m[obj.Offset] = name
+	}
+	return m
+}
@@ -0,0 +1,64 @@
+package t3xf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tasmFile returns a TASM encoded file with a single module "M" and a testcase
+// "TC" at T3XF offset 4.
+func tasmFile() []byte {
+	data := []byte("M\x00TC\x00M.ttcn3\x00")
+	t3xf := []byte{
+		0x03, 0x00, 0x00, 0x00, // NOP
+		0x03, 0x00, 0x00, 0x00, // NOP
+	}
+	text := []byte{
+		0x03, 0x00, 0x00, 0x00, // NOP
+	}
+
+	strings := []String{{0, 1}, {2, 2}, {5, 7}}
+	modules := []Module{{ModuleStringId: 0, SourceStringId: 2}}
+	testcases := []Testcase{{NameStringId: 1, ModuleId: 0, AssetOffset: 4, Line: 23}}
+	blocks := []Block{{AssetOffset: 0, TextOffset: 0, Length: 4}}
+
+	var h tasmHeader
+	h.T3xfSection = uint32(len(t3xf))
+	h.TextSection = uint32(len(text))
+	h.ModuleTable = uint32(binary.Size(modules))
+	h.TestcaseTable = uint32(binary.Size(testcases))
+	h.BlockTable = uint32(binary.Size(blocks))
+	h.StringTable = uint32(binary.Size(strings))
+	h.DataSection = uint32(len(data))
+
+	var b bytes.Buffer
+	b.WriteString("T3XFASM\x00")
+	for _, v := range []interface{}{h, t3xf, text, modules, testcases, blocks, strings, data} {
+		binary.Write(&b, binary.LittleEndian, v)
+	}
+	return b.Bytes()
+}
+
+func TestObjects(t *testing.T) {
+	f, err := read(bytes.NewReader(tasmFile()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "M", f.ModuleName(0))
+	assert.Equal(t, "", f.ModuleName(1))
+	assert.Equal(t, "M.ttcn3", f.String(2))
+	assert.Equal(t, "", f.String(3))
+	assert.Equal(t, []byte{0x03, 0x00, 0x00, 0x00}, f.Block(0))
+	assert.Nil(t, f.Block(1))
+	assert.Equal(t, map[int]string{4: "M.TC"}, f.Symbols())
+
+	var tables []string
+	for _, obj := range f.Objects() {
+		tables = append(tables, obj.Table+":"+obj.Name)
+	}
+	assert.Equal(t, []string{"Modules:M", "Testcases:TC", "Blocks:", "Strings:M", "Strings:TC", "Strings:M.ttcn3"}, tables)
+}
@@ -491,3 +491,38 @@ func Unpack(x uint32) (Opcode, int) {
 
 	panic("internal error")
 }
+
+// Pack encodes op and arg into a single t3xf instruction word. Pack is the
+// inverse of Unpack. For GOTO, REF and FROZEN_REF arg is a 4-byte aligned
+// address.
+func Pack(op Opcode, arg int) uint32 {
+	switch op {
+	case REF:
+		return uint32(arg) &^ (1 << 31) &^ 0x3
+	case FROZEN_REF:
+		return uint32(arg)&^0x3 | (1 << 31)
+	case LINE:
+		return uint32(arg)<<2 | lineClass
+	case GOTO:
+		return uint32(arg)&^0x3 | gotoClass
+	default:
+		return uint32(arg)<<16 | uint32(op&0xfff)<<4 | instrClass
+	}
+}
+
+var opcodeNames = func() map[string]Opcode {
+	m := make(map[string]Opcode, len(opcodeStrings))
+	for op, s := range opcodeStrings {
+		if s != "" {
+			m[s] = Opcode(op)
+		}
+	}
+	return m
+}()
+
+// Lookup returns the opcode for the given instruction name. Pseudo opcodes are
+// not included.
+func Lookup(name string) (Opcode, bool) {
+	op, ok := opcodeNames[name]
+	return op, ok
+}
@@ -89,67 +89,67 @@ func read(r io.Reader) (*File, error) {
 
 		Sections: Sections{
 			T3XF: make([]byte, h.T3xfSection),
-			text: make([]byte, h.TextSection),
-			data: make([]byte, h.DataSection),
+			Text: make([]byte, h.TextSection),
+			Data: make([]byte, h.DataSection),
 		},
 		Tables: Tables{
-			ints:             make([]tasmInt32, count(h.Int32Table, tasmInt32{})),
-			names:            make([]tasmName, count(h.NameTable, tasmName{})),
-			modules:          make([]tasmModule, count(h.ModuleTable, tasmModule{})),
-			typeAliases:      make([]tasmSubType, count(h.TypeAliasTable, tasmSubType{})),
-			recordTypes:      make([]tasmRecord, count(h.RecordTypeTable, tasmRecord{})),
-			setTypes:         make([]tasmSet, count(h.SetTypeTable, tasmSet{})),
-			recordOfTypes:    make([]tasmRecordOf, count(h.RecordOfTypeTable, tasmRecordOf{})),
-			setOfTypes:       make([]tasmSetOf, count(h.SetOfTypeTable, tasmSetOf{})),
-			unionTypes:       make([]tasmUnion, count(h.UnionTypeTable, tasmUnion{})),
-			enumeratedTypes:  make([]tasmEnum, count(h.EnumeratedTypeTable, tasmEnum{})),
-			arrayTypes:       make([]tasmArray, count(h.ArrayTypeTable, tasmArray{})),
-			closureTypes:     make([]tasmClosure, count(h.ClosureTypeTable, tasmClosure{})),
-			messagePortTypes: make([]tasmPort, count(h.MessagePortTypeTable, tasmPort{})),
-			componentTypes:   make([]tasmComponent, count(h.ComponentTypeTable, tasmComponent{})),
-			consts:           make([]tasmConst, count(h.ConstTable, tasmConst{})),
-			modulePars:       make([]tasmConst, count(h.ModuleParTable, tasmConst{})),
-			templates:        make([]tasmTemplate, count(h.TemplateTable, tasmTemplate{})),
-			testcases:        make([]tasmTestcase, count(h.TestcaseTable, tasmTestcase{})),
-			functions:        make([]tasmFunction, count(h.FunctionTable, tasmFunction{})),
-			extFunctions:     make([]tasmExtFunction, count(h.ExtFunctionTable, tasmExtFunction{})),
-			altsteps:         make([]tasmAltstep, count(h.AltstepTable, tasmAltstep{})),
-			blocks:           make([]tasmBlock, count(h.BlockTable, tasmBlock{})),
-			controls:         make([]tasmControl, count(h.ControlTable, tasmControl{})),
-			strings:          make([]tasmString, count(h.StringTable, tasmString{})),
-			collections:      make([]tasmCollection, count(h.CollectionTable, tasmCollection{})),
+			Ints:             make([]Int32, count(h.Int32Table, Int32{})),
+			Names:            make([]Name, count(h.NameTable, Name{})),
+			Modules:          make([]Module, count(h.ModuleTable, Module{})),
+			TypeAliases:      make([]TypeAlias, count(h.TypeAliasTable, TypeAlias{})),
+			RecordTypes:      make([]RecordType, count(h.RecordTypeTable, RecordType{})),
+			SetTypes:         make([]SetType, count(h.SetTypeTable, SetType{})),
+			RecordOfTypes:    make([]RecordOfType, count(h.RecordOfTypeTable, RecordOfType{})),
+			SetOfTypes:       make([]SetOfType, count(h.SetOfTypeTable, SetOfType{})),
+			UnionTypes:       make([]UnionType, count(h.UnionTypeTable, UnionType{})),
+			EnumeratedTypes:  make([]EnumType, count(h.EnumeratedTypeTable, EnumType{})),
+			ArrayTypes:       make([]ArrayType, count(h.ArrayTypeTable, ArrayType{})),
+			ClosureTypes:     make([]ClosureType, count(h.ClosureTypeTable, ClosureType{})),
+			MessagePortTypes: make([]PortType, count(h.MessagePortTypeTable, PortType{})),
+			ComponentTypes:   make([]ComponentType, count(h.ComponentTypeTable, ComponentType{})),
+			Consts:           make([]Const, count(h.ConstTable, Const{})),
+			ModulePars:       make([]Const, count(h.ModuleParTable, Const{})),
+			Templates:        make([]Template, count(h.TemplateTable, Template{})),
+			Testcases:        make([]Testcase, count(h.TestcaseTable, Testcase{})),
+			Functions:        make([]Function, count(h.FunctionTable, Function{})),
+			ExtFunctions:     make([]ExtFunction, count(h.ExtFunctionTable, ExtFunction{})),
+			Altsteps:         make([]Altstep, count(h.AltstepTable, Altstep{})),
+			Blocks:           make([]Block, count(h.BlockTable, Block{})),
+			Controls:         make([]Control, count(h.ControlTable, Control{})),
+			Strings:          make([]String, count(h.StringTable, String{})),
+			Collections:      make([]Collection, count(h.CollectionTable, Collection{})),
 		},
 	}
 
 	// Note, below fields must be read in this order.
 	er.read(&f.Sections.T3XF)
-	er.read(&f.Sections.text)
-	er.read(&f.Tables.ints)
-	er.read(&f.Tables.names)
-	er.read(&f.Tables.modules)
-	er.read(&f.Tables.typeAliases)
-	er.read(&f.Tables.recordTypes)
-	er.read(&f.Tables.setTypes)
-	er.read(&f.Tables.recordOfTypes)
-	er.read(&f.Tables.setOfTypes)
-	er.read(&f.Tables.unionTypes)
-	er.read(&f.Tables.enumeratedTypes)
-	er.read(&f.Tables.arrayTypes)
-	er.read(&f.Tables.closureTypes)
-	er.read(&f.Tables.messagePortTypes)
-	er.read(&f.Tables.componentTypes)
-	er.read(&f.Tables.consts)
-	er.read(&f.Tables.modulePars)
-	er.read(&f.Tables.templates)
-	er.read(&f.Tables.testcases)
-	er.read(&f.Tables.functions)
-	er.read(&f.Tables.extFunctions)
-	er.read(&f.Tables.altsteps)
-	er.read(&f.Tables.blocks)
-	er.read(&f.Tables.controls)
-	er.read(&f.Tables.strings)
-	er.read(&f.Tables.collections)
-	er.read(&f.Sections.data)
+	er.read(&f.Sections.Text)
+	er.read(&f.Tables.Ints)
+	er.read(&f.Tables.Names)
+	er.read(&f.Tables.Modules)
+	er.read(&f.Tables.TypeAliases)
+	er.read(&f.Tables.RecordTypes)
+	er.read(&f.Tables.SetTypes)
+	er.read(&f.Tables.RecordOfTypes)
+	er.read(&f.Tables.SetOfTypes)
+	er.read(&f.Tables.UnionTypes)
+	er.read(&f.Tables.EnumeratedTypes)
+	er.read(&f.Tables.ArrayTypes)
+	er.read(&f.Tables.ClosureTypes)
+	er.read(&f.Tables.MessagePortTypes)
+	er.read(&f.Tables.ComponentTypes)
+	er.read(&f.Tables.Consts)
+	er.read(&f.Tables.ModulePars)
+	er.read(&f.Tables.Templates)
+	er.read(&f.Tables.Testcases)
+	er.read(&f.Tables.Functions)
+	er.read(&f.Tables.ExtFunctions)
+	er.read(&f.Tables.Altsteps)
+	er.read(&f.Tables.Blocks)
+	er.read(&f.Tables.Controls)
+	er.read(&f.Tables.Strings)
+	er.read(&f.Tables.Collections)
+	er.read(&f.Sections.Data)
 
 	if er.err != nil {
 		return nil, er.err
@@ -31,74 +31,88 @@ type tasmHeader struct {
 	DataSection          uint32
 }
 
+// Sections contains the raw sections of a t3xf file. Text and Data sections are
+// only available in TASM files.
 type Sections struct {
 	T3XF []byte
-	text []byte
-	data []byte
+	Text []byte
+	Data []byte
 }
 
+// Tables contains the TASM tables in the order they are stored in the file.
+// The tables are empty for plain t3xf files.
 type Tables struct {
-	altsteps         []tasmAltstep
-	arrayTypes       []tasmArray
-	blocks           []tasmBlock
-	closureTypes     []tasmClosure
-	collections      []tasmCollection
-	componentTypes   []tasmComponent
-	consts           []tasmConst
-	controls         []tasmControl
-	enumeratedTypes  []tasmEnum
-	extFunctions     []tasmExtFunction
-	functions        []tasmFunction
-	ints             []tasmInt32
-	messagePortTypes []tasmPort
-	modulePars       []tasmConst
-	modules          []tasmModule
-	names            []tasmName
-	recordOfTypes    []tasmRecordOf
-	recordTypes      []tasmRecord
-	setOfTypes       []tasmSetOf
-	setTypes         []tasmSet
-	strings          []tasmString
-	templates        []tasmTemplate
-	testcases        []tasmTestcase
-	typeAliases      []tasmSubType
-	unionTypes       []tasmUnion
+	Ints             []Int32
+	Names            []Name
+	Modules          []Module
+	TypeAliases      []TypeAlias
+	RecordTypes      []RecordType
+	SetTypes         []SetType
+	RecordOfTypes    []RecordOfType
+	SetOfTypes       []SetOfType
+	UnionTypes       []UnionType
+	EnumeratedTypes  []EnumType
+	ArrayTypes       []ArrayType
+	ClosureTypes     []ClosureType
+	MessagePortTypes []PortType
+	ComponentTypes   []ComponentType
+	Consts           []Const
+	ModulePars       []Const
+	Templates        []Template
+	Testcases        []Testcase
+	Functions        []Function
+	ExtFunctions     []ExtFunction
+	Altsteps         []Altstep
+	Blocks           []Block
+	Controls         []Control
+	Strings          []String
+	Collections      []Collection
 }
 
-type tasmModule struct {
+// Module is an entry of the TASM module table.
+type Module struct {
 	ModuleStringId uint32
 	SourceStringId uint32
 	BlockId        uint32
 	HasControl     uint16
 }
 
-type tasmString struct {
+// String is an entry of the TASM string table. It references a string in
+// the data section.
+type String struct {
 	DataOffset uint32
 	Length     uint32
 }
 
-type tasmCollection struct {
+// Collection is an entry of the TASM collection table.
+type Collection struct {
 	DataOffset uint32
 	Length     uint32
 }
 
-type tasmInt32 struct {
+// Int32 is an entry of the TASM integer table.
+type Int32 struct {
 	AssetOffset uint32
 	Value       int32
 }
 
-type tasmName struct {
+// Name is an entry of the TASM name table. It associates a t3xf offset with a
+// string.
+type Name struct {
 	AssetOffset uint32
 	StringId    uint32
 }
 
-type tasmBlock struct {
+// Block is an entry of the TASM block table. It references code in the text
+// section.
+type Block struct {
 	AssetOffset uint32
 	TextOffset  uint32
 	Length      uint32
 }
 
-type tasmControl struct {
+// Control is an entry of the TASM control part table.
+type Control struct {
 	AssetOffset uint32
 	ModuleId    uint16
 	BlockId     uint32
@@ -106,7 +120,8 @@ type tasmControl struct {
 	Line        uint32
 }
 
-type tasmTestcase struct {
+// Testcase is an entry of the TASM testcase table.
+type Testcase struct {
 	NameStringId     uint32
 	ModuleId         uint16
 	RunsOnFileOffset uint32
@@ -117,7 +132,8 @@ type tasmTestcase struct {
 	Line             uint32
 }
 
-type tasmFunction struct {
+// Function is an entry of the TASM function table.
+type Function struct {
 	NameStringId         uint32
 	ModuleId             uint16
 	RunsOnFileOffset     uint32
@@ -129,7 +145,8 @@ type tasmFunction struct {
 	Line                 uint32
 }
 
-type tasmExtFunction struct {
+// ExtFunction is an entry of the TASM external function table.
+type ExtFunction struct {
 	NameStringId         uint32
 	ModuleId             uint16
 	ReturnTypeFileOffset uint32
@@ -140,7 +157,8 @@ type tasmExtFunction struct {
 	Line                 uint32
 }
 
-type tasmAltstep struct {
+// Altstep is an entry of the TASM altstep table.
+type Altstep struct {
 	NameStringId     uint32
 	ModuleId         uint16
 	RunsOnFileOffset uint32
@@ -150,7 +168,8 @@ type tasmAltstep struct {
 	Line             uint32
 }
 
-type tasmClosure struct {
+// ClosureType is an entry of the TASM closure type table.
+type ClosureType struct {
 	NameStringId uint32
 	ModuleId     uint16
 	AssetOffset  uint32
@@ -158,7 +177,8 @@ type tasmClosure struct {
 	Line         uint32
 }
 
-type tasmSubType struct {
+// TypeAlias is an entry of the TASM type alias table.
+type TypeAlias struct {
 	AssetOffset        uint32
 	ModuleId           uint16
 	NameStringId       uint32
@@ -168,7 +188,8 @@ type tasmSubType struct {
 	Line               uint32
 }
 
-type tasmRecord struct {
+// RecordType is an entry of the TASM record type table.
+type RecordType struct {
 	AssetOffset       uint32
 	ModuleId          uint16
 	NameStringId      uint32
@@ -178,7 +199,8 @@ type tasmRecord struct {
 	Line              uint32
 }
 
-type tasmSet struct {
+// SetType is an entry of the TASM set type table.
+type SetType struct {
 	AssetOffset       uint32
 	ModuleId          uint16
 	NameStringId      uint32
@@ -188,7 +210,8 @@ type tasmSet struct {
 	Line              uint32
 }
 
-type tasmRecordOf struct {
+// RecordOfType is an entry of the TASM record of type table.
+type RecordOfType struct {
 	AssetOffset       uint32
 	ModuleId          uint16
 	NameStringId      uint32
@@ -198,7 +221,8 @@ type tasmRecordOf struct {
 	Line              uint32
 }
 
-type tasmSetOf struct {
+// SetOfType is an entry of the TASM set of type table.
+type SetOfType struct {
 	AssetOffset           uint32
 	ModuleId              uint16
 	NameStringId          uint32
@@ -208,7 +232,8 @@ type tasmSetOf struct {
 	Line                  uint32
 }
 
-type tasmUnion struct {
+// UnionType is an entry of the TASM union type table.
+type UnionType struct {
 	AssetOffset       uint32
 	ModuleId          uint16
 	NameStringId      uint32
@@ -218,7 +243,8 @@ type tasmUnion struct {
 	Line              uint32
 }
 
-type tasmEnum struct {
+// EnumType is an entry of the TASM enumerated type table.
+type EnumType struct {
 	AssetOffset       uint32
 	ModuleId          uint16
 	NameStringId      uint32
@@ -228,7 +254,8 @@ type tasmEnum struct {
 	Line              uint32
 }
 
-type tasmArray struct {
+// ArrayType is an entry of the TASM array type table.
+type ArrayType struct {
 	AssetOffset           uint32
 	ModuleId              uint16
 	NameStringId          uint32
@@ -238,7 +265,8 @@ type tasmArray struct {
 	Line                  uint32
 }
 
-type tasmPort struct {
+// PortType is an entry of the TASM message port type table.
+type PortType struct {
 	AssetOffset                  uint32
 	ModuleId                     uint16
 	NameStringId                 uint32
@@ -251,7 +279,8 @@ type tasmPort struct {
 	Line                         uint32
 }
 
-type tasmComponent struct {
+// ComponentType is an entry of the TASM component type table.
+type ComponentType struct {
 	AssetOffset                uint32
 	ModuleId                   uint16
 	NameStringId               uint32
@@ -261,7 +290,8 @@ type tasmComponent struct {
 	Line                       uint32
 }
 
-type tasmConst struct {
+// Const is an entry of the TASM constant and module parameter tables.
+type Const struct {
 	AssetOffset           uint32
 	ModuleId              uint16
 	NameStringId          uint32
@@ -271,7 +301,8 @@ type tasmConst struct {
 	Line                  uint32
 }
 
-type tasmTemplate struct {
+// Template is an entry of the TASM template table.
+type Template struct {
 	AssetOffset           uint32
 	ModuleId              uint16
 	NameStringId          uint32
@@ -0,0 +1,529 @@
+package project
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/nokia/ntt/internal/cache"
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/log"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// LockFile records the resolved dependencies of a test suite. It is
+	// stored next to the manifest.
+	LockFile = "package.lock"
+
+	// DepsDir is the directory, relative to the suite root, where fetched
+	// dependencies are stored.
+	DepsDir = ".ntt/deps"
+)
+
+// A Lock describes the dependencies of a test suite as resolved by a Fetcher.
+type Lock struct {
+	// Requires lists the names of the direct dependencies.
+	Requires     []string      `yaml:"requires,omitempty"`
+	Dependencies []*Dependency `yaml:"dependencies"`
+}
+
+// A Dependency is a resolved import.
+type Dependency struct {
+	Name     string
+	Version  string   `yaml:"version,omitempty"` // Version declared by the manifest of the dependency.
+	Source   string   // Path, git URL or archive location.
+	Ref      string   `yaml:"ref,omitempty"`    // Commit as given by the import, like a tag or an abbreviated hash.
+	Commit   string   `yaml:"commit,omitempty"` // Full hash of the commit Ref resolved to.
+	SHA256   string   `yaml:"sha256,omitempty"`
+	Dir      string   // Directory of the dependency, relative to the suite root if possible.
+	Requires []string `yaml:"requires,omitempty"` // Names of the dependencies imported by this one.
+}
+
+// Matches returns true if the dependency was resolved from import imp.
+func (dep *Dependency) Matches(imp Import) bool {
+	switch {
+	case dep.Source != imp.Source():
+		return false
+	case imp.Git != "":
+		return dep.Ref == imp.Commit && dep.Commit != ""
+	case imp.Archive != "":
+		return imp.SHA256 == "" || dep.SHA256 == imp.SHA256
+	}
+	return true
+}
+
+// Lookup returns the dependency with the given name or nil.
+func (l *Lock) Lookup(name string) *Dependency {
+	if l == nil {
+		return nil
+	}
+	for _, dep := range l.Dependencies {
+		if dep.Name == name {
+			return dep
+		}
+	}
+	return nil
+}
+
+// ReadLock reads the lock file of the suite in directory root. If there's no
+// lock file, ReadLock returns nil and no error.
+func ReadLock(root string) (*Lock, error) {
+	b, err := ioutil.ReadFile(filepath.Join(root, LockFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l Lock
+	if err := yaml.UnmarshalStrict(b, &l); err != nil {
+		return nil, fmt.Errorf("%s: %w", LockFile, err)
+	}
+	return &l, nil
+}
+
+// WriteLock writes lock file of the suite in directory root.
+func WriteLock(root string, l *Lock) error {
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	b = append([]byte("# Generated by ntt deps fetch. DO NOT EDIT.\n"), b...)
+	return ioutil.WriteFile(filepath.Join(root, LockFile), b, 0644)
+}
+
+// A Fetcher resolves the imports of a project and its dependencies, and
+// fetches git and archive imports into DepsDir.
+type Fetcher struct {
+	// Vendor is an optional directory with local copies of dependencies:
+	// git repositories named after the import ("name.git" or "name") and
+	// archives named like the basename of the archive location.
+	Vendor string
+
+	// Offline prevents access to remote repositories and archives. Only
+	// local paths and the vendor directory are used.
+	Offline bool
+}
+
+// Fetch resolves all imports of p transitively. Dependencies already fetched
+// by a previous run, as recorded in the lock file, are reused. Fetch does not
+// write the lock file.
+func (f *Fetcher) Fetch(p *Project) (*Lock, error) {
+	prev, err := ReadLock(p.root)
+	if err != nil {
+		return nil, err
+	}
+
+	type item struct {
+		imp    Import
+		owner  *Project    // The project declaring the import.
+		parent *Dependency // nil for direct imports.
+	}
+
+	var (
+		errs  error
+		lock  = &Lock{}
+		keys  = make(map[string]string)
+		queue []item
+	)
+	for _, imp := range p.Manifest.Imports {
+		queue = append(queue, item{imp: imp, owner: p})
+	}
+
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+
+		imp := it.imp
+		if err := imp.Check(); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", it.owner.root, err))
+			continue
+		}
+
+		key := imp.Source()
+		switch {
+		case imp.Path != "":
+			dir, err := it.owner.evalPath(imp.Path)
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+			key, _ = filepath.Abs(dir)
+		case imp.Git != "":
+			key += "@" + imp.Commit
+		}
+		name := importName(imp, key)
+
+		if it.parent != nil {
+			it.parent.Requires = appendUnique(it.parent.Requires, name)
+		} else {
+			lock.Requires = appendUnique(lock.Requires, name)
+		}
+
+		if k, ok := keys[name]; ok {
+			if k != key {
+				errs = multierror.Append(errs, fmt.Errorf("import %q: conflicting sources %q and %q", name, k, key))
+			}
+			continue
+		}
+		keys[name] = key
+
+		dep, err := f.resolve(p.root, it.owner, name, imp, prev.Lookup(name))
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("import %q: %w", name, err))
+			continue
+		}
+		lock.Dependencies = append(lock.Dependencies, dep)
+
+		if c, _ := ParseConstraint(imp.Version); !c.Match(dep.Version) {
+			if dep.Version == "" {
+				errs = multierror.Append(errs, fmt.Errorf("import %q: version required by %q, but dependency declares none", name, imp.Version))
+			} else {
+				errs = multierror.Append(errs, fmt.Errorf("import %q: version %s does not satisfy %q", name, dep.Version, imp.Version))
+			}
+		}
+
+		// Dependencies with a manifest may have dependencies of their own.
+		dir := fs.Real(p.root, dep.Dir)
+		if fs.IsRegular(filepath.Join(dir, ManifestFile)) {
+			sub, err := Open(dir)
+			if err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("import %q: %w", name, err))
+				continue
+			}
+			for _, imp := range sub.Manifest.Imports {
+				queue = append(queue, item{imp: imp, owner: sub, parent: dep})
+			}
+		}
+	}
+	return lock, errs
+}
+
+// resolve returns the dependency of an import. Git and archive imports are
+// fetched unless the previous dependency prev is still valid.
+func (f *Fetcher) resolve(root string, owner *Project, name string, imp Import, prev *Dependency) (*Dependency, error) {
+	dep := &Dependency{Name: name, Source: imp.Source()}
+	dest := filepath.Join(root, DepsDir, name)
+	if imp.Path == "" {
+		if rel, err := filepath.Rel(filepath.Join(root, DepsDir), dest); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("invalid import name %q", name)
+		}
+	}
+	reuse := prev != nil && prev.Source == dep.Source && fs.IsDir(dest)
+
+	switch {
+	case imp.Path != "":
+		dir, _ := owner.evalPath(imp.Path)
+		if !fs.IsDir(dir) {
+			return nil, fmt.Errorf("%q must be a directory", dir)
+		}
+		dest = dir
+
+	case imp.Git != "":
+		dep.Ref = imp.Commit
+		if reuse && prev.Ref == imp.Commit && prev.Commit != "" {
+			dep.Commit = prev.Commit
+			break
+		}
+		sha, err := f.fetchGit(name, imp, dest)
+		if err != nil {
+			return nil, err
+		}
+		dep.Commit = sha
+
+	case imp.Archive != "":
+		if reuse && (imp.SHA256 == "" || imp.SHA256 == prev.SHA256) {
+			dep.SHA256 = prev.SHA256
+			break
+		}
+		sum, err := f.fetchArchive(owner, name, imp, dest)
+		if err != nil {
+			return nil, err
+		}
+		dep.SHA256 = sum
+	}
+
+	dep.Dir = dest
+	if rel, err := filepath.Rel(root, dest); err == nil {
+		dep.Dir = rel
+	}
+	dep.Version = manifestVersion(dest)
+	return dep, nil
+}
+
+// fetchGit clones the repository of imp into dest, checks out the pinned
+// commit and returns its full hash.
+func (f *Fetcher) fetchGit(name string, imp Import, dest string) (string, error) {
+	src := imp.Git
+	if f.Vendor != "" {
+		for _, dir := range []string{name + ".git", name} {
+			if path := filepath.Join(f.Vendor, dir); fs.IsDir(path) {
+				src = path
+				break
+			}
+		}
+	}
+	if src == imp.Git && f.Offline && isRemote(src) {
+		return "", fmt.Errorf("%s: not available offline", src)
+	}
+
+	log.Verbosef("fetching %s@%s from %s\n", name, imp.Commit, src)
+	tmp, err := tempDir(dest)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	if _, err := git("clone", "--quiet", "--no-checkout", "--", src, tmp); err != nil {
+		return "", err
+	}
+	if _, err := git("-C", tmp, "checkout", "--quiet", "--detach", imp.Commit); err != nil {
+		return "", err
+	}
+	sha, err := git("-C", tmp, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return sha, replaceDir(tmp, dest)
+}
+
+// fetchArchive extracts the archive of imp into dest and returns the SHA-256
+// sum of the archive.
+func (f *Fetcher) fetchArchive(owner *Project, name string, imp Import, dest string) (string, error) {
+	src := imp.Archive
+	if isURL(src) {
+		if vendored := filepath.Join(f.Vendor, filepath.Base(src)); f.Vendor != "" && fs.IsRegular(vendored) {
+			src = vendored
+		}
+	} else {
+		src, _ = owner.evalPath(src)
+	}
+
+	if isURL(src) {
+		if f.Offline {
+			return "", fmt.Errorf("%s: not available offline", src)
+		}
+		file, err := download(src)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(file)
+		src = file
+	}
+
+	sum, err := cache.HashFile(src)
+	if err != nil {
+		return "", err
+	}
+	if imp.SHA256 != "" && imp.SHA256 != sum {
+		return "", fmt.Errorf("%s: checksum mismatch: got %s, want %s", imp.Archive, sum, imp.SHA256)
+	}
+
+	log.Verbosef("extracting %s from %s\n", name, src)
+	tmp, err := tempDir(dest)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	if err := extract(src, imp.Archive, tmp); err != nil {
+		return "", err
+	}
+
+	// Archives usually contain a single top-level directory.
+	dir := tmp
+	if entries, err := ioutil.ReadDir(tmp); err == nil && len(entries) == 1 && entries[0].IsDir() {
+		dir = filepath.Join(tmp, entries[0].Name())
+	}
+	return sum, replaceDir(dir, dest)
+}
+
+// extract extracts archive file into directory dir. The format is determined
+// by the extension of name.
+func extract(file string, name string, dir string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(f, info.Size())
+		if err != nil {
+			return err
+		}
+		for _, zf := range zr.File {
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			err = extractFile(dir, zf.Name, zf.Mode(), rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		r = gz
+	case strings.HasSuffix(name, ".tar"):
+		r = f
+	default:
+		return fmt.Errorf("%s: unsupported archive format", name)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir, tar.TypeReg, tar.TypeRegA:
+			if err := extractFile(dir, hdr.Name, hdr.FileInfo().Mode(), tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractFile creates file or directory name below dir.
+func extractFile(dir string, name string, mode os.FileMode, r io.Reader) error {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if rel, err := filepath.Rel(dir, path); err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("%s: illegal path in archive", name)
+	}
+	if mode.IsDir() {
+		return os.MkdirAll(path, 0755)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0200)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// download stores the content of url in a temporary file.
+func download(url string) (string, error) {
+	log.Verbosef("downloading %s\n", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	f, err := ioutil.TempFile("", "ntt-archive")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+// git runs git and returns its output without surrounding whitespace.
+func git(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	log.Debugln("+", cmd.String())
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// tempDir creates a temporary directory next to dest.
+func tempDir(dest string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+	return ioutil.TempDir(filepath.Dir(dest), filepath.Base(dest)+".*")
+}
+
+// replaceDir replaces directory dest by dir.
+func replaceDir(dir, dest string) error {
+	if err := os.RemoveAll(dest); err != nil {
+		return err
+	}
+	return os.Rename(dir, dest)
+}
+
+// manifestVersion returns the version declared by the manifest in dir.
+func manifestVersion(dir string) string {
+	b, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return ""
+	}
+	var m struct{ Version string }
+	yaml.Unmarshal(b, &m)
+	return m.Version
+}
+
+// importName returns the name of an import. Unnamed imports are named after
+// their directory.
+func importName(imp Import, dir string) string {
+	if imp.Name != "" {
+		return imp.Name
+	}
+	return filepath.Base(dir)
+}
+
+func isURL(s string) bool {
+	return strings.Contains(s, "://")
+}
+
+// isRemote returns true if git repository s is a URL or uses the scp-like
+// syntax "[user@]host:path". A colon after the first slash or a single drive
+// letter before the colon denotes a local path.
+func isRemote(s string) bool {
+	if isURL(s) {
+		return true
+	}
+	i := strings.Index(s, ":")
+	return i > 1 && !strings.ContainsAny(s[:i], `/\`)
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, x := range list {
+		if x == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,199 @@
+package project_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportUnmarshal(t *testing.T) {
+	p := open("testdata/deps/suite")
+	assert.Equal(t, []project.Import{
+		{Path: "../common"},
+		{Name: "codecs", Version: "^1.2", Git: "https://example.com/codecs.git", Commit: "3f2a9c0"},
+		{Name: "sctlib", Archive: "https://example.com/sctlib-1.4.tar.gz", SHA256: "9b2c"},
+	}, p.(*project.Project).Manifest.Imports)
+
+	for _, imp := range p.(*project.Project).Manifest.Imports {
+		assert.Nil(t, imp.Check())
+	}
+	assert.NotNil(t, (&project.Import{Name: "x"}).Check())
+	assert.NotNil(t, (&project.Import{Name: "x", Git: "a.git"}).Check())
+	assert.NotNil(t, (&project.Import{Name: "x", Path: "a", Archive: "a.zip"}).Check())
+	assert.NotNil(t, (&project.Import{Git: "a.git", Commit: "1234"}).Check())
+	assert.NotNil(t, (&project.Import{Path: "a", Version: ">>1"}).Check())
+	assert.NotNil(t, (&project.Import{Name: "x", Git: "a.git", Commit: "--upload-pack=touch /tmp/x"}).Check())
+	for _, name := range []string{"..", "../..", "a/b", `a\b`, ".", "./a"} {
+		assert.NotNil(t, (&project.Import{Name: name, Archive: "a.zip"}).Check(), name)
+	}
+}
+
+func TestFetchUntrusted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deps")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(path string, content string) {
+		path = filepath.Join(dir, path)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	// A third-party manifest must not escape the dependency directory.
+	write("lib/package.yml", "imports:\n  - name: ../..\n    archive: lib.tar.gz\n")
+	write("suite/package.yml", "imports:\n  - name: lib\n    path: ../lib\n")
+	write("suite/a.ttcn3", "module a {}")
+	_, err = fetchFrom(t, filepath.Join(dir, "suite"), &project.Fetcher{Offline: true})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), `import "../..": name must not contain path separators`)
+	assert.FileExists(t, filepath.Join(dir, "suite/a.ttcn3"))
+
+	// scp-like git sources are remote.
+	write("scp/package.yml", "imports:\n  - name: codecs\n    git: git@example.invalid:codecs.git\n    commit: 1234\n")
+	_, err = fetchFrom(t, filepath.Join(dir, "scp"), &project.Fetcher{Offline: true})
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "git@example.invalid:codecs.git: not available offline")
+}
+
+func TestFetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir, err := ioutil.TempDir("", "deps")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(path string, content string) {
+		path = filepath.Join(dir, path)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	// The vendor directory provides a git repository and an archive.
+	write("vendor/codecs/codecs.ttcn3", "module codecs {}")
+	commit := gitCommit(t, filepath.Join(dir, "vendor/codecs"))
+	writeArchive(t, filepath.Join(dir, "vendor/sct-1.0.tar.gz"), map[string]string{
+		"sct-1.0/package.yml": "version: 1.0.0\n",
+		"sct-1.0/sct.ttcn3":   "module sct {}",
+	})
+
+	// The suite imports a library, which imports an archive.
+	write("lib/package.yml", "version: 1.2.0\nimports:\n  - name: sct\n    archive: https://example.invalid/sct-1.0.tar.gz\n")
+	write("lib/lib.ttcn3", "module lib {}")
+	manifest := func(suite string, constraint string, ref string) {
+		write(suite+"/package.yml", `
+imports:
+  - name: lib
+    version: `+constraint+`
+    path: ../lib
+  - name: codecs
+    git: https://example.invalid/codecs.git
+    commit: `+ref+`
+`)
+	}
+	manifest("suite", "^1.2", commit[:7])
+	manifest("other", "^2", commit)
+
+	root := filepath.Join(dir, "suite")
+	fetch := func(f *project.Fetcher) (*project.Lock, error) {
+		return fetchFrom(t, root, f)
+	}
+
+	// Remote sources are not available offline.
+	_, err = fetch(&project.Fetcher{Offline: true})
+	assert.NotNil(t, err)
+
+	lock, err := fetch(&project.Fetcher{Vendor: filepath.Join(dir, "vendor"), Offline: true})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"lib", "codecs"}, lock.Requires)
+	require.Len(t, lock.Dependencies, 3)
+
+	lib := lock.Lookup("lib")
+	assert.Equal(t, "1.2.0", lib.Version)
+	assert.Equal(t, "../lib", lib.Dir)
+	assert.Equal(t, []string{"sct"}, lib.Requires)
+
+	codecs := lock.Lookup("codecs")
+	assert.Equal(t, commit[:7], codecs.Ref)
+	assert.Equal(t, commit, codecs.Commit)
+	assert.Equal(t, ".ntt/deps/codecs", codecs.Dir)
+	assert.FileExists(t, filepath.Join(root, ".ntt/deps/codecs/codecs.ttcn3"))
+
+	sct := lock.Lookup("sct")
+	assert.Equal(t, "1.0.0", sct.Version)
+	assert.Len(t, sct.SHA256, 64)
+	assert.FileExists(t, filepath.Join(root, ".ntt/deps/sct/sct.ttcn3"))
+
+	// Imports are resolved using the lock file.
+	require.Nil(t, project.WriteLock(root, lock))
+	imports, err := open(root).Imports()
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "lib"),
+		filepath.Join(root, ".ntt/deps/codecs"),
+		filepath.Join(root, ".ntt/deps/sct"),
+	}, imports)
+
+	// Locked dependencies are not fetched again.
+	lock2, err := fetch(&project.Fetcher{Offline: true})
+	require.Nil(t, err)
+	assert.Equal(t, lock, lock2)
+
+	// Imports changed after fetching are reported.
+	manifest("suite", "^1.2", commit)
+	fs.Open(filepath.Join(root, project.ManifestFile)).Reset()
+	_, err = open(root).Imports()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `import "codecs": lock file out of date. Run 'ntt deps fetch'`)
+	}
+
+	_, err = fetchFrom(t, filepath.Join(dir, "other"), &project.Fetcher{Vendor: filepath.Join(dir, "vendor"), Offline: true})
+	require.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), `version 1.2.0 does not satisfy "^2"`), err.Error())
+}
+
+func fetchFrom(t *testing.T, root string, f *project.Fetcher) (*project.Lock, error) {
+	p, err := project.Open(root)
+	require.Nil(t, err)
+	return f.Fetch(p)
+}
+
+func gitCommit(t *testing.T, dir string) string {
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.Nil(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "--quiet")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "init")
+	return git("rev-parse", "HEAD")
+}
+
+func writeArchive(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	require.Nil(t, err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.Nil(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.Nil(t, err)
+	}
+	require.Nil(t, tw.Close())
+	require.Nil(t, gz.Close())
+}
@@ -1,12 +1,105 @@
 package project
 
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nokia/ntt/internal/errors"
+	"github.com/nokia/ntt/internal/loc"
+	"gopkg.in/yaml.v3"
+)
+
 const ManifestFile = "package.yml"
 
+// ManifestSchema is the JSON schema of the manifest file.
+const ManifestSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "ntt test suite manifest (package.yml)",
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "name":            { "type": "string", "description": "Name of the test suite" },
+    "version":         { "type": "string", "description": "Version of the test suite, used by version constraints of dependent suites" },
+    "sources":         { "type": "array", "items": { "type": "string" }, "description": "TTCN-3 source files and directories" },
+    "imports":         { "type": "array", "items": { "$ref": "#/definitions/import" }, "description": "Directories and dependencies required to build the test suite" },
+    "variables":       { "type": "object", "additionalProperties": { "type": "string" }, "description": "Variables available for expansion" },
+    "test_hook":       { "type": "string", "description": "Path of the test hook" },
+    "parameters_file": { "type": "string", "description": "Path of the module parameters file" },
+    "parameters_dir":  { "type": "string", "description": "Optional directory of the module parameters file" },
+    "timeout":         { "type": "number", "minimum": 0, "description": "Global timeout for tests in seconds" }
+  },
+  "definitions": {
+    "import": {
+      "oneOf": [
+        { "type": "string", "description": "Import directory" },
+        {
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {
+            "name":    { "type": "string", "description": "Name of the dependency" },
+            "version": { "type": "string", "description": "Version constraint, like ^1.2" },
+            "path":    { "type": "string", "description": "Local directory" },
+            "git":     { "type": "string", "description": "Git repository" },
+            "commit":  { "type": "string", "description": "Git commit the dependency is pinned to" },
+            "archive": { "type": "string", "description": "URL or path of a .tar.gz, .tgz, .tar or .zip archive" },
+            "sha256":  { "type": "string", "description": "Checksum of the archive" }
+          }
+        }
+      ]
+    }
+  }
+}`
+
+// manifestSchema is ManifestSchema with references resolved.
+var manifestSchema = func() *Schema {
+	s, err := ParseSchema(ManifestSchema)
+	if err != nil {
+		panic(err)
+	}
+	var defs struct {
+		Definitions map[string]*Schema `json:"definitions"`
+	}
+	if err := json.Unmarshal([]byte(ManifestSchema), &defs); err != nil {
+		panic(err)
+	}
+	s.Properties["imports"].Items = defs.Definitions["import"]
+	return s
+}()
+
+// ValidateManifest validates the content b of manifest file against
+// ManifestSchema. Imports are checked for completeness, too.
+func ValidateManifest(file string, b []byte) errors.ErrorList {
+	errs := manifestSchema.Validate(file, b)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	var doc struct {
+		Imports []yaml.Node
+	}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return errs
+	}
+	for _, n := range doc.Imports {
+		var imp Import
+		if n.Kind != yaml.MappingNode || n.Decode(&imp) != nil {
+			continue
+		}
+		if err := imp.Check(); err != nil {
+			errs.Add(loc.Position{Filename: file, Line: n.Line, Column: n.Column}, err.Error())
+		}
+	}
+	return errs
+}
+
 type Manifest struct {
 	// Static configuration
 	Name      string
+	Version   string
 	Sources   []string
-	Imports   []string
+	Imports   []Import
 	Variables map[string]string
 
 	// Runtime configuration
@@ -15,3 +108,86 @@ type Manifest struct {
 	ParametersDir  string  `yaml:"parameters_dir"`  // Optional path for parameters_file.
 	Timeout        float64 `yaml:"timeout"`         // Global timeout for tests.
 }
+
+// An Import describes a dependency required to build a test suite. Its source
+// is either a local directory (Path), a git repository pinned by commit (Git
+// and Commit) or an archive (Archive).
+//
+// In a manifest an import is either a plain directory path or a mapping:
+//
+//	imports:
+//	  - ../common
+//	  - name: codecs
+//	    version: ^1.2
+//	    git: https://example.com/codecs.git
+//	    commit: 3f2a9c0
+//	  - name: sctlib
+//	    archive: https://example.com/sctlib-1.4.tar.gz
+type Import struct {
+	Name    string
+	Version string // Version constraint, see ParseConstraint.
+	Path    string
+	Git     string
+	Commit  string
+	Archive string
+	SHA256  string `yaml:"sha256"` // Optional checksum of the archive.
+}
+
+// UnmarshalYAML accepts plain directory paths as well as mappings.
+func (imp *Import) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		*imp = Import{Path: path}
+		return nil
+	}
+	type plain Import
+	return unmarshal((*plain)(imp))
+}
+
+// Source returns the location the import is fetched from.
+func (imp *Import) Source() string {
+	switch {
+	case imp.Git != "":
+		return imp.Git
+	case imp.Archive != "":
+		return imp.Archive
+	default:
+		return imp.Path
+	}
+}
+
+// Check returns an error if the import is incomplete or ambiguous.
+func (imp *Import) Check() error {
+	n := 0
+	for _, s := range []string{imp.Path, imp.Git, imp.Archive} {
+		if s != "" {
+			n++
+		}
+	}
+	switch {
+	case n == 0:
+		return fmt.Errorf("import %q: one of path, git or archive required", imp.Name)
+	case n > 1:
+		return fmt.Errorf("import %q: only one of path, git or archive allowed", imp.Name)
+	case imp.Path == "" && imp.Name == "":
+		return fmt.Errorf("import %q: name required", imp.Source())
+	case imp.Git != "" && imp.Commit == "":
+		return fmt.Errorf("import %q: git imports must be pinned by commit", imp.Name)
+	case imp.Git == "" && imp.Commit != "":
+		return fmt.Errorf("import %q: commit requires a git import", imp.Name)
+	case strings.HasPrefix(imp.Commit, "-"):
+		return fmt.Errorf("import %q: invalid commit %q", imp.Name, imp.Commit)
+	case imp.Name != "" && !validName(imp.Name):
+		return fmt.Errorf("import %q: name must not contain path separators or \"..\"", imp.Name)
+	}
+	if _, err := ParseConstraint(imp.Version); err != nil {
+		return fmt.Errorf("import %q: %w", imp.Name, err)
+	}
+	return nil
+}
+
+// validName returns true if name is usable as directory name below DepsDir.
+func validName(name string) bool {
+	clean := filepath.Clean(name)
+	return clean != "." && clean != ".." && clean == name && !strings.ContainsAny(name, `/\`)
+}
@@ -55,6 +55,18 @@ func Files(p Interface) ([]string, error) {
 	return files, multierror.Flatten(errs)
 }
 
+// ASN1Files returns all ASN.1 files (.asn, .asn1) found in the import
+// directories of p. Like the build, ASN.1 modules are only expected in import
+// directories.
+func ASN1Files(p Interface) ([]string, error) {
+	dirs, err := p.Imports()
+	var files []string
+	for _, dir := range dirs {
+		files = append(files, fs.FindASN1Files(dir)...)
+	}
+	return files, err
+}
+
 // FindAllFiles returns all .ttcn3 files including auxiliary files from
 // k3 installation
 func FindAllFiles(p Interface) []string {
@@ -180,7 +192,11 @@ func Open(path string) (*Project, error) {
 	file := filepath.Join(p.root, ManifestFile)
 	if b, err := fs.Content(file); err == nil {
 		log.Debugf("%s: update configuration using manifest %q\n", p.String(), file)
-		return &p, yaml.UnmarshalStrict(b, &p.Manifest)
+		err := yaml.UnmarshalStrict(b, &p.Manifest)
+		if errs := ValidateManifest(file, b); len(errs) > 0 {
+			err = errs
+		}
+		return &p, err
 	}
 
 	// Fall back to recursive scanning
@@ -237,6 +253,9 @@ func (p *Project) Sources() ([]string, error) {
 	return srcs, errs
 }
 
+// Imports returns the directories of all imports. Git and archive imports, as
+// well as imports of dependencies, are resolved using the lock file. Imports
+// changed since the lock file was written are reported as errors.
 func (p *Project) Imports() ([]string, error) {
 	var errs error
 
@@ -244,17 +263,64 @@ func (p *Project) Imports() ([]string, error) {
 		return strings.Fields(env), nil
 	}
 
-	var imports []string
-	for _, dir := range p.Manifest.Imports {
-		dir, err := p.evalPath(dir)
+	var lock *Lock
+	if p.root != "" {
+		l, err := ReadLock(p.root)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		lock = l
+	}
+
+	var (
+		imports []string
+		names   []string
+	)
+	for _, imp := range p.Manifest.Imports {
+		if imp.Path == "" {
+			dep := lock.Lookup(imp.Name)
+			if dep == nil {
+				errs = multierror.Append(errs, fmt.Errorf("import %q: dependency not fetched. Run 'ntt deps fetch'", imp.Name))
+				continue
+			}
+			if !dep.Matches(imp) {
+				errs = multierror.Append(errs, fmt.Errorf("import %q: lock file out of date. Run 'ntt deps fetch'", imp.Name))
+				continue
+			}
+			names = append(names, dep.Name)
+			imports = append(imports, fs.Real(p.root, dep.Dir))
+			continue
+		}
+
+		dir, err := p.evalPath(imp.Path)
 		if err != nil {
 			errs = multierror.Append(errs, err)
 		}
 		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
 			errs = multierror.Append(errs, fmt.Errorf("%q must be a directory", dir))
 		}
+		names = append(names, importName(imp, dir))
 		imports = append(imports, dir)
 	}
+
+	// Add the dependencies of dependencies.
+	visited := make(map[string]bool)
+	for len(names) > 0 {
+		name := names[0]
+		names = names[1:]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+		if dep := lock.Lookup(name); dep != nil {
+			for _, req := range dep.Requires {
+				if d := lock.Lookup(req); d != nil && !visited[req] {
+					names = append(names, req)
+					imports = appendUnique(imports, fs.Real(p.root, d.Dir))
+				}
+			}
+		}
+	}
 	return imports, errs
 }
 
@@ -304,7 +370,7 @@ func (p *Project) findFilesRecursive() error {
 		}
 
 		for _, dir := range fs.FindTTCN3DirectoriesRecursive(path) {
-			p.Manifest.Imports = append(p.Manifest.Imports, dir)
+			p.Manifest.Imports = append(p.Manifest.Imports, Import{Path: dir})
 			log.Debugf("Found import %q\n", dir)
 		}
 	}
@@ -357,5 +423,6 @@ var knownVars = map[string]bool{
 	"NTT_SOURCE_DIR":      true,
 	"NTT_TEST_HOOK":       true,
 	"NTT_TIMEOUT":         true,
+	"NTT_VENDOR":          true,
 	"NTT_VARIABLES":       true,
 }
@@ -0,0 +1,290 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nokia/ntt/internal/errors"
+	"github.com/nokia/ntt/internal/loc"
+	"gopkg.in/yaml.v3"
+)
+
+// A Schema describes the structure of a YAML document. It implements the
+// subset of JSON Schema required to describe manifests: type, properties,
+// additionalProperties, required, items, oneOf, enum and minimum.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Additional        `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+}
+
+// Additional describes properties not listed by Properties. Like in JSON
+// Schema it is either a boolean or a schema.
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *Additional) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(b, &a.Schema)
+}
+
+func (a *Additional) MarshalJSON() ([]byte, error) {
+	if a.Schema != nil {
+		return json.Marshal(a.Schema)
+	}
+	return json.Marshal(a.Allowed)
+}
+
+// ParseSchema parses a schema in JSON format.
+func ParseSchema(s string) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(s), &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// Validate checks YAML document b against the schema. The returned list
+// contains an error for each violation, with the position of the offending
+// node.
+func (s *Schema) Validate(file string, b []byte) errors.ErrorList {
+	var errs errors.ErrorList
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		errs.Add(syntaxErrorPos(file, err), strings.TrimPrefix(err.Error(), "yaml: "))
+		return errs
+	}
+	if len(doc.Content) > 0 {
+		v := validator{file: file}
+		v.validate(s, doc.Content[0], "")
+		errs = v.errs
+	}
+	return errs
+}
+
+// syntaxErrorPos extracts the position from YAML syntax errors like
+// "yaml: line 3: mapping values are not allowed in this context".
+func syntaxErrorPos(file string, err error) loc.Position {
+	pos := loc.Position{Filename: file}
+	if s := strings.TrimPrefix(err.Error(), "yaml: line "); s != err.Error() {
+		if i := strings.IndexByte(s, ':'); i > 0 {
+			pos.Line, _ = strconv.Atoi(s[:i])
+			pos.Column = 1
+		}
+	}
+	return pos
+}
+
+type validator struct {
+	file string
+	errs errors.ErrorList
+}
+
+func (v *validator) errorf(n *yaml.Node, format string, args ...interface{}) {
+	pos := loc.Position{Filename: v.file, Line: n.Line, Column: n.Column}
+	v.errs.Add(pos, fmt.Sprintf(format, args...))
+}
+
+// validate validates node n, named path, against schema s.
+func (v *validator) validate(s *Schema, n *yaml.Node, path string) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+
+	// Empty values are treated like missing values.
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+
+	if len(s.OneOf) > 0 {
+		for _, alt := range s.OneOf {
+			if hasType(n, alt.Type) {
+				v.validate(alt, n, path)
+				return
+			}
+		}
+		var types []string
+		for _, alt := range s.OneOf {
+			types = append(types, alt.Type)
+		}
+		v.errorf(n, "%s: expected %s, got %s", name(path), strings.Join(types, " or "), typeOf(n))
+		return
+	}
+
+	if s.Type != "" && !hasType(n, s.Type) {
+		v.errorf(n, "%s: expected %s, got %s", name(path), s.Type, typeOf(n))
+		return
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		v.validateMapping(s, n, path)
+	case yaml.SequenceNode:
+		if s.Items != nil {
+			for i, item := range n.Content {
+				v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case yaml.ScalarNode:
+		if len(s.Enum) > 0 && !contains(s.Enum, n.Value) {
+			v.errorf(n, "%s: %q is not one of %s", name(path), n.Value, strings.Join(s.Enum, ", "))
+		}
+		if s.Minimum != nil {
+			if f, err := strconv.ParseFloat(n.Value, 64); err == nil && f < *s.Minimum {
+				v.errorf(n, "%s: must be at least %v", name(path), *s.Minimum)
+			}
+		}
+	}
+}
+
+func (v *validator) validateMapping(s *Schema, n *yaml.Node, path string) {
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		field := key.Value
+		if path != "" {
+			field = path + "." + key.Value
+		}
+		if seen[key.Value] {
+			v.errorf(key, "duplicate field %q", field)
+			continue
+		}
+		seen[key.Value] = true
+
+		if prop, ok := s.Properties[key.Value]; ok {
+			v.validate(prop, val, field)
+			continue
+		}
+		switch a := s.AdditionalProperties; {
+		case a != nil && a.Schema != nil:
+			v.validate(a.Schema, val, field)
+		case a != nil && !a.Allowed:
+			msg := fmt.Sprintf("unknown field %q", field)
+			if alt := suggest(key.Value, s.Properties); alt != "" {
+				msg += fmt.Sprintf(". Did you mean %q?", alt)
+			}
+			v.errorf(key, "%s", msg)
+		}
+	}
+	for _, req := range s.Required {
+		if !seen[req] {
+			v.errorf(n, "%s: missing required field %q", name(path), req)
+		}
+	}
+}
+
+func name(path string) string {
+	if path == "" {
+		return "document"
+	}
+	return fmt.Sprintf("%q", path)
+}
+
+func hasType(n *yaml.Node, typ string) bool {
+	switch typ {
+	case "":
+		return true
+	case "object":
+		return n.Kind == yaml.MappingNode
+	case "array":
+		return n.Kind == yaml.SequenceNode
+	case "string":
+		// Like the manifest decoder, accept any scalar as string.
+		return n.Kind == yaml.ScalarNode
+	case "number":
+		return n.Kind == yaml.ScalarNode && (n.Tag == "!!int" || n.Tag == "!!float")
+	case "integer":
+		return n.Kind == yaml.ScalarNode && n.Tag == "!!int"
+	case "boolean":
+		return n.Kind == yaml.ScalarNode && n.Tag == "!!bool"
+	}
+	return false
+}
+
+func typeOf(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch n.Tag {
+	case "!!int", "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	}
+	return "string"
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns the property most similar to key, if any is similar enough
+// to be a likely typo.
+func suggest(key string, props map[string]*Schema) string {
+	var names []string
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, dist := "", len(key)/3+1
+	for _, name := range names {
+		if d := levenshtein(key, name); d <= dist {
+			best, dist = name, d-1
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cur := row[j]
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			row[j] = min3(row[j]+1, row[j-1]+1, prev+cost)
+			prev = cur
+		}
+	}
+	return row[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,84 @@
+package project_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateManifest(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: ``},
+		{input: `
+name: suite
+version: 1.0
+sources:
+  - a.ttcn3
+imports:
+  - ../common
+  - name: codecs
+    git: https://example.com/codecs.git
+    commit: 3f2a9c0
+variables:
+  FOO: bar
+  N: 1
+parameters_file: suite.parameters
+timeout: 1.5
+test_hook:
+`},
+		{
+			input: "name: suite\nparameter_file: suite.parameters\n",
+			want:  []string{`package.yml:2:1: unknown field "parameter_file". Did you mean "parameters_file"?`},
+		},
+		{
+			input: "timeout: soon\nsources: a.ttcn3\nxyz: 1\n",
+			want: []string{
+				`package.yml:1:10: "timeout": expected number, got string`,
+				`package.yml:2:10: "sources": expected array, got string`,
+				`package.yml:3:1: unknown field "xyz"`,
+			},
+		},
+		{
+			input: "timeout: -1\nvariables:\n  A: [1, 2]\n",
+			want: []string{
+				`package.yml:1:10: "timeout": must be at least 0`,
+				`package.yml:3:6: "variables.A": expected string, got array`,
+			},
+		},
+		{
+			input: "imports:\n  - [a, b]\n  - name: x\n    gti: foo\n  - name: y\n    git: foo\n",
+			want: []string{
+				`package.yml:2:5: "imports[0]": expected string or object, got array`,
+				`package.yml:4:5: unknown field "imports[1].gti". Did you mean "git"?`,
+			},
+		},
+		{
+			input: "imports:\n  - name: y\n    git: foo\n",
+			want:  []string{`package.yml:2:5: import "y": git imports must be pinned by commit`},
+		},
+		{
+			input: "name: a\nname: b\n",
+			want:  []string{`package.yml:2:1: duplicate field "name"`},
+		},
+		{
+			input: "name: a\n  sources: b\n",
+			want:  []string{`package.yml:2:1: line 2: mapping values are not allowed in this context`},
+		},
+		{
+			input: "- a\n",
+			want:  []string{`package.yml:1:1: document: expected object, got array`},
+		},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, e := range project.ValidateManifest("package.yml", []byte(tt.input)) {
+			got = append(got, e.Error())
+		}
+		assert.Equal(t, tt.want, got, tt.input)
+	}
+}
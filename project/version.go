@@ -0,0 +1,182 @@
+package project
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Constraint restricts the versions of a dependency. Constraints are a comma
+// or space separated list of terms, all of which must match:
+//
+//	1.2.3     exactly 1.2.3; partial versions like 1.2 match any 1.2.x
+//	>=1.2     at least 1.2.0 (also >, <, <=, !=)
+//	^1.2.3    compatible with 1.2.3: at least 1.2.3, but below 2.0.0
+//	~1.2.3    approximately 1.2.3: at least 1.2.3, but below 1.3.0
+//
+// An empty constraint or "*" matches any version.
+type Constraint struct {
+	s     string
+	terms []term
+}
+
+type term struct {
+	op      string
+	version version
+}
+
+// ParseConstraint parses a version constraint.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{s: s}
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if f == "*" {
+			continue
+		}
+		i := strings.IndexFunc(f, func(r rune) bool { return !strings.ContainsRune("=!<>^~", r) })
+		if i < 0 {
+			return nil, fmt.Errorf("invalid version constraint %q", s)
+		}
+		op := f[:i]
+		switch op {
+		case "", "=", "!=", "<", "<=", ">", ">=", "^", "~":
+		default:
+			return nil, fmt.Errorf("invalid version constraint %q: unknown operator %q", s, op)
+		}
+		v, err := parseVersion(f[i:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		c.terms = append(c.terms, term{op: op, version: v})
+	}
+	return c, nil
+}
+
+func (c *Constraint) String() string {
+	return c.s
+}
+
+// Match returns true if version v satisfies the constraint. Invalid versions
+// only match constraints matching any version.
+func (c *Constraint) Match(v string) bool {
+	if len(c.terms) == 0 {
+		return true
+	}
+	ver, err := parseVersion(v)
+	if err != nil {
+		return false
+	}
+	for _, t := range c.terms {
+		if !t.match(ver) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t term) match(v version) bool {
+	cmp := compareVersions(v, t.version)
+	switch t.op {
+	case "", "=":
+		return v.hasPrefix(t.version)
+	case "!=":
+		return !v.hasPrefix(t.version)
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "^":
+		// The left-most non-zero component must not change.
+		i := 0
+		for i < len(t.version.nums)-1 && t.version.nums[i] == 0 {
+			i++
+		}
+		return cmp >= 0 && compareVersions(v, t.version.bump(i)) < 0
+	case "~":
+		i := len(t.version.nums) - 1
+		if i > 1 {
+			i = 1
+		}
+		return cmp >= 0 && compareVersions(v, t.version.bump(i)) < 0
+	}
+	return false
+}
+
+// version is a dotted version number like "v1.2.3-rc1".
+type version struct {
+	nums []int
+	pre  string
+}
+
+func parseVersion(s string) (version, error) {
+	var v version
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, v.pre = s[:i], s[i+1:]
+	}
+	for _, f := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return version{}, fmt.Errorf("invalid version %q", s)
+		}
+		v.nums = append(v.nums, n)
+	}
+	return v, nil
+}
+
+// hasPrefix returns true if the components of prefix match the leading
+// components of v.
+func (v version) hasPrefix(prefix version) bool {
+	if len(prefix.nums) > len(v.nums) {
+		return compareVersions(v, prefix) == 0
+	}
+	for i, n := range prefix.nums {
+		if v.nums[i] != n {
+			return false
+		}
+	}
+	return prefix.pre == "" || prefix.pre == v.pre
+}
+
+// bump returns the smallest version incrementing component i of v.
+func (v version) bump(i int) version {
+	nums := make([]int, i+1)
+	copy(nums, v.nums)
+	nums[i]++
+	return version{nums: nums}
+}
+
+// compareVersions compares a and b. Missing components are treated as zero.
+// Pre-releases sort before releases.
+func compareVersions(a, b version) int {
+	for i := 0; i < len(a.nums) || i < len(b.nums); i++ {
+		var x, y int
+		if i < len(a.nums) {
+			x = a.nums[i]
+		}
+		if i < len(b.nums) {
+			y = b.nums[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	switch {
+	case a.pre == b.pre:
+		return 0
+	case a.pre == "":
+		return 1
+	case b.pre == "":
+		return -1
+	case a.pre < b.pre:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,46 @@
+package project_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{"", []string{"1.0", "v2.3.4"}, []string{}},
+		{"*", []string{"1.0", "abc"}, []string{}},
+		{"1.2", []string{"1.2", "1.2.0", "1.2.9", "v1.2.3"}, []string{"1.3", "1.20", "1"}},
+		{"=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"!=1.2", []string{"1.3"}, []string{"1.2.1"}},
+		{">=1.2, <2", []string{"1.2.0", "1.9.9"}, []string{"1.1", "2.0.0"}},
+		{">1.2 <=1.4", []string{"1.2.1", "1.4"}, []string{"1.2", "1.4.1"}},
+		{"^1.2.3", []string{"1.2.3", "1.9"}, []string{"1.2.2", "2.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0", "1.9"}, []string{"2.0"}},
+		{">=1.0", []string{"1.0.0"}, []string{"1.0.0-rc1"}},
+	}
+
+	for _, tt := range tests {
+		c, err := project.ParseConstraint(tt.constraint)
+		require.Nil(t, err, tt.constraint)
+		for _, v := range tt.match {
+			assert.True(t, c.Match(v), "%q should match %q", tt.constraint, v)
+		}
+		for _, v := range tt.noMatch {
+			assert.False(t, c.Match(v), "%q should not match %q", tt.constraint, v)
+		}
+	}
+
+	for _, s := range []string{">>1.0", "1.x", "^", "1..2"} {
+		_, err := project.ParseConstraint(s)
+		assert.NotNil(t, err, s)
+	}
+}
@@ -25,12 +25,72 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type Behaviour_Kind int32
+
+const (
+	Behaviour_FUNCTION Behaviour_Kind = 0
+	Behaviour_ALTSTEP  Behaviour_Kind = 1
+	Behaviour_TESTCASE Behaviour_Kind = 2
+)
+
+// Enum value maps for Behaviour_Kind.
+var (
+	Behaviour_Kind_name = map[int32]string{
+		0: "FUNCTION",
+		1: "ALTSTEP",
+		2: "TESTCASE",
+	}
+	Behaviour_Kind_value = map[string]int32{
+		"FUNCTION": 0,
+		"ALTSTEP":  1,
+		"TESTCASE": 2,
+	}
+)
+
+func (x Behaviour_Kind) Enum() *Behaviour_Kind {
+	p := new(Behaviour_Kind)
+	*p = x
+	return p
+}
+
+func (x Behaviour_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Behaviour_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_module_proto_enumTypes[0].Descriptor()
+}
+
+func (Behaviour_Kind) Type() protoreflect.EnumType {
+	return &file_module_proto_enumTypes[0]
+}
+
+func (x Behaviour_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Behaviour_Kind.Descriptor instead.
+func (Behaviour_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_module_proto_rawDescGZIP(), []int{2, 0}
+}
+
+// Module describes a TTCN-3 module and the signatures of its definitions.
+// Bodies of behaviours are not included.
 type Module struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Name     string    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Location *Location `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Comment  string    `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	// Names of imported modules.
+	Imports          []string      `protobuf:"bytes,4,rep,name=imports,proto3" json:"imports,omitempty"`
+	Types            []*Type       `protobuf:"bytes,5,rep,name=types,proto3" json:"types,omitempty"`
+	Constants        []*Definition `protobuf:"bytes,6,rep,name=constants,proto3" json:"constants,omitempty"`
+	Templates        []*Definition `protobuf:"bytes,7,rep,name=templates,proto3" json:"templates,omitempty"`
+	ModuleParameters []*Definition `protobuf:"bytes,8,rep,name=module_parameters,json=moduleParameters,proto3" json:"module_parameters,omitempty"`
+	Behaviours       []*Behaviour  `protobuf:"bytes,9,rep,name=behaviours,proto3" json:"behaviours,omitempty"`
 }
 
 func (x *Module) Reset() {
@@ -72,15 +132,449 @@ func (x *Module) GetName() string {
 	return ""
 }
 
+func (x *Module) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Module) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Module) GetImports() []string {
+	if x != nil {
+		return x.Imports
+	}
+	return nil
+}
+
+func (x *Module) GetTypes() []*Type {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *Module) GetConstants() []*Definition {
+	if x != nil {
+		return x.Constants
+	}
+	return nil
+}
+
+func (x *Module) GetTemplates() []*Definition {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+func (x *Module) GetModuleParameters() []*Definition {
+	if x != nil {
+		return x.ModuleParameters
+	}
+	return nil
+}
+
+func (x *Module) GetBehaviours() []*Behaviour {
+	if x != nil {
+		return x.Behaviours
+	}
+	return nil
+}
+
+// Definition describes a constant, template or module parameter.
+type Definition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Location *Location `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Comment  string    `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	Type     *Type     `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	// Value is the source text of the value or template expression. Module
+	// parameters without default value have an empty value.
+	Value string `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	// Formal parameters of parameterized templates.
+	Parameters []*FormalParameter `protobuf:"bytes,6,rep,name=parameters,proto3" json:"parameters,omitempty"`
+}
+
+func (x *Definition) Reset() {
+	*x = Definition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_module_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Definition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Definition) ProtoMessage() {}
+
+func (x *Definition) ProtoReflect() protoreflect.Message {
+	mi := &file_module_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Definition.ProtoReflect.Descriptor instead.
+func (*Definition) Descriptor() ([]byte, []int) {
+	return file_module_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Definition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Definition) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Definition) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Definition) GetType() *Type {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *Definition) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Definition) GetParameters() []*FormalParameter {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+// Behaviour describes the signature of a function, altstep or testcase.
+type Behaviour struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind           Behaviour_Kind     `protobuf:"varint,1,opt,name=kind,proto3,enum=ntt.Behaviour_Kind" json:"kind,omitempty"`
+	Name           string             `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Location       *Location          `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Comment        string             `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	External       bool               `protobuf:"varint,5,opt,name=external,proto3" json:"external,omitempty"`
+	Parameters     []*FormalParameter `protobuf:"bytes,6,rep,name=parameters,proto3" json:"parameters,omitempty"`
+	ReturnType     *Type              `protobuf:"bytes,7,opt,name=return_type,json=returnType,proto3" json:"return_type,omitempty"`
+	ReturnTemplate bool               `protobuf:"varint,8,opt,name=return_template,json=returnTemplate,proto3" json:"return_template,omitempty"`
+	RunsOn         *Type              `protobuf:"bytes,9,opt,name=runs_on,json=runsOn,proto3" json:"runs_on,omitempty"`
+	Mtc            *Type              `protobuf:"bytes,10,opt,name=mtc,proto3" json:"mtc,omitempty"`
+	System         *Type              `protobuf:"bytes,11,opt,name=system,proto3" json:"system,omitempty"`
+}
+
+func (x *Behaviour) Reset() {
+	*x = Behaviour{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_module_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Behaviour) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Behaviour) ProtoMessage() {}
+
+func (x *Behaviour) ProtoReflect() protoreflect.Message {
+	mi := &file_module_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Behaviour.ProtoReflect.Descriptor instead.
+func (*Behaviour) Descriptor() ([]byte, []int) {
+	return file_module_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Behaviour) GetKind() Behaviour_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Behaviour_FUNCTION
+}
+
+func (x *Behaviour) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Behaviour) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Behaviour) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Behaviour) GetExternal() bool {
+	if x != nil {
+		return x.External
+	}
+	return false
+}
+
+func (x *Behaviour) GetParameters() []*FormalParameter {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *Behaviour) GetReturnType() *Type {
+	if x != nil {
+		return x.ReturnType
+	}
+	return nil
+}
+
+func (x *Behaviour) GetReturnTemplate() bool {
+	if x != nil {
+		return x.ReturnTemplate
+	}
+	return false
+}
+
+func (x *Behaviour) GetRunsOn() *Type {
+	if x != nil {
+		return x.RunsOn
+	}
+	return nil
+}
+
+func (x *Behaviour) GetMtc() *Type {
+	if x != nil {
+		return x.Mtc
+	}
+	return nil
+}
+
+func (x *Behaviour) GetSystem() *Type {
+	if x != nil {
+		return x.System
+	}
+	return nil
+}
+
+// FormalParameter describes a formal parameter of a behaviour or template.
+type FormalParameter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Direction Parameter_Direction `protobuf:"varint,2,opt,name=direction,proto3,enum=ntt.Parameter_Direction" json:"direction,omitempty"`
+	Type      *Type               `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Template  bool                `protobuf:"varint,4,opt,name=template,proto3" json:"template,omitempty"`
+	// Source text of the default value, if any.
+	DefaultValue string `protobuf:"bytes,5,opt,name=default_value,json=defaultValue,proto3" json:"default_value,omitempty"`
+}
+
+func (x *FormalParameter) Reset() {
+	*x = FormalParameter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_module_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FormalParameter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FormalParameter) ProtoMessage() {}
+
+func (x *FormalParameter) ProtoReflect() protoreflect.Message {
+	mi := &file_module_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FormalParameter.ProtoReflect.Descriptor instead.
+func (*FormalParameter) Descriptor() ([]byte, []int) {
+	return file_module_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FormalParameter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FormalParameter) GetDirection() Parameter_Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Parameter_IN
+}
+
+func (x *FormalParameter) GetType() *Type {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *FormalParameter) GetTemplate() bool {
+	if x != nil {
+		return x.Template
+	}
+	return false
+}
+
+func (x *FormalParameter) GetDefaultValue() string {
+	if x != nil {
+		return x.DefaultValue
+	}
+	return ""
+}
+
 var File_module_proto protoreflect.FileDescriptor
 
 var file_module_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03,
-	0x6e, 0x74, 0x74, 0x22, 0x1c, 0x0a, 0x06, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x42, 0x1f, 0x5a, 0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x6e, 0x6f, 0x6b, 0x69, 0x61, 0x2f, 0x6e, 0x74, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x74, 0x74, 0x1a, 0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x0f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xe8, 0x02, 0x0a, 0x06, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x29, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x1f,
+	0x0a, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e,
+	0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x12,
+	0x2d, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x2d,
+	0x0a, 0x09, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x09, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x12, 0x3c, 0x0a,
+	0x11, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65,
+	0x72, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x44,
+	0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x6d, 0x6f, 0x64, 0x75, 0x6c,
+	0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x2e, 0x0a, 0x0a, 0x62,
+	0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x75, 0x72, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x42, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x75, 0x72, 0x52,
+	0x0a, 0x62, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x75, 0x72, 0x73, 0x22, 0xd0, 0x01, 0x0a, 0x0a,
+	0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29,
+	0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x34, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x61,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6e,
+	0x74, 0x74, 0x2e, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x22, 0xc9,
+	0x03, 0x0a, 0x09, 0x42, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x75, 0x72, 0x12, 0x27, 0x0a, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6e, 0x74, 0x74,
+	0x2e, 0x42, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x75, 0x72, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x08, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74,
+	0x74, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x34, 0x0a, 0x0a, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x65, 0x74, 0x65, 0x72, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73,
+	0x12, 0x2a, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x0a, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x27, 0x0a, 0x0f,
+	0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x73, 0x5f, 0x6f, 0x6e,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x06, 0x72, 0x75, 0x6e, 0x73, 0x4f, 0x6e, 0x12, 0x1b, 0x0a, 0x03, 0x6d, 0x74, 0x63,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x03, 0x6d, 0x74, 0x63, 0x12, 0x21, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x22, 0x2f, 0x0a, 0x04, 0x4b, 0x69, 0x6e,
+	0x64, 0x12, 0x0c, 0x0a, 0x08, 0x46, 0x55, 0x4e, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12,
+	0x0b, 0x0a, 0x07, 0x41, 0x4c, 0x54, 0x53, 0x54, 0x45, 0x50, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08,
+	0x54, 0x45, 0x53, 0x54, 0x43, 0x41, 0x53, 0x45, 0x10, 0x02, 0x22, 0xbd, 0x01, 0x0a, 0x0f, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x36, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x74, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x65,
+	0x66, 0x61, 0x75, 0x6c, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x1f, 0x5a, 0x1d, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x6f, 0x6b, 0x69, 0x61, 0x2f, 0x6e,
+	0x74, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -95,16 +589,42 @@ func file_module_proto_rawDescGZIP() []byte {
 	return file_module_proto_rawDescData
 }
 
-var file_module_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_module_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_module_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_module_proto_goTypes = []interface{}{
-	(*Module)(nil), // 0: ntt.Module
+	(Behaviour_Kind)(0),      // 0: ntt.Behaviour.Kind
+	(*Module)(nil),           // 1: ntt.Module
+	(*Definition)(nil),       // 2: ntt.Definition
+	(*Behaviour)(nil),        // 3: ntt.Behaviour
+	(*FormalParameter)(nil),  // 4: ntt.FormalParameter
+	(*Location)(nil),         // 5: ntt.Location
+	(*Type)(nil),             // 6: ntt.Type
+	(Parameter_Direction)(0), // 7: ntt.Parameter.Direction
 }
 var file_module_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5,  // 0: ntt.Module.location:type_name -> ntt.Location
+	6,  // 1: ntt.Module.types:type_name -> ntt.Type
+	2,  // 2: ntt.Module.constants:type_name -> ntt.Definition
+	2,  // 3: ntt.Module.templates:type_name -> ntt.Definition
+	2,  // 4: ntt.Module.module_parameters:type_name -> ntt.Definition
+	3,  // 5: ntt.Module.behaviours:type_name -> ntt.Behaviour
+	5,  // 6: ntt.Definition.location:type_name -> ntt.Location
+	6,  // 7: ntt.Definition.type:type_name -> ntt.Type
+	4,  // 8: ntt.Definition.parameters:type_name -> ntt.FormalParameter
+	0,  // 9: ntt.Behaviour.kind:type_name -> ntt.Behaviour.Kind
+	5,  // 10: ntt.Behaviour.location:type_name -> ntt.Location
+	4,  // 11: ntt.Behaviour.parameters:type_name -> ntt.FormalParameter
+	6,  // 12: ntt.Behaviour.return_type:type_name -> ntt.Type
+	6,  // 13: ntt.Behaviour.runs_on:type_name -> ntt.Type
+	6,  // 14: ntt.Behaviour.mtc:type_name -> ntt.Type
+	6,  // 15: ntt.Behaviour.system:type_name -> ntt.Type
+	7,  // 16: ntt.FormalParameter.direction:type_name -> ntt.Parameter.Direction
+	6,  // 17: ntt.FormalParameter.type:type_name -> ntt.Type
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_module_proto_init() }
@@ -112,6 +632,9 @@ func file_module_proto_init() {
 	if File_module_proto != nil {
 		return
 	}
+	file_location_proto_init()
+	file_parameter_proto_init()
+	file_type_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_module_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Module); i {
@@ -125,19 +648,56 @@ func file_module_proto_init() {
 				return nil
 			}
 		}
+		file_module_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Definition); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_module_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Behaviour); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_module_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FormalParameter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_module_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_module_proto_goTypes,
 		DependencyIndexes: file_module_proto_depIdxs,
+		EnumInfos:         file_module_proto_enumTypes,
 		MessageInfos:      file_module_proto_msgTypes,
 	}.Build()
 	File_module_proto = out.File
@@ -33,6 +33,7 @@ const (
 	ScalarType_FLOAT   ScalarType = 2
 	ScalarType_INTEGER ScalarType = 3
 	ScalarType_VERDICT ScalarType = 4
+	ScalarType_DEFAULT ScalarType = 5
 )
 
 // Enum value maps for ScalarType.
@@ -43,6 +44,7 @@ var (
 		2: "FLOAT",
 		3: "INTEGER",
 		4: "VERDICT",
+		5: "DEFAULT",
 	}
 	ScalarType_value = map[string]int32{
 		"NULL":    0,
@@ -50,6 +52,7 @@ var (
 		"FLOAT":   2,
 		"INTEGER": 3,
 		"VERDICT": 4,
+		"DEFAULT": 5,
 	}
 )
 
@@ -80,15 +83,68 @@ func (ScalarType) EnumDescriptor() ([]byte, []int) {
 	return file_type_proto_rawDescGZIP(), []int{0}
 }
 
+type PortType_Kind int32
+
+const (
+	PortType_MESSAGE   PortType_Kind = 0
+	PortType_PROCEDURE PortType_Kind = 1
+	PortType_MIXED     PortType_Kind = 2
+)
+
+// Enum value maps for PortType_Kind.
+var (
+	PortType_Kind_name = map[int32]string{
+		0: "MESSAGE",
+		1: "PROCEDURE",
+		2: "MIXED",
+	}
+	PortType_Kind_value = map[string]int32{
+		"MESSAGE":   0,
+		"PROCEDURE": 1,
+		"MIXED":     2,
+	}
+)
+
+func (x PortType_Kind) Enum() *PortType_Kind {
+	p := new(PortType_Kind)
+	*p = x
+	return p
+}
+
+func (x PortType_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PortType_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_type_proto_enumTypes[1].Descriptor()
+}
+
+func (PortType_Kind) Type() protoreflect.EnumType {
+	return &file_type_proto_enumTypes[1]
+}
+
+func (x PortType_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PortType_Kind.Descriptor instead.
+func (PortType_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_type_proto_rawDescGZIP(), []int{8, 0}
+}
+
 type Type struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Encode    string `protobuf:"bytes,3,opt,name=encode,proto3" json:"encode,omitempty"`
-	Variant   string `protobuf:"bytes,4,opt,name=variant,proto3" json:"variant,omitempty"`
-	Extension string `protobuf:"bytes,5,opt,name=extension,proto3" json:"extension,omitempty"`
+	Name       string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Encode     string      `protobuf:"bytes,3,opt,name=encode,proto3" json:"encode,omitempty"`
+	Variant    string      `protobuf:"bytes,4,opt,name=variant,proto3" json:"variant,omitempty"`
+	Extension  string      `protobuf:"bytes,5,opt,name=extension,proto3" json:"extension,omitempty"`
+	Constraint *Constraint `protobuf:"bytes,6,opt,name=constraint,proto3" json:"constraint,omitempty"`
+	// Location and comment of named type definitions.
+	Location *Location `protobuf:"bytes,7,opt,name=location,proto3" json:"location,omitempty"`
+	Comment  string    `protobuf:"bytes,8,opt,name=comment,proto3" json:"comment,omitempty"`
 	// Types that are assignable to Kind:
 	//	*Type_Array
 	//	*Type_RecordOf
@@ -108,6 +164,7 @@ type Type struct {
 	//	*Type_Port
 	//	*Type_Timer
 	//	*Type_Address
+	//	*Type_Reference
 	Kind isType_Kind `protobuf_oneof:"kind"`
 }
 
@@ -171,6 +228,27 @@ func (x *Type) GetExtension() string {
 	return ""
 }
 
+func (x *Type) GetConstraint() *Constraint {
+	if x != nil {
+		return x.Constraint
+	}
+	return nil
+}
+
+func (x *Type) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Type) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
 func (m *Type) GetKind() isType_Kind {
 	if m != nil {
 		return m.Kind
@@ -304,6 +382,13 @@ func (x *Type) GetAddress() *Type {
 	return nil
 }
 
+func (x *Type) GetReference() string {
+	if x, ok := x.GetKind().(*Type_Reference); ok {
+		return x.Reference
+	}
+	return ""
+}
+
 type isType_Kind interface {
 	isType_Kind()
 }
@@ -380,6 +465,12 @@ type Type_Address struct {
 	Address *Type `protobuf:"bytes,26,opt,name=address,proto3,oneof"`
 }
 
+type Type_Reference struct {
+	// Reference to a named type, qualified by its module, for example
+	// "M.T". Named types are listed in Module.types.
+	Reference string `protobuf:"bytes,27,opt,name=reference,proto3,oneof"`
+}
+
 func (*Type_Array) isType_Kind() {}
 
 func (*Type_RecordOf) isType_Kind() {}
@@ -416,13 +507,16 @@ func (*Type_Timer) isType_Kind() {}
 
 func (*Type_Address) isType_Kind() {}
 
+func (*Type_Reference) isType_Kind() {}
+
 type Field struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Type *Type  `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type     *Type  `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Optional bool   `protobuf:"varint,3,opt,name=optional,proto3" json:"optional,omitempty"`
 }
 
 func (x *Field) Reset() {
@@ -471,6 +565,13 @@ func (x *Field) GetType() *Type {
 	return nil
 }
 
+func (x *Field) GetOptional() bool {
+	if x != nil {
+		return x.Optional
+	}
+	return false
+}
+
 type StructType struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -620,10 +721,14 @@ func (x *StringType) GetElementWidth() int32 {
 	return 0
 }
 
+// Constraint describes length and range restrictions with constant bounds.
 type Constraint struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Length *Constraint_Bounds   `protobuf:"bytes,1,opt,name=length,proto3" json:"length,omitempty"`
+	Ranges []*Constraint_Bounds `protobuf:"bytes,2,rep,name=ranges,proto3" json:"ranges,omitempty"`
 }
 
 func (x *Constraint) Reset() {
@@ -658,10 +763,28 @@ func (*Constraint) Descriptor() ([]byte, []int) {
 	return file_type_proto_rawDescGZIP(), []int{5}
 }
 
+func (x *Constraint) GetLength() *Constraint_Bounds {
+	if x != nil {
+		return x.Length
+	}
+	return nil
+}
+
+func (x *Constraint) GetRanges() []*Constraint_Bounds {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
 type ComponentType struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Extends []*Type `protobuf:"bytes,1,rep,name=extends,proto3" json:"extends,omitempty"`
+	// Variables, constants, timers and ports of the component.
+	Definitions []*Field `protobuf:"bytes,2,rep,name=definitions,proto3" json:"definitions,omitempty"`
 }
 
 func (x *ComponentType) Reset() {
@@ -696,10 +819,26 @@ func (*ComponentType) Descriptor() ([]byte, []int) {
 	return file_type_proto_rawDescGZIP(), []int{6}
 }
 
+func (x *ComponentType) GetExtends() []*Type {
+	if x != nil {
+		return x.Extends
+	}
+	return nil
+}
+
+func (x *ComponentType) GetDefinitions() []*Field {
+	if x != nil {
+		return x.Definitions
+	}
+	return nil
+}
+
 type EnumType struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Values []*EnumType_Enum `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
 }
 
 func (x *EnumType) Reset() {
@@ -734,10 +873,22 @@ func (*EnumType) Descriptor() ([]byte, []int) {
 	return file_type_proto_rawDescGZIP(), []int{7}
 }
 
+func (x *EnumType) GetValues() []*EnumType_Enum {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
 type PortType struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Kind  PortType_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=ntt.PortType_Kind" json:"kind,omitempty"`
+	In    []*Type       `protobuf:"bytes,2,rep,name=in,proto3" json:"in,omitempty"`
+	Out   []*Type       `protobuf:"bytes,3,rep,name=out,proto3" json:"out,omitempty"`
+	Inout []*Type       `protobuf:"bytes,4,rep,name=inout,proto3" json:"inout,omitempty"`
 }
 
 func (x *PortType) Reset() {
@@ -772,6 +923,34 @@ func (*PortType) Descriptor() ([]byte, []int) {
 	return file_type_proto_rawDescGZIP(), []int{8}
 }
 
+func (x *PortType) GetKind() PortType_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return PortType_MESSAGE
+}
+
+func (x *PortType) GetIn() []*Type {
+	if x != nil {
+		return x.In
+	}
+	return nil
+}
+
+func (x *PortType) GetOut() []*Type {
+	if x != nil {
+		return x.Out
+	}
+	return nil
+}
+
+func (x *PortType) GetInout() []*Type {
+	if x != nil {
+		return x.Inout
+	}
+	return nil
+}
+
 type TimerType struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -817,6 +996,8 @@ type Constraint_Bounds struct {
 
 	Start int64 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
 	End   int64 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	// Unbounded ranges, like "(0..infinity)", have no end.
+	Infinite bool `protobuf:"varint,3,opt,name=infinite,proto3" json:"infinite,omitempty"`
 }
 
 func (x *Constraint_Bounds) Reset() {
@@ -865,100 +1046,205 @@ func (x *Constraint_Bounds) GetEnd() int64 {
 	return 0
 }
 
+func (x *Constraint_Bounds) GetInfinite() bool {
+	if x != nil {
+		return x.Infinite
+	}
+	return false
+}
+
+type EnumType_Enum struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value int64  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *EnumType_Enum) Reset() {
+	*x = EnumType_Enum{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_type_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EnumType_Enum) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumType_Enum) ProtoMessage() {}
+
+func (x *EnumType_Enum) ProtoReflect() protoreflect.Message {
+	mi := &file_type_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumType_Enum.ProtoReflect.Descriptor instead.
+func (*EnumType_Enum) Descriptor() ([]byte, []int) {
+	return file_type_proto_rawDescGZIP(), []int{7, 0}
+}
+
+func (x *EnumType_Enum) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EnumType_Enum) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
 var File_type_proto protoreflect.FileDescriptor
 
 var file_type_proto_rawDesc = []byte{
 	0x0a, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x6e, 0x74,
-	0x74, 0x22, 0xab, 0x07, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x74, 0x1a, 0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xc1, 0x08, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
 	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16,
 	0x0a, 0x06, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
 	0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e,
 	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74,
 	0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25,
-	0x0a, 0x05, 0x61, 0x72, 0x72, 0x61, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e,
-	0x6e, 0x74, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x05,
-	0x61, 0x72, 0x72, 0x61, 0x79, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f,
-	0x6f, 0x66, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x63, 0x6f, 0x72,
-	0x64, 0x4f, 0x66, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x65, 0x74, 0x5f, 0x6f, 0x66, 0x18, 0x0b, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x79,
-	0x70, 0x65, 0x48, 0x00, 0x52, 0x05, 0x73, 0x65, 0x74, 0x4f, 0x66, 0x12, 0x2f, 0x0a, 0x09, 0x62,
-	0x69, 0x74, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x48,
-	0x00, 0x52, 0x09, 0x62, 0x69, 0x74, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x31, 0x0a, 0x0a,
-	0x63, 0x68, 0x61, 0x72, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70,
-	0x65, 0x48, 0x00, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x72, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12,
-	0x31, 0x0a, 0x0a, 0x68, 0x65, 0x78, 0x74, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0e, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67,
-	0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x0a, 0x68, 0x65, 0x78, 0x74, 0x73, 0x74, 0x72, 0x69,
-	0x6e, 0x67, 0x12, 0x33, 0x0a, 0x0b, 0x6f, 0x63, 0x74, 0x65, 0x74, 0x73, 0x74, 0x72, 0x69, 0x6e,
-	0x67, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74,
-	0x72, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x6f, 0x63, 0x74, 0x65,
-	0x74, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x44, 0x0a, 0x14, 0x75, 0x6e, 0x69, 0x76, 0x65,
-	0x72, 0x73, 0x61, 0x6c, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18,
-	0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x69,
-	0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x13, 0x75, 0x6e, 0x69, 0x76, 0x65, 0x72,
-	0x73, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x72, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x2b, 0x0a,
-	0x07, 0x61, 0x6e, 0x79, 0x74, 0x79, 0x70, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48,
-	0x00, 0x52, 0x07, 0x61, 0x6e, 0x79, 0x74, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x72, 0x65,
-	0x63, 0x6f, 0x72, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74,
-	0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x06, 0x72,
-	0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x23, 0x0a, 0x03, 0x73, 0x65, 0x74, 0x18, 0x13, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54,
-	0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x03, 0x73, 0x65, 0x74, 0x12, 0x27, 0x0a, 0x05, 0x75, 0x6e,
-	0x69, 0x6f, 0x6e, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e,
-	0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x05, 0x75, 0x6e,
-	0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x18, 0x15, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x63, 0x61, 0x6c, 0x61, 0x72,
-	0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x12, 0x32,
-	0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x16, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x12, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e,
-	0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65,
-	0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x0a, 0x65, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64,
-	0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x45, 0x6e, 0x75,
-	0x6d, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x0a, 0x65, 0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61,
-	0x74, 0x65, 0x64, 0x12, 0x23, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x18, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x54, 0x79, 0x70, 0x65,
-	0x48, 0x00, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65,
-	0x72, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x69,
-	0x6d, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72,
-	0x12, 0x25, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x1a, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x07,
-	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22,
-	0x3a, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74,
-	0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x30, 0x0a, 0x0a, 0x53,
-	0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x06, 0x66, 0x69, 0x65,
-	0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x6e, 0x74, 0x74, 0x2e,
-	0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0x60, 0x0a,
-	0x08, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a, 0x07, 0x65, 0x6c, 0x65,
-	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74,
-	0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x2f,
-	0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2f,
+	0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61,
-	0x69, 0x6e, 0x74, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x22,
-	0x31, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a,
-	0x0d, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x57, 0x69, 0x64,
-	0x74, 0x68, 0x22, 0x3e, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74,
-	0x1a, 0x30, 0x0a, 0x06, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
-	0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x65,
-	0x6e, 0x64, 0x22, 0x0f, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x54,
-	0x79, 0x70, 0x65, 0x22, 0x0a, 0x0a, 0x08, 0x45, 0x6e, 0x75, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x22,
-	0x0a, 0x0a, 0x08, 0x50, 0x6f, 0x72, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x0b, 0x0a, 0x09, 0x54,
-	0x69, 0x6d, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x2a, 0x48, 0x0a, 0x0a, 0x53, 0x63, 0x61, 0x6c,
-	0x61, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x55, 0x4c, 0x4c, 0x10, 0x00,
-	0x12, 0x0b, 0x0a, 0x07, 0x42, 0x4f, 0x4f, 0x4c, 0x45, 0x41, 0x4e, 0x10, 0x01, 0x12, 0x09, 0x0a,
-	0x05, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x54, 0x45,
-	0x47, 0x45, 0x52, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x56, 0x45, 0x52, 0x44, 0x49, 0x43, 0x54,
-	0x10, 0x04, 0x42, 0x1f, 0x5a, 0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x6e, 0x6f, 0x6b, 0x69, 0x61, 0x2f, 0x6e, 0x74, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x69, 0x6e, 0x74, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12,
+	0x29, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x05, 0x61, 0x72, 0x72, 0x61, 0x79, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x48, 0x00, 0x52, 0x05, 0x61, 0x72, 0x72, 0x61, 0x79, 0x12, 0x2c, 0x0a, 0x09, 0x72,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x6f, 0x66, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52,
+	0x08, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4f, 0x66, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x65, 0x74,
+	0x5f, 0x6f, 0x66, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x05, 0x73, 0x65, 0x74, 0x4f,
+	0x66, 0x12, 0x2f, 0x0a, 0x09, 0x62, 0x69, 0x74, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x09, 0x62, 0x69, 0x74, 0x73, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x12, 0x31, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x72, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x72, 0x73,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x31, 0x0a, 0x0a, 0x68, 0x65, 0x78, 0x74, 0x73, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e,
+	0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x0a, 0x68, 0x65,
+	0x78, 0x74, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x33, 0x0a, 0x0b, 0x6f, 0x63, 0x74, 0x65,
+	0x74, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00,
+	0x52, 0x0b, 0x6f, 0x63, 0x74, 0x65, 0x74, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x44, 0x0a,
+	0x14, 0x75, 0x6e, 0x69, 0x76, 0x65, 0x72, 0x73, 0x61, 0x6c, 0x5f, 0x63, 0x68, 0x61, 0x72, 0x73,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74,
+	0x74, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x13,
+	0x75, 0x6e, 0x69, 0x76, 0x65, 0x72, 0x73, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x72, 0x73, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x12, 0x2b, 0x0a, 0x07, 0x61, 0x6e, 0x79, 0x74, 0x79, 0x70, 0x65, 0x18, 0x11,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x07, 0x61, 0x6e, 0x79, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x29, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x23, 0x0a, 0x03, 0x73,
+	0x65, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x03, 0x73, 0x65, 0x74,
+	0x12, 0x27, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x6f, 0x6e, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x48, 0x00, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x63, 0x61,
+	0x6c, 0x61, 0x72, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e,
+	0x53, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x06, 0x73, 0x63,
+	0x61, 0x6c, 0x61, 0x72, 0x12, 0x32, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e,
+	0x74, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x43, 0x6f,
+	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x09, 0x63,
+	0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x0a, 0x65, 0x6e, 0x75, 0x6d,
+	0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x18, 0x17, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e,
+	0x74, 0x74, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x0a, 0x65,
+	0x6e, 0x75, 0x6d, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x12, 0x23, 0x0a, 0x04, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x50, 0x6f,
+	0x72, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x26,
+	0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x6e, 0x74, 0x74, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52,
+	0x05, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79,
+	0x70, 0x65, 0x48, 0x00, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1e, 0x0a,
+	0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x06, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x56, 0x0a, 0x05, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x22, 0x30, 0x0a,
+	0x0a, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x06, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x6e, 0x74,
+	0x74, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22,
+	0x60, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x23, 0x0a, 0x07, 0x65,
+	0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e,
+	0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x2f, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74,
+	0x72, 0x61, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e,
+	0x74, 0x22, 0x31, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x23, 0x0a, 0x0d, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x65, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x57,
+	0x69, 0x64, 0x74, 0x68, 0x22, 0xba, 0x01, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61,
+	0x69, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72,
+	0x61, 0x69, 0x6e, 0x74, 0x2e, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x52, 0x06, 0x6c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x12, 0x2e, 0x0a, 0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72,
+	0x61, 0x69, 0x6e, 0x74, 0x2e, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x52, 0x06, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x1a, 0x4c, 0x0a, 0x06, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x66, 0x69, 0x6e, 0x69, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x6e, 0x66, 0x69, 0x6e, 0x69, 0x74,
+	0x65, 0x22, 0x62, 0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x23, 0x0a, 0x07, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07,
+	0x65, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x73, 0x12, 0x2c, 0x0a, 0x0b, 0x64, 0x65, 0x66, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x6e,
+	0x74, 0x74, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x68, 0x0a, 0x08, 0x45, 0x6e, 0x75, 0x6d, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x2a, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x54, 0x79, 0x70, 0x65,
+	0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x1a, 0x30, 0x0a,
+	0x04, 0x45, 0x6e, 0x75, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22,
+	0xba, 0x01, 0x0a, 0x08, 0x50, 0x6f, 0x72, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6e, 0x74, 0x74,
+	0x2e, 0x50, 0x6f, 0x72, 0x74, 0x54, 0x79, 0x70, 0x65, 0x2e, 0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x12, 0x19, 0x0a, 0x02, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x09, 0x2e, 0x6e, 0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x02, 0x69, 0x6e, 0x12,
+	0x1b, 0x0a, 0x03, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e,
+	0x74, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x03, 0x6f, 0x75, 0x74, 0x12, 0x1f, 0x0a, 0x05,
+	0x69, 0x6e, 0x6f, 0x75, 0x74, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6e, 0x74,
+	0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x69, 0x6e, 0x6f, 0x75, 0x74, 0x22, 0x2d, 0x0a,
+	0x04, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x0b, 0x0a, 0x07, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45,
+	0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x50, 0x52, 0x4f, 0x43, 0x45, 0x44, 0x55, 0x52, 0x45, 0x10,
+	0x01, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x49, 0x58, 0x45, 0x44, 0x10, 0x02, 0x22, 0x0b, 0x0a, 0x09,
+	0x54, 0x69, 0x6d, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x2a, 0x55, 0x0a, 0x0a, 0x53, 0x63, 0x61,
+	0x6c, 0x61, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x55, 0x4c, 0x4c, 0x10,
+	0x00, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x4f, 0x4f, 0x4c, 0x45, 0x41, 0x4e, 0x10, 0x01, 0x12, 0x09,
+	0x0a, 0x05, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x54,
+	0x45, 0x47, 0x45, 0x52, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x56, 0x45, 0x52, 0x44, 0x49, 0x43,
+	0x54, 0x10, 0x04, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x05,
+	0x42, 0x1f, 0x5a, 0x1d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e,
+	0x6f, 0x6b, 0x69, 0x61, 0x2f, 0x6e, 0x74, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -973,50 +1259,64 @@ func file_type_proto_rawDescGZIP() []byte {
 	return file_type_proto_rawDescData
 }
 
-var file_type_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_type_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_type_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_type_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_type_proto_goTypes = []interface{}{
 	(ScalarType)(0),           // 0: ntt.ScalarType
-	(*Type)(nil),              // 1: ntt.Type
-	(*Field)(nil),             // 2: ntt.Field
-	(*StructType)(nil),        // 3: ntt.StructType
-	(*ListType)(nil),          // 4: ntt.ListType
-	(*StringType)(nil),        // 5: ntt.StringType
-	(*Constraint)(nil),        // 6: ntt.Constraint
-	(*ComponentType)(nil),     // 7: ntt.ComponentType
-	(*EnumType)(nil),          // 8: ntt.EnumType
-	(*PortType)(nil),          // 9: ntt.PortType
-	(*TimerType)(nil),         // 10: ntt.TimerType
-	(*Constraint_Bounds)(nil), // 11: ntt.Constraint.Bounds
+	(PortType_Kind)(0),        // 1: ntt.PortType.Kind
+	(*Type)(nil),              // 2: ntt.Type
+	(*Field)(nil),             // 3: ntt.Field
+	(*StructType)(nil),        // 4: ntt.StructType
+	(*ListType)(nil),          // 5: ntt.ListType
+	(*StringType)(nil),        // 6: ntt.StringType
+	(*Constraint)(nil),        // 7: ntt.Constraint
+	(*ComponentType)(nil),     // 8: ntt.ComponentType
+	(*EnumType)(nil),          // 9: ntt.EnumType
+	(*PortType)(nil),          // 10: ntt.PortType
+	(*TimerType)(nil),         // 11: ntt.TimerType
+	(*Constraint_Bounds)(nil), // 12: ntt.Constraint.Bounds
+	(*EnumType_Enum)(nil),     // 13: ntt.EnumType.Enum
+	(*Location)(nil),          // 14: ntt.Location
 }
 var file_type_proto_depIdxs = []int32{
-	4,  // 0: ntt.Type.array:type_name -> ntt.ListType
-	4,  // 1: ntt.Type.record_of:type_name -> ntt.ListType
-	4,  // 2: ntt.Type.set_of:type_name -> ntt.ListType
-	5,  // 3: ntt.Type.bitstring:type_name -> ntt.StringType
-	5,  // 4: ntt.Type.charstring:type_name -> ntt.StringType
-	5,  // 5: ntt.Type.hextstring:type_name -> ntt.StringType
-	5,  // 6: ntt.Type.octetstring:type_name -> ntt.StringType
-	5,  // 7: ntt.Type.universal_charstring:type_name -> ntt.StringType
-	3,  // 8: ntt.Type.anytype:type_name -> ntt.StructType
-	3,  // 9: ntt.Type.record:type_name -> ntt.StructType
-	3,  // 10: ntt.Type.set:type_name -> ntt.StructType
-	3,  // 11: ntt.Type.union:type_name -> ntt.StructType
-	0,  // 12: ntt.Type.scalar:type_name -> ntt.ScalarType
-	7,  // 13: ntt.Type.component:type_name -> ntt.ComponentType
-	8,  // 14: ntt.Type.enumerated:type_name -> ntt.EnumType
-	9,  // 15: ntt.Type.port:type_name -> ntt.PortType
-	10, // 16: ntt.Type.timer:type_name -> ntt.TimerType
-	1,  // 17: ntt.Type.address:type_name -> ntt.Type
-	1,  // 18: ntt.Field.type:type_name -> ntt.Type
-	2,  // 19: ntt.StructType.fields:type_name -> ntt.Field
-	1,  // 20: ntt.ListType.element:type_name -> ntt.Type
-	6,  // 21: ntt.ListType.constraint:type_name -> ntt.Constraint
-	22, // [22:22] is the sub-list for method output_type
-	22, // [22:22] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	7,  // 0: ntt.Type.constraint:type_name -> ntt.Constraint
+	14, // 1: ntt.Type.location:type_name -> ntt.Location
+	5,  // 2: ntt.Type.array:type_name -> ntt.ListType
+	5,  // 3: ntt.Type.record_of:type_name -> ntt.ListType
+	5,  // 4: ntt.Type.set_of:type_name -> ntt.ListType
+	6,  // 5: ntt.Type.bitstring:type_name -> ntt.StringType
+	6,  // 6: ntt.Type.charstring:type_name -> ntt.StringType
+	6,  // 7: ntt.Type.hextstring:type_name -> ntt.StringType
+	6,  // 8: ntt.Type.octetstring:type_name -> ntt.StringType
+	6,  // 9: ntt.Type.universal_charstring:type_name -> ntt.StringType
+	4,  // 10: ntt.Type.anytype:type_name -> ntt.StructType
+	4,  // 11: ntt.Type.record:type_name -> ntt.StructType
+	4,  // 12: ntt.Type.set:type_name -> ntt.StructType
+	4,  // 13: ntt.Type.union:type_name -> ntt.StructType
+	0,  // 14: ntt.Type.scalar:type_name -> ntt.ScalarType
+	8,  // 15: ntt.Type.component:type_name -> ntt.ComponentType
+	9,  // 16: ntt.Type.enumerated:type_name -> ntt.EnumType
+	10, // 17: ntt.Type.port:type_name -> ntt.PortType
+	11, // 18: ntt.Type.timer:type_name -> ntt.TimerType
+	2,  // 19: ntt.Type.address:type_name -> ntt.Type
+	2,  // 20: ntt.Field.type:type_name -> ntt.Type
+	3,  // 21: ntt.StructType.fields:type_name -> ntt.Field
+	2,  // 22: ntt.ListType.element:type_name -> ntt.Type
+	7,  // 23: ntt.ListType.constraint:type_name -> ntt.Constraint
+	12, // 24: ntt.Constraint.length:type_name -> ntt.Constraint.Bounds
+	12, // 25: ntt.Constraint.ranges:type_name -> ntt.Constraint.Bounds
+	2,  // 26: ntt.ComponentType.extends:type_name -> ntt.Type
+	3,  // 27: ntt.ComponentType.definitions:type_name -> ntt.Field
+	13, // 28: ntt.EnumType.values:type_name -> ntt.EnumType.Enum
+	1,  // 29: ntt.PortType.kind:type_name -> ntt.PortType.Kind
+	2,  // 30: ntt.PortType.in:type_name -> ntt.Type
+	2,  // 31: ntt.PortType.out:type_name -> ntt.Type
+	2,  // 32: ntt.PortType.inout:type_name -> ntt.Type
+	33, // [33:33] is the sub-list for method output_type
+	33, // [33:33] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_type_proto_init() }
@@ -1024,6 +1324,7 @@ func file_type_proto_init() {
 	if File_type_proto != nil {
 		return
 	}
+	file_location_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_type_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Type); i {
@@ -1157,6 +1458,18 @@ func file_type_proto_init() {
 				return nil
 			}
 		}
+		file_type_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EnumType_Enum); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_type_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*Type_Array)(nil),
@@ -1177,14 +1490,15 @@ func file_type_proto_init() {
 		(*Type_Port)(nil),
 		(*Type_Timer)(nil),
 		(*Type_Address)(nil),
+		(*Type_Reference)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_type_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
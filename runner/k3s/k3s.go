@@ -1,19 +1,27 @@
 package k3s
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/nokia/ntt/internal/env"
 	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/loc"
 	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/results"
+	"github.com/nokia/ntt/interpreter"
 	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/runner"
+	"github.com/nokia/ntt/runtime"
+	"github.com/nokia/ntt/ttcn3/parser"
 )
 
 type Runner struct {
@@ -40,6 +48,138 @@ func (r *Runner) Run(w io.Writer, testID string) error {
 	return multierror.Append(err, r.report(w, testID)).ErrorOrNil()
 }
 
+// Execute runs test t and returns its verdict. Module parameter overrides are
+// appended to a copy of the parameters file of the test suite, which is passed
+// to the test using NTT_PARAMETERS_FILE. The output of the test is written to
+// w while the test is running.
+//
+// The values of out and inout parameters are read back from the parameters
+// file after the run. The last assignment of a parameter wins, so tests and
+// adapters report values by appending them to the file. Parameters without
+// assignment are not reported.
+func (r *Runner) Execute(w io.Writer, t runner.Test) (runner.Result, error) {
+	r.clean(t.Name)
+
+	cmd := nttCommand(r.p, "run", "-j1", "--results-file=test_results.json", "--no-summary")
+	cmd.Dir = r.Dir
+	cmd.Env = append(cmd.Env, "SCT_K3_SERVER=ON")
+	cmd.Stdin = strings.NewReader(t.Name + "\n")
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	var file string
+	if len(t.ModulePars) > 0 || len(t.Outputs) > 0 {
+		var err error
+		if file, err = r.writeParameters(t); err != nil {
+			return runner.Result{}, err
+		}
+		cmd.Env = append(cmd.Env, "NTT_PARAMETERS_FILE="+file)
+	}
+
+	err := cmd.Run()
+	verdict, rerr := r.verdict(t.Name)
+	res := runner.Result{Verdict: verdict}
+	if len(t.Outputs) > 0 {
+		var oerr error
+		res.Outputs, oerr = readOutputs(file, t.Outputs)
+		rerr = multierror.Append(rerr, oerr).ErrorOrNil()
+	}
+	return res, multierror.Append(err, rerr).ErrorOrNil()
+}
+
+// readOutputs returns the values of the parameters names assigned in the
+// parameters file. Values are expected on a single line in TTCN-3 value
+// notation.
+func readOutputs(file string, names []string) (map[string]runtime.Object, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, name := range names {
+		values[name] = ""
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		i := strings.Index(line, ":=")
+		if i < 0 {
+			continue
+		}
+		name := strings.TrimSpace(line[:i])
+		if _, ok := values[name]; ok {
+			values[name] = strings.TrimSuffix(strings.TrimSpace(line[i+2:]), ";")
+		}
+	}
+
+	var (
+		outputs = make(map[string]runtime.Object)
+		errs    error
+	)
+	for _, name := range names {
+		if values[name] == "" {
+			continue
+		}
+		x, err := parser.ParseExpr(loc.NewFileSet(), file, values[name])
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		obj := interpreter.Eval(x, runtime.NewEnv(nil))
+		if err, ok := obj.(*runtime.Error); ok {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		outputs[name] = obj
+	}
+	return outputs, errs
+}
+
+// writeParameters writes the parameters file for test t into the working
+// directory and returns its path.
+func (r *Runner) writeParameters(t runner.Test) (string, error) {
+	var buf bytes.Buffer
+	if p, ok := r.p.(interface{ ParametersFile() (*fs.File, error) }); ok {
+		if f, err := p.ParametersFile(); err == nil && f != nil {
+			if b, err := f.Bytes(); err == nil {
+				buf.Write(b)
+				buf.WriteString("\n")
+			}
+		}
+	}
+
+	names := make([]string, 0, len(t.ModulePars))
+	for name := range t.ModulePars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s := %s\n", name, t.ModulePars[name])
+	}
+
+	path := filepath.Join(r.Dir, t.Name+".parameters")
+	return path, ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// verdict returns the verdict of the latest run of testID.
+func (r *Runner) verdict(testID string) (string, error) {
+	f, err := os.Open(filepath.Join(r.Dir, "test_results.json"))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	runs, err := results.ImportJSON(f)
+	if err != nil {
+		return "", err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].Name == testID {
+			return runs[i].Verdict, nil
+		}
+	}
+	return "", fmt.Errorf("no results for test %s", testID)
+}
+
 func (r *Runner) report(w io.Writer, testID string) error {
 
 	// Display a nice summary
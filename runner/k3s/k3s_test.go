@@ -0,0 +1,42 @@
+package k3s
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOutputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "k3s")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "M.tc.parameters")
+	require.Nil(t, ioutil.WriteFile(file, []byte(`M.p := 23
+M.q := "x"
+M.p := 42;
+M.r := { a := 1 }
+M.bad := 1 +
+`), 0644))
+
+	outputs, err := readOutputs(file, []string{"M.p", "M.r", "M.missing"})
+	require.Nil(t, err)
+	assert.Equal(t, map[string]runtime.Object{
+		"M.p": runtime.NewInt("42"),
+		"M.r": outputs["M.r"],
+	}, outputs)
+	if r, ok := outputs["M.r"].(*runtime.Record); assert.True(t, ok) {
+		a, _ := r.Get("a")
+		assert.Equal(t, runtime.NewInt("1"), a)
+	}
+
+	_, err = readOutputs(file, []string{"M.bad"})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "M.bad")
+	}
+}
@@ -1,7 +1,10 @@
 package runner
 
 import (
+	"errors"
 	"io"
+
+	"github.com/nokia/ntt/runtime"
 )
 
 type Runner interface {
@@ -9,3 +12,37 @@ type Runner interface {
 	LogDir(testID string) string
 	Dir() string
 }
+
+// Test describes a single test run.
+type Test struct {
+	// Name of the test, like "M.tc".
+	Name string
+
+	// ModulePars overrides module parameters. Keys are qualified names, like
+	// "M.p", values use TTCN-3 value notation.
+	ModulePars map[string]string
+
+	// Outputs lists the qualified names of out and inout parameters whose
+	// values after the run are requested.
+	Outputs []string
+}
+
+// Result is the outcome of a test run.
+type Result struct {
+	// Verdict is the final verdict of the test, like "pass" or "fail".
+	Verdict string
+
+	// Outputs holds the values of out parameters reported by the test
+	// runner, keyed by qualified name. Requested parameters without value
+	// are reported without value.
+	Outputs map[string]runtime.Object
+}
+
+// ErrUnsupported is returned by executors for tests they cannot run, like
+// tests requesting out parameters from runners without support for them.
+var ErrUnsupported = errors.New("unsupported")
+
+// An Executor runs tests with module parameters and reports their results.
+type Executor interface {
+	Execute(w io.Writer, t Test) (Result, error)
+}
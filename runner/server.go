@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nokia/ntt/codec"
+	pb "github.com/nokia/ntt/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the gRPC Runtime service. Tests are executed one at a
+// time.
+type Server struct {
+	pb.UnimplementedRuntimeServer
+
+	Executor Executor
+
+	// Types resolves the types of module parameters.
+	Types *codec.Registry
+
+	mu         sync.Mutex
+	modulePars map[string]*pb.Type
+}
+
+// NewServer returns a Runtime server executing tests with e. The module
+// parameters of mods may be overridden by run requests.
+func NewServer(e Executor, mods ...*pb.Module) *Server {
+	s := &Server{
+		Executor:   e,
+		Types:      codec.NewRegistry(),
+		modulePars: make(map[string]*pb.Type),
+	}
+	s.Types.AddModules(mods...)
+	for _, m := range mods {
+		for _, d := range m.ModuleParameters {
+			s.modulePars[m.Name+"."+d.Name] = d.Type
+		}
+	}
+	return s
+}
+
+// Register registers the Runtime service with g.
+func (s *Server) Register(g *grpc.Server) {
+	pb.RegisterRuntimeServer(g, s)
+}
+
+// Run executes the requested test. The output of the test is streamed while
+// the test is running. The final response carries the verdict and the out
+// and inout parameters.
+func (s *Server) Run(req *pb.RunRequest, stream pb.Runtime_RunServer) error {
+	if req.TestName == "" {
+		return status.Error(codes.InvalidArgument, "missing test name")
+	}
+
+	t := Test{Name: req.TestName, ModulePars: make(map[string]string)}
+	for _, p := range req.Parameters {
+		if p.Direction != pb.Parameter_IN {
+			t.Outputs = append(t.Outputs, p.Name)
+		}
+		if p.Direction == pb.Parameter_OUT {
+			continue
+		}
+		typ, ok := s.modulePars[p.Name]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "unknown module parameter %q", p.Name)
+		}
+		v, err := codec.Format(s.Types, typ, p.Value)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "%s: %s", p.Name, err.Error())
+		}
+		t.ModulePars[p.Name] = v
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := &streamWriter{name: req.TestName, stream: stream}
+	res, err := s.Executor.Execute(w, t)
+	if w.err != nil {
+		return w.err
+	}
+	if errors.Is(err, ErrUnsupported) {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		// Failing tests are reported by their verdict.
+		if res.Verdict == "" {
+			return status.Error(codes.Internal, err.Error())
+		}
+		fmt.Fprintln(w, err.Error())
+	}
+
+	resp := &pb.RunResponse{
+		TestName: req.TestName,
+		Verdict:  verdict(res.Verdict),
+	}
+	for _, p := range req.Parameters {
+		if p.Direction == pb.Parameter_IN {
+			continue
+		}
+		// Values not reported by the executor are left empty. The
+		// input value would be stale.
+		out := &pb.Parameter{Name: p.Name, Direction: p.Direction}
+		if obj, ok := res.Outputs[p.Name]; ok {
+			typ, ok := s.modulePars[p.Name]
+			if !ok {
+				return status.Errorf(codes.InvalidArgument, "unknown module parameter %q", p.Name)
+			}
+			if out.Value, err = codec.FromObject(s.Types, typ, obj); err != nil {
+				return status.Errorf(codes.Internal, "%s: %s", p.Name, err.Error())
+			}
+		}
+		resp.Parameters = append(resp.Parameters, out)
+	}
+	return stream.Send(resp)
+}
+
+// verdict returns the protobuf verdict of s. Unknown verdicts are errors.
+func verdict(s string) pb.Verdict {
+	if v, ok := pb.Verdict_value[strings.ToUpper(strings.TrimSpace(s))]; ok {
+		return pb.Verdict(v)
+	}
+	return pb.Verdict_ERROR
+}
+
+// streamWriter sends everything written as output of a run response.
+type streamWriter struct {
+	name   string
+	stream pb.Runtime_RunServer
+	err    error
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.err = w.stream.Send(&pb.RunResponse{TestName: w.name, Output: string(p)}); w.err != nil {
+		return 0, w.err
+	}
+	return len(p), nil
+}
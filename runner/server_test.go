@@ -0,0 +1,157 @@
+package runner_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/codec"
+	"github.com/nokia/ntt/internal/generator"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/runner"
+	"github.com/nokia/ntt/runtime"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeExecutor struct {
+	tests  []runner.Test
+	result runner.Result
+	err    error
+}
+
+func (e *fakeExecutor) Execute(w io.Writer, t runner.Test) (runner.Result, error) {
+	e.tests = append(e.tests, t)
+	fmt.Fprintf(w, "running %s\n", t.Name)
+	return e.result, e.err
+}
+
+func startServer(t *testing.T, e runner.Executor) (pb.RuntimeClient, func()) {
+	t.Helper()
+	req, err := generator.NewRequest(ttcn3.Parse(`
+		module M {
+			type record R { integer a, charstring b optional }
+			modulepar integer p;
+			modulepar R r;
+		}`))
+	require.Nil(t, err)
+
+	dir, err := ioutil.TempDir("", "runner")
+	require.Nil(t, err)
+	socket := filepath.Join(dir, "ntt.sock")
+	l, err := codec.Listen(socket)
+	require.Nil(t, err)
+
+	s := runner.NewServer(e, req.Modules...)
+	g := codec.NewServer(s.Types)
+	s.Register(g)
+	go g.Serve(l)
+
+	_, conn, err := codec.Dial(context.Background(), socket)
+	require.Nil(t, err)
+	return pb.NewRuntimeClient(conn), func() {
+		conn.Close()
+		g.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func intValue(i int32) *pb.Value {
+	return &pb.Value{Kind: &pb.Value_IntValue{IntValue: i}}
+}
+
+func run(t *testing.T, c pb.RuntimeClient, req *pb.RunRequest) ([]*pb.RunResponse, error) {
+	t.Helper()
+	stream, err := c.Run(context.Background(), req)
+	require.Nil(t, err)
+	var resps []*pb.RunResponse
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return resps, nil
+		}
+		if err != nil {
+			return resps, err
+		}
+		resps = append(resps, resp)
+	}
+}
+
+func TestServerRun(t *testing.T) {
+	e := &fakeExecutor{result: runner.Result{
+		Verdict: "pass",
+		Outputs: map[string]runtime.Object{"M.p": runtime.NewInt("42")},
+	}}
+	c, stop := startServer(t, e)
+	defer stop()
+
+	r := &pb.Value{Kind: &pb.Value_CompositeValue{CompositeValue: &pb.Composite{
+		Values: []*pb.Value{intValue(1), {}},
+	}}}
+	resps, err := run(t, c, &pb.RunRequest{
+		TestName: "M.tc",
+		Parameters: []*pb.Parameter{
+			{Name: "M.p", Direction: pb.Parameter_INOUT, Value: intValue(23)},
+			{Name: "M.r", Value: r},
+		},
+	})
+	require.Nil(t, err)
+
+	require.Len(t, e.tests, 1)
+	assert.Equal(t, map[string]string{"M.p": "23", "M.r": "{ a := 1, b := omit }"}, e.tests[0].ModulePars)
+	assert.Equal(t, []string{"M.p"}, e.tests[0].Outputs)
+
+	require.Len(t, resps, 2)
+	assert.Equal(t, "running M.tc\n", resps[0].Output)
+	last := resps[1]
+	assert.Equal(t, "M.tc", last.TestName)
+	assert.Equal(t, pb.Verdict_PASS, last.Verdict)
+	require.Len(t, last.Parameters, 1)
+	assert.Equal(t, "M.p", last.Parameters[0].Name)
+	assert.Equal(t, int32(42), last.Parameters[0].Value.GetIntValue())
+
+	// Input values of parameters not reported by the executor are not
+	// echoed.
+	e.result.Outputs = nil
+	resps, err = run(t, c, &pb.RunRequest{
+		TestName:   "M.tc",
+		Parameters: []*pb.Parameter{{Name: "M.p", Direction: pb.Parameter_INOUT, Value: intValue(23)}},
+	})
+	require.Nil(t, err)
+	last = resps[len(resps)-1]
+	require.Len(t, last.Parameters, 1)
+	assert.Nil(t, last.Parameters[0].Value)
+}
+
+func TestServerErrors(t *testing.T) {
+	e := &fakeExecutor{err: errors.New("build failed")}
+	c, stop := startServer(t, e)
+	defer stop()
+
+	_, err := run(t, c, &pb.RunRequest{TestName: "M.tc", Parameters: []*pb.Parameter{{Name: "M.x"}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = run(t, c, &pb.RunRequest{TestName: "M.tc", Parameters: []*pb.Parameter{{Name: "M.p", Value: &pb.Value{Kind: &pb.Value_StringValue{}}}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = run(t, c, &pb.RunRequest{TestName: "M.tc"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Contains(t, err.Error(), "build failed")
+
+	e.result.Verdict = "fail"
+	resps, err := run(t, c, &pb.RunRequest{TestName: "M.tc"})
+	require.Nil(t, err)
+	assert.Equal(t, pb.Verdict_FAIL, resps[len(resps)-1].Verdict)
+
+	e.err = fmt.Errorf("%w: out parameters", runner.ErrUnsupported)
+	_, err = run(t, c, &pb.RunRequest{TestName: "M.tc", Parameters: []*pb.Parameter{{Name: "M.p", Direction: pb.Parameter_OUT}}})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -204,6 +204,10 @@ func (s *String) hashKey() hashKey {
 type Bitstring struct {
 	Value *big.Int
 	Unit  Unit
+
+	// Length is the number of digits, including leading zeros. Octetstrings
+	// have two digits per octet.
+	Length int
 }
 
 func (b *Bitstring) Type() ObjectType { return BITSTRING }
@@ -257,7 +261,7 @@ func NewBitstring(s string) (*Bitstring, error) {
 	s = strings.Map(removeWhitespaces, s[1:len(s)-2])
 
 	if i, ok := new(big.Int).SetString(s, unit.Base()); ok {
-		return &Bitstring{Value: i, Unit: unit}, nil
+		return &Bitstring{Value: i, Unit: unit, Length: len(s)}, nil
 	}
 
 	// TODO(5nord) parse and return Bitstring templates (e.g. '01*1'B)
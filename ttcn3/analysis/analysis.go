@@ -0,0 +1,215 @@
+// Package analysis defines the interface between TTCN-3 analyzers, like the
+// checks of ntt lint, and the programs running them, like the lint command
+// and the language server.
+//
+// An Analyzer inspects the syntax trees of a test suite and reports
+// diagnostics. Analyzers are configured by a section of the lint
+// configuration file and may request facts, like type information or the
+// module import graph, which are computed once per run and shared by all
+// analyzers.
+//
+// Rule packs provide analyzers by calling Register from an init function.
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/types"
+)
+
+// An Analyzer describes a check and its options.
+type Analyzer struct {
+	// Name identifies the analyzer. It is also the key of its section in
+	// the configuration file and may be used in NOLINT directives.
+	Name string
+
+	// Doc documents the analyzer. The first line is a short summary.
+	Doc string
+
+	// Config returns a pointer to a new, zero configuration value, which
+	// is decoded from the analyzer's configuration section. Its type is
+	// the schema of the section: unknown keys and mistyped values are
+	// rejected. Config is nil if the analyzer has no options.
+	Config func() interface{}
+
+	// Requires lists the facts the analyzer depends on.
+	Requires Facts
+
+	// Suite reports whether the analyzer needs all files of a test suite,
+	// like checks for unused definitions. Suite analyzers are not run on
+	// single files, as the language server does.
+	Suite bool
+
+	// NoLint lists alternative names which silence the analyzer's
+	// diagnostics in NOLINT directives.
+	NoLint []string
+
+	// Run applies the analyzer to a pass.
+	Run func(*Pass) error
+}
+
+func (a *Analyzer) String() string {
+	return a.Name
+}
+
+// Facts are information about a test suite computed before analyzers run.
+type Facts uint
+
+const (
+	// Types provides type information in Pass.TypesInfo and
+	// Pass.TypesScope.
+	Types Facts = 1 << iota
+
+	// Scopes provides an index of all definitions in Pass.DB to resolve
+	// identifiers with ttcn3.Tree.LookupWithDB.
+	Scopes
+
+	// Imports provides the module import graph in Pass.Imports.
+	Imports
+)
+
+// A Pass provides information to the Run function of an analyzer.
+type Pass struct {
+	Analyzer *Analyzer
+
+	// Files are the syntax trees to analyze. Files with syntax errors or
+	// ignored by the configuration are not included.
+	Files []*ttcn3.Tree
+
+	// Project is the test suite the files belong to. It may be nil.
+	Project project.Interface
+
+	// Config is the configuration value returned by Analyzer.Config,
+	// decoded from the configuration section.
+	Config interface{}
+
+	// Ignore lists modules and files excluded from analysis.
+	Ignore *Ignore
+
+	// Facts, as requested by Analyzer.Requires.
+	TypesInfo  *types.Info
+	TypesScope types.Scope
+	DB         *ttcn3.DB
+	Imports    ImportGraph
+
+	// Report reports a diagnostic.
+	Report func(Diagnostic)
+}
+
+// A Module is a module of a syntax tree.
+type Module struct {
+	Tree *ttcn3.Tree
+	Node *ast.Module
+}
+
+// Modules returns the modules of all files, except ignored modules.
+func (p *Pass) Modules() []Module {
+	var mods []Module
+	for _, tree := range p.Files {
+		for _, def := range tree.Modules() {
+			mod := def.Node.(*ast.Module)
+			if !p.Ignore.Module(ast.Name(mod.Name)) {
+				mods = append(mods, Module{Tree: tree, Node: mod})
+			}
+		}
+	}
+	return mods
+}
+
+// Reportf reports a diagnostic about node n of tree.
+func (p *Pass) Reportf(tree *ttcn3.Tree, n ast.Node, format string, args ...interface{}) {
+	p.Report(Diagnostic{
+		Pos:     tree.Position(n.Pos()),
+		End:     tree.Position(n.End()),
+		Message: fmt.Sprintf(format, args...),
+		Node:    n,
+	})
+}
+
+// A Diagnostic is a message associated with a source location.
+type Diagnostic struct {
+	Pos      loc.Position
+	End      loc.Position
+	Analyzer string
+	Severity Severity
+	Message  string
+
+	// Symbol is the qualified name of the definition enclosing Node, like
+	// "M.f". It is set by the Runner and identifies findings in baselines.
+	Symbol string
+
+	// SuggestedFixes are alternative changes resolving the diagnostic.
+	SuggestedFixes []SuggestedFix
+
+	// Node is the syntax node the diagnostic is about. The comments of
+	// its first token are searched for NOLINT directives.
+	Node ast.Node
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Pos, d.Severity, d.Message)
+}
+
+// Severity is the severity of a diagnostic.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+
+	// Off disables an analyzer. It is used in configurations only.
+	Off Severity = "off"
+)
+
+// A SuggestedFix is a change resolving a diagnostic.
+type SuggestedFix struct {
+	Message   string
+	TextEdits []TextEdit
+}
+
+// A TextEdit replaces the text between Pos and End with NewText.
+type TextEdit struct {
+	Pos     loc.Position
+	End     loc.Position
+	NewText string
+}
+
+// ImportGraph maps module names to the names of the modules they import.
+type ImportGraph map[string][]string
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]*Analyzer)
+)
+
+// Register makes analyzers available to the lint command and the language
+// server. It panics if an analyzer with the same name is already registered.
+func Register(analyzers ...*Analyzer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, a := range analyzers {
+		if _, dup := registry[a.Name]; dup {
+			panic(fmt.Sprintf("analysis: analyzer %q registered twice", a.Name))
+		}
+		registry[a.Name] = a
+	}
+}
+
+// All returns all registered analyzers sorted by name.
+func All() []*Analyzer {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	list := make([]*Analyzer, 0, len(registry))
+	for _, a := range registry {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
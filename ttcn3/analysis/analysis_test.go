@@ -0,0 +1,335 @@
+package analysis_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// funcs reports every function.
+var funcs = &analysis.Analyzer{
+	Name:   "funcs",
+	NoLint: []string{"Alias"},
+	Run: func(pass *analysis.Pass) error {
+		for _, mod := range pass.Modules() {
+			ast.Inspect(mod.Node, func(n ast.Node) bool {
+				if n, ok := n.(*ast.FuncDecl); ok {
+					pass.Reportf(mod.Tree, n, "function %s", ast.Name(n))
+				}
+				return true
+			})
+		}
+		return nil
+	},
+}
+
+// count reports the number of files, but only if configured.
+var count = &analysis.Analyzer{
+	Name:   "count",
+	Config: func() interface{} { return new(bool) },
+	Suite:  true,
+	Run: func(pass *analysis.Pass) error {
+		if *pass.Config.(*bool) {
+			pass.Report(analysis.Diagnostic{Message: fmt.Sprintf("%d files", len(pass.Files))})
+		}
+		return nil
+	},
+}
+
+func run(t *testing.T, r analysis.Runner, config string, srcs map[string]string) []string {
+	t.Helper()
+	cfg, err := analysis.ParseConfig([]byte(config), r.Analyzers...)
+	require.Nil(t, err)
+	r.Config = cfg
+
+	var files []string
+	for name, src := range srcs {
+		fs.Open(name).SetBytes([]byte(src))
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	diags, err := r.Run(files...)
+	require.Nil(t, err)
+
+	var s []string
+	for _, d := range diags {
+		s = append(s, fmt.Sprintf("%s: %s: %s", d.Pos, d.Analyzer, d.Message))
+	}
+	return s
+}
+
+func TestRunner(t *testing.T) {
+	srcs := map[string]string{
+		"runner_a.ttcn3": `module A {
+	function f() {}
+	// NOLINT(funcs)
+	function g() {}
+	/* NOLINT(Other, Alias) */
+	function h() {}
+}`,
+		"runner_b.ttcn3":   "module B { function i() {} }",
+		"runner_gen.ttcn3": "module Gen { function j() {} }",
+		"runner_err.ttcn3": "module Err {",
+	}
+
+	r := analysis.Runner{Analyzers: []*analysis.Analyzer{funcs, count}}
+	actual := run(t, r, `
+count: true
+ignore:
+  modules: ["^B$"]
+  files: ["gen"]
+`, srcs)
+	assert.Equal(t, []string{
+		"-: count: 2 files",
+		"runner_a.ttcn3:2:2: funcs: function f",
+		"runner_err.ttcn3:1:13: syntax: expected '}', found 'EOF'",
+	}, actual)
+
+	r.Partial = true
+	actual = run(t, r, "count: true", map[string]string{"runner_c.ttcn3": "module C { function k() {} }"})
+	assert.Equal(t, []string{"runner_c.ttcn3:1:12: funcs: function k"}, actual)
+}
+
+func TestNewImportGraph(t *testing.T) {
+	trees := []*ttcn3.Tree{
+		ttcn3.Parse(`module A { import from B all; import from B all; group G { import from C all; } }`),
+		ttcn3.Parse(`module B { function f() {} } module C {}`),
+	}
+	assert.Equal(t, analysis.ImportGraph{
+		"A": {"B", "C"},
+		"B": {},
+		"C": {},
+	}, analysis.NewImportGraph(nil, trees...))
+
+	onlyC := func(imp *ast.ImportDecl) bool { return ast.Name(imp.Module) == "C" }
+	assert.Equal(t, analysis.ImportGraph{
+		"A": {"C"},
+		"B": {},
+		"C": {},
+	}, analysis.NewImportGraph(onlyC, trees...))
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{input: ""},
+		{input: "count: true\nignore:\n  files: [a]"},
+		{input: "foo: 1", err: `unknown configuration key "foo"`},
+		{input: "funcs: 1", err: `unknown configuration key "funcs"`},
+		{input: "count: 23", err: "count: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!int `23` into bool"},
+		{input: "ignore:\n  modules: [\"(\"]", err: "ignore: error parsing regexp: missing closing ): `(`"},
+		{input: "ignore:\n  module: [a]", err: "ignore: yaml: unmarshal errors:\n  line 1: field module not found in type analysis.Ignore"},
+		{input: "root: true\nseverity:\n  funcs: off\n  count: info\noverrides:\n  - files: [gen]\n    severity: {funcs: warning}"},
+		{input: "severity:\n  foo: off", err: `severity: unknown analyzer "foo"`},
+		{input: "severity:\n  funcs: fatal", err: `severity: funcs: invalid severity "fatal" (want error, warning, info or off)`},
+		{input: "overrides:\n  - files: [\"(\"]", err: "overrides: error parsing regexp: missing closing ): `(`"},
+		{input: "overrides:\n  - file: [a]", err: "overrides: yaml: unmarshal errors:\n  line 1: field file not found in type analysis.Override"},
+	}
+
+	for _, tt := range tests {
+		_, err := analysis.ParseConfig([]byte(tt.input), funcs, count)
+		if tt.err == "" {
+			assert.Nil(t, err, tt.input)
+			continue
+		}
+		if assert.NotNil(t, err, tt.input) {
+			assert.Equal(t, tt.err, err.Error(), tt.input)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	assert.True(t, analysis.Match("^f", "foo"))
+	assert.False(t, analysis.Match("!^f", "foo"))
+	assert.True(t, analysis.Match("!^f", "bar"))
+	assert.False(t, analysis.Match("(", "("))
+	assert.True(t, analysis.MatchAny([]string{"x", "o+"}, "foo"))
+}
+
+func TestSeverity(t *testing.T) {
+	fs.Open("severity_a.ttcn3").SetBytes([]byte("module A { function f() {} }"))
+	fs.Open("severity_gen.ttcn3").SetBytes([]byte("module Gen { function g() {} }"))
+
+	tests := []struct {
+		config string
+		want   []string
+	}{
+		{config: "", want: []string{
+			"severity_a.ttcn3:1:12: error: function f",
+			"severity_gen.ttcn3:1:14: error: function g",
+		}},
+		{config: "severity: {funcs: warning}", want: []string{
+			"severity_a.ttcn3:1:12: warning: function f",
+			"severity_gen.ttcn3:1:14: warning: function g",
+		}},
+		{config: "severity: {funcs: off}", want: nil},
+		{config: `
+severity: {funcs: off}
+overrides:
+  - files: [gen]
+    severity: {funcs: error}
+  - files: [gen]
+    severity: {funcs: info}`, want: []string{
+			"severity_gen.ttcn3:1:14: info: function g",
+		}},
+	}
+
+	for _, tt := range tests {
+		cfg, err := analysis.ParseConfig([]byte(tt.config), funcs)
+		require.Nil(t, err, tt.config)
+		r := analysis.Runner{Analyzers: []*analysis.Analyzer{funcs}, Config: cfg}
+		diags, err := r.Run("severity_a.ttcn3", "severity_gen.ttcn3")
+		require.Nil(t, err, tt.config)
+		var actual []string
+		for _, d := range diags {
+			actual = append(actual, d.String())
+		}
+		assert.Equal(t, tt.want, actual, tt.config)
+	}
+}
+
+func TestNolintScope(t *testing.T) {
+	srcs := map[string]string{
+		"nolint_a.ttcn3": `// NOLINT(funcs)
+module A {
+	function f() {}
+}`,
+		"nolint_b.ttcn3": `module B {
+	function g() {}
+	// NOLINT(inner)
+	function h() {
+		// NOLINT(Alias)
+		{
+			var integer x;
+		}
+	}
+	function i() {}
+}`,
+		"nolint_c.ttcn3": `module C {
+	// NOLINTFILE(funcs)
+	function j() {}
+	function k() {}
+}`,
+	}
+
+	// inner reports every variable declaration.
+	inner := &analysis.Analyzer{
+		Name: "inner",
+		Run: func(pass *analysis.Pass) error {
+			for _, mod := range pass.Modules() {
+				ast.Inspect(mod.Node, func(n ast.Node) bool {
+					if n, ok := n.(*ast.ValueDecl); ok {
+						pass.Reportf(mod.Tree, n, "variable")
+					}
+					return true
+				})
+			}
+			return nil
+		},
+	}
+
+	r := analysis.Runner{Analyzers: []*analysis.Analyzer{funcs, inner}}
+	actual := run(t, r, "", srcs)
+	assert.Equal(t, []string{
+		"nolint_b.ttcn3:2:2: funcs: function g",
+		"nolint_b.ttcn3:4:2: funcs: function h",
+		"nolint_b.ttcn3:10:2: funcs: function i",
+	}, actual)
+}
+
+func TestHierarchy(t *testing.T) {
+	root, err := ioutil.TempDir("", "ntt-lint")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+
+	write := func(name string, content string) string {
+		path := filepath.Join(root, name)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	write(analysis.ConfigFile, "root: true\ncount: true\nseverity: {funcs: warning}")
+	write("a/"+analysis.ConfigFile, "severity: {funcs: info}\nignore: {modules: [\"^Gen$\"]}")
+	write("a/b/"+analysis.ConfigFile, "root: true")
+	write("bad/"+analysis.ConfigFile, "severity: {foo: off}")
+
+	h := &analysis.Hierarchy{Analyzers: []*analysis.Analyzer{funcs, count}}
+
+	c, err := h.Config(filepath.Join(root, "x.ttcn3"))
+	require.Nil(t, err)
+	assert.Equal(t, analysis.Warning, c.SeverityOf("funcs", "x.ttcn3"))
+	assert.True(t, c.Enabled(count))
+
+	c, err = h.Config(filepath.Join(root, "a", "c", "x.ttcn3"))
+	require.Nil(t, err)
+	assert.Equal(t, analysis.Info, c.SeverityOf("funcs", "x.ttcn3"))
+	assert.True(t, c.Enabled(count))
+	assert.True(t, c.Ignore.Module("Gen"))
+
+	c, err = h.Config(filepath.Join(root, "a", "b", "x.ttcn3"))
+	require.Nil(t, err)
+	assert.Equal(t, analysis.Severity(""), c.SeverityOf("funcs", "x.ttcn3"))
+	assert.False(t, c.Enabled(count))
+
+	_, err = h.Config(filepath.Join(root, "bad", "x.ttcn3"))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), `severity: unknown analyzer "foo"`)
+	}
+
+	files := []string{
+		write("x.ttcn3", "module X { function f() {} }"),
+		write("a/y.ttcn3", "module Y { function g() {} }"),
+		write("a/gen.ttcn3", "module Gen { function h() {} }"),
+		write("a/b/z.ttcn3", "module Z { function i() {} }"),
+	}
+	// Imports outside of the tree use the fallback configuration.
+	outside, err := ioutil.TempDir("", "ntt-lint-import")
+	require.Nil(t, err)
+	defer os.RemoveAll(outside)
+	lib := filepath.Join(outside, "lib.ttcn3")
+	require.Nil(t, ioutil.WriteFile(lib, []byte("module Lib { function j() {} }"), 0644))
+	files = append(files, lib)
+	fallback, err := analysis.ReadConfig(filepath.Join(root, analysis.ConfigFile), []*analysis.Analyzer{funcs, count})
+	require.Nil(t, err)
+	missing, err := analysis.ReadConfig(filepath.Join(outside, analysis.ConfigFile), nil)
+	assert.Nil(t, err)
+	assert.Nil(t, missing)
+
+	h = &analysis.Hierarchy{Analyzers: []*analysis.Analyzer{funcs, count}}
+	r := analysis.Runner{Analyzers: []*analysis.Analyzer{funcs, count}, Configs: h, Config: fallback}
+	diags, err := r.Run(files...)
+	require.Nil(t, err)
+	var actual []string
+	for _, d := range diags {
+		var rel string
+		switch {
+		case d.Pos.Filename == lib:
+			rel = "lib"
+		case d.Pos.Filename != "":
+			rel, _ = filepath.Rel(root, d.Pos.Filename)
+		}
+		actual = append(actual, fmt.Sprintf("%s: %s: %s", rel, d.Severity, d.Message))
+	}
+	sort.Strings(actual)
+	assert.Equal(t, []string{
+		": error: 5 files",
+		"a/b/z.ttcn3: error: function i",
+		"a/y.ttcn3: info: function g",
+		"lib: warning: function j",
+		"x.ttcn3: warning: function f",
+	}, actual)
+}
@@ -0,0 +1,187 @@
+package analysis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/ttcn3/ast"
+)
+
+// BaselineFile is the default name of the baseline file.
+const BaselineFile = ".ntt-lint-baseline.json"
+
+// A Baseline records accepted findings, usually of legacy code. Findings in
+// the baseline are not reported again, which lets large test suites adopt
+// new checks gradually.
+//
+// Findings are identified by analyzer, file, enclosing symbol and a hash of
+// the offending source text. For definitions only the header is hashed.
+// Moving code inside a file or editing the body of a function therefore does
+// not invalidate the baseline, while changing the offending code does.
+type Baseline struct {
+	Version  int       `json:"version"`
+	Findings []Finding `json:"findings"`
+
+	// dir is the directory of the baseline file. File names are relative
+	// to it.
+	dir string
+}
+
+// A Finding is a baseline entry.
+type Finding struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file"`
+	Symbol  string `json:"symbol,omitempty"`
+	Hash    string `json:"hash"`
+	Message string `json:"message"`
+
+	// Count is the number of identical findings.
+	Count int `json:"count"`
+}
+
+func (f Finding) key() string {
+	return strings.Join([]string{f.Rule, f.File, f.Symbol, f.Hash}, "\x00")
+}
+
+// NewBaseline returns a baseline of diagnostics to be stored in file path.
+func NewBaseline(path string, diags []Diagnostic) *Baseline {
+	b := &Baseline{Version: 1, dir: filepath.Dir(path)}
+	index := make(map[string]int)
+	for _, d := range diags {
+		f := b.finding(d)
+		if i, ok := index[f.key()]; ok {
+			b.Findings[i].Count++
+			continue
+		}
+		f.Count = 1
+		index[f.key()] = len(b.Findings)
+		b.Findings = append(b.Findings, f)
+	}
+	sort.SliceStable(b.Findings, func(i, j int) bool {
+		if b.Findings[i].File != b.Findings[j].File {
+			return b.Findings[i].File < b.Findings[j].File
+		}
+		return b.Findings[i].Rule < b.Findings[j].Rule
+	})
+	return b
+}
+
+// ReadBaseline reads a baseline file. It returns nil and no error if the file
+// does not exist.
+func ReadBaseline(path string) (*Baseline, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	b.dir = filepath.Dir(path)
+	return &b, nil
+}
+
+// Write writes the baseline to file path.
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Filter returns the diagnostics not recorded in the baseline. Each baseline
+// entry hides as many diagnostics as it counts.
+func (b *Baseline) Filter(diags []Diagnostic) []Diagnostic {
+	if b == nil {
+		return diags
+	}
+	count := make(map[string]int)
+	for _, f := range b.Findings {
+		count[f.key()] += f.Count
+	}
+	var result []Diagnostic
+	for _, d := range diags {
+		k := b.finding(d).key()
+		if count[k] > 0 {
+			count[k]--
+			continue
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+func (b *Baseline) finding(d Diagnostic) Finding {
+	file := d.Pos.Filename
+	if rel, err := filepath.Rel(b.dir, file); err == nil && filepath.IsAbs(file) == filepath.IsAbs(b.dir) {
+		file = filepath.ToSlash(rel)
+	}
+	return Finding{
+		Rule:    d.Analyzer,
+		File:    file,
+		Symbol:  d.Symbol,
+		Hash:    hash(d),
+		Message: d.Message,
+	}
+}
+
+// hash returns the hash of the source text of a diagnostic, ignoring
+// whitespace. For definitions with a body, like functions or modules, only the
+// header up to the body is hashed. Findings about a whole definition, like
+// max_lines or complexity, therefore survive edits of its body, while changing
+// the name or signature brings them back. Diagnostics without range are hashed
+// by message.
+func hash(d Diagnostic) string {
+	text := d.Message
+	if d.Pos.IsValid() && d.End.IsValid() && d.Pos.Offset <= d.End.Offset {
+		end := d.End.Offset
+		if n := headerLen(d.Node); n > 0 && d.Pos.Offset+n < end {
+			end = d.Pos.Offset + n
+		}
+		if b, err := fs.Content(d.Pos.Filename); err == nil && end <= len(b) {
+			text = strings.Join(strings.Fields(string(b[d.Pos.Offset:end])), " ")
+		}
+	}
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:8])
+}
+
+// headerLen returns the length of the header of definition n, which is the
+// source text before its body. It returns 0 if n has no body.
+func headerLen(n ast.Node) int {
+	var body loc.Pos
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		if n.Body != nil {
+			body = n.Body.Pos()
+		}
+	case *ast.ControlPart:
+		if n.Body != nil {
+			body = n.Body.Pos()
+		}
+	case *ast.ComponentTypeDecl:
+		if n.Body != nil {
+			body = n.Body.Pos()
+		}
+	case *ast.Module:
+		body = n.LBrace.Pos()
+	case *ast.GroupDecl:
+		body = n.LBrace.Pos()
+	}
+	if !body.IsValid() || body < n.Pos() {
+		return 0
+	}
+	return int(body - n.Pos())
+}
@@ -0,0 +1,79 @@
+package analysis_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ntt-baseline")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "a.ttcn3")
+	path := filepath.Join(dir, analysis.BaselineFile)
+	r := analysis.Runner{Analyzers: []*analysis.Analyzer{funcs}}
+	lint := func(src string) []analysis.Diagnostic {
+		fs.Open(file).SetBytes([]byte(src))
+		diags, err := r.Run(file)
+		require.Nil(t, err)
+		return diags
+	}
+
+	b, err := analysis.ReadBaseline(path)
+	assert.Nil(t, err)
+	assert.Nil(t, b)
+
+	diags := lint("module M { function f() {} function g() {} }")
+	require.Nil(t, analysis.NewBaseline(path, diags).Write(path))
+
+	b, err = analysis.ReadBaseline(path)
+	require.Nil(t, err)
+	assert.Equal(t, []analysis.Finding{
+		{Rule: "funcs", File: "a.ttcn3", Symbol: "M.f", Hash: b.Findings[0].Hash, Message: "function f", Count: 1},
+		{Rule: "funcs", File: "a.ttcn3", Symbol: "M.g", Hash: b.Findings[1].Hash, Message: "function g", Count: 1},
+	}, b.Findings)
+
+	names := func(diags []analysis.Diagnostic) []string {
+		var names []string
+		for _, d := range diags {
+			names = append(names, d.Message)
+		}
+		return names
+	}
+
+	// Shifted lines and edited function bodies do not matter, but
+	// modified signatures and new code do.
+	diags = b.Filter(lint("module M {\n\n  function f() { log(1) }\n  function g(integer x) {}\n  function h() {}\n}"))
+	assert.Equal(t, []string{"function g", "function h"}, names(diags))
+
+	// Findings about whole definitions survive edits of the body.
+	long := &analysis.Analyzer{
+		Name: "max_lines",
+		Run: func(pass *analysis.Pass) error {
+			for _, mod := range pass.Modules() {
+				ast.Inspect(mod.Node, func(n ast.Node) bool {
+					if n, ok := n.(*ast.FuncDecl); ok {
+						begin := mod.Tree.Position(n.Pos())
+						end := mod.Tree.Position(n.End())
+						pass.Reportf(mod.Tree, n, "%s has %d lines", ast.Name(n), end.Line-begin.Line+1)
+					}
+					return true
+				})
+			}
+			return nil
+		},
+	}
+	r.Analyzers = []*analysis.Analyzer{long}
+	b = analysis.NewBaseline(path, lint("module M {\n  function f() {\n    log(1);\n  }\n}"))
+	assert.Empty(t, b.Filter(lint("module M {\n  function f() {\n    log(1);\n    log(2);\n  }\n}")))
+	assert.Equal(t, []string{"f2 has 3 lines"}, names(b.Filter(lint("module M {\n  function f2() {\n    log(1);\n  }\n}"))))
+}
@@ -0,0 +1,316 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is a parsed lint configuration.
+type Config struct {
+	// Sections maps analyzer names to their decoded configuration values.
+	// Analyzers with options run only if their section is present.
+	Sections map[string]interface{}
+
+	// Ignore lists modules and files excluded from analysis.
+	Ignore Ignore
+
+	// Severity maps analyzer names to the severity of their diagnostics.
+	// Severity Off disables an analyzer.
+	Severity map[string]Severity
+
+	// Overrides change severities for files matching patterns.
+	Overrides []Override
+
+	// Root stops inheritance from configurations of parent directories.
+	Root bool
+}
+
+// An Override changes severities of diagnostics in files matching any of the
+// regular expressions Files.
+type Override struct {
+	Files    []string
+	Severity map[string]Severity
+}
+
+// Enabled returns true if analyzer a should run. Analyzers without options
+// are always enabled, unless their severity is Off and no override enables
+// them.
+func (c *Config) Enabled(a *Analyzer) bool {
+	if c != nil && c.Severity[a.Name] == Off && !c.overridden(a.Name) {
+		return false
+	}
+	if a.Config == nil {
+		return true
+	}
+	if c == nil {
+		return false
+	}
+	_, ok := c.Sections[a.Name]
+	return ok
+}
+
+// SeverityOf returns the configured severity of diagnostics of analyzer a in
+// file path. Overrides take precedence; the last matching override wins. The
+// result is empty, if no severity is configured.
+func (c *Config) SeverityOf(a string, path string) Severity {
+	if c == nil {
+		return ""
+	}
+	for i := len(c.Overrides) - 1; i >= 0; i-- {
+		o := c.Overrides[i]
+		if s, ok := o.Severity[a]; ok && MatchAny(o.Files, path) {
+			return s
+		}
+	}
+	return c.Severity[a]
+}
+
+// overridden returns true if an override sets a severity other than Off for
+// analyzer a.
+func (c *Config) overridden(a string) bool {
+	for _, o := range c.Overrides {
+		if s, ok := o.Severity[a]; ok && s != Off {
+			return true
+		}
+	}
+	return false
+}
+
+// Section returns the configuration value of analyzer a or nil.
+func (c *Config) Section(a *Analyzer) interface{} {
+	if c == nil {
+		return nil
+	}
+	return c.Sections[a.Name]
+}
+
+// ParseConfig parses a YAML formatted lint configuration. Each top-level key
+// names an analyzer, whose section is strictly decoded into the value
+// returned by its Config function. The key "ignore" lists modules and files
+// to exclude, "severity" maps analyzer names to severities, "overrides" lists
+// severities for files matching patterns and "root" stops inheritance from
+// parent directories. Unknown keys are reported as error.
+func ParseConfig(b []byte, analyzers ...*Analyzer) (*Config, error) {
+	var raw yaml.MapSlice
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	// Severities are decoded from the original document, because YAML
+	// treats "off" as boolean, which does not survive re-encoding.
+	var severities struct {
+		Severity  map[string]Severity
+		Overrides []Override
+	}
+	if err := yaml.Unmarshal(b, &severities); err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]*Analyzer)
+	for _, a := range analyzers {
+		known[a.Name] = a
+	}
+
+	c := &Config{Sections: make(map[string]interface{})}
+	for _, item := range raw {
+		key := fmt.Sprint(item.Key)
+
+		section, err := yaml.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "ignore":
+			if err := yaml.UnmarshalStrict(section, &c.Ignore); err != nil {
+				return nil, fmt.Errorf("ignore: %w", err)
+			}
+			if err := c.Ignore.compile(); err != nil {
+				return nil, fmt.Errorf("ignore: %w", err)
+			}
+			continue
+
+		case "severity":
+			c.Severity = severities.Severity
+			if err := checkSeverities(c.Severity, known); err != nil {
+				return nil, fmt.Errorf("severity: %w", err)
+			}
+			continue
+
+		case "overrides":
+			if err := yaml.UnmarshalStrict(section, &c.Overrides); err != nil {
+				return nil, fmt.Errorf("overrides: %w", err)
+			}
+			for i := range c.Overrides {
+				c.Overrides[i].Severity = severities.Overrides[i].Severity
+			}
+			for _, o := range c.Overrides {
+				for _, p := range o.Files {
+					if _, err := Regexp(p); err != nil {
+						return nil, fmt.Errorf("overrides: %w", err)
+					}
+				}
+				if err := checkSeverities(o.Severity, known); err != nil {
+					return nil, fmt.Errorf("overrides: %w", err)
+				}
+			}
+			continue
+
+		case "root":
+			if err := yaml.UnmarshalStrict(section, &c.Root); err != nil {
+				return nil, fmt.Errorf("root: %w", err)
+			}
+			continue
+		}
+
+		a, ok := known[key]
+		if !ok || a.Config == nil {
+			return nil, fmt.Errorf("unknown configuration key %q", key)
+		}
+		v := a.Config()
+		if err := yaml.UnmarshalStrict(section, v); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		c.Sections[key] = v
+	}
+	return c, nil
+}
+
+func checkSeverities(m map[string]Severity, known map[string]*Analyzer) error {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := known[name]; !ok {
+			return fmt.Errorf("unknown analyzer %q", name)
+		}
+		switch m[name] {
+		case Error, Warning, Info, Off:
+		default:
+			return fmt.Errorf("%s: invalid severity %q (want error, warning, info or off)", name, m[name])
+		}
+	}
+	return nil
+}
+
+// Merge returns the configuration c inherited from configuration parent:
+// sections of c replace sections of parent, severities of c take precedence,
+// and ignore patterns and overrides are appended. If c is a root
+// configuration parent is not inherited.
+func (c *Config) Merge(parent *Config) *Config {
+	if c == nil {
+		return parent
+	}
+	if parent == nil || c.Root {
+		return c
+	}
+	m := &Config{
+		Sections: make(map[string]interface{}),
+		Severity: make(map[string]Severity),
+		Root:     parent.Root,
+	}
+	for k, v := range parent.Sections {
+		m.Sections[k] = v
+	}
+	for k, v := range c.Sections {
+		m.Sections[k] = v
+	}
+	for k, v := range parent.Severity {
+		m.Severity[k] = v
+	}
+	for k, v := range c.Severity {
+		m.Severity[k] = v
+	}
+	m.Ignore.Modules = append(append([]string(nil), parent.Ignore.Modules...), c.Ignore.Modules...)
+	m.Ignore.Files = append(append([]string(nil), parent.Ignore.Files...), c.Ignore.Files...)
+	m.Overrides = append(append([]Override(nil), parent.Overrides...), c.Overrides...)
+	return m
+}
+
+// Ignore lists regular expressions of modules and files excluded from
+// analysis. A leading exclamation mark inverts a pattern.
+type Ignore struct {
+	Modules []string
+	Files   []string
+}
+
+func (ig *Ignore) compile() error {
+	for _, p := range append(ig.Modules, ig.Files...) {
+		if _, err := Regexp(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Module returns true if module name is ignored.
+func (ig *Ignore) Module(name string) bool {
+	return ig != nil && MatchAny(ig.Modules, name)
+}
+
+// File returns true if file path is ignored.
+func (ig *Ignore) File(path string) bool {
+	return ig != nil && MatchAny(ig.Files, path)
+}
+
+var regexes sync.Map
+
+// Regexp returns the compiled regular expression of pattern p. A leading
+// exclamation mark is not part of the expression. Expressions are cached.
+func Regexp(p string) (*regexp.Regexp, error) {
+	p = strings.TrimPrefix(p, "!")
+	if r, ok := regexes.Load(p); ok {
+		return r.(*regexp.Regexp), nil
+	}
+	r, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+	regexes.Store(p, r)
+	return r, nil
+}
+
+// Match reports whether s matches pattern p. Patterns with leading exclamation
+// mark match if the expression does not. Invalid expressions never match.
+func Match(p string, s string) bool {
+	r, err := Regexp(p)
+	if err != nil {
+		return false
+	}
+	return r.MatchString(s) != strings.HasPrefix(p, "!")
+}
+
+// MatchAny reports whether s matches any of the patterns.
+func MatchAny(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if Match(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompilePatterns compiles the keys of pattern maps, like used by naming
+// conventions, and returns the first error.
+func CompilePatterns(maps ...map[string]string) error {
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, err := Regexp(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package analysis
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/printer"
+)
+
+// ApplyFixes applies the fixes to the content src of a file and returns the
+// result. Slice applied reports for each fix whether it was applied. Fixes
+// are applied as a whole: a fix with edits overlapping the edits of a
+// preceding fix is skipped. Text not covered by edits, including comments and
+// formatting, is left untouched.
+func ApplyFixes(src []byte, fixes []SuggestedFix) (result []byte, applied []bool) {
+	var edits []TextEdit
+	applied = make([]bool, len(fixes))
+next:
+	for i, fix := range fixes {
+		for _, e := range fix.TextEdits {
+			if e.Pos.Offset > e.End.Offset || e.End.Offset > len(src) {
+				continue next
+			}
+			for _, o := range edits {
+				if overlaps(e, o) {
+					continue next
+				}
+			}
+		}
+		edits = append(edits, fix.TextEdits...)
+		applied[i] = true
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Pos.Offset < edits[j].Pos.Offset })
+
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range edits {
+		buf.Write(src[last:e.Pos.Offset])
+		buf.WriteString(e.NewText)
+		last = e.End.Offset
+	}
+	buf.Write(src[last:])
+	return buf.Bytes(), applied
+}
+
+// overlaps reports whether two edits overlap. Insertions at the same offset
+// overlap, too, because their order would be undefined.
+func overlaps(a, b TextEdit) bool {
+	if a.Pos.Offset == b.Pos.Offset {
+		return true
+	}
+	return a.Pos.Offset < b.End.Offset && b.Pos.Offset < a.End.Offset
+}
+
+// Replace returns an edit replacing the text of tree between begin and end
+// offsets with text.
+func Replace(tree *ttcn3.Tree, begin, end int, text string) TextEdit {
+	file := tree.FileSet.File(tree.Root.Pos())
+	return TextEdit{
+		Pos:     tree.Position(file.Pos(begin)),
+		End:     tree.Position(file.Pos(end)),
+		NewText: text,
+	}
+}
+
+// ReplaceNode returns an edit replacing the text of node n with text.
+func ReplaceNode(tree *ttcn3.Tree, n ast.Node, text string) TextEdit {
+	return TextEdit{
+		Pos:     tree.Position(n.Pos()),
+		End:     tree.Position(n.End()),
+		NewText: text,
+	}
+}
+
+// Print returns the text of node n formatted by printer.Print. Fixes use it to
+// render new or rewritten nodes, which are then inserted with text edits, so
+// the formatting around them is preserved. Empty lines are dropped and lines
+// after the first one are prefixed with indent.
+func Print(tree *ttcn3.Tree, n ast.Node, indent string) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Print(&buf, tree.FileSet, n); err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"+indent), nil
+}
+
+// Source returns the content of the file of tree.
+func Source(tree *ttcn3.Tree) ([]byte, error) {
+	b, err := fs.Content(tree.Filename())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tree.Filename(), err)
+	}
+	return b, nil
+}
+
+// Indentation returns the text between the beginning of the line and offset,
+// with all characters except tabs replaced by spaces.
+func Indentation(src []byte, offset int) string {
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+	indent := []byte(string(src[start:offset]))
+	for i, c := range indent {
+		if c != '\t' {
+			indent[i] = ' '
+		}
+	}
+	return string(indent)
+}
+
+// LineStart returns the offset of the beginning of the line containing
+// offset, if the line contains only whitespace before offset. Otherwise it
+// returns -1.
+func LineStart(src []byte, offset int) int {
+	for i := offset - 1; i >= 0; i-- {
+		switch src[i] {
+		case ' ', '\t':
+		case '\n':
+			return i + 1
+		default:
+			return -1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/analysis/passes"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func edit(begin, end int, text string) analysis.TextEdit {
+	return analysis.TextEdit{Pos: loc.Position{Offset: begin}, End: loc.Position{Offset: end}, NewText: text}
+}
+
+func TestApplyFixes(t *testing.T) {
+	fixes := []analysis.SuggestedFix{
+		{TextEdits: []analysis.TextEdit{edit(4, 5, "X"), edit(0, 0, ">")}},
+		{TextEdits: []analysis.TextEdit{edit(8, 9, "Y"), edit(4, 6, "Z")}},
+		{TextEdits: []analysis.TextEdit{edit(11, 11, "!")}},
+	}
+	b, applied := analysis.ApplyFixes([]byte("abc def ghi"), fixes)
+	assert.Equal(t, ">abc Xef ghi!", string(b))
+	assert.Equal(t, []bool{true, false, true}, applied)
+}
+
+// TestDiffCaseElse checks the diff printed by ntt lint --diff for a select
+// statement in K&R style.
+func TestDiffCaseElse(t *testing.T) {
+	src := `module M {
+function f(integer x) {
+	select (x) {
+	case (1) {
+		log(x);
+	}
+	}
+}
+}
+`
+	file := "diff_case_else.ttcn3"
+	fs.Open(file).SetBytes([]byte(src))
+
+	cfg, err := analysis.ParseConfig([]byte("require_case_else: true"), passes.RequireCaseElse)
+	require.Nil(t, err)
+	r := analysis.Runner{Analyzers: []*analysis.Analyzer{passes.RequireCaseElse}, Config: cfg}
+	diags, err := r.Run(file)
+	require.Nil(t, err)
+	require.Len(t, diags, 1)
+
+	b, _ := analysis.ApplyFixes([]byte(src), diags[0].SuggestedFixes)
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(src),
+		B:        difflib.SplitLines(string(b)),
+		FromFile: file,
+		ToFile:   file,
+		Context:  3,
+	})
+	require.Nil(t, err)
+	assert.Equal(t, `--- diff_case_else.ttcn3
++++ diff_case_else.ttcn3
+@@ -4,6 +4,8 @@
+ 	case (1) {
+ 		log(x);
+ 	}
++	case else {
++	}
+ 	}
+ }
+ }
+`, diff)
+}
@@ -0,0 +1,78 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/nokia/ntt/internal/fs"
+)
+
+// ConfigFile is the name of lint configuration files.
+const ConfigFile = ".ntt-lint.yml"
+
+// Hierarchy provides the lint configuration of files. The configuration of a
+// file is read from the ConfigFile in its directory and inherits from the
+// configuration files of all parent directories up to a root configuration.
+// See Config.Merge for details.
+type Hierarchy struct {
+	// Analyzers are the analyzers known to configuration files.
+	Analyzers []*Analyzer
+
+	mu      sync.Mutex
+	configs map[string]*Config
+	errs    map[string]error
+}
+
+// Config returns the configuration of file path. It is nil if neither the
+// directory of the file nor any parent directory has a configuration file.
+func (h *Hierarchy) Config(path string) (*Config, error) {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.configs == nil {
+		h.configs = make(map[string]*Config)
+		h.errs = make(map[string]error)
+	}
+	return h.dir(dir)
+}
+
+// dir returns the configuration of directory dir. Directories without
+// configuration file share the configuration of their parent directory.
+func (h *Hierarchy) dir(dir string) (*Config, error) {
+	if c, ok := h.configs[dir]; ok {
+		return c, h.errs[dir]
+	}
+
+	c, err := ReadConfig(filepath.Join(dir, ConfigFile), h.Analyzers)
+	if err == nil && (c == nil || !c.Root) {
+		if up := filepath.Dir(dir); up != dir {
+			var parent *Config
+			parent, err = h.dir(up)
+			c = c.Merge(parent)
+		}
+	}
+	h.configs[dir], h.errs[dir] = c, err
+	return c, err
+}
+
+// ReadConfig returns the parsed configuration file path or nil if it does not
+// exist.
+func ReadConfig(path string, analyzers []*Analyzer) (*Config, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	b, err := fs.Open(path).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseConfig(b, analyzers...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,122 @@
+package analysis
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+)
+
+var (
+	nolintRegex     = regexp.MustCompile(`^[/*\s]*NOLINT\(([^\)]+)\)[/*\r\n\s]*$`)
+	nolintFileRegex = regexp.MustCompile(`^[/*\s]*NOLINTFILE\(([^\)]+)\)[/*\r\n\s]*$`)
+)
+
+// isSilenced returns true if the comments of the first token of n contain a
+// NOLINT directive with the name of analyzer a or one of its aliases.
+func isSilenced(n ast.Node, a *Analyzer) bool {
+	if n == nil {
+		return false
+	}
+	return matchNames(directives(nolintRegex, ast.FirstToken(n).Comments()), a)
+}
+
+// nolint are the NOLINT directives of a file.
+type nolint struct {
+	// file lists the names of NOLINTFILE directives, which silence
+	// analyzers in the whole file.
+	file []string
+
+	// regions are the syntax constructs preceded by NOLINT directives.
+	regions []nolintRegion
+}
+
+type nolintRegion struct {
+	begin, end int
+	names      []string
+}
+
+// nolintOf collects the NOLINT directives of tree. A NOLINT directive in the
+// comments before a module, definition or statement silences analyzers in
+// the whole construct. A NOLINTFILE directive before any construct silences
+// analyzers in the whole file.
+func nolintOf(tree *ttcn3.Tree) *nolint {
+	nl := &nolint{}
+	ast.Inspect(tree.Root, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.Module, *ast.ModuleDef, ast.Decl, ast.Stmt, *ast.CommClause, *ast.CaseClause, *ast.FormalPar, *ast.Field:
+		case nil:
+			return false
+		default:
+			return true
+		}
+		tok := ast.FirstToken(n)
+		if tok == nil || !tok.IsValid() {
+			return true
+		}
+		comments := tok.Comments()
+		if comments == "" {
+			return true
+		}
+		if names := directives(nolintRegex, comments); len(names) > 0 {
+			nl.regions = append(nl.regions, nolintRegion{
+				begin: tree.Position(n.Pos()).Offset,
+				end:   tree.Position(n.End()).Offset,
+				names: names,
+			})
+		}
+		nl.file = append(nl.file, directives(nolintFileRegex, comments)...)
+		return true
+	})
+	return nl
+}
+
+// silences returns true if d is inside a construct silencing analyzer a or
+// if a is silenced for the whole file.
+func (nl *nolint) silences(d Diagnostic, a *Analyzer) bool {
+	if matchNames(nl.file, a) {
+		return true
+	}
+	if !d.Pos.IsValid() {
+		return false
+	}
+	for _, r := range nl.regions {
+		if r.begin <= d.Pos.Offset && d.Pos.Offset < r.end && matchNames(r.names, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// directives returns the names listed by the directives matching regular
+// expression r in comments.
+func directives(r *regexp.Regexp, comments string) []string {
+	var names []string
+	scanner := bufio.NewScanner(strings.NewReader(comments))
+	for scanner.Scan() {
+		if s := r.FindStringSubmatch(scanner.Text()); len(s) == 2 {
+			for _, name := range strings.Split(s[1], ",") {
+				names = append(names, strings.TrimSpace(name))
+			}
+		}
+	}
+	return names
+}
+
+// matchNames returns true if names contain the name of analyzer a or one of
+// its aliases.
+func matchNames(names []string, a *Analyzer) bool {
+	for _, s := range names {
+		if s == a.Name {
+			return true
+		}
+		for _, alias := range a.NoLint {
+			if s == alias {
+				return true
+			}
+		}
+	}
+	return false
+}
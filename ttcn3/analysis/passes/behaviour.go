@@ -0,0 +1,589 @@
+package passes
+
+import (
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// RequireAltGuard reports alt statements which may block forever.
+var RequireAltGuard = &analysis.Analyzer{
+	Name: "require_alt_guard",
+	Doc: `Every alt-statement must have a timeout- or else-guard.
+
+Alt-statements without such a guard may block forever. Altstep invocations
+are assumed to provide a guard. Activated defaults are not considered.
+
+Example:
+
+	require_alt_guard: true`,
+	Config: func() interface{} { return new(bool) },
+	Run:    runRequireAltGuard,
+}
+
+func runRequireAltGuard(pass *analysis.Pass) error {
+	if !*pass.Config.(*bool) {
+		return nil
+	}
+	for _, mod := range pass.Modules() {
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			if n, ok := n.(*ast.AltStmt); ok && n.Tok.Kind == token.ALT && !hasAltGuard(n) {
+				pass.Reportf(mod.Tree, n, "alt statement without timeout or else guard may block forever")
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+func hasAltGuard(n *ast.AltStmt) bool {
+	if n.Body == nil {
+		return true
+	}
+	for _, s := range n.Body.Stmts {
+		c, ok := s.(*ast.CommClause)
+		if !ok {
+			continue
+		}
+		if c.Else.IsValid() {
+			return true
+		}
+		stmt, ok := c.Comm.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		switch _, op := operation(stmt.Expr); op {
+		case "timeout":
+			return true
+		case "":
+			if _, ok := stmt.Expr.(*ast.CallExpr); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequirePortConnection reports receiving operations on component ports,
+// which are not mapped or connected by the testcase running them.
+var RequirePortConnection = &analysis.Analyzer{
+	Name: "require_port_connection",
+	Doc: `Ports must be mapped or connected before receiving from them.
+
+A receiving operation is checked for every testcase reaching it. The port must
+be used in a map- or connect-operation of the testcase or of any function or
+altstep the testcase calls or starts. Ports and calls are matched by name.
+Behaviours not reached by any testcase are not checked.
+
+Example:
+
+	require_port_connection: true`,
+	Config: func() interface{} { return new(bool) },
+	Suite:  true,
+	Run:    runRequirePortConnection,
+}
+
+func runRequirePortConnection(pass *analysis.Pass) error {
+	if !*pass.Config.(*bool) {
+		return nil
+	}
+
+	// Port names, testcases and the ports mapped or connected by each
+	// behaviour.
+	ports := make(map[string]bool)
+	connects := make(map[string]map[string]bool)
+	var tests []string
+	for _, tree := range pass.Files {
+		ast.Inspect(tree.Root, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueDecl:
+				if n.Kind.Kind == token.PORT {
+					for _, d := range n.Decls {
+						ports[ast.Name(d.Name)] = true
+					}
+				}
+			case *ast.FuncDecl:
+				f := ast.Name(n)
+				if n.IsTest() {
+					tests = append(tests, f)
+				}
+				if connects[f] == nil {
+					connects[f] = make(map[string]bool)
+				}
+				ast.Inspect(n, func(n ast.Node) bool {
+					c, ok := n.(*ast.CallExpr)
+					if !ok || c.Args == nil {
+						return true
+					}
+					if name := ast.Name(c.Fun); name != "map" && name != "connect" {
+						return true
+					}
+					for _, arg := range c.Args.List {
+						if b, ok := arg.(*ast.BinaryExpr); ok && b.Op.Kind == token.COLON {
+							connects[f][objectName(b.Y)] = true
+						}
+					}
+					return true
+				})
+				return false
+			}
+			return true
+		})
+	}
+
+	// The testcases reaching a behaviour together with the ports they
+	// map or connect.
+	type context struct {
+		test      string
+		connected map[string]bool
+	}
+	contexts := make(map[string][]context)
+	g := newCallGraph(pass.Files)
+	for _, tc := range tests {
+		reached := g.callees(tc)
+		ctx := context{test: tc, connected: make(map[string]bool)}
+		for name := range reached {
+			for port := range connects[name] {
+				ctx.connected[port] = true
+			}
+		}
+		for name := range reached {
+			contexts[name] = append(contexts[name], ctx)
+		}
+	}
+
+	for _, mod := range pass.Modules() {
+		ast.WalkModuleDefs(func(def *ast.ModuleDef) bool {
+			f, ok := def.Def.(*ast.FuncDecl)
+			if !ok {
+				return true
+			}
+			ctxs := contexts[ast.Name(f)]
+			ast.Inspect(f, func(n ast.Node) bool {
+				e, ok := n.(ast.Expr)
+				if !ok {
+					return true
+				}
+				switch x, op := operation(e); op {
+				case "receive", "trigger", "getcall", "getreply", "catch", "check":
+					name := objectName(x)
+					if !ports[name] {
+						return false
+					}
+					for _, ctx := range ctxs {
+						if !ctx.connected[name] {
+							pass.Reportf(mod.Tree, n, "port %q is never mapped or connected in testcase %q", name, ctx.test)
+							break
+						}
+					}
+					return false
+				}
+				return true
+			})
+			return true
+		}, mod.Node)
+	}
+	return nil
+}
+
+// RequireVerdict reports test cases which never set a verdict.
+var RequireVerdict = &analysis.Analyzer{
+	Name: "require_verdict",
+	Doc: `Every testcase must set a verdict.
+
+A verdict set by any function or altstep called by the testcase counts, too.
+Calls are resolved by name.
+
+Example:
+
+	require_verdict: true`,
+	Config: func() interface{} { return new(bool) },
+	Suite:  true,
+	Run:    runRequireVerdict,
+}
+
+func runRequireVerdict(pass *analysis.Pass) error {
+	if !*pass.Config.(*bool) {
+		return nil
+	}
+	setters := newCallGraph(pass.Files).callers("setverdict")
+	for _, mod := range pass.Modules() {
+		ast.WalkModuleDefs(func(def *ast.ModuleDef) bool {
+			if f, ok := def.Def.(*ast.FuncDecl); ok && f.IsTest() && !callsAny(f, setters) {
+				pass.Reportf(mod.Tree, f, "testcase %q never sets a verdict", ast.Name(f))
+			}
+			return true
+		}, mod.Node)
+	}
+	return nil
+}
+
+// UnreachableCode reports statements following stop-, return-, repeat-,
+// break-, continue- or goto-statements.
+var UnreachableCode = &analysis.Analyzer{
+	Name: "unreachable_code",
+	Doc: `Statements must be reachable.
+
+Statements following stop, return, repeat, break, continue or goto are never
+executed, unless they are labeled.
+
+Example:
+
+	unreachable_code: true`,
+	Config: func() interface{} { return new(bool) },
+	Run:    runUnreachableCode,
+}
+
+func runUnreachableCode(pass *analysis.Pass) error {
+	if !*pass.Config.(*bool) {
+		return nil
+	}
+	for _, mod := range pass.Modules() {
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			b, ok := n.(*ast.BlockStmt)
+			if !ok {
+				return true
+			}
+			for i := 1; i < len(b.Stmts); i++ {
+				if isTerminating(b.Stmts[i-1]) && !isLabel(b.Stmts[i]) {
+					pass.Reportf(mod.Tree, b.Stmts[i], "unreachable code")
+					break
+				}
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+func isTerminating(s ast.Stmt) bool {
+	switch s := s.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		switch s.Tok.Kind {
+		case token.REPEAT, token.BREAK, token.CONTINUE, token.GOTO:
+			return true
+		}
+	case *ast.ExprStmt:
+		switch ast.Name(s.Expr) {
+		case "stop", "kill", "self.stop", "self.kill", "mtc.stop", "mtc.kill", "testcase.stop":
+			return true
+		}
+	}
+	return false
+}
+
+func isLabel(s ast.Stmt) bool {
+	b, ok := s.(*ast.BranchStmt)
+	return ok && b.Tok.Kind == token.LABEL
+}
+
+// RequireDeactivate reports activated defaults which are never deactivated.
+var RequireDeactivate = &analysis.Analyzer{
+	Name: "require_deactivate",
+	Doc: `Activated defaults must be deactivated.
+
+A default is deactivated, if the variable storing the default reference is
+passed to any deactivate-operation of the test suite, or if the activating
+behaviour deactivates all defaults after the activation, directly or by
+calling a function doing so. Statements are ordered by their position in the
+source.
+
+Example:
+
+	require_deactivate: true`,
+	Config: func() interface{} { return new(bool) },
+	Suite:  true,
+	Run:    runRequireDeactivate,
+}
+
+func runRequireDeactivate(pass *analysis.Pass) error {
+	if !*pass.Config.(*bool) {
+		return nil
+	}
+
+	// Variables deactivated anywhere and behaviours deactivating all
+	// defaults.
+	deactivated := make(map[string]bool)
+	var all []string
+	for _, tree := range pass.Files {
+		ast.Inspect(tree.Root, func(n ast.Node) bool {
+			f, ok := n.(*ast.FuncDecl)
+			if !ok {
+				return true
+			}
+			ast.Inspect(f, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.ExprStmt:
+					if ast.Name(n.Expr) != "deactivate" {
+						break
+					}
+					if args := deactivateArgs(n); len(args) > 0 {
+						for _, arg := range args {
+							deactivated[objectName(arg)] = true
+						}
+					} else {
+						all = append(all, ast.Name(f))
+					}
+				}
+				return true
+			})
+			return false
+		})
+	}
+	deactivators := newCallGraph(pass.Files).callers(all...)
+
+	for _, mod := range pass.Modules() {
+		ast.WalkModuleDefs(func(def *ast.ModuleDef) bool {
+			f, ok := def.Def.(*ast.FuncDecl)
+			if !ok {
+				return true
+			}
+
+			// Default references stored in variables and the position
+			// of the last deactivation of all defaults.
+			stored := make(map[ast.Node]string)
+			var (
+				activations []*ast.CallExpr
+				last        loc.Pos
+			)
+			ast.Inspect(f, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.Declarator:
+					stored[n.Value] = ast.Name(n.Name)
+				case *ast.BinaryExpr:
+					if n.Op.Kind == token.ASSIGN {
+						stored[n.Y] = objectName(n.X)
+					}
+				case *ast.ExprStmt:
+					if ast.Name(n.Expr) == "deactivate" && len(deactivateArgs(n)) == 0 {
+						last = n.Pos()
+					}
+				case *ast.CallExpr:
+					if ast.Name(n.Fun) == "activate" {
+						activations = append(activations, n)
+					}
+					if deactivators[callee(n)] {
+						last = n.Pos()
+					}
+				}
+				return true
+			})
+
+			for _, c := range activations {
+				if c.Pos() < last {
+					continue
+				}
+				if name := stored[c]; name == "" || !deactivated[name] {
+					pass.Reportf(mod.Tree, c, "activated default is never deactivated")
+				}
+			}
+			return true
+		}, mod.Node)
+	}
+	return nil
+}
+
+// deactivateArgs returns the arguments of deactivate-statement s. Statements
+// deactivating all defaults have no arguments.
+func deactivateArgs(s *ast.ExprStmt) []ast.Expr {
+	if c, ok := s.Expr.(*ast.CallExpr); ok && c.Args != nil {
+		return c.Args.List
+	}
+	return nil
+}
+
+// RequireTimerHandling reports timers which are started but never stopped
+// nor awaited.
+var RequireTimerHandling = &analysis.Analyzer{
+	Name: "require_timer_handling",
+	Doc: `Started timers must be stopped or awaited.
+
+Timers are matched by name: a timer is considered handled, if a timer with the
+same name is used in any timeout- or stop-operation of the test suite.
+
+Example:
+
+	require_timer_handling: true`,
+	Config: func() interface{} { return new(bool) },
+	Suite:  true,
+	Run:    runRequireTimerHandling,
+}
+
+func runRequireTimerHandling(pass *analysis.Pass) error {
+	if !*pass.Config.(*bool) {
+		return nil
+	}
+
+	timers := make(map[string]bool)
+	handled := make(map[string]bool)
+	for _, tree := range pass.Files {
+		ast.Inspect(tree.Root, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueDecl:
+				if n.Kind.Kind == token.TIMER {
+					for _, d := range n.Decls {
+						timers[ast.Name(d.Name)] = true
+					}
+				}
+			case ast.Expr:
+				if x, op := operation(n); op == "timeout" || op == "stop" {
+					handled[objectName(x)] = true
+				}
+			}
+			return true
+		})
+	}
+	if handled["any timer"] || handled["all timer"] {
+		return nil
+	}
+
+	for _, mod := range pass.Modules() {
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			e, ok := n.(ast.Expr)
+			if !ok {
+				return true
+			}
+			if x, op := operation(e); op == "start" {
+				if name := objectName(x); timers[name] && !handled[name] {
+					pass.Reportf(mod.Tree, n, "timer %q is started but never stopped or awaited", name)
+				}
+				return false
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// operation returns the object and the name of operations like "p.receive(x)"
+// or "t.timeout". Operation is empty if e is not an operation.
+func operation(e ast.Expr) (object ast.Expr, operation string) {
+	if r, ok := e.(*ast.RedirectExpr); ok {
+		e = r.X
+	}
+	if c, ok := e.(*ast.CallExpr); ok {
+		e = c.Fun
+	}
+	if s, ok := e.(*ast.SelectorExpr); ok && s.X != nil {
+		return s.X, ast.Name(s.Sel)
+	}
+	return nil, ""
+}
+
+// objectName returns the name of an object reference like "p[i]" without
+// indices.
+func objectName(e ast.Expr) string {
+	for {
+		x, ok := e.(*ast.IndexExpr)
+		if !ok {
+			return ast.Name(e)
+		}
+		e = x.X
+	}
+}
+
+// callGraph maps behaviour names to the names of behaviours they call. Calls
+// are resolved by name only.
+type callGraph map[string]map[string]bool
+
+func newCallGraph(trees []*ttcn3.Tree) callGraph {
+	g := make(callGraph)
+	for _, tree := range trees {
+		ast.Inspect(tree.Root, func(n ast.Node) bool {
+			f, ok := n.(*ast.FuncDecl)
+			if !ok {
+				return true
+			}
+			name := ast.Name(f)
+			if g[name] == nil {
+				g[name] = make(map[string]bool)
+			}
+			for callee := range calls(f) {
+				g[name][callee] = true
+			}
+			return false
+		})
+	}
+	return g
+}
+
+// callers returns the names of the behaviours calling any of the given names,
+// directly or indirectly, including the names themselves.
+func (g callGraph) callers(names ...string) map[string]bool {
+	result := make(map[string]bool)
+	for _, name := range names {
+		result[name] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for caller, callees := range g {
+			if result[caller] {
+				continue
+			}
+			for callee := range callees {
+				if result[callee] {
+					result[caller] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+// callees returns the names of the behaviours called by name, directly or
+// indirectly, including name itself.
+func (g callGraph) callees(name string) map[string]bool {
+	result := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		for callee := range g[queue[0]] {
+			if !result[callee] {
+				result[callee] = true
+				queue = append(queue, callee)
+			}
+		}
+		queue = queue[1:]
+	}
+	return result
+}
+
+// calls returns the names of behaviours called by n. Qualified names are
+// reduced to the unqualified name.
+func calls(n ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(n, func(n ast.Node) bool {
+		if c, ok := n.(*ast.CallExpr); ok {
+			if name := callee(c); name != "" {
+				names[name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// callee returns the unqualified name of the behaviour called by c.
+func callee(c *ast.CallExpr) string {
+	switch fun := c.Fun.(type) {
+	case *ast.Ident:
+		return ast.Name(fun)
+	case *ast.SelectorExpr:
+		return ast.Name(fun.Sel)
+	}
+	return ""
+}
+
+func callsAny(n ast.Node, names map[string]bool) bool {
+	for name := range calls(n) {
+		if names[name] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package passes
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/ast"
+)
+
+// ClonesConfig configures the Clones analyzer.
+type ClonesConfig struct {
+	// MinSize is the number of AST nodes a template or behaviour must
+	// have to be checked for clones.
+	MinSize int `yaml:"min_size"`
+}
+
+// Clones reports duplicate templates and behaviours.
+var Clones = &analysis.Analyzer{
+	Name: "clones",
+	Doc: `Templates and behaviours must not be duplicated.
+
+Definitions are compared by the structure of their syntax trees. Identifiers
+and literals are ignored, hence definitions differing only in names or field
+values are clones, too. Templates are clones only if they have the same type.
+Only complete definitions with identical structure are reported; similar
+definitions and duplicated parts of definitions are not detected.
+
+    clones.min_size  Number of syntax tree nodes a definition must have
+                     to be checked
+
+Example:
+
+	clones:
+	  min_size: 40`,
+	Config: func() interface{} { return new(ClonesConfig) },
+	Suite:  true,
+	Run:    runClones,
+}
+
+// clone is a definition of a clone group.
+type clone struct {
+	tree *ttcn3.Tree
+	node ast.Node
+	name string
+}
+
+func runClones(pass *analysis.Pass) error {
+	cfg := pass.Config.(*ClonesConfig)
+	if cfg.MinSize == 0 {
+		return nil
+	}
+
+	var keys []string
+	groups := make(map[string][]clone)
+	for _, mod := range pass.Modules() {
+		if pass.Ignore.Module(ast.Name(mod.Node)) {
+			continue
+		}
+		ast.WalkModuleDefs(func(def *ast.ModuleDef) bool {
+			switch n := def.Def.(type) {
+			case *ast.TemplateDecl:
+			case *ast.FuncDecl:
+				if n.Body == nil {
+					return true
+				}
+			default:
+				return true
+			}
+			fp, size := fingerprint(def.Def)
+			if size < cfg.MinSize {
+				return true
+			}
+			key := fmt.Sprintf("%T:%x", def.Def, fp)
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], clone{
+				tree: mod.Tree,
+				node: def.Def,
+				name: ast.Name(mod.Node) + "." + ast.Name(def.Def),
+			})
+			return true
+		}, mod.Node)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		for i, c := range group {
+			var others []string
+			for j, o := range group {
+				if i != j {
+					others = append(others, fmt.Sprintf("%q", o.name))
+				}
+			}
+			pass.Reportf(c.tree, c.node, "%s %q is a clone of %s; %s",
+				kindOf(c.node), c.name, strings.Join(others, ", "), suggestion(c.node))
+		}
+	}
+	return nil
+}
+
+// fingerprint returns a hash of the normalised syntax tree of n and the number
+// of its nodes. Identifiers and literals are normalised to placeholders, except
+// the type of templates: templates of different types are not interchangeable.
+func fingerprint(n ast.Node) ([sha256.Size]byte, int) {
+	var (
+		b    strings.Builder
+		size int
+	)
+	ast.Inspect(n, func(n ast.Node) bool {
+		if n == nil {
+			b.WriteString(")")
+			return false
+		}
+		size++
+		switch n := n.(type) {
+		case *ast.Ident:
+			b.WriteString("ID")
+			return false
+		case *ast.ValueLiteral:
+			b.WriteString("LIT")
+			return false
+		case *ast.BinaryExpr:
+			fmt.Fprintf(&b, "(%T %s", n, n.Op.Kind)
+		case *ast.UnaryExpr:
+			fmt.Fprintf(&b, "(%T %s", n, n.Op.Kind)
+		case *ast.FuncDecl:
+			fmt.Fprintf(&b, "(%T %s", n, n.Kind.Kind)
+		case *ast.TemplateDecl:
+			fmt.Fprintf(&b, "(%T %s", n, ast.Name(n.Type))
+		case *ast.ValueDecl:
+			fmt.Fprintf(&b, "(%T %s", n, n.Kind.Kind)
+		case *ast.BranchStmt:
+			fmt.Fprintf(&b, "(%T %s", n, n.Tok.Kind)
+		case *ast.AltStmt:
+			fmt.Fprintf(&b, "(%T %s", n, n.Tok.Kind)
+		default:
+			fmt.Fprintf(&b, "(%T", n)
+		}
+		return true
+	})
+	return sha256.Sum256([]byte(b.String())), size
+}
+
+func kindOf(n ast.Node) string {
+	if f, ok := n.(*ast.FuncDecl); ok {
+		return f.Kind.String()
+	}
+	return "template"
+}
+
+func suggestion(n ast.Node) string {
+	if _, ok := n.(*ast.TemplateDecl); ok {
+		return "consider a parameterised template or a modifies base"
+	}
+	return "consider a parameterised " + kindOf(n)
+}
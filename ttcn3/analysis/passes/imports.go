@@ -0,0 +1,234 @@
+package passes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/ast"
+)
+
+// ImportsConfig configures the Imports analyzer.
+type ImportsConfig struct {
+	// Forbidden maps regular expressions of importing modules to regular
+	// expressions of imported modules and the message reported for such
+	// an import. An exclamation mark inverts a match.
+	Forbidden map[string]map[string]string
+
+	// Cycles reports import cycles.
+	Cycles bool
+
+	// MaxFanIn is the number of modules a module may be imported by.
+	MaxFanIn int `yaml:"max_fan_in"`
+
+	// MaxFanOut is the number of modules a module may import.
+	MaxFanOut int `yaml:"max_fan_out"`
+}
+
+// Imports enforces the architecture of module imports.
+var Imports = &analysis.Analyzer{
+	Name: "imports",
+	Doc: `Module imports must follow architecture and layering rules.
+
+    imports.forbidden    Forbidden imports between modules
+    imports.cycles       Checks for import cycles
+    imports.max_fan_in   Number of modules a module may be imported by
+    imports.max_fan_out  Number of modules a module may import
+
+Example:
+
+	imports:
+	  forbidden:
+	    "^Lib_":
+	      "^TC_": "library modules must not import test modules"
+	    "^Lib_Common_":
+	      # An exclamation mark inverts the match.
+	      "!^Lib_Common_": "common library modules must only import common library modules"
+	  cycles: true
+	  max_fan_in: 50
+	  max_fan_out: 20`,
+	Config:   func() interface{} { return new(ImportsConfig) },
+	Requires: analysis.Imports,
+	Suite:    true,
+	Run:      runImports,
+}
+
+func runImports(pass *analysis.Pass) error {
+	cfg := pass.Config.(*ImportsConfig)
+	var patterns []map[string]string
+	for from, to := range cfg.Forbidden {
+		patterns = append(patterns, map[string]string{from: ""}, to)
+	}
+	if err := analysis.CompilePatterns(patterns...); err != nil {
+		return err
+	}
+
+	importers := make(map[string]map[string]bool)
+	for mod, imports := range pass.Imports {
+		for _, imported := range imports {
+			if importers[imported] == nil {
+				importers[imported] = make(map[string]bool)
+			}
+			importers[imported][mod] = true
+		}
+	}
+
+	var cycles map[string][]string
+	if cfg.Cycles {
+		cycles = importCycles(pass.Imports)
+	}
+
+	for _, mod := range pass.Modules() {
+		name := ast.Name(mod.Node)
+		if pass.Ignore.Module(name) {
+			continue
+		}
+
+		if n := len(importers[name]); cfg.MaxFanIn > 0 && n > cfg.MaxFanIn {
+			pass.Reportf(mod.Tree, mod.Node, "module %q must not be imported by more than %d modules (%d)", name, cfg.MaxFanIn, n)
+		}
+		if n := len(pass.Imports[name]); cfg.MaxFanOut > 0 && n > cfg.MaxFanOut {
+			pass.Reportf(mod.Tree, mod.Node, "module %q must not import more than %d modules (%d)", name, cfg.MaxFanOut, n)
+		}
+
+		cycle := cycles[name]
+		ast.WalkModuleDefs(func(def *ast.ModuleDef) bool {
+			imp, ok := def.Def.(*ast.ImportDecl)
+			if !ok {
+				return true
+			}
+			imported := ast.Name(imp.Module)
+			for from, to := range cfg.Forbidden {
+				if !analysis.Match(from, name) {
+					continue
+				}
+				for p, msg := range to {
+					if analysis.Match(p, imported) {
+						pass.Reportf(mod.Tree, def, "%s", msg)
+					}
+				}
+			}
+			if len(cycle) > 1 && cycle[1] == imported {
+				pass.Reportf(mod.Tree, def, "import cycle: %s", strings.Join(cycle, " -> "))
+				cycle = nil
+			}
+			return true
+		}, mod.Node)
+	}
+	return nil
+}
+
+// importCycles returns a cycle for every set of modules importing each other.
+// The cycle is keyed by its first module, which is the lexically smallest
+// module of the set. The cycle starts and ends with this module.
+func importCycles(g analysis.ImportGraph) map[string][]string {
+	cycles := make(map[string][]string)
+	for _, scc := range stronglyConnected(g) {
+		sort.Strings(scc)
+		start := scc[0]
+		if len(scc) == 1 && !contains(g[start], start) {
+			continue
+		}
+		in := make(map[string]bool)
+		for _, m := range scc {
+			in[m] = true
+		}
+		cycles[start] = shortestCycle(g, start, in)
+	}
+	return cycles
+}
+
+// shortestCycle returns the shortest path from start back to start using only
+// modules in set in.
+func shortestCycle(g analysis.ImportGraph, start string, in map[string]bool) []string {
+	prev := make(map[string]string)
+	queue := []string{start}
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+		for _, next := range g[m] {
+			if !in[next] {
+				continue
+			}
+			if next == start {
+				path := []string{start}
+				for ; m != start; m = prev[m] {
+					path = append([]string{m}, path...)
+				}
+				return append([]string{start}, path...)
+			}
+			if _, ok := prev[next]; !ok {
+				prev[next] = m
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// stronglyConnected returns the strongly connected components of g using
+// Tarjan's algorithm.
+func stronglyConnected(g analysis.ImportGraph) [][]string {
+	var (
+		index   = make(map[string]int)
+		low     = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   []string
+		result  [][]string
+	)
+
+	var visit func(m string)
+	visit = func(m string) {
+		index[m] = len(index)
+		low[m] = index[m]
+		stack = append(stack, m)
+		onStack[m] = true
+
+		for _, next := range g[m] {
+			if _, ok := index[next]; !ok {
+				visit(next)
+				if low[next] < low[m] {
+					low[m] = low[next]
+				}
+			} else if onStack[next] && index[next] < low[m] {
+				low[m] = index[next]
+			}
+		}
+
+		if low[m] == index[m] {
+			var scc []string
+			for {
+				n := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[n] = false
+				scc = append(scc, n)
+				if n == m {
+					break
+				}
+			}
+			result = append(result, scc)
+		}
+	}
+
+	// Visit modules in a stable order for deterministic results.
+	var mods []string
+	for m := range g {
+		mods = append(mods, m)
+	}
+	sort.Strings(mods)
+	for _, m := range mods {
+		if _, ok := index[m]; !ok {
+			visit(m)
+		}
+	}
+	return result
+}
+
+func contains(s []string, x string) bool {
+	for _, y := range s {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,354 @@
+package passes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/doc"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// NamingConfig configures the Naming analyzer. Each field maps regular
+// expressions to the message reported for identifiers not matching. An
+// exclamation mark inverts a match.
+type NamingConfig struct {
+	Modules         map[string]string
+	Tests           map[string]string
+	Functions       map[string]string
+	Altsteps        map[string]string
+	Parameters      map[string]string
+	ComponentVars   map[string]string `yaml:"component_vars"`
+	VarTemplates    map[string]string `yaml:"var_templates"`
+	PortTypes       map[string]string `yaml:"port_types"`
+	Ports           map[string]string
+	GlobalConsts    map[string]string `yaml:"global_consts"`
+	ComponentConsts map[string]string `yaml:"component_consts"`
+	Templates       map[string]string
+	Locals          map[string]string
+}
+
+// Naming checks identifiers against naming conventions.
+var Naming = &analysis.Analyzer{
+	Name: "naming",
+	Doc: `Identifiers must follow naming conventions.
+
+    naming.modules            Checks for module identifiers.
+    naming.tests              Checks for test-case identifier.
+    naming.functions          Checks for function identifiers.
+    naming.altsteps           Checks for altstep identifiers.
+    naming.parameters         Checks for parameter identifiers.
+    naming.component_vars     Checks for component variable identifiers.
+    naming.var_templates      Checks for variable template identifiers.
+    naming.port_types         Checks for port type identifiers.
+    naming.ports              Checks for port instance identifiers.
+    naming.global_consts      Checks for global constant identifiers.
+    naming.component_consts   Checks for component scoped constant identifiers.
+    naming.templates          Checks for constant template identifiers.
+    naming.locals             Checks for local variable identifiers.
+
+Example:
+
+	naming:
+	  tests:
+	    # An exlamation mark inverts the match.
+	    "!.{130,}": "testcase identifiers must not be longer than 130 characters"
+	  functions:
+	    "^[a-z]"      : "function identifiers must begin with a lower case letter"
+	    "!^(f|func)_" : "function identifiers must not begin with f_ or func_"
+	  global_consts:
+	    "^[A-Z0-9_]+$": "global constants must be UPPER_CASE"`,
+	Config: func() interface{} { return new(NamingConfig) },
+	NoLint: []string{"TemplateDef"},
+	Run:    runNaming,
+}
+
+func runNaming(pass *analysis.Pass) error {
+	cfg := pass.Config.(*NamingConfig)
+	if err := analysis.CompilePatterns(
+		cfg.Modules, cfg.Tests, cfg.Functions, cfg.Altsteps, cfg.Parameters,
+		cfg.ComponentVars, cfg.VarTemplates, cfg.PortTypes, cfg.Ports,
+		cfg.GlobalConsts, cfg.ComponentConsts, cfg.Templates, cfg.Locals,
+	); err != nil {
+		return err
+	}
+
+	for _, mod := range pass.Modules() {
+		tree := mod.Tree
+		check := func(n ast.Node, patterns map[string]string) {
+			checkPatterns(pass, tree, n, patterns, nil, ast.Name(n))
+		}
+
+		// checkLocal checks local variables, which may be renamed,
+		// because all references are inside scope. Parameters are not
+		// renamed, because named actual parameters of callers
+		// reference them, too.
+		checkLocal := func(n ast.Node, id *ast.Ident, scope ast.Node, patterns map[string]string) {
+			fix := func(p string) []analysis.SuggestedFix {
+				if scope == nil {
+					return nil
+				}
+				return prefixFix(tree, scope, id, p)
+			}
+			checkPatterns(pass, tree, n, patterns, fix, ast.Name(n))
+		}
+
+		stack := make([]ast.Node, 0, 64)
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			if n == nil {
+				stack = stack[:len(stack)-1]
+				return false
+			}
+
+			switch n := n.(type) {
+			case *ast.Module:
+				check(n, cfg.Modules)
+
+			case *ast.FuncDecl:
+				switch n.Kind.Kind {
+				case token.TESTCASE:
+					check(n, cfg.Tests)
+				case token.FUNCTION:
+					check(n, cfg.Functions)
+				case token.ALTSTEP:
+					check(n, cfg.Altsteps)
+				}
+
+			case *ast.FormalPar:
+				check(n, cfg.Parameters)
+
+			case *ast.PortTypeDecl:
+				check(n, cfg.PortTypes)
+
+			case *ast.TemplateDecl:
+				check(n, cfg.Templates)
+
+			case *ast.Declarator:
+				if len(stack) < 2 {
+					break
+				}
+
+				// The parent of a declarator should always be
+				// a ValueDecl. If not, we have some internal
+				// issues, it's okay to panic then.
+				parent := stack[len(stack)-1].(*ast.ValueDecl)
+				scope := stack[:len(stack)-1]
+
+				switch {
+				case isPort(parent):
+					check(n, cfg.Ports)
+				case isConst(parent):
+					switch {
+					case inGlobalScope(scope):
+						check(n, cfg.GlobalConsts)
+					case inComponentScope(scope):
+						check(n, cfg.ComponentConsts)
+					}
+				case isVarTemplate(parent):
+					check(n, cfg.VarTemplates)
+				case isVar(parent):
+					switch {
+					case inComponentScope(scope):
+						check(n, cfg.ComponentVars)
+					default:
+						checkLocal(n, n.Name, enclosingFunc(stack), cfg.Locals)
+					}
+				}
+			}
+
+			stack = append(stack, n)
+			return true
+		})
+	}
+	return nil
+}
+
+// TagsConfig configures the Tags analyzer.
+type TagsConfig struct {
+	// Tests maps regular expressions to the message reported for test
+	// cases without matching documentation tag.
+	Tests map[string]string
+}
+
+// Tags checks the documentation tags of test cases.
+var Tags = &analysis.Analyzer{
+	Name: "tags",
+	Doc: `Test cases must have documentation tags.
+
+    tags.tests                Checks for test-case tags.
+
+Example:
+
+	tags:
+	  tests:
+	    "@author": "testcases must have a @author tag"`,
+	Config: func() interface{} { return new(TagsConfig) },
+	NoLint: []string{"TemplateDef"},
+	Run:    runTags,
+}
+
+func runTags(pass *analysis.Pass) error {
+	cfg := pass.Config.(*TagsConfig)
+	if len(cfg.Tests) == 0 {
+		return nil
+	}
+	if err := analysis.CompilePatterns(cfg.Tests); err != nil {
+		return err
+	}
+	for _, mod := range pass.Modules() {
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			if n, ok := n.(*ast.FuncDecl); ok && n.Kind.Kind == token.TESTCASE {
+				var tags []string
+				for _, t := range doc.FindAllTags(ast.FirstToken(n).Comments()) {
+					tags = append(tags, strings.Join(t, ":"))
+				}
+				checkPatterns(pass, mod.Tree, n, cfg.Tests, nil, tags...)
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// checkPatterns reports the message of every pattern not matching any of ss.
+// Function fix, if not nil, suggests fixes for a violated pattern.
+func checkPatterns(pass *analysis.Pass, tree *ttcn3.Tree, n ast.Node, patterns map[string]string, fix func(p string) []analysis.SuggestedFix, ss ...string) {
+next:
+	for p, msg := range patterns {
+		for _, s := range ss {
+			if analysis.Match(p, s) {
+				continue next
+			}
+		}
+		d := analysis.Diagnostic{
+			Pos:     tree.Position(n.Pos()),
+			End:     tree.Position(n.End()),
+			Message: msg,
+			Node:    n,
+		}
+		if fix != nil {
+			d.SuggestedFixes = fix(p)
+		}
+		pass.Report(d)
+	}
+}
+
+// prefixFix suggests to prepend the literal prefix required by pattern p,
+// like "^v_", to identifier id and all its references inside scope. There is
+// no fix if the prefixed name does not match the pattern either.
+func prefixFix(tree *ttcn3.Tree, scope ast.Node, id *ast.Ident, p string) []analysis.SuggestedFix {
+	if !strings.HasPrefix(p, "^") {
+		return nil
+	}
+	r, err := regexp.Compile(p[1:])
+	if err != nil {
+		return nil
+	}
+	prefix, _ := r.LiteralPrefix()
+	name := id.String()
+	newName := prefix + name
+	if prefix == "" || !analysis.Match(p, newName) {
+		return nil
+	}
+
+	var edits []analysis.TextEdit
+	for _, ref := range references(scope, name) {
+		// The reference is replaced only below a wrapper root, which
+		// keeps the shared syntax tree intact.
+		renamed := ast.Apply(ref, func(c *ast.Cursor) bool {
+			c.Replace(&ast.Ident{Tok: ast.NewToken(ref.Pos(), token.IDENT, newName)})
+			return false
+		}, nil)
+		text, err := analysis.Print(tree, renamed, "")
+		if err != nil {
+			return nil
+		}
+		edits = append(edits, analysis.ReplaceNode(tree, ref, text))
+	}
+	return []analysis.SuggestedFix{{
+		Message:   fmt.Sprintf("Rename %q to %q", name, newName),
+		TextEdits: edits,
+	}}
+}
+
+// references returns the identifiers named name inside scope. Field names of
+// selectors and of assignments in composite literals or named actual
+// parameters are not references.
+func references(scope ast.Node, name string) []*ast.Ident {
+	skip := make(map[ast.Node]bool)
+	var refs []*ast.Ident
+	ast.Inspect(scope, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			skip[n.Sel] = true
+		case *ast.CompositeLiteral:
+			skipAssigned(skip, n.List)
+		case *ast.CallExpr:
+			if n.Args != nil {
+				skipAssigned(skip, n.Args.List)
+			}
+		case *ast.Ident:
+			if !skip[n] && n.String() == name {
+				refs = append(refs, n)
+			}
+		}
+		return true
+	})
+	return refs
+}
+
+func skipAssigned(skip map[ast.Node]bool, list []ast.Expr) {
+	for _, e := range list {
+		if b, ok := e.(*ast.BinaryExpr); ok && b.Op.Kind == token.ASSIGN {
+			skip[b.X] = true
+		}
+	}
+}
+
+func enclosingFunc(stack []ast.Node) ast.Node {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if f, ok := stack[i].(*ast.FuncDecl); ok {
+			return f
+		}
+	}
+	return nil
+}
+
+func inComponentScope(stack []ast.Node) bool {
+	for _, n := range stack {
+		if _, ok := n.(*ast.ComponentTypeDecl); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func inGlobalScope(stack []ast.Node) bool {
+	for _, n := range stack {
+		switch n.(type) {
+		case *ast.Module, *ast.ModuleDef, *ast.GroupDecl, *ast.ModuleParameterGroup:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isPort(d *ast.ValueDecl) bool {
+	return d.Kind.Kind == token.PORT
+}
+
+func isConst(d *ast.ValueDecl) bool {
+	return d.Kind.Kind == token.CONST
+}
+
+func isVar(d *ast.ValueDecl) bool {
+	return !isVarTemplate(d) && d.Kind.Kind == token.VAR
+}
+
+func isVarTemplate(d *ast.ValueDecl) bool {
+	return d.TemplateRestriction != nil
+}
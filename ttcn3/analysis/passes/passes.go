@@ -0,0 +1,26 @@
+// Package passes provides the built-in analyzers of ntt lint. Importing the
+// package registers them.
+package passes
+
+import "github.com/nokia/ntt/ttcn3/analysis"
+
+func init() {
+	analysis.Register(
+		MaxLines,
+		AlignedBraces,
+		RequireCaseElse,
+		Complexity,
+		Naming,
+		Tags,
+		Usage,
+		Unused,
+		RequireAltGuard,
+		RequirePortConnection,
+		RequireVerdict,
+		UnreachableCode,
+		RequireDeactivate,
+		RequireTimerHandling,
+		Imports,
+		Clones,
+	)
+}
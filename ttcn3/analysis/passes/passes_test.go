@@ -0,0 +1,369 @@
+package passes_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/analysis/passes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lint(t *testing.T, a *analysis.Analyzer, config string, src string) []string {
+	t.Helper()
+	cfg, err := analysis.ParseConfig([]byte(config), a)
+	require.Nil(t, err)
+
+	file := fmt.Sprintf("%s_%s.ttcn3", t.Name(), a.Name)
+	fs.Open(file).SetBytes([]byte(src))
+
+	r := analysis.Runner{Analyzers: []*analysis.Analyzer{a}, Config: cfg}
+	diags, err := r.Run(file)
+	require.Nil(t, err)
+
+	var s []string
+	for _, d := range diags {
+		s = append(s, fmt.Sprintf("%d:%d: %s", d.Pos.Line, d.Pos.Column, d.Message))
+	}
+	return s
+}
+
+func TestMaxLines(t *testing.T) {
+	src := `module M {
+function f() {
+}
+function g() {
+
+}
+// NOLINT(CodeStatistics.TooLong)
+function h() {
+
+}
+}`
+	assert.Equal(t, []string{`4:1: "g" must not have more than 1 lines (2)`},
+		lint(t, passes.MaxLines, "max_lines: 1", src))
+	assert.Nil(t, lint(t, passes.MaxLines, "max_lines: 0", src))
+}
+
+func TestAlignedBraces(t *testing.T) {
+	src := `module M
+{
+function f() {
+  }
+function g()
+{
+}
+}`
+	assert.Equal(t, []string{"4:3: braces must be in the same line or same column"},
+		lint(t, passes.AlignedBraces, "aligned_braces: true", src))
+	assert.Nil(t, lint(t, passes.AlignedBraces, "aligned_braces: false", src))
+}
+
+func TestRequireCaseElse(t *testing.T) {
+	src := `module M {
+function f(integer x) {
+	select (x) { case (1) {} }
+	select (x) { case (1) {} case else {} }
+}
+}`
+	assert.Equal(t, []string{"3:2: missing case else in select statement"},
+		lint(t, passes.RequireCaseElse, "require_case_else: true", src))
+}
+
+func TestComplexity(t *testing.T) {
+	src := `module M {
+function f(boolean a := x or y) {
+	if (a and b) {}
+	select (a) { case (true) {} case else {} }
+}
+function g() {
+	alt { [] p.receive {} [a] p.receive {} [else] {} }
+}
+}`
+	assert.Equal(t, []string{
+		`2:1: cyclomatic complexity of "f" (4) must not be higher than 3`,
+		`6:1: cyclomatic complexity of "g" (4) must not be higher than 3`,
+	}, lint(t, passes.Complexity, "complexity: {max: 3}", src))
+	assert.Equal(t, []string{
+		`2:1: cyclomatic complexity of "f" (4) must not be higher than 3`,
+	}, lint(t, passes.Complexity, "complexity: {max: 3, ignore_guards: true}", src))
+}
+
+func TestNaming(t *testing.T) {
+	src := `module M {
+const integer c := 1;
+type component C {
+	var integer v;
+	const integer C_OK := 1;
+}
+function F_bad(integer X) {
+	var integer Y;
+}
+}`
+	config := `
+naming:
+  global_consts: {"^[A-Z]+$": "global constants must be upper case"}
+  component_consts: {"^C_": "component constants must begin with C_"}
+  component_vars: {"^v_": "component variables must begin with v_"}
+  functions:
+    "^[a-z]": "functions must begin with a lower case letter"
+  parameters: {"^[a-z]": "parameters must begin with a lower case letter"}
+  locals: {"!^[A-Z]": "locals must not begin with an upper case letter"}
+`
+	assert.Equal(t, []string{
+		"2:15: global constants must be upper case",
+		"4:14: component variables must begin with v_",
+		"7:1: functions must begin with a lower case letter",
+		"7:16: parameters must begin with a lower case letter",
+		"8:14: locals must not begin with an upper case letter",
+	}, lint(t, passes.Naming, config, src))
+}
+
+func TestTags(t *testing.T) {
+	src := `module M {
+// @author alice
+testcase a() runs on C {}
+testcase b() runs on C {}
+}`
+	assert.Equal(t, []string{"4:1: testcases must have a @author tag"},
+		lint(t, passes.Tags, `tags: {tests: {"^@author": "testcases must have a @author tag"}}`, src))
+}
+
+func TestUsage(t *testing.T) {
+	src := `module M {
+function f() { foo(); foo(); foo(); bar(); }
+}`
+	assert.Equal(t, []string{
+		`2:23: "foo" must not be used more than 2 times. Use bar.`,
+		`2:30: "foo" must not be used more than 2 times. Use bar.`,
+	}, lint(t, passes.Usage, "usage: {foo: {limit: 2, text: Use bar.}}", src))
+}
+
+func TestUnusedDefinitions(t *testing.T) {
+	src := `module M {
+const integer a := 1, b := 2;
+template integer t := a;
+function f() { log(t) }
+function g() {}
+// NOLINT(unused)
+function h() {}
+testcase tc() { f() }
+}`
+	assert.Equal(t, []string{
+		`2:23: "b" is not used by any test case or control part`,
+		`5:10: "g" is not used by any test case or control part`,
+	}, lint(t, passes.Unused, "unused: {definitions: true}", src))
+}
+
+func TestRequireAltGuard(t *testing.T) {
+	src := `module M {
+function f() {
+	alt { [] p.receive {} }
+	alt { [] p.receive {} [] t.timeout {} }
+	alt { [] p.receive {} [else] {} }
+	alt { [] p.receive {} [] as_guard() }
+	interleave { [] p.receive {} }
+}
+}`
+	assert.Equal(t, []string{
+		"3:2: alt statement without timeout or else guard may block forever",
+	}, lint(t, passes.RequireAltGuard, "require_alt_guard: true", src))
+}
+
+func TestRequirePortConnection(t *testing.T) {
+	src := `module M {
+type component C { port P p1, p2, p3[2] }
+testcase tc1() runs on C {
+	map(self:p2, system:p2);
+	init();
+	f();
+}
+testcase tc2() runs on C {
+	init();
+	var C c := C.create;
+	c.start(f());
+}
+function init() runs on C {
+	map(self:p1, system:p1);
+	connect(self:p3[0], mtc:p3[0]);
+}
+function f() runs on C {
+	p1.receive;
+	p2.receive(integer:?) -> value x;
+	p3[1].receive;
+	any port.receive;
+}
+function g() runs on C { p2.receive }
+}`
+	assert.Equal(t, []string{
+		`19:2: port "p2" is never mapped or connected in testcase "tc2"`,
+	}, lint(t, passes.RequirePortConnection, "require_port_connection: true", src))
+}
+
+func TestRequireVerdict(t *testing.T) {
+	src := `module M {
+testcase tc1() { setverdict(pass) }
+testcase tc2() { f() }
+testcase tc3() { g() }
+function f() { M.h() }
+function g() {}
+function h() { setverdict(fail) }
+}`
+	assert.Equal(t, []string{
+		`4:1: testcase "tc3" never sets a verdict`,
+	}, lint(t, passes.RequireVerdict, "require_verdict: true", src))
+}
+
+func TestUnreachableCode(t *testing.T) {
+	src := `module M {
+function f() return integer {
+	alt { [] t.timeout { repeat; log(1) } }
+	goto L;
+	label L;
+	stop;
+	log(2);
+	log(3);
+	return 1;
+}
+}`
+	assert.Equal(t, []string{
+		"3:31: unreachable code",
+		"7:2: unreachable code",
+	}, lint(t, passes.UnreachableCode, "unreachable_code: true", src))
+}
+
+func TestRequireDeactivate(t *testing.T) {
+	src := `module M {
+function f() {
+	var default d1 := activate(as());
+	var default d2;
+	d2 := activate(as());
+	activate(as());
+	deactivate(d1);
+}
+function g() {
+	activate(as());
+	cleanup();
+}
+function cleanup() { deactivate }
+function h() {
+	deactivate;
+	var default d := activate(as());
+	cleanup();
+	activate(as());
+}
+}`
+	assert.Equal(t, []string{
+		"5:8: activated default is never deactivated",
+		"6:2: activated default is never deactivated",
+		"18:2: activated default is never deactivated",
+	}, lint(t, passes.RequireDeactivate, "require_deactivate: true", src))
+}
+
+func TestRequireTimerHandling(t *testing.T) {
+	src := `module M {
+type component C { timer t1 }
+function f() runs on C {
+	timer t2, t3[2];
+	t1.start(1.0);
+	t2.start;
+	t3[0].start;
+	v_ptc.start(g());
+	t3[1].stop;
+}
+altstep as() runs on C { [] t1.timeout {} }
+}`
+	assert.Equal(t, []string{
+		`6:2: timer "t2" is started but never stopped or awaited`,
+	}, lint(t, passes.RequireTimerHandling, "require_timer_handling: true", src))
+}
+
+func TestImports(t *testing.T) {
+	src := `module Lib_A { import from TC_X all; import from Lib_B all }
+module Lib_B { import from Lib_C all }
+module Lib_C { import from Lib_A all; import from Lib_B all }
+module TC_X { import from Lib_B all }`
+
+	assert.Equal(t, []string{
+		`1:1: module "Lib_A" must not import more than 1 modules (2)`,
+		`1:16: library modules must not import test modules`,
+		`1:38: import cycle: Lib_A -> Lib_B -> Lib_C -> Lib_A`,
+		`2:1: module "Lib_B" must not be imported by more than 2 modules (3)`,
+		`3:1: module "Lib_C" must not import more than 1 modules (2)`,
+	}, lint(t, passes.Imports, `imports: {forbidden: {"^Lib_": {"^TC_": "library modules must not import test modules"}}, cycles: true, max_fan_in: 2, max_fan_out: 1}`, src))
+}
+
+func TestClones(t *testing.T) {
+	src := `module M {
+template R t1 := { a := 1, b := "x", c := { d := true } }
+template R t2 := { a := 2, b := "y", c := { d := false } }
+template R t3 := { a := 1, b := "x" }
+template S t4 := { x := 5, y := "z", z := { w := true } }
+function f(integer x) { if (x > 1) { log(x) } }
+function g(integer y) { if (y > 2) { log(y) } }
+function h(integer y) { if (y < 2) { log(y) } }
+template S t5 := { x := 6, y := "a", z := { w := false } }
+}`
+	assert.Equal(t, []string{
+		`2:1: template "M.t1" is a clone of "M.t2"; consider a parameterised template or a modifies base`,
+		`3:1: template "M.t2" is a clone of "M.t1"; consider a parameterised template or a modifies base`,
+		`5:1: template "M.t4" is a clone of "M.t5"; consider a parameterised template or a modifies base`,
+		`6:1: function "M.f" is a clone of "M.g"; consider a parameterised function`,
+		`7:1: function "M.g" is a clone of "M.f"; consider a parameterised function`,
+		`9:1: template "M.t5" is a clone of "M.t4"; consider a parameterised template or a modifies base`,
+	}, lint(t, passes.Clones, "clones: {min_size: 5}", src))
+	assert.Nil(t, lint(t, passes.Clones, "clones: {min_size: 100}", src))
+}
+
+// fix applies the first suggested fix of every diagnostic to srcs and returns
+// the new content of the first file.
+func fix(t *testing.T, a *analysis.Analyzer, config string, srcs ...string) string {
+	t.Helper()
+	cfg, err := analysis.ParseConfig([]byte(config), a)
+	require.Nil(t, err)
+
+	var files []string
+	for i, src := range srcs {
+		file := fmt.Sprintf("%s_%s_%d.ttcn3", t.Name(), a.Name, i)
+		fs.Open(file).SetBytes([]byte(src))
+		files = append(files, file)
+	}
+
+	r := analysis.Runner{Analyzers: []*analysis.Analyzer{a}, Config: cfg}
+	diags, err := r.Run(files...)
+	require.Nil(t, err)
+
+	var fixes []analysis.SuggestedFix
+	for _, d := range diags {
+		if d.Pos.Filename == files[0] && len(d.SuggestedFixes) > 0 {
+			fixes = append(fixes, d.SuggestedFixes[0])
+		}
+	}
+	b, _ := analysis.ApplyFixes([]byte(srcs[0]), fixes)
+	return string(b)
+}
+
+func TestFixes(t *testing.T) {
+	assert.Equal(t, "module M\n{\n  function f()\n  {\n    // keep\n  }\n}",
+		fix(t, passes.AlignedBraces, "aligned_braces: true", "module M\n{\n  function f()\n  {\n    // keep\n    }\n}"))
+
+	assert.Equal(t, "module M {\nfunction f(integer x) {\n\tselect (x) {\n\tcase (1) {}\n\tcase else {}\n\t}\n\tselect (x) { case (1) {} case else {} }\n\tselect (x) {case else { } }\n}\n}",
+		fix(t, passes.RequireCaseElse, "require_case_else: true", "module M {\nfunction f(integer x) {\n\tselect (x) {\n\tcase (1) {}\n\t}\n\tselect (x) { case (1) {} }\n\tselect (x) {}\n}\n}"))
+	assert.Equal(t, "module M {\n  function f(integer x) {\n    select (x) {\n      case (1) {\n      }\n      case else {\n      }\n    }\n  }\n}",
+		fix(t, passes.RequireCaseElse, "require_case_else: true", "module M {\n  function f(integer x) {\n    select (x) {\n      case (1) {\n      }\n    }\n  }\n}"))
+	assert.Equal(t, "module M {\nfunction f(integer x) {\n\tselect (x) {\n\tcase (1)\n\t{\n\t\tlog(x);\n\t}\n\tcase else\n\t{\n\t}\n\t}\n\tselect (x) { case (1) /* one */ { log(x) } case else { } }\n}\n}",
+		fix(t, passes.RequireCaseElse, "require_case_else: true", "module M {\nfunction f(integer x) {\n\tselect (x) {\n\tcase (1)\n\t{\n\t\tlog(x);\n\t}\n\t}\n\tselect (x) { case (1) /* one */ { log(x) } }\n}\n}"))
+
+	assert.Equal(t, "module M {\n\timport from B all;\n\tconst integer x := b;\n}",
+		fix(t, passes.Unused, "unused: {imports: true}",
+			"module M {\n\timport from A all;\n\timport from B all;\n\tconst integer x := b;\n}",
+			"module A { const integer a := 1 }",
+			"module B { const integer b := 1 }",
+		))
+
+	assert.Equal(t, "module M {\nfunction f(integer x) {\n\tvar integer v_y := x;\n\tv_y := r.y + g(y := x) + { y := v_y }.y;\n}\n}",
+		fix(t, passes.Naming, `naming: {parameters: {"^p_": "p_"}, locals: {"^v_[a-z]": "v_"}}`,
+			"module M {\nfunction f(integer x) {\n\tvar integer y := x;\n\ty := r.y + g(y := x) + { y := y }.y;\n}\n}"))
+}
@@ -0,0 +1,275 @@
+package passes
+
+import (
+	"strings"
+
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/metrics"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// MaxLines reports behaviour bodies exceeding a number of lines.
+var MaxLines = &analysis.Analyzer{
+	Name: "max_lines",
+	Doc: `Number of lines a behaviour body must not exceed.
+
+Example:
+
+	max_lines: 40`,
+	Config: func() interface{} { return new(int) },
+	NoLint: []string{"CodeStatistics.TooLong"},
+	Run:    runMaxLines,
+}
+
+func runMaxLines(pass *analysis.Pass) error {
+	max := *pass.Config.(*int)
+	if max == 0 {
+		return nil
+	}
+	for _, mod := range pass.Modules() {
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			if n, ok := n.(*ast.FuncDecl); ok {
+				begin := mod.Tree.Position(n.Pos())
+				end := mod.Tree.Position(n.End())
+				if lines := end.Line - begin.Line; lines > max {
+					pass.Reportf(mod.Tree, n, "%q must not have more than %d lines (%d)", ast.Name(n), max, lines)
+				}
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// AlignedBraces reports braces which are neither in the same line nor in the
+// same column.
+var AlignedBraces = &analysis.Analyzer{
+	Name: "aligned_braces",
+	Doc: `Braces must be in the same column or same line.
+
+Example:
+
+	aligned_braces: true`,
+	Config: func() interface{} { return new(bool) },
+	Run:    runAlignedBraces,
+}
+
+func runAlignedBraces(pass *analysis.Pass) error {
+	if !*pass.Config.(*bool) {
+		return nil
+	}
+	for _, mod := range pass.Modules() {
+		tree := mod.Tree
+		src, _ := analysis.Source(tree)
+		check := func(left, right ast.Node) {
+			checkBraces(pass, tree, src, left, right)
+		}
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.Module:
+				check(n.LBrace, n.RBrace)
+			case *ast.PortTypeDecl:
+				check(n.LBrace, n.RBrace)
+			case *ast.BlockStmt:
+				check(n.LBrace, n.RBrace)
+			case *ast.CompositeLiteral:
+				check(n.LBrace, n.RBrace)
+			case *ast.ExceptExpr:
+				check(n.LBrace, n.RBrace)
+			case *ast.SelectStmt:
+				check(n.LBrace, n.RBrace)
+			case *ast.StructSpec:
+				check(n.LBrace, n.RBrace)
+			case *ast.EnumSpec:
+				check(n.LBrace, n.RBrace)
+			case *ast.ModuleParameterGroup:
+				check(n.LBrace, n.RBrace)
+			case *ast.StructTypeDecl:
+				check(n.LBrace, n.RBrace)
+			case *ast.EnumTypeDecl:
+				check(n.LBrace, n.RBrace)
+			case *ast.ImportDecl:
+				check(n.LBrace, n.RBrace)
+			case *ast.GroupDecl:
+				check(n.LBrace, n.RBrace)
+			case *ast.WithSpec:
+				check(n.LBrace, n.RBrace)
+			case *ast.ParenExpr:
+				if n.LParen.Kind == token.LBRACE {
+					check(n.LParen, n.RParen)
+				}
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// checkBraces reports unaligned braces. If the right brace begins a line, the
+// suggested fix indents it to the column of the left brace.
+func checkBraces(pass *analysis.Pass, tree *ttcn3.Tree, src []byte, left ast.Node, right ast.Node) {
+	p1 := tree.Position(left.Pos())
+	p2 := tree.Position(right.Pos())
+	if p1.Line == p2.Line || p1.Column == p2.Column {
+		return
+	}
+
+	d := analysis.Diagnostic{
+		Pos:     p2,
+		End:     tree.Position(right.End()),
+		Message: "braces must be in the same line or same column",
+		Node:    right,
+	}
+	if src != nil {
+		if start := analysis.LineStart(src, p2.Offset); start >= 0 {
+			d.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   "Align braces",
+				TextEdits: []analysis.TextEdit{analysis.Replace(tree, start, p2.Offset, analysis.Indentation(src, p1.Offset))},
+			}}
+		}
+	}
+	pass.Report(d)
+}
+
+// RequireCaseElse reports select statements without case else.
+var RequireCaseElse = &analysis.Analyzer{
+	Name: "require_case_else",
+	Doc: `Every select-statement must have one case-else.
+
+Example:
+
+	require_case_else: true`,
+	Config: func() interface{} { return new(bool) },
+	Run:    runRequireCaseElse,
+}
+
+func runRequireCaseElse(pass *analysis.Pass) error {
+	if !*pass.Config.(*bool) {
+		return nil
+	}
+	for _, mod := range pass.Modules() {
+		src, _ := analysis.Source(mod.Tree)
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			if n, ok := n.(*ast.SelectStmt); ok && !hasCaseElse(n) {
+				pass.Report(analysis.Diagnostic{
+					Pos:            mod.Tree.Position(n.Pos()),
+					End:            mod.Tree.Position(n.End()),
+					Message:        "missing case else in select statement",
+					SuggestedFixes: addCaseElse(mod.Tree, src, n),
+					Node:           n,
+				})
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// addCaseElse suggests an empty case else after the last clause of select
+// statement n. The case else follows the style of the preceding clause: the
+// text between its condition and body, and an empty body are reused. Select
+// statements without clauses get the case else before the closing brace.
+func addCaseElse(tree *ttcn3.Tree, src []byte, n *ast.SelectStmt) []analysis.SuggestedFix {
+	if src == nil || !n.RBrace.IsValid() {
+		return nil
+	}
+	offset := func(pos loc.Pos) int { return tree.Position(pos).Offset }
+	edit := func(pos int, text string) []analysis.SuggestedFix {
+		return []analysis.SuggestedFix{{
+			Message:   "Add case else",
+			TextEdits: []analysis.TextEdit{analysis.Replace(tree, pos, pos, text)},
+		}}
+	}
+
+	if len(n.Body) == 0 {
+		return edit(offset(n.RBrace.Pos()), "case else { } ")
+	}
+
+	last := n.Body[len(n.Body)-1]
+	if last.Case == nil || last.Body == nil || !last.Body.LBrace.IsValid() || !last.Body.RBrace.IsValid() {
+		return nil
+	}
+	lbrace, rbrace := offset(last.Body.LBrace.Pos()), offset(last.Body.RBrace.Pos())
+
+	sep := string(src[offset(last.Case.End()):lbrace])
+	if strings.TrimSpace(sep) != "" {
+		sep = " "
+	}
+
+	body := string(src[lbrace : rbrace+1])
+	if strings.TrimSpace(body[1:len(body)-1]) != "" {
+		body = "{ }"
+		if start := analysis.LineStart(src, rbrace); start >= 0 {
+			body = "{\n" + string(src[start:rbrace]) + "}"
+		}
+	}
+
+	text := " case else" + sep + body
+	begin := offset(last.Pos())
+	if start := analysis.LineStart(src, begin); start >= 0 {
+		text = "\n" + string(src[start:begin]) + "case else" + sep + body
+	}
+	return edit(offset(last.End()), text)
+}
+
+func hasCaseElse(n *ast.SelectStmt) bool {
+	for _, c := range n.Body {
+		if c.Case == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ComplexityConfig configures the Complexity analyzer.
+type ComplexityConfig struct {
+	// Max is the cyclomatic complexity a behaviour must not exceed.
+	Max int
+
+	// IgnoreGuards ignores the complexity of alt- and interleave guards.
+	IgnoreGuards bool `yaml:"ignore_guards"`
+}
+
+// Complexity reports behaviours with high cyclomatic complexity.
+var Complexity = &analysis.Analyzer{
+	Name: "complexity",
+	Doc: `Cyclomatic complexity must not exceed a maximum.
+
+    complexity.max           Cyclomatic complexity must not exceed.
+    complexity.ignore_guards Ignore complexity of alt- and interleave guards
+
+Example:
+
+	complexity:
+	  max: 15
+	  ignore_guards: true`,
+	Config: func() interface{} { return new(ComplexityConfig) },
+	NoLint: []string{"CodeStatistics.TooComplex"},
+	Run:    runComplexity,
+}
+
+func runComplexity(pass *analysis.Pass) error {
+	cfg := pass.Config.(*ComplexityConfig)
+	if cfg.Max == 0 {
+		return nil
+	}
+	for _, mod := range pass.Modules() {
+		nodes := []ast.Node{mod.Node}
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			if n, ok := n.(*ast.FuncDecl); ok {
+				nodes = append(nodes, n)
+				return false
+			}
+			return true
+		})
+		for _, n := range nodes {
+			if v := metrics.Complexity(n, cfg.IgnoreGuards); v > cfg.Max {
+				pass.Reportf(mod.Tree, n, "cyclomatic complexity of %q (%d) must not be higher than %d", ast.Name(n), v, cfg.Max)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package passes
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/analysis"
+	"github.com/nokia/ntt/ttcn3/ast"
+)
+
+// UsageLimit limits the usage of an identifier.
+type UsageLimit struct {
+	Text  string
+	Limit int
+}
+
+// Usage reports identifiers used more often than allowed.
+var Usage = &analysis.Analyzer{
+	Name: "usage",
+	Doc: `Identifiers must not be used more often than a limit.
+
+When TTCN-3 code is refactored incrementally, it happens that references to
+legacy code are faster added than one can remove them. This check helps with a
+warning, as soon as the usage of a symbol exceed a defined limit.
+
+Example:
+
+	usage:
+	  "foo":
+	    limit: 12
+	    text: Use "bar" instead.`,
+	Config: func() interface{} { return new(map[string]*UsageLimit) },
+	Suite:  true,
+	Run:    runUsage,
+}
+
+func runUsage(pass *analysis.Pass) error {
+	limits := *pass.Config.(*map[string]*UsageLimit)
+	if len(limits) == 0 {
+		return nil
+	}
+	count := make(map[string]int)
+	for _, mod := range pass.Modules() {
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			name := id.String()
+			u, ok := limits[name]
+			if !ok || u == nil {
+				return true
+			}
+			count[name]++
+			if count[name] >= u.Limit {
+				pass.Reportf(mod.Tree, id, "%q must not be used more than %d times. %s", name, u.Limit, u.Text)
+			}
+			return true
+		})
+	}
+	return nil
+}
+
+// UnusedConfig configures the Unused analyzer.
+type UnusedConfig struct {
+	// Modules reports modules of imported directories, which are not
+	// imported by any module.
+	Modules bool
+
+	// Imports reports import declarations of modules, which do not
+	// provide any identifier used by the importing module.
+	Imports bool
+
+	// Definitions reports module definitions, which are not reachable
+	// from any test case or control part.
+	Definitions bool
+}
+
+// Unused reports unused modules, imports and definitions.
+var Unused = &analysis.Analyzer{
+	Name: "unused",
+	Doc: `Modules, imports and definitions must be used.
+
+    unused.modules      Checks for unused modules
+    unused.imports      Checks for unused imports
+    unused.definitions  Checks for definitions not reachable from any
+                        test case or control part
+
+Example:
+
+	unused:
+	  modules: true
+	  imports: true
+	  definitions: true`,
+	Config:   func() interface{} { return new(UnusedConfig) },
+	Requires: analysis.Imports | analysis.Scopes,
+	Suite:    true,
+	Run:      runUnused,
+}
+
+func runUnused(pass *analysis.Pass) error {
+	cfg := pass.Config.(*UnusedConfig)
+	if cfg.Imports {
+		unusedImports(pass)
+	}
+	if cfg.Modules && pass.Project != nil {
+		unusedModules(pass)
+	}
+	if cfg.Definitions {
+		unusedDefinitions(pass)
+	}
+	return nil
+}
+
+func unusedModules(pass *analysis.Pass) {
+	used := make(map[string]bool)
+	for mod, imports := range pass.Imports {
+		if pass.Ignore.Module(mod) {
+			continue
+		}
+		for _, imported := range imports {
+			used[imported] = true
+		}
+	}
+
+	pkgs, _ := pass.Project.Imports()
+	for _, pkg := range pkgs {
+		files, _ := filepath.Glob(pkg + "/*.ttcn3")
+		for _, file := range files {
+			if pass.Ignore.File(file) {
+				continue
+			}
+
+			mod := filepath.Base(file)
+			mod = strings.TrimSuffix(mod, filepath.Ext(mod))
+			if pass.Ignore.Module(mod) {
+				continue
+			}
+
+			if !used[mod] {
+				pass.Report(analysis.Diagnostic{
+					Pos:     loc.Position{Filename: file},
+					Message: "unused module",
+				})
+			}
+		}
+	}
+}
+
+// unusedImports reports imports of modules not defining any of the
+// identifiers used by the importing module. Modules unknown to the index are
+// not reported.
+func unusedImports(pass *analysis.Pass) {
+	for _, mod := range pass.Modules() {
+		used := make(map[string]bool)
+		ast.Inspect(mod.Node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ImportDecl:
+				return false
+			case *ast.Ident:
+				used[n.String()] = true
+			}
+			return true
+		})
+
+		src, _ := analysis.Source(mod.Tree)
+		ast.WalkModuleDefs(func(def *ast.ModuleDef) bool {
+			imp, ok := def.Def.(*ast.ImportDecl)
+			if !ok {
+				return true
+			}
+			name := ast.Name(imp.Module)
+			files := pass.DB.Modules[name]
+			if len(files) == 0 || used[name] || definesAny(pass.DB, files, used) {
+				return true
+			}
+			d := analysis.Diagnostic{
+				Pos:     mod.Tree.Position(imp.Pos()),
+				End:     mod.Tree.Position(imp.End()),
+				Message: fmt.Sprintf("module %q is imported but not used", name),
+				Node:    def,
+			}
+			if src != nil {
+				d.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Remove import",
+					TextEdits: []analysis.TextEdit{removeDef(mod.Tree, src, def)},
+				}}
+			}
+			pass.Report(d)
+			return true
+		}, mod.Node)
+	}
+}
+
+// unusedDefinitions reports module definitions not reachable from any test
+// case or control part.
+func unusedDefinitions(pass *analysis.Pass) {
+	// Diagnostics are attached to module definitions, so NOLINT comments
+	// work for value declarations, too.
+	defs := make(map[*ast.Ident]*ast.ModuleDef)
+	for _, mod := range pass.Modules() {
+		if pass.Ignore.Module(ast.Name(mod.Node)) {
+			continue
+		}
+		ast.WalkModuleDefs(func(def *ast.ModuleDef) bool {
+			ast.Inspect(def.Def, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					defs[id] = def
+				}
+				return true
+			})
+			return true
+		}, mod.Node)
+	}
+
+	for _, d := range ttcn3.NewRefGraph(pass.DB, pass.Files...).Unused() {
+		def, ok := defs[d.Ident]
+		if !ok {
+			continue
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:     d.Tree.Position(d.Ident.Pos()),
+			End:     d.Tree.Position(d.Ident.End()),
+			Message: fmt.Sprintf("%q is not used by any test case or control part", d.Ident.String()),
+			Node:    def,
+		})
+	}
+}
+
+// definesAny returns true if any of the files defines any of the names.
+func definesAny(db *ttcn3.DB, files map[string]bool, names map[string]bool) bool {
+	for name := range names {
+		for file := range db.Names[name] {
+			if files[file] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeDef returns an edit removing module definition def including its
+// semicolon. If def is the only content of its line, the line is removed.
+func removeDef(tree *ttcn3.Tree, src []byte, def *ast.ModuleDef) analysis.TextEdit {
+	begin := tree.Position(def.Pos()).Offset
+	end := tree.Position(def.End()).Offset
+	end = skipSpace(src, end)
+	if end < len(src) && src[end] == ';' {
+		end = skipSpace(src, end+1)
+	}
+	if start := analysis.LineStart(src, begin); start >= 0 && (end == len(src) || src[end] == '\n') {
+		begin = start
+		if end < len(src) {
+			end++
+		}
+	}
+	return analysis.Replace(tree, begin, end, "")
+}
+
+func skipSpace(src []byte, i int) int {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\r') {
+		i++
+	}
+	return i
+}
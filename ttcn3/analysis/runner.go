@@ -0,0 +1,360 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/nokia/ntt/internal/errors"
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/types"
+)
+
+// A Runner applies analyzers to TTCN-3 source files.
+type Runner struct {
+	// Analyzers to run. Analyzers not enabled by Config are skipped.
+	Analyzers []*Analyzer
+
+	// Config is the lint configuration. It may be nil.
+	Config *Config
+
+	// Configs, if not nil, provides the configuration of each file and
+	// takes precedence over Config. Config is used for files without
+	// configuration file in their directory or any parent directory.
+	Configs *Hierarchy
+
+	// Project is the test suite the files belong to. It may be nil.
+	Project project.Interface
+
+	// Partial indicates that only some files of a test suite are analyzed,
+	// like the files opened in an editor. Suite analyzers are skipped.
+	Partial bool
+}
+
+// Run parses files and returns the diagnostics of all analyzers, sorted by
+// position. Syntax errors are reported as diagnostics, too. Diagnostics
+// silenced by NOLINT directives or by severity Off are removed. The returned
+// error contains configuration errors and the errors of failed analyzers.
+//
+// Files are grouped by configuration. Each group is analyzed with its own
+// configuration. Suite analyzers see the files of all groups, but report
+// only diagnostics of files of their group.
+func (r *Runner) Run(files ...string) ([]Diagnostic, error) {
+	var (
+		mu       sync.Mutex
+		errs     *multierror.Error
+		reported = make(map[string]bool)
+		configs  = make(map[string]*Config)
+	)
+	configOf := func(path string) *Config {
+		if r.Configs == nil {
+			return r.Config
+		}
+		if c, ok := configs[path]; ok {
+			return c
+		}
+		c, err := r.Configs.Config(path)
+		if err != nil && !reported[err.Error()] {
+			reported[err.Error()] = true
+			errs = multierror.Append(errs, err)
+		}
+		if c == nil && err == nil {
+			c = r.Config
+		}
+		configs[path] = c
+		return c
+	}
+
+	var paths []string
+	for _, f := range files {
+		if !ignoreOf(configOf(f)).File(f) {
+			paths = append(paths, f)
+		}
+	}
+
+	var (
+		diags []Diagnostic
+		trees = make([]*ttcn3.Tree, len(paths))
+		wg    sync.WaitGroup
+	)
+	wg.Add(len(paths))
+	for i := range paths {
+		go func(i int) {
+			defer wg.Done()
+			trees[i] = ttcn3.ParseFile(paths[i])
+		}(i)
+	}
+	wg.Wait()
+
+	type group struct {
+		config *Config
+		files  []*ttcn3.Tree
+	}
+	var (
+		valid    []*ttcn3.Tree
+		groups   []*group
+		byConfig = make(map[*Config]*group)
+	)
+	for _, tree := range trees {
+		if tree.Err != nil {
+			diags = append(diags, syntaxErrors(tree.Err)...)
+			continue
+		}
+		valid = append(valid, tree)
+		c := configOf(tree.Filename())
+		g, ok := byConfig[c]
+		if !ok {
+			g = &group{config: c}
+			byConfig[c] = g
+			groups = append(groups, g)
+		}
+		g.files = append(g.files, tree)
+	}
+
+	enabled := func(g *group, a *Analyzer) bool {
+		return g.config.Enabled(a) && !(r.Partial && a.Suite)
+	}
+
+	var facts Facts
+	for _, g := range groups {
+		for _, a := range r.Analyzers {
+			if enabled(g, a) {
+				facts |= a.Requires
+			}
+		}
+	}
+
+	pass := Pass{
+		Files:   valid,
+		Project: r.Project,
+	}
+	if facts&Types != 0 {
+		pass.TypesInfo, pass.TypesScope = typesInfo(valid)
+	}
+	if facts&Scopes != 0 {
+		pass.DB = &ttcn3.DB{}
+		pass.DB.Index(paths...)
+	}
+	if facts&Imports != 0 {
+		pass.Imports = NewImportGraph(nil, valid...)
+	}
+
+	type finding struct {
+		Diagnostic
+		analyzer *Analyzer
+		group    *group
+	}
+	var found []finding
+	for _, g := range groups {
+		for _, a := range r.Analyzers {
+			if !enabled(g, a) {
+				continue
+			}
+			wg.Add(1)
+			go func(g *group, a *Analyzer) {
+				defer wg.Done()
+				var local []finding
+				pass := pass
+				pass.Analyzer = a
+				pass.Config = g.config.Section(a)
+				pass.Ignore = ignoreOf(g.config)
+				if !a.Suite {
+					pass.Files = g.files
+				}
+				pass.Report = func(d Diagnostic) {
+					d.Analyzer = a.Name
+					local = append(local, finding{d, a, g})
+				}
+				err := a.Run(&pass)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = multierror.Append(errs, fmt.Errorf("%s: %w", a.Name, err))
+				}
+				found = append(found, local...)
+			}(g, a)
+		}
+	}
+	wg.Wait()
+
+	firstGroup := func(a *Analyzer) *group {
+		for _, g := range groups {
+			if enabled(g, a) {
+				return g
+			}
+		}
+		return nil
+	}
+
+	byFile := make(map[string]*ttcn3.Tree)
+	for _, tree := range valid {
+		byFile[tree.Filename()] = tree
+	}
+	nolints := make(map[string]*nolint)
+	for _, f := range found {
+		d := f.Diagnostic
+		file := d.Pos.Filename
+
+		// Suite analyzers report diagnostics of all groups. Diagnostics
+		// without file are kept once.
+		if f.analyzer.Suite && len(groups) > 1 {
+			if file == "" && f.group != firstGroup(f.analyzer) || file != "" && configOf(file) != f.group.config {
+				continue
+			}
+		}
+
+		if isSilenced(d.Node, f.analyzer) {
+			continue
+		}
+		if tree := byFile[file]; tree != nil {
+			if nolints[file] == nil {
+				nolints[file] = nolintOf(tree)
+			}
+			if nolints[file].silences(d, f.analyzer) {
+				continue
+			}
+		}
+
+		if s := configOf(file).SeverityOf(f.analyzer.Name, file); s != "" {
+			if s == Off {
+				continue
+			}
+			d.Severity = s
+		}
+		diags = append(diags, d)
+	}
+
+	for i := range diags {
+		d := &diags[i]
+		if d.Severity == "" {
+			d.Severity = Error
+		}
+		if tree := byFile[d.Pos.Filename]; tree != nil && d.Node != nil && d.Symbol == "" {
+			d.Symbol = symbolAt(tree, d.Node.Pos())
+		}
+	}
+	Sort(diags)
+	return diags, errs.ErrorOrNil()
+}
+
+func ignoreOf(c *Config) *Ignore {
+	if c == nil {
+		return nil
+	}
+	return &c.Ignore
+}
+
+// Sort sorts diagnostics by position and message.
+func Sort(diags []Diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i], diags[j]
+		if a.Pos.Filename != b.Pos.Filename {
+			return a.Pos.Filename < b.Pos.Filename
+		}
+		if a.Pos.Line != b.Pos.Line {
+			return a.Pos.Line < b.Pos.Line
+		}
+		if a.Pos.Column != b.Pos.Column {
+			return a.Pos.Column < b.Pos.Column
+		}
+		return a.Message < b.Message
+	})
+}
+
+func syntaxErrors(err error) []Diagnostic {
+	var list []*errors.Error
+	switch err := err.(type) {
+	case *errors.ErrorList:
+		list = err.List()
+	case errors.ErrorList:
+		list = err.List()
+	default:
+		return []Diagnostic{{Analyzer: "syntax", Message: err.Error()}}
+	}
+
+	var diags []Diagnostic
+	for _, e := range list {
+		diags = append(diags, Diagnostic{Pos: e.Pos, End: e.Pos, Analyzer: "syntax", Message: e.Msg})
+	}
+	return diags
+}
+
+// typesInfo inserts all trees into a common scope. Errors, like
+// redefinitions, are ignored; reporting them is up to the analyzers.
+func typesInfo(trees []*ttcn3.Tree) (*types.Info, types.Scope) {
+	info := &types.Info{}
+	universe := &types.Module{}
+	for _, tree := range trees {
+		for _, def := range tree.Modules() {
+			info.InsertTree(def.Node, universe)
+		}
+	}
+	return info, universe
+}
+
+// symbolAt returns the qualified name of the module definition at pos.
+func symbolAt(tree *ttcn3.Tree, pos loc.Pos) string {
+	for _, def := range tree.Modules() {
+		mod := def.Node.(*ast.Module)
+		if pos < mod.Pos() || mod.End() <= pos {
+			continue
+		}
+		name := ast.Name(mod)
+		ast.WalkModuleDefs(func(d *ast.ModuleDef) bool {
+			if d.Pos() <= pos && pos < d.End() {
+				if s := ast.Name(d.Def); s != "" {
+					name += "." + s
+				}
+				return false
+			}
+			return true
+		}, mod)
+		return name
+	}
+	return ""
+}
+
+// NewImportGraph returns the import graph of all modules of trees. Every module
+// is a node of the graph, even if it imports nothing. Modules imported
+// several times by the same module are added once. If filter is not nil, only
+// import declarations it returns true for are added.
+func NewImportGraph(filter func(*ast.ImportDecl) bool, trees ...*ttcn3.Tree) ImportGraph {
+	g := make(ImportGraph)
+	for _, tree := range trees {
+		for _, def := range tree.Modules() {
+			mod := def.Node.(*ast.Module)
+			name := ast.Name(mod.Name)
+			if _, ok := g[name]; !ok {
+				g[name] = []string{}
+			}
+			ast.Inspect(mod, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.ImportDecl:
+					imported := ast.Name(n.Module)
+					if (filter == nil || filter(n)) && !contains(g[name], imported) {
+						g[name] = append(g[name], imported)
+					}
+					return false
+				case *ast.FuncDecl, *ast.ComponentTypeDecl, *ast.ControlPart:
+					return false
+				}
+				return true
+			})
+		}
+	}
+	return g
+}
+
+func contains(s []string, x string) bool {
+	for _, y := range s {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
@@ -585,6 +585,7 @@ type (
 	CaseClause struct {
 		Tok  Token      // Position of "case"
 		Case *ParenExpr // nil means else-case
+		Else Token      // Position of "else" or nil
 		Body *BlockStmt // Case body
 	}
 
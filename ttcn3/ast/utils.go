@@ -497,6 +497,7 @@ func Children(n Node) []Node {
 	case *CaseClause:
 		children = add(children, n.Tok)
 		children = add(children, n.Case)
+		children = add(children, n.Else)
 		children = add(children, n.Body)
 
 	case *CommClause:
@@ -0,0 +1,497 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/loc"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/printer"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// Formats supported by Generate.
+var Formats = []string{"markdown", "html"}
+
+// Generate generates documentation pages for all modules found in trees.
+//
+// Every module gets its own page listing types, templates, functions and
+// testcases together with their comments and documentation tags.
+// References to other documented definitions are resolved with
+// ttcn3.Tree.LookupWithDB and rendered as links. The index page lists all
+// modules; the tags page indexes all definitions by tag, like @author,
+// @purpose or @verdict. Modules named like these pages are reported as
+// errors.
+//
+// Parameter "format" selects the output format: markdown (default) or html.
+// Syntax errors are reported as diagnostics.
+func Generate(params map[string]string, trees ...*ttcn3.Tree) (*pb.GeneratorResponse, error) {
+	format := params["format"]
+	if format == "" {
+		format = "markdown"
+	}
+
+	var r renderer
+	switch format {
+	case "markdown":
+		r = &markdown{}
+	case "html":
+		r = &html{}
+	default:
+		return nil, fmt.Errorf("unknown documentation format %q. Supported formats: %s", format, strings.Join(Formats, ", "))
+	}
+
+	resp := &pb.GeneratorResponse{
+		Parameters: map[string]string{"format": format},
+	}
+
+	s := newSite(r.ext())
+	var files []string
+	for _, tree := range trees {
+		if tree.Filename() != "" {
+			files = append(files, tree.Filename())
+		}
+	}
+	s.db.Index(files...)
+
+	for _, tree := range trees {
+		if tree.Err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &pb.Diagnostic{
+				Location: &pb.Location{File: tree.Filename()},
+				Message:  tree.Err.Error(),
+			})
+			continue
+		}
+		s.collect(tree)
+	}
+	resp.Diagnostics = append(resp.Diagnostics, s.diags...)
+	sort.SliceStable(s.pages, func(i, j int) bool { return s.pages[i].Name < s.pages[j].Name })
+	s.link()
+
+	for _, p := range s.pages {
+		content, err := r.module(p)
+		if err != nil {
+			return nil, err
+		}
+		resp.Files = append(resp.Files, &pb.GeneratorResponse_File{Name: p.File, Content: []byte(content)})
+	}
+	for name, fn := range map[string]func(*site) (string, error){"index": r.index, "tags": r.tags} {
+		content, err := fn(s)
+		if err != nil {
+			return nil, err
+		}
+		resp.Files = append(resp.Files, &pb.GeneratorResponse_File{Name: name + r.ext(), Content: []byte(content)})
+	}
+	sort.Slice(resp.Files, func(i, j int) bool { return resp.Files[i].Name < resp.Files[j].Name })
+	return resp, nil
+}
+
+// Page is the documentation of a module.
+type Page struct {
+	Name     string
+	File     string
+	Comment  string
+	Tags     [][]string
+	Sections []*Section
+}
+
+// Section is a list of definitions of the same kind, like types or
+// testcases.
+type Section struct {
+	Title   string
+	Entries []*Entry
+}
+
+// Entry is the documentation of a single definition.
+type Entry struct {
+	Name      string
+	Module    string
+	Signature string
+	Comment   string
+	Tags      [][]string
+	Refs      []*Link
+
+	node ast.Node
+	tree *ttcn3.Tree
+}
+
+// Link refers to the documentation of a definition.
+type Link struct {
+	Name string
+	URL  string
+}
+
+// TagIndex lists all entries having a specific tag.
+type TagIndex struct {
+	Tag     string
+	Entries []TagEntry
+}
+
+// TagEntry is an entry of a tag index.
+type TagEntry struct {
+	Value string
+	Link  *Link
+}
+
+type site struct {
+	ext   string
+	db    *ttcn3.DB
+	pages []*Page
+	byMod map[string]*Page
+	defs  map[ast.Node]*Entry
+	diags []*pb.Diagnostic
+}
+
+func newSite(ext string) *site {
+	return &site{
+		ext:   ext,
+		db:    &ttcn3.DB{},
+		byMod: make(map[string]*Page),
+		defs:  make(map[ast.Node]*Entry),
+	}
+}
+
+// sections in order of appearance on a page.
+var sections = []string{"Types", "Templates", "Functions", "Testcases"}
+
+// reserved are the names of generated pages, which modules must not use.
+// Names are compared case insensitive, because not all file systems are case
+// sensitive.
+var reserved = []string{"index", "tags"}
+
+func isReserved(name string) bool {
+	for _, r := range reserved {
+		if strings.EqualFold(name, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *site) collect(tree *ttcn3.Tree) {
+	for _, m := range tree.Modules() {
+		mod := m.Node.(*ast.Module)
+		name := ast.Name(mod)
+		if isReserved(name) {
+			pos := tree.Position(mod.Name.Pos())
+			s.diags = append(s.diags, &pb.Diagnostic{
+				Location: &pb.Location{File: tree.Filename(), Line: int32(pos.Line), Column: int32(pos.Column)},
+				Message:  fmt.Sprintf("module %s clashes with the generated %s page", name, strings.ToLower(name)),
+			})
+			continue
+		}
+		p, ok := s.byMod[name]
+		if !ok {
+			p = &Page{Name: name, File: name + s.ext}
+			for _, title := range sections {
+				p.Sections = append(p.Sections, &Section{Title: title})
+			}
+			s.byMod[name] = p
+			s.pages = append(s.pages, p)
+		}
+		if p.Comment == "" {
+			p.Comment, p.Tags = splitComment(mod.Tok.Comments())
+		}
+
+		ast.WalkModuleDefs(func(d *ast.ModuleDef) bool {
+			sec, sig := signature(d.Def, tree)
+			if sec < 0 {
+				return true
+			}
+			e := &Entry{
+				Name:      ast.Name(d.Def),
+				Module:    name,
+				Signature: sig,
+				node:      d.Def,
+				tree:      tree,
+			}
+			e.Comment, e.Tags = splitComment(ast.FirstToken(d).Comments())
+			p.Sections[sec].Entries = append(p.Sections[sec].Entries, e)
+			s.defs[d.Def] = e
+
+			// Lookup returns the field of sub-type definitions.
+			if st, ok := d.Def.(*ast.SubTypeDecl); ok && st.Field != nil {
+				s.defs[st.Field] = e
+			}
+			return true
+		}, mod)
+	}
+}
+
+// signature returns the section index and the source text of the signature of
+// a documented definition. Bodies of behaviours are omitted. The index is -1
+// for definitions without documentation.
+func signature(n ast.Node, tree *ttcn3.Tree) (int, string) {
+	switch n := n.(type) {
+	case *ast.SubTypeDecl, *ast.StructTypeDecl, *ast.EnumTypeDecl, *ast.ComponentTypeDecl, *ast.PortTypeDecl, *ast.SignatureDecl, *ast.BehaviourTypeDecl:
+		return 0, source(tree, n, n.End())
+	case *ast.TemplateDecl:
+		return 1, source(tree, n, n.End())
+	case *ast.FuncDecl:
+		end := n.End()
+		if n.Body != nil {
+			end = n.Body.Pos()
+		}
+		if n.Kind.Kind == token.TESTCASE {
+			return 3, source(tree, n, end)
+		}
+		return 2, source(tree, n, end)
+	}
+	return -1, ""
+}
+
+// source returns the source text from the beginning of n up to end. If the
+// source file is not available, the printed syntax tree is returned.
+func source(tree *ttcn3.Tree, n ast.Node, end loc.Pos) string {
+	b, err := fs.Content(tree.Filename())
+	from, to := tree.Position(n.Pos()).Offset, tree.Position(end).Offset
+	if err != nil || from < 0 || to > len(b) || from > to {
+		var buf bytes.Buffer
+		printer.Print(&buf, tree.FileSet, n)
+		return strings.TrimSpace(buf.String())
+	}
+	return strings.TrimSpace(string(b[from:to]))
+}
+
+// link resolves the references of all entries.
+func (s *site) link() {
+	for _, p := range s.pages {
+		for _, sec := range p.Sections {
+			for _, e := range sec.Entries {
+				e.Refs = s.refs(e)
+			}
+		}
+	}
+}
+
+// refs returns links to the documented definitions referenced by e.
+func (s *site) refs(e *Entry) []*Link {
+	var (
+		links []*Link
+		seen  = map[*Entry]bool{e: true}
+	)
+
+	add := func(x ast.Expr) {
+		for _, def := range e.tree.LookupWithDB(x, s.db) {
+			target := s.defs[def.Node]
+			if target == nil || seen[target] {
+				continue
+			}
+			seen[target] = true
+			links = append(links, s.linkTo(target))
+		}
+	}
+
+	ast.Inspect(e.node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			return false
+		case *ast.SelectorExpr:
+			if _, ok := n.X.(*ast.Ident); ok {
+				add(n)
+				return false
+			}
+		case *ast.Ident:
+			add(n)
+		}
+		return true
+	})
+	return links
+}
+
+func (s *site) linkTo(e *Entry) *Link {
+	return &Link{Name: e.Name, URL: e.Module + s.ext + "#" + e.Name}
+}
+
+// tagIndexes returns the tags of all modules and definitions sorted by name.
+func (s *site) tagIndexes() []*TagIndex {
+	idx := make(map[string]*TagIndex)
+	add := func(tags [][]string, l *Link) {
+		for _, t := range tags {
+			ti, ok := idx[t[0]]
+			if !ok {
+				ti = &TagIndex{Tag: t[0]}
+				idx[t[0]] = ti
+			}
+			ti.Entries = append(ti.Entries, TagEntry{Value: t[1], Link: l})
+		}
+	}
+
+	for _, p := range s.pages {
+		add(p.Tags, &Link{Name: p.Name, URL: p.File})
+		for _, sec := range p.Sections {
+			for _, e := range sec.Entries {
+				add(e.Tags, s.linkTo(e))
+			}
+		}
+	}
+
+	var list []*TagIndex
+	for _, ti := range idx {
+		list = append(list, ti)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Tag < list[j].Tag })
+	return list
+}
+
+// splitComment strips comment markers from s and separates the text from
+// the documentation tags.
+func splitComment(s string) (string, [][]string) {
+	var (
+		lines []string
+		tags  [][]string
+	)
+	for _, line := range strings.Split(s, "\n") {
+		if t := FindTag(line); t != nil {
+			tags = append(tags, t)
+			continue
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "//"):
+			line = strings.TrimPrefix(line, "//")
+		case strings.HasPrefix(line, "/*"):
+			line = strings.TrimPrefix(line, "/*")
+		case strings.HasPrefix(line, "*") && !strings.HasPrefix(line, "*/"):
+			line = strings.TrimPrefix(line, "*")
+		}
+		line = strings.TrimSuffix(line, "*/")
+		lines = append(lines, strings.TrimRight(strings.TrimPrefix(line, " "), " \t"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), tags
+}
+
+// renderer renders pages of a specific format.
+type renderer interface {
+	ext() string
+	module(*Page) (string, error)
+	index(*site) (string, error)
+	tags(*site) (string, error)
+}
+
+type markdown struct{}
+
+func (markdown) ext() string { return ".md" }
+
+func (markdown) module(p *Page) (string, error) { return execute(mdModule, p) }
+func (markdown) index(s *site) (string, error)  { return execute(mdIndex, s.pages) }
+func (markdown) tags(s *site) (string, error)   { return execute(mdTags, s.tagIndexes()) }
+
+type html struct{}
+
+func (html) ext() string { return ".html" }
+
+func (html) module(p *Page) (string, error) { return execute(htmlModule, p) }
+func (html) index(s *site) (string, error)  { return execute(htmlIndex, s.pages) }
+func (html) tags(s *site) (string, error)   { return execute(htmlTags, s.tagIndexes()) }
+
+func execute(t interface{}, data interface{}) (string, error) {
+	var b bytes.Buffer
+	var err error
+	switch t := t.(type) {
+	case *template.Template:
+		err = t.Execute(&b, data)
+	case *htmltemplate.Template:
+		err = t.Execute(&b, data)
+	}
+	return b.String(), err
+}
+
+var funcs = template.FuncMap{
+	"summary": func(s string) string {
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			return s[:i]
+		}
+		return s
+	},
+}
+
+var (
+	mdModule = template.Must(template.New("module").Funcs(funcs).Parse(`# Module {{.Name}}
+{{if .Comment}}
+{{.Comment}}
+{{end}}{{if .Tags}}
+{{range .Tags}}* **{{index . 0}}** {{index . 1}}
+{{end}}{{end}}{{range .Sections}}{{if .Entries}}
+## {{.Title}}
+{{range .Entries}}
+<a id="{{.Name}}"></a>
+### {{.Name}}
+
+` + "```ttcn3" + `
+{{.Signature}}
+` + "```" + `
+{{if .Comment}}
+{{.Comment}}
+{{end}}{{if .Tags}}
+{{range .Tags}}* **{{index . 0}}** {{index . 1}}
+{{end}}{{end}}{{if .Refs}}
+References: {{range $i, $l := .Refs}}{{if $i}}, {{end}}[{{$l.Name}}]({{$l.URL}}){{end}}
+{{end}}{{end}}{{end}}{{end}}`))
+
+	mdIndex = template.Must(template.New("index").Funcs(funcs).Parse(`# Modules
+{{range .}}
+* [{{.Name}}]({{.File}}){{if .Comment}}: {{summary .Comment}}{{end}}{{end}}
+
+See also the [index of tags](tags.md).
+`))
+
+	mdTags = template.Must(template.New("tags").Parse(`# Tags
+{{range .}}
+## {{.Tag}}
+{{range .Entries}}
+* [{{.Link.Name}}]({{.Link.URL}}){{if .Value}}: {{.Value}}{{end}}{{end}}
+{{end}}`))
+
+	htmlModule = htmltemplate.Must(htmltemplate.New("module").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Module {{.Name}}</title></head>
+<body>
+<p><a href="index.html">Modules</a> | <a href="tags.html">Tags</a></p>
+<h1>Module {{.Name}}</h1>
+{{if .Comment}}<pre class="comment">{{.Comment}}</pre>
+{{end}}{{if .Tags}}<dl>{{range .Tags}}<dt>{{index . 0}}</dt><dd>{{index . 1}}</dd>{{end}}</dl>
+{{end}}{{range .Sections}}{{if .Entries}}<h2>{{.Title}}</h2>
+{{range .Entries}}<h3 id="{{.Name}}">{{.Name}}</h3>
+<pre><code>{{.Signature}}</code></pre>
+{{if .Comment}}<pre class="comment">{{.Comment}}</pre>
+{{end}}{{if .Tags}}<dl>{{range .Tags}}<dt>{{index . 0}}</dt><dd>{{index . 1}}</dd>{{end}}</dl>
+{{end}}{{if .Refs}}<p>References: {{range $i, $l := .Refs}}{{if $i}}, {{end}}<a href="{{$l.URL}}">{{$l.Name}}</a>{{end}}</p>
+{{end}}{{end}}{{end}}{{end}}</body>
+</html>
+`))
+
+	htmlIndex = htmltemplate.Must(htmltemplate.New("index").Funcs(htmltemplate.FuncMap(funcs)).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Modules</title></head>
+<body>
+<p><a href="index.html">Modules</a> | <a href="tags.html">Tags</a></p>
+<h1>Modules</h1>
+<ul>
+{{range .}}<li><a href="{{.File}}">{{.Name}}</a>{{if .Comment}}: {{summary .Comment}}{{end}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+	htmlTags = htmltemplate.Must(htmltemplate.New("tags").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Tags</title></head>
+<body>
+<p><a href="index.html">Modules</a> | <a href="tags.html">Tags</a></p>
+<h1>Tags</h1>
+{{range .}}<h2>{{.Tag}}</h2>
+<ul>
+{{range .Entries}}<li><a href="{{.Link.URL}}">{{.Link.Name}}</a>{{if .Value}}: {{.Value}}{{end}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+)
@@ -0,0 +1,110 @@
+package doc_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/generator"
+	pb "github.com/nokia/ntt/protobuf"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generate(t *testing.T, format string, srcs map[string]string) map[string]string {
+	t.Helper()
+	var trees []*ttcn3.Tree
+	for name, src := range srcs {
+		fs.Open(name).SetBytes([]byte(src))
+		trees = append(trees, ttcn3.ParseFile(name))
+	}
+	resp, err := doc.Generate(map[string]string{"format": format}, trees...)
+	require.Nil(t, err)
+	assert.Equal(t, format, resp.Parameters["format"])
+
+	files := make(map[string]string)
+	for _, f := range resp.Files {
+		files[f.Name] = string(f.Content)
+	}
+	return files
+}
+
+var sources = map[string]string{
+	"a.ttcn3": `
+// Module A tests things.
+// @author alice
+module A {
+	import from B all;
+
+	// A record.
+	type record R { I a }
+
+	// Tests R.
+	// @purpose check R
+	// @verdict pass
+	testcase tc() runs on B.C {
+		var R r := { a := 1 };
+	}
+}`,
+	"b.ttcn3": `
+module B {
+	type integer I;
+	type component C {}
+	template I t := 1;
+	function f(I x) return I { return x }
+}`,
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	files := generate(t, "markdown", sources)
+	assert.Len(t, files, 4)
+
+	a := files["A.md"]
+	assert.Contains(t, a, "# Module A\n\nModule A tests things.\n\n* **@author** alice\n")
+	assert.Contains(t, a, "## Types\n\n<a id=\"R\"></a>\n### R\n\n```ttcn3\ntype record R")
+	assert.Contains(t, a, "A record.\n\nReferences: [I](B.md#I)\n")
+	assert.Contains(t, a, "## Testcases\n")
+	assert.Contains(t, a, "* **@purpose** check R\n* **@verdict** pass\n")
+	assert.Contains(t, a, "References: [C](B.md#C)\n")
+	assert.NotContains(t, a, "var R r")
+
+	b := files["B.md"]
+	assert.Contains(t, b, "## Templates\n")
+	assert.Contains(t, b, "## Functions\n")
+	assert.NotContains(t, b, "Testcases")
+
+	assert.Equal(t, "# Modules\n\n* [A](A.md): Module A tests things.\n* [B](B.md)\n\nSee also the [index of tags](tags.md).\n", files["index.md"])
+	assert.Contains(t, files["tags.md"], "## @purpose\n\n* [tc](A.md#tc): check R\n")
+	assert.Contains(t, files["tags.md"], "## @author\n\n* [A](A.md): alice\n")
+	assert.True(t, strings.HasPrefix(files["B.md"], "# Module B\n\n## Types\n"))
+}
+
+func TestGenerateHTML(t *testing.T) {
+	files := generate(t, "html", sources)
+	assert.Contains(t, files["A.html"], `<h3 id="R">R</h3>`)
+	assert.Contains(t, files["A.html"], `References: <a href="B.html#I">I</a>`)
+	assert.Contains(t, files["index.html"], `<li><a href="A.html">A</a>: Module A tests things.</li>`)
+	assert.Contains(t, files["tags.html"], `<h2>@verdict</h2>`)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := doc.Generate(map[string]string{"format": "pdf"})
+	assert.NotNil(t, err)
+
+	fs.Open("broken.ttcn3").SetBytes([]byte("module {"))
+	resp, err := doc.Generate(nil, ttcn3.ParseFile("broken.ttcn3"))
+	require.Nil(t, err)
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, pb.Diagnostic_ERROR, resp.Diagnostics[0].Severity)
+	}
+
+	fs.Open("index.ttcn3").SetBytes([]byte("module A {}\nmodule Index {}"))
+	resp, err = doc.Generate(nil, ttcn3.ParseFile("index.ttcn3"))
+	require.Nil(t, err)
+	if assert.Len(t, resp.Diagnostics, 1) {
+		d := resp.Diagnostics[0]
+		assert.Equal(t, "index.ttcn3:2:8: error: module Index clashes with the generated index page", generator.FormatDiagnostic(d))
+	}
+}
@@ -0,0 +1,315 @@
+// Package metrics computes code metrics of TTCN-3 modules and behaviours.
+package metrics
+
+import (
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// Metrics are the metrics of a module or behaviour.
+type Metrics struct {
+	// Kind is "module", "testcase", "function", "altstep" or "control".
+	Kind string `json:"kind"`
+
+	Module string `json:"module"`
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+
+	// LOC is the number of lines, including comments and empty lines.
+	LOC int `json:"loc"`
+
+	// Complexity is the cyclomatic complexity. The complexity of a module
+	// is the sum of the complexities of its behaviours.
+	Complexity int `json:"complexity"`
+
+	// Nesting is the maximum depth of nested compound statements.
+	Nesting int `json:"nesting"`
+
+	// Params is the number of formal parameters.
+	Params int `json:"params"`
+
+	// FanIn is the number of behaviours calling a behaviour, or the number
+	// of modules importing a module.
+	FanIn int `json:"fan_in"`
+
+	// FanOut is the number of behaviours called by a behaviour, or the
+	// number of modules imported by a module.
+	FanOut int `json:"fan_out"`
+
+	// Alts is the number of alt- and interleave branches.
+	Alts int `json:"alts"`
+
+	// Templates is the number of template definitions.
+	Templates int `json:"templates"`
+}
+
+// Module are the metrics of a module and its behaviours.
+type Module struct {
+	Metrics
+	Behaviours []*Metrics `json:"behaviours"`
+}
+
+// Compute computes the metrics of all modules of trees. Files with syntax
+// errors are skipped. Calls and imports are resolved by name only.
+func Compute(trees ...*ttcn3.Tree) []*Module {
+	var (
+		mods    []*Module
+		imports = make(map[string]map[string]bool)
+		calls   = make(map[string]map[string]bool)
+		bodies  = make(map[*Metrics]ast.Node)
+	)
+
+	for _, tree := range trees {
+		if tree.Err != nil {
+			continue
+		}
+		for _, def := range tree.Modules() {
+			mod := def.Node.(*ast.Module)
+			name := ast.Name(mod)
+			m := &Module{Metrics: measure(tree, mod, "module", name, name)}
+			if imports[name] == nil {
+				imports[name] = make(map[string]bool)
+			}
+
+			ast.Inspect(mod, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.ImportDecl:
+					imports[name][ast.Name(n.Module)] = true
+					return false
+				case *ast.TemplateDecl:
+					m.Templates++
+					return false
+				case *ast.FuncDecl:
+					if n.Body == nil {
+						return false
+					}
+					b := measure(tree, n, n.Kind.String(), name, ast.Name(n))
+					b.Params = params(n.Params)
+					m.Behaviours = append(m.Behaviours, &b)
+					bodies[&b] = n
+					return false
+				case *ast.ControlPart:
+					b := measure(tree, n, "control", name, "control")
+					m.Behaviours = append(m.Behaviours, &b)
+					bodies[&b] = n
+					return false
+				}
+				return true
+			})
+
+			for _, b := range m.Behaviours {
+				m.Complexity += b.Complexity
+				m.Alts += b.Alts
+				if b.Nesting > m.Nesting {
+					m.Nesting = b.Nesting
+				}
+			}
+			mods = append(mods, m)
+		}
+	}
+
+	// Only calls of behaviours of the measured modules are counted.
+	known := make(map[string]bool)
+	for _, m := range mods {
+		for _, b := range m.Behaviours {
+			if b.Kind != "control" {
+				known[b.Name] = true
+			}
+		}
+	}
+	for _, m := range mods {
+		for _, b := range m.Behaviours {
+			callees := make(map[string]bool)
+			for name := range callsOf(bodies[b]) {
+				if known[name] {
+					callees[name] = true
+				}
+			}
+			b.FanOut = len(callees)
+			calls[b.Module+"."+b.Name] = callees
+		}
+	}
+	callers := make(map[string]int)
+	for _, callees := range calls {
+		for name := range callees {
+			callers[name]++
+		}
+	}
+	importers := make(map[string]int)
+	for _, deps := range imports {
+		for name := range deps {
+			importers[name]++
+		}
+	}
+	for _, m := range mods {
+		m.FanIn = importers[m.Name]
+		m.FanOut = len(imports[m.Name])
+		for _, b := range m.Behaviours {
+			if b.Kind != "control" {
+				b.FanIn = callers[b.Name]
+			}
+		}
+	}
+	return mods
+}
+
+// measure returns the metrics of n, which do not depend on other modules or
+// behaviours.
+func measure(tree *ttcn3.Tree, n ast.Node, kind string, module string, name string) Metrics {
+	begin := tree.Position(n.Pos())
+	end := tree.Position(n.End())
+	m := Metrics{
+		Kind:   kind,
+		Module: module,
+		Name:   name,
+		File:   tree.Filename(),
+		Line:   begin.Line,
+		LOC:    end.Line - begin.Line + 1,
+	}
+	if _, ok := n.(*ast.Module); ok {
+		return m
+	}
+	m.Complexity = Complexity(n, false)
+	m.Nesting = Nesting(n)
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CommClause:
+			m.Alts++
+		case *ast.ValueDecl:
+			if n.Kind.Kind == token.TEMPLATE || n.TemplateRestriction != nil {
+				m.Templates += len(n.Decls)
+			}
+		}
+		return true
+	})
+	return m
+}
+
+func params(p *ast.FormalPars) int {
+	if p == nil {
+		return 0
+	}
+	return len(p.List)
+}
+
+// callsOf returns the names of behaviours called by n. Qualified names are
+// reduced to the unqualified name.
+func callsOf(n ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(n, func(n ast.Node) bool {
+		if c, ok := n.(*ast.CallExpr); ok {
+			switch fun := c.Fun.(type) {
+			case *ast.Ident:
+				names[ast.Name(fun)] = true
+			case *ast.SelectorExpr:
+				names[ast.Name(fun.Sel)] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// Complexity returns the cyclomatic complexity (McCabe) of behaviour n. If n
+// is neither a function, altstep, testcase nor control part, only decision
+// points outside of functions, altsteps and testcases are counted. Default
+// values of parameters are not counted. If ignoreGuards is true, alt- and
+// interleave guards are not counted.
+func Complexity(n ast.Node, ignoreGuards bool) int {
+	cc := 0
+	switch n.(type) {
+	case *ast.FuncDecl, *ast.ControlPart:
+		cc = 1 // Intial McCabe value
+	}
+	root := n
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			// Behaviours are counted separately.
+			return n == root
+
+		case *ast.FormalPar:
+			// We do not descent any further, because we do
+			// not want to count cyclomatic complexity for
+			// default values.
+			return false
+
+		case *ast.BinaryExpr:
+			if n.Op.Kind == token.AND || n.Op.Kind == token.OR {
+				cc++
+			}
+
+		case *ast.IfStmt:
+			cc++
+
+		case *ast.CaseClause:
+			// Do not count case else for complexity
+			if n.Case != nil {
+				cc++
+			}
+
+		case *ast.CommClause:
+			if ignoreGuards {
+				return true
+			}
+
+			// Do not count else-guards
+			if n.Else.IsValid() {
+				return true
+			}
+			// Every AltGuard increases cyclomatic complexity.
+			cc++
+
+			// Every AltGuard expressions also increases complexity.
+			if n.X != nil {
+				cc++
+			}
+		}
+		return true
+	})
+	return cc
+}
+
+// Nesting returns the maximum depth of nested compound statements (if,
+// select, loops, alt and interleave) in n. Else-if chains do not increase the
+// depth.
+func Nesting(n ast.Node) int {
+	var (
+		max   int
+		stack []ast.Node
+		depth []int
+	)
+	ast.Inspect(n, func(n ast.Node) bool {
+		if n == nil {
+			stack, depth = stack[:len(stack)-1], depth[:len(depth)-1]
+			return false
+		}
+		d := 0
+		if len(depth) > 0 {
+			d = depth[len(depth)-1]
+		}
+		switch n := n.(type) {
+		case *ast.IfStmt:
+			if parent, ok := top(stack).(*ast.IfStmt); !ok || parent.Else != n {
+				d++
+			}
+		case *ast.SelectStmt, *ast.ForStmt, *ast.WhileStmt, *ast.DoWhileStmt, *ast.AltStmt:
+			d++
+		}
+		if d > max {
+			max = d
+		}
+		stack, depth = append(stack, n), append(depth, d)
+		return true
+	})
+	return max
+}
+
+func top(stack []ast.Node) ast.Node {
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
@@ -0,0 +1,83 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(name string, src string) *ttcn3.Tree {
+	fs.Open(name).SetBytes([]byte(src))
+	return ttcn3.ParseFile(name)
+}
+
+func TestCompute(t *testing.T) {
+	a := parse("metrics_a.ttcn3", `module A {
+	import from B all;
+	template integer t1 := 1;
+	template integer t2 := 2;
+	function f(integer x, integer y) {
+		if (x > 0 and y > 0) {
+			while (true) {
+				if (x == 1) {
+				} else if (x == 2) {
+				} else if (x == 3) {
+				}
+			}
+		}
+	}
+	testcase tc() runs on C {
+		var template integer t3 := 3, t4 := 4;
+		f(1, 2);
+		B.g();
+		alt {
+		[] p.receive { f(3, 4) }
+		[] T.timeout {}
+		[else] {}
+		}
+	}
+	external function ext();
+	control {
+		execute(tc());
+	}
+}`)
+	b := parse("metrics_b.ttcn3", `module B {
+	import from A all;
+	import from A all;
+	function g() { select (x) { case (1) { h() } case else {} } }
+	function h() {}
+}`)
+	err := parse("metrics_err.ttcn3", "module Err {")
+
+	mods := metrics.Compute(a, b, err)
+	if !assert.Len(t, mods, 2) {
+		return
+	}
+
+	assert.Equal(t, metrics.Metrics{
+		Kind: "module", Module: "A", Name: "A", File: "metrics_a.ttcn3", Line: 1,
+		LOC: 29, Complexity: 10, Nesting: 3, FanIn: 1, FanOut: 1, Alts: 3, Templates: 2,
+	}, mods[0].Metrics)
+	assert.Equal(t, []*metrics.Metrics{
+		{Kind: "function", Module: "A", Name: "f", File: "metrics_a.ttcn3", Line: 5,
+			LOC: 10, Complexity: 6, Nesting: 3, Params: 2, FanIn: 1},
+		{Kind: "testcase", Module: "A", Name: "tc", File: "metrics_a.ttcn3", Line: 15,
+			LOC: 10, Complexity: 3, Nesting: 1, FanOut: 2, FanIn: 1, Alts: 3, Templates: 2},
+		{Kind: "control", Module: "A", Name: "control", File: "metrics_a.ttcn3", Line: 26,
+			LOC: 3, Complexity: 1, FanOut: 1},
+	}, mods[0].Behaviours)
+
+	assert.Equal(t, metrics.Metrics{
+		Kind: "module", Module: "B", Name: "B", File: "metrics_b.ttcn3", Line: 1,
+		LOC: 6, Complexity: 3, Nesting: 1, FanIn: 1, FanOut: 1,
+	}, mods[1].Metrics)
+	assert.Equal(t, []*metrics.Metrics{
+		{Kind: "function", Module: "B", Name: "g", File: "metrics_b.ttcn3", Line: 4,
+			LOC: 1, Complexity: 2, Nesting: 1, FanIn: 1, FanOut: 1},
+		{Kind: "function", Module: "B", Name: "h", File: "metrics_b.ttcn3", Line: 5,
+			LOC: 1, Complexity: 1, FanIn: 1},
+	}, mods[1].Behaviours)
+}
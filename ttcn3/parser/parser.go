@@ -1280,6 +1280,10 @@ func (p *parser) addName(n ast.Node) {
 		for _, n := range n.Decls {
 			p.addName(n)
 		}
+	case *ast.ModuleParameterGroup:
+		for _, n := range n.Decls {
+			p.addName(n)
+		}
 	default:
 		if name := ast.Name(n); name != "" {
 			p.names[name] = true
@@ -2476,7 +2480,7 @@ func (p *parser) parseCaseStmt() *ast.CaseClause {
 	x := new(ast.CaseClause)
 	x.Tok = p.expect(token.CASE)
 	if p.tok == token.ELSE {
-		p.consume() // TODO(5nord) move token into AST
+		x.Else = p.consume()
 	} else {
 		x.Case = p.parseParenExpr()
 	}
@@ -346,6 +346,7 @@ func (p *printer) print(values ...interface{}) {
 			}
 			p.print(n.Tok)
 			p.print(n.Case)
+			p.print(n.Else)
 			p.print(n.Body)
 
 		case *ast.CommClause:
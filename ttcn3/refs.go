@@ -0,0 +1,210 @@
+package ttcn3
+
+import (
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// RefGraph is a graph of module definitions and the module definitions they
+// reference.
+//
+// References are resolved by name: an identifier references all definitions
+// with that name in the current module and in imported modules. This
+// over-approximates the actual references, for example when a local variable
+// shadows a global definition, but it never misses one.
+type RefGraph struct {
+	// Defs are the module definitions of all files, in file order.
+	Defs []*Definition
+
+	// Refs maps definitions to the definitions they reference.
+	Refs map[*Definition][]*Definition
+
+	// Roots are the definitions executed directly: test cases and
+	// control parts.
+	Roots []*Definition
+}
+
+// NewRefGraph builds the reference graph of the module definitions of trees.
+// The database is used to find the modules visible by imports; it should
+// index all files of the test suite.
+func NewRefGraph(db *DB, trees ...*Tree) *RefGraph {
+	g := &RefGraph{Refs: make(map[*Definition][]*Definition)}
+
+	// Module definitions indexed by module and name.
+	index := make(map[*ast.Module]map[string][]*Definition)
+	modules := make(map[string][]*ast.Module)
+	owner := make(map[*Definition]*ast.Module)
+
+	// Types of value declarations are shared by their declarators.
+	types := make(map[*Definition]ast.Expr)
+
+	for _, tree := range trees {
+		if tree.Err != nil {
+			continue
+		}
+		for _, m := range tree.Modules() {
+			mod := m.Node.(*ast.Module)
+			modules[ast.Name(mod)] = append(modules[ast.Name(mod)], mod)
+			index[mod] = make(map[string][]*Definition)
+			ast.WalkModuleDefs(func(def *ast.ModuleDef) bool {
+				for _, d := range moduleDefs(tree, def.Def) {
+					g.Defs = append(g.Defs, d)
+					owner[d] = mod
+					if v, ok := d.Node.(*ast.Declarator); ok {
+						types[d] = declType(def.Def, v)
+					}
+					name := d.Ident.String()
+					index[mod][name] = append(index[mod][name], d)
+					if isRoot(d.Node) {
+						g.Roots = append(g.Roots, d)
+					}
+				}
+				return true
+			}, mod)
+		}
+	}
+
+	type key struct {
+		mod  *ast.Module
+		name string
+	}
+	cache := make(map[key][]*Definition)
+	resolve := func(mod *ast.Module, name string) []*Definition {
+		k := key{mod, name}
+		if defs, ok := cache[k]; ok {
+			return defs
+		}
+		var defs []*Definition
+		for _, m := range db.VisibleModules(name, mod) {
+			for _, mod := range modules[ast.Name(m.Node)] {
+				defs = append(defs, index[mod][name]...)
+			}
+		}
+		cache[k] = defs
+		return defs
+	}
+
+	for _, d := range g.Defs {
+		seen := make(map[*Definition]bool)
+		visit := func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok || id == d.Ident {
+				return true
+			}
+			for _, ref := range resolve(owner[d], id.String()) {
+				if ref != d && !seen[ref] {
+					seen[ref] = true
+					g.Refs[d] = append(g.Refs[d], ref)
+				}
+			}
+			return true
+		}
+		ast.Inspect(types[d], visit)
+		ast.Inspect(d.Node, visit)
+	}
+	return g
+}
+
+// Reachable returns the definitions reachable from the roots.
+func (g *RefGraph) Reachable() map[*Definition]bool {
+	reached := make(map[*Definition]bool)
+	var visit func(d *Definition)
+	visit = func(d *Definition) {
+		if reached[d] {
+			return
+		}
+		reached[d] = true
+		for _, ref := range g.Refs[d] {
+			visit(ref)
+		}
+	}
+	for _, r := range g.Roots {
+		visit(r)
+	}
+	return reached
+}
+
+// Unused returns the definitions not reachable from any test case or control
+// part, in file order.
+func (g *RefGraph) Unused() []*Definition {
+	reached := g.Reachable()
+	var unused []*Definition
+	for _, d := range g.Defs {
+		if !reached[d] {
+			unused = append(unused, d)
+		}
+	}
+	return unused
+}
+
+// moduleDefs returns the definitions declared by module definition n.
+// Imports, friend declarations and groups do not declare definitions.
+func moduleDefs(tree *Tree, n ast.Node) []*Definition {
+	def := func(id *ast.Ident, n ast.Node) *Definition {
+		return &Definition{Ident: id, Node: n, Tree: tree}
+	}
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		return []*Definition{def(n.Name, n)}
+	case *ast.ControlPart:
+		return []*Definition{def(n.Name, n)}
+	case *ast.TemplateDecl:
+		return []*Definition{def(n.Name, n)}
+	case *ast.SignatureDecl:
+		return []*Definition{def(n.Name, n)}
+	case *ast.SubTypeDecl:
+		if n.Field != nil {
+			return []*Definition{def(n.Field.Name, n)}
+		}
+	case *ast.StructTypeDecl:
+		return []*Definition{def(n.Name, n)}
+	case *ast.EnumTypeDecl:
+		return []*Definition{def(n.Name, n)}
+	case *ast.ComponentTypeDecl:
+		return []*Definition{def(n.Name, n)}
+	case *ast.PortTypeDecl:
+		return []*Definition{def(n.Name, n)}
+	case *ast.ValueDecl:
+		var defs []*Definition
+		for _, d := range n.Decls {
+			defs = append(defs, def(d.Name, d))
+		}
+		return defs
+	case *ast.ModuleParameterGroup:
+		var defs []*Definition
+		for _, v := range n.Decls {
+			defs = append(defs, moduleDefs(tree, v)...)
+		}
+		return defs
+	}
+	return nil
+}
+
+// declType returns the type of declarator d of module definition n.
+func declType(n ast.Node, d *ast.Declarator) ast.Expr {
+	switch n := n.(type) {
+	case *ast.ValueDecl:
+		for _, x := range n.Decls {
+			if x == d {
+				return n.Type
+			}
+		}
+	case *ast.ModuleParameterGroup:
+		for _, v := range n.Decls {
+			if t := declType(v, d); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+func isRoot(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		return n.Kind.Kind == token.TESTCASE
+	case *ast.ControlPart:
+		return true
+	}
+	return false
+}
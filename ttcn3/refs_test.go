@@ -0,0 +1,53 @@
+package ttcn3_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefGraph(t *testing.T) {
+	fs.SetContent("refs1.ttcn3", []byte(`
+	module A {
+		import from B all
+		type integer Int
+		type record R { Int x }
+		modulepar { R mp_r; integer mp_unused }
+		const integer C1 := 1, C2 := 2
+		template R t := { x := C1 }
+		function f() { log(t) }
+		function g() { h() }
+		testcase tc() runs on B.Comp { f(); log(mp_r) }
+	}`))
+	fs.SetContent("refs2.ttcn3", []byte(`
+	module B {
+		type component Comp {}
+		function h() {}
+		function t() {}
+	}
+	module C {
+		function f() {}
+		control { execute(k()) }
+		testcase k() {}
+	}`))
+
+	files := []string{"refs1.ttcn3", "refs2.ttcn3"}
+	db := &ttcn3.DB{}
+	db.Index(files...)
+	var trees []*ttcn3.Tree
+	for _, f := range files {
+		trees = append(trees, ttcn3.ParseFile(f))
+	}
+
+	var unused []string
+	for _, d := range ttcn3.NewRefGraph(db, trees...).Unused() {
+		unused = append(unused, ast.Name(d.Node))
+	}
+
+	// Function B.t is considered used, because references are resolved by name
+	// only.
+	assert.Equal(t, []string{"mp_unused", "C2", "g", "h", "f"}, unused)
+}
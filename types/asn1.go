@@ -0,0 +1,191 @@
+package types
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/nokia/ntt/asn1"
+	"github.com/nokia/ntt/internal/loc"
+	"github.com/nokia/ntt/ttcn3/ast"
+	"github.com/nokia/ntt/ttcn3/token"
+)
+
+// InsertASN1 inserts the ASN.1 module m into the given parent scope scp. Types
+// and values are mapped to TTCN-3 as specified by ETSI ES 201 873-7 and are
+// inserted using their TTCN-3 names. Value sets become subtypes of their
+// governing type. Classes, information objects and parameterised assignments
+// have no TTCN-3 counterpart and are skipped.
+func (info *Info) InsertASN1(m *asn1.Module, scp Scope) error {
+	if scp == nil {
+		panic("scp is nil")
+	}
+
+	name := asn1.TTCN3Name(m.Name)
+	mod, ok := scp.Lookup(name).(*Module)
+	if !ok {
+		if obj := scp.Lookup(name); obj != nil {
+			return &RedefinitionError{Name: name, OldPos: begin(obj), NewPos: m.Pos}
+		}
+		mod = &Module{Name: name, Scope: scp}
+		if err := insert(name, mod, scp); err != nil {
+			return err
+		}
+	}
+	for _, imp := range m.Imports {
+		mod.Imports = append(mod.Imports, asn1.TTCN3Name(imp.Module))
+	}
+
+	c := &asn1Converter{module: m}
+	var errs *multierror.Error
+	for _, a := range m.Assignments {
+		if a.Params != nil {
+			continue
+		}
+		name := asn1.TTCN3Name(a.Name)
+		switch a.Kind {
+		case asn1.TypeAssignment, asn1.ValueSetAssignment:
+			obj := &NamedType{
+				Name:  name,
+				Type:  c.typ(a.Type, mod),
+				Scope: mod,
+			}
+			errs = multierror.Append(errs, insert(name, obj, mod))
+		case asn1.ValueAssignment:
+			obj := &Var{
+				Name:  name,
+				Type:  c.typ(a.Type, mod),
+				Scope: mod,
+				begin: a.Pos,
+				end:   a.End,
+			}
+			errs = multierror.Append(errs, insert(name, obj, mod))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// asn1Converter converts the ASN.1 types of a module into TTCN-3 types.
+type asn1Converter struct {
+	module *asn1.Module
+
+	// expanding tracks assignments expanded by "COMPONENTS OF" to stop on
+	// recursive definitions.
+	expanding map[string]bool
+}
+
+func (c *asn1Converter) typ(t *asn1.Type, scp Scope) Type {
+	switch t.Kind {
+	case asn1.BooleanType:
+		return Boolean
+	case asn1.IntegerType:
+		return Integer
+	case asn1.RealType:
+		return Float
+	case asn1.BitStringType:
+		return Bitstring
+	case asn1.OctetStringType:
+		return Octetstring
+	case asn1.ObjectIdentifierType, asn1.RelativeOIDType:
+		return Objid
+	case asn1.StringType:
+		switch t.Name {
+		case "IA5String", "ISO646String", "NumericString", "PrintableString", "VisibleString",
+			"GeneralizedTime", "UTCTime", "DATE", "DATE-TIME", "DURATION", "TIME", "TIME-OF-DAY":
+			return Charstring
+		}
+		return UniversalCharstring
+
+	case asn1.NullType:
+		s := c.newStruct(EnumeratedType, t, scp)
+		insert("NULL", &Var{Name: "NULL", Type: s, Scope: s, begin: t.Pos, end: t.End}, s)
+		return s
+
+	case asn1.EnumeratedType:
+		s := c.newStruct(EnumeratedType, t, scp)
+		for _, n := range t.NamedNumbers {
+			name := asn1.TTCN3Name(n.Name)
+			insert(name, &Var{Name: name, Type: s, Scope: s, begin: n.Pos, end: n.Pos}, s)
+		}
+		return s
+
+	case asn1.SequenceType, asn1.SetType, asn1.ChoiceType:
+		kind := RecordType
+		switch t.Kind {
+		case asn1.SetType:
+			kind = SetType
+		case asn1.ChoiceType:
+			kind = UnionType
+		}
+		s := c.newStruct(kind, t, scp)
+		c.components(t.Components, s)
+		return s
+
+	case asn1.SequenceOfType, asn1.SetOfType:
+		kind := RecordOfType
+		if t.Kind == asn1.SetOfType {
+			kind = SetOfType
+		}
+		return &List{
+			kind:     kind,
+			ElemType: c.typ(t.Elem, scp),
+			Scope:    scp,
+			begin:    t.Pos,
+			end:      t.End,
+		}
+
+	case asn1.FieldType:
+		// Fixed type value fields have the type of the field, all other
+		// fields denote open types.
+		if a := c.module.Lookup(t.Name); a != nil && t.Module == "" && a.Class != nil {
+			if f := a.Class.Lookup(t.Field); f != nil && f.Kind == asn1.FixedTypeValueField {
+				return c.typ(f.Type, scp)
+			}
+		}
+		return c.newStruct(UnionType, t, scp)
+
+	case asn1.InstanceOfType, asn1.ExternalType:
+		return c.newStruct(RecordType, t, scp)
+
+	case asn1.AnyType:
+		return c.newStruct(UnionType, t, scp)
+
+	case asn1.ReferenceType:
+		var x ast.Expr = ident(asn1.TTCN3Name(t.Name))
+		if t.Module != "" {
+			x = &ast.SelectorExpr{X: ident(asn1.TTCN3Name(t.Module)), Sel: ident(asn1.TTCN3Name(t.Name))}
+		}
+		return &Ref{Expr: x, Scp: scp}
+	}
+	return &Basic{kind: UnknownType}
+}
+
+func (c *asn1Converter) newStruct(kind Kind, t *asn1.Type, scp Scope) *Struct {
+	return &Struct{kind: kind, Scope: scp, begin: t.Pos, end: t.End}
+}
+
+// components inserts the components of SEQUENCE, SET and CHOICE types into s.
+// Components of types referenced by "COMPONENTS OF" are copied.
+func (c *asn1Converter) components(comps []*asn1.Component, s *Struct) {
+	for _, comp := range comps {
+		if !comp.ComponentsOf {
+			name := asn1.TTCN3Name(comp.Name)
+			insert(name, &NamedType{Name: name, Type: c.typ(comp.Type, s), Scope: s}, s)
+			continue
+		}
+
+		t := comp.Type
+		if t.Kind == asn1.ReferenceType && t.Module == "" && !c.expanding[t.Name] {
+			if a := c.module.Lookup(t.Name); a != nil && a.Kind == asn1.TypeAssignment {
+				t = a.Type
+				if c.expanding == nil {
+					c.expanding = make(map[string]bool)
+				}
+				c.expanding[a.Name] = true
+				defer delete(c.expanding, a.Name)
+			}
+		}
+		c.components(t.Components, s)
+	}
+}
+
+func ident(name string) *ast.Ident {
+	return &ast.Ident{Tok: ast.NewToken(loc.NoPos, token.IDENT, name)}
+}
@@ -0,0 +1,77 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/asn1"
+	"github.com/nokia/ntt/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestASN1(t *testing.T) {
+	mods, err := asn1.Parse("test.asn", []byte(`
+		My-Module DEFINITIONS AUTOMATIC TAGS ::= BEGIN
+		IMPORTS Id FROM Common-Types;
+		Header ::= SEQUENCE { id Id, flags BIT STRING }
+		Message ::= SEQUENCE {
+			COMPONENTS OF Header,
+			body   CHOICE { text UTF8String, data OCTET STRING, none NULL },
+			items  SEQUENCE OF Item,
+			oid    OBJECT IDENTIFIER,
+			record BOOLEAN OPTIONAL
+		}
+		Item ::= ENUMERATED { first-item, second-item }
+		max-items INTEGER ::= 10
+		Small INTEGER ::= { 1 | 2 }
+		Wrapper { Type } ::= SEQUENCE { value Type }
+		OP ::= CLASS { &code INTEGER }
+		END
+
+		Common-Types DEFINITIONS ::= BEGIN
+		Id ::= INTEGER (0..255)
+		END`))
+	require.Nil(t, err)
+
+	scp := &orderedScope{t: t}
+	info := &types.Info{}
+	for _, m := range mods {
+		require.Nil(t, info.InsertASN1(m, scp))
+	}
+
+	mod := scp.Module("My_Module")
+	assert.Equal(t, []string{"Common_Types"}, mod.Imports)
+	assert.Equal(t, []string{"Header", "Message", "Item", "max_items", "Small"}, mod.Names())
+
+	msg := mod.Lookup("Message").(*types.NamedType).Type.(*types.Struct)
+	assert.Equal(t, types.RecordType, msg.Kind())
+	assert.Equal(t, []string{"id", "flags", "body", "items", "oid", "record_"}, msg.Names())
+
+	field := func(s types.Scope, name string) types.Type {
+		return s.Lookup(name).(*types.NamedType).Type
+	}
+	assert.Equal(t, types.Bitstring, field(msg, "flags"))
+	assert.Equal(t, types.Objid, field(msg, "oid"))
+	assert.Equal(t, types.Boolean, field(msg, "record_"))
+
+	body := field(msg, "body").(*types.Struct)
+	assert.Equal(t, types.UnionType, body.Kind())
+	assert.Equal(t, types.UniversalCharstring, field(body, "text"))
+	assert.Equal(t, types.EnumeratedType, field(body, "none_").Kind())
+
+	items := field(msg, "items").(*types.List)
+	assert.Equal(t, types.RecordOfType, items.Kind())
+	item := types.Resolve(items.ElemType.(*types.Ref))
+	assert.Equal(t, "Item", item.(*types.NamedType).Name)
+	assert.Equal(t, []string{"first_item", "second_item"}, item.(*types.NamedType).Type.(*types.Struct).Names())
+
+	// Imported types are resolved through the module imports.
+	id := types.Resolve(field(msg, "id").(*types.Ref))
+	if assert.NotNil(t, id) {
+		assert.Equal(t, types.Integer, id.(*types.NamedType).Type)
+	}
+
+	maxItems := mod.Lookup("max_items").(*types.Var)
+	assert.Equal(t, types.Integer, maxItems.Type)
+	assert.Equal(t, 13, maxItems.Begin().Line)
+}
@@ -104,7 +104,8 @@ func (info *Info) InsertTree(n ast.Node, scp Scope) error {
 		} else {
 
 			mod = &Module{
-				Name: n.Name.String(),
+				Name:  n.Name.String(),
+				Scope: scp,
 			}
 			err = insert(mod.Name, mod, scp)
 		}
@@ -117,9 +118,25 @@ func (info *Info) InsertTree(n ast.Node, scp Scope) error {
 	case *ast.ModuleDef:
 		return info.InsertTree(n.Def, scp)
 
+	case *ast.ImportDecl:
+		if mod, ok := scp.(*Module); ok && n.Module != nil {
+			mod.Imports = append(mod.Imports, n.Module.String())
+		}
+		return nil
+
+	case *ast.FriendDecl, *ast.ControlPart:
+		return nil
+
 	case *ast.ValueDecl:
 		return insertValueDecl(n, scp, info).ErrorOrNil()
 
+	case *ast.ModuleParameterGroup:
+		var errs *multierror.Error
+		for _, d := range n.Decls {
+			errs = multierror.Append(errs, insertValueDecl(d, scp, info))
+		}
+		return errs.ErrorOrNil()
+
 	case *ast.TemplateDecl:
 		return insertTemplateDecl(n, scp, info)
 
@@ -135,6 +152,15 @@ func (info *Info) InsertTree(n ast.Node, scp Scope) error {
 	case *ast.ComponentTypeDecl:
 		return insertComponentTypeDecl(n, scp, info)
 
+	case *ast.PortTypeDecl:
+		return insertPortTypeDecl(n, scp, info)
+
+	case *ast.FuncDecl:
+		return insertFuncDecl(n, scp, info)
+
+	case *ast.SignatureDecl:
+		return insertSignatureDecl(n, scp, info)
+
 	case *ast.NodeList:
 		return insertNodes(n.Nodes, scp, info).ErrorOrNil()
 
@@ -170,7 +196,12 @@ func insertModuleDefs(n []*ast.ModuleDef, scp Scope, info *Info) *multierror.Err
 
 func insertValueDecl(n *ast.ValueDecl, scp Scope, info *Info) *multierror.Error {
 	var errs *multierror.Error
-	typ := info.TypeOf(n.Type, scp)
+
+	// Timer declarations have no type expression.
+	var typ Type = Timer
+	if n.Type != nil {
+		typ = info.TypeOf(n.Type, scp)
+	}
 
 	for _, decl := range n.Decls {
 		errs = multierror.Append(errs, insertDeclarator(decl, typ, scp, info))
@@ -219,8 +250,9 @@ func insertStructTypeDecl(n *ast.StructTypeDecl, scp Scope, info *Info) error {
 
 	name := n.Name.String()
 	obj := &NamedType{
-		Name: name,
-		Type: typ,
+		Name:  name,
+		Type:  typ,
+		Scope: scp,
 	}
 
 	return insert(name, obj, scp)
@@ -239,8 +271,9 @@ func insertEnumTypeDecl(n *ast.EnumTypeDecl, scp Scope, info *Info) error {
 
 	name := n.Name.String()
 	obj := &NamedType{
-		Name: name,
-		Type: typ,
+		Name:  name,
+		Type:  typ,
+		Scope: scp,
 	}
 
 	return insert(name, obj, scp)
@@ -260,13 +293,78 @@ func insertComponentTypeDecl(n *ast.ComponentTypeDecl, scp Scope, info *Info) er
 
 	name := n.Name.String()
 	obj := &NamedType{
-		Name: name,
-		Type: comp,
+		Name:  name,
+		Type:  comp,
+		Scope: scp,
 	}
 
 	return multierror.Append(errs, insert(name, obj, scp)).ErrorOrNil()
 }
 
+func insertPortTypeDecl(n *ast.PortTypeDecl, scp Scope, info *Info) error {
+	info.trackScopes(n, scp)
+	name := n.Name.String()
+	obj := &NamedType{
+		Name:  name,
+		Scope: scp,
+		Type: &Port{
+			Scope: scp,
+			begin: info.position(ast.FirstToken(n).Pos()),
+			end:   info.position(n.End()),
+		},
+	}
+	return insert(name, obj, scp)
+}
+
+func insertFuncDecl(n *ast.FuncDecl, scp Scope, info *Info) error {
+	name := n.Name.String()
+	obj := &Func{
+		Name:  name,
+		Kind:  funcKind(n.Kind.Kind),
+		Scope: scp,
+		begin: info.position(n.Name.Pos()),
+		end:   info.position(n.Name.End()),
+	}
+	obj.Params = formalPars(n.Params, scp, info)
+	if n.Return != nil {
+		obj.Result = info.TypeOf(n.Return.Type, scp)
+	}
+	return insert(name, obj, scp)
+}
+
+func insertSignatureDecl(n *ast.SignatureDecl, scp Scope, info *Info) error {
+	name := n.Name.String()
+	obj := &Func{
+		Name:   name,
+		Kind:   SignatureType,
+		Params: formalPars(n.Params, scp, info),
+		Scope:  scp,
+		begin:  info.position(n.Name.Pos()),
+		end:    info.position(n.Name.End()),
+	}
+	if n.Return != nil {
+		obj.Result = info.TypeOf(n.Return.Type, scp)
+	}
+	return insert(name, obj, scp)
+}
+
+func formalPars(n *ast.FormalPars, scp Scope, info *Info) []*Var {
+	if n == nil {
+		return nil
+	}
+	var vars []*Var
+	for _, p := range n.List {
+		vars = append(vars, &Var{
+			Name:  p.Name.String(),
+			Type:  wrapArray(p.ArrayDef, info.TypeOf(p.Type, scp), scp, info),
+			Scope: scp,
+			begin: info.position(p.Name.Pos()),
+			end:   info.position(p.Name.End()),
+		})
+	}
+	return vars
+}
+
 func insertNamedType(n *ast.Field, scp Scope, info *Info) error {
 	if n.ValueConstraint != nil {
 		info.trackScopes(n.ValueConstraint, scp)
@@ -362,6 +460,17 @@ func structKind(tok token.Kind) Kind {
 	}
 }
 
+func funcKind(tok token.Kind) Kind {
+	switch tok {
+	case token.TESTCASE:
+		return TestcaseType
+	case token.ALTSTEP:
+		return AltstepType
+	default:
+		return FunctionType
+	}
+}
+
 func listKind(tok token.Kind) Kind {
 	switch tok {
 	case token.RECORD:
@@ -383,6 +492,17 @@ func begin(obj Object) loc.Position {
 
 var (
 	predefinedTypes = map[string]Type{
-		"integer": Integer,
+		"integer":              Integer,
+		"boolean":              Boolean,
+		"float":                Float,
+		"bitstring":            Bitstring,
+		"hexstring":            Hexstring,
+		"octetstring":          Octetstring,
+		"charstring":           Charstring,
+		"universal charstring": UniversalCharstring,
+		"verdicttype":          Verdict,
+		"default":              Default,
+		"timer":                Timer,
+		"objid":                Objid,
 	}
 )
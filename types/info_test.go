@@ -206,3 +206,62 @@ func TestModule(t *testing.T) {
 	}
 
 }
+
+func TestFuncDecl(t *testing.T) {
+	input := `
+		module m {
+			function f(integer x, boolean y[2]) return float {}
+			testcase tc() runs on C {}
+		}`
+	scp, _, _ := makeScope(t, input)
+	m := scp.Module("m")
+
+	f, ok := m.Lookup("f").(*types.Func)
+	if !ok {
+		t.Fatalf("f is not a function. got=%T", m.Lookup("f"))
+	}
+	assert.Equal(t, types.FunctionType, f.Kind)
+	assert.Equal(t, types.Float, f.Result)
+	if assert.Len(t, f.Params, 2) {
+		assert.Equal(t, types.Integer, f.Params[0].Type)
+		assert.Equal(t, types.ArrayType, f.Params[1].Type.Kind())
+	}
+
+	tc, ok := m.Lookup("tc").(*types.Func)
+	if !ok {
+		t.Fatalf("tc is not a function. got=%T", m.Lookup("tc"))
+	}
+	assert.Equal(t, types.TestcaseType, tc.Kind)
+}
+
+func TestResolve(t *testing.T) {
+	input := `
+		module a {
+			import from b all;
+			type B x;
+			type b.B y;
+			type record R { B f, C g }
+		}
+		module b {
+			type integer B;
+			type port P message { inout integer }
+		}`
+	scp, _, _ := makeScope(t, input)
+	a := scp.Module("a")
+	b := scp.Module("b")
+	assert.Equal(t, []string{"b"}, a.Imports)
+
+	x := a.Lookup("x").(*types.NamedType).Type.(*types.Ref)
+	assert.Equal(t, b.Lookup("B"), types.Resolve(x))
+
+	y := a.Lookup("y").(*types.NamedType).Type.(*types.Ref)
+	assert.Equal(t, b.Lookup("B"), types.Resolve(y))
+
+	R := a.Lookup("R").(*types.NamedType).Type.(types.Scope)
+	f := R.Lookup("f").(*types.NamedType).Type.(*types.Ref)
+	assert.Equal(t, b.Lookup("B"), types.Resolve(f))
+	g := R.Lookup("g").(*types.NamedType).Type.(*types.Ref)
+	assert.Nil(t, types.Resolve(g))
+
+	assert.Equal(t, types.PortType, b.Lookup("P").(*types.NamedType).Kind())
+}
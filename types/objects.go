@@ -12,10 +12,11 @@ type pair struct {
 
 // Module represents a TTCN-3 module.
 type Module struct {
-	Name  string
-	Scope Scope
-	pairs []pair
-	names map[string]pair
+	Name    string
+	Scope   Scope
+	Imports []string // Names of imported modules
+	pairs   []pair
+	names   map[string]pair
 }
 
 // EnclsosingScope returns the parent (== global) scope of the module
@@ -23,7 +24,8 @@ func (m *Module) EnclosingScope() Scope {
 	return m.Scope
 }
 
-// Insert inserts an object into the scope.
+// Insert inserts an object into the scope. If the name is already defined, the
+// scope is not changed and the existing object is returned.
 func (m *Module) Insert(name string, obj Object) Object {
 	if m.names == nil {
 		m.names = make(map[string]pair)
@@ -34,7 +36,7 @@ func (m *Module) Insert(name string, obj Object) Object {
 
 	m.names[name] = pair{name, obj}
 	m.pairs = append(m.pairs, pair{name, obj})
-	return obj
+	return nil
 }
 
 // Lookup returns the object with the given name in the scope.
@@ -86,7 +88,8 @@ func (s *Struct) EnclosingScope() Scope {
 	return s.Scope
 }
 
-// Insert inserts an object into the scope.
+// Insert inserts an object into the scope. If the name is already defined, the
+// scope is not changed and the existing object is returned.
 func (s *Struct) Insert(name string, obj Object) Object {
 	if s.names == nil {
 		s.names = make(map[string]pair)
@@ -97,7 +100,7 @@ func (s *Struct) Insert(name string, obj Object) Object {
 
 	s.names[name] = pair{name, obj}
 	s.fields = append(s.fields, pair{name, obj})
-	return obj
+	return nil
 }
 
 // Lookup returns the object with the given name in the scope.
@@ -175,7 +178,8 @@ func (c *Component) EnclosingScope() Scope {
 	return c.Scope
 }
 
-// Insert inserts an object into the scope.
+// Insert inserts an object into the scope. If the name is already defined, the
+// scope is not changed and the existing object is returned.
 func (c *Component) Insert(name string, obj Object) Object {
 	if c.names == nil {
 		c.names = make(map[string]pair)
@@ -186,7 +190,7 @@ func (c *Component) Insert(name string, obj Object) Object {
 
 	c.names[name] = pair{name, obj}
 	c.fields = append(c.fields, pair{name, obj})
-	return obj
+	return nil
 }
 
 // Lookup returns the object with the given name in the scope.
@@ -248,6 +252,56 @@ func (v *Var) EnclosingScope() Scope {
 	return v.Scope
 }
 
+// Port represents a port type.
+type Port struct {
+	Scope Scope
+
+	begin, end loc.Position
+}
+
+func (p *Port) EnclosingScope() Scope {
+	return p.Scope
+}
+
+func (p *Port) Kind() Kind {
+	return PortType
+}
+
+func (p *Port) CompatibleTo(other Type) bool {
+	return p == other
+}
+
+func (p *Port) Begin() loc.Position {
+	return p.begin
+}
+
+func (p *Port) End() loc.Position {
+	return p.end
+}
+
+// Func represents a function, altstep, testcase or signature.
+type Func struct {
+	Name   string
+	Kind   Kind   // FunctionType, AltstepType, TestcaseType or SignatureType
+	Params []*Var // Formal parameters
+	Result Type   // Return type or nil
+	Scope  Scope
+
+	begin, end loc.Position
+}
+
+func (f *Func) EnclosingScope() Scope {
+	return f.Scope
+}
+
+func (f *Func) Begin() loc.Position {
+	return f.begin
+}
+
+func (f *Func) End() loc.Position {
+	return f.end
+}
+
 // Basic represents a basic TTCN-3 type, such as integer, boolean, ...
 type Basic struct {
 	kind Kind
@@ -0,0 +1,22 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsert(t *testing.T) {
+	scopes := map[string]types.Scope{
+		"module":    &types.Module{},
+		"struct":    &types.Struct{},
+		"component": &types.Component{},
+	}
+	for name, scp := range scopes {
+		x := &types.Var{}
+		assert.Nil(t, scp.Insert("x", x), name)
+		assert.Equal(t, x, scp.Insert("x", &types.Var{}), name)
+		assert.Equal(t, x, scp.Lookup("x"), name)
+	}
+}
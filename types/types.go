@@ -9,23 +9,48 @@ import (
 )
 
 var (
-	Integer = &Basic{kind: IntegerType}
-	Boolean = &Basic{kind: BooleanType}
+	Integer             = &Basic{kind: IntegerType}
+	Boolean             = &Basic{kind: BooleanType}
+	Float               = &Basic{kind: FloatType}
+	Bitstring           = &Basic{kind: BitstringType}
+	Hexstring           = &Basic{kind: HexstringType}
+	Octetstring         = &Basic{kind: OctetstringType}
+	Charstring          = &Basic{kind: CharstringType}
+	UniversalCharstring = &Basic{kind: UniversalCharstringType}
+	Verdict             = &Basic{kind: VerdictType}
+	Default             = &Basic{kind: DefaultType}
+	Timer               = &Basic{kind: TimerType}
+	Objid               = &Basic{kind: ObjidType}
 )
 
 const (
-	UnknownType    Kind = "unknown type"
-	IntegerType    Kind = "integer"
-	UnionType      Kind = "union"
-	EnumeratedType Kind = "enumerated"
-	SetType        Kind = "set"
-	RecordType     Kind = "record"
-	BooleanType    Kind = "boolean"
-	RecordOfType   Kind = "record of"
-	SetOfType      Kind = "set of"
-	ArrayType      Kind = "array of"
-	ComponentType  Kind = "component"
-	TypeReference  Kind = "type reference"
+	UnknownType             Kind = "unknown type"
+	IntegerType             Kind = "integer"
+	FloatType               Kind = "float"
+	BitstringType           Kind = "bitstring"
+	HexstringType           Kind = "hexstring"
+	OctetstringType         Kind = "octetstring"
+	CharstringType          Kind = "charstring"
+	UniversalCharstringType Kind = "universal charstring"
+	VerdictType             Kind = "verdicttype"
+	DefaultType             Kind = "default"
+	TimerType               Kind = "timer"
+	ObjidType               Kind = "objid"
+	UnionType               Kind = "union"
+	EnumeratedType          Kind = "enumerated"
+	SetType                 Kind = "set"
+	RecordType              Kind = "record"
+	BooleanType             Kind = "boolean"
+	RecordOfType            Kind = "record of"
+	SetOfType               Kind = "set of"
+	ArrayType               Kind = "array of"
+	ComponentType           Kind = "component"
+	PortType                Kind = "port"
+	FunctionType            Kind = "function"
+	AltstepType             Kind = "altstep"
+	TestcaseType            Kind = "testcase"
+	SignatureType           Kind = "signature"
+	TypeReference           Kind = "type reference"
 )
 
 // Kind returns the kind of the object.
@@ -41,7 +66,8 @@ type Object interface {
 type Scope interface {
 	Object
 
-	// Insert inserts an object into the scope.
+	// Insert inserts an object into the scope. It returns the already
+	// existing object, if the name is defined already, and nil otherwise.
 	Insert(name string, obj Object) Object
 
 	// Lookup returns the object with the given name in the scope.
@@ -84,6 +110,57 @@ func Equal(a, b Object) bool {
 	return true
 }
 
+// Resolve returns the object referenced by r and stores it in r.Obj.
+// Identifiers are looked up in the scope of the reference and its enclosing
+// scopes. When a module scope is reached, the modules imported by it are
+// searched, too. Selector expressions, like "M.T", are resolved in the scope
+// of the preceding object. Resolve returns nil if the object could not be
+// found.
+func Resolve(r *Ref) Object {
+	if r.Obj == nil {
+		r.Obj = resolve(r.Expr, r.Scp)
+	}
+	return r.Obj
+}
+
+func resolve(n ast.Expr, scp Scope) Object {
+	switch n := n.(type) {
+	case *ast.Ident:
+		return lookup(n.String(), scp)
+	case *ast.ParametrizedIdent:
+		return resolve(n.Ident, scp)
+	case *ast.SelectorExpr:
+		obj := resolve(n.X, scp)
+		if nt, ok := obj.(*NamedType); ok {
+			obj = nt.Type
+		}
+		if s, ok := obj.(Scope); ok && n.Sel != nil {
+			return s.Lookup(ast.Name(n.Sel))
+		}
+	}
+	return nil
+}
+
+func lookup(name string, scp Scope) Object {
+	for ; scp != nil; scp = scp.EnclosingScope() {
+		if obj := scp.Lookup(name); obj != nil {
+			return obj
+		}
+		m, ok := scp.(*Module)
+		if !ok || m.Scope == nil {
+			continue
+		}
+		for _, imp := range m.Imports {
+			if im, ok := m.Scope.Lookup(imp).(*Module); ok && im != m {
+				if obj := im.Lookup(name); obj != nil {
+					return obj
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // NodeNotImplementedError is returned when a syntax node is not implemented.
 type NodeNotImplementedError struct {
 	Node ast.Node